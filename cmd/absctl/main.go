@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"log/slog"
 	"os"
@@ -23,6 +24,19 @@ import (
 	"syscall"
 
 	"github.com/aerospike/absctl/internal/cli"
+	"github.com/aerospike/absctl/internal/models"
+)
+
+const (
+	// exitCodeError is returned when the command fails.
+	exitCodeError = 1
+	// exitCodeVerifyFailed is returned when the backup doesn't match its manifest.
+	exitCodeVerifyFailed = 2
+	// exitCodeBackupInterrupted is returned when the backup was stopped by a signal and can be continued.
+	exitCodeBackupInterrupted = 3
+	// exitCodeSignatureNotChecked is returned when the backup matches its manifest,
+	// but the signature of the manifest was not checked.
+	exitCodeSignatureNotChecked = 4
 )
 
 var (
@@ -33,14 +47,20 @@ var (
 
 func main() {
 	// Initializing context with cancel for graceful shutdown.
-	ctx, cancel := context.WithCancel(context.Background())
-	sigChan := make(chan os.Signal, 1)
+	// The first signal stops the command. A backup that saves its state is stopped after its open files
+	// are closed and the state is saved. The second signal force quits.
+	ctx, cancel := context.WithCancelCause(context.Background())
+	sigChan := make(chan os.Signal, 2)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, os.Kill)
 
 	go func() {
 		sig := <-sigChan
-		log.Printf("stopping backup: %v\n", sig)
-		cancel()
+		log.Printf("stopping backup: %v, send the signal again to force quit\n", sig)
+		cancel(models.ErrInterrupted)
+
+		sig = <-sigChan
+		log.Printf("force quitting: %v\n", sig)
+		os.Exit(exitCodeError)
 	}()
 
 	// Return c to log errors properly.
@@ -49,6 +69,20 @@ func main() {
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		c.Logger.Error("failed to execute", slog.Any("error", err))
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode maps the command error to the process exit code.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, models.ErrVerifyFailed):
+		return exitCodeVerifyFailed
+	case errors.Is(err, models.ErrVerifySignatureNotChecked):
+		return exitCodeSignatureNotChecked
+	case errors.Is(err, models.ErrBackupInterrupted):
+		return exitCodeBackupInterrupted
+	default:
+		return exitCodeError
 	}
 }
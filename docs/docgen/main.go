@@ -297,6 +297,16 @@ func buildSections(operation string, opID flags.Operation) []docSection {
 		},
 	})
 
+	// 13. SFTP Flags
+	sections = append(sections, docSection{
+		TextContent: flags.SectionTextSftp,
+		FS:          flags.NewSftp(opID).NewFlagSet(),
+		YAMLPrefix:  "sftp",
+		FlagToYAMLPath: func(prefix string, f *pflag.Flag) string {
+			return prefix + "." + strings.TrimPrefix(f.Name, "sftp-")
+		},
+	})
+
 	return sections
 }
 
@@ -380,7 +390,7 @@ func generateYAMLContent(operation string, sections []docSection) (string, error
 	addComments(&doc, "", comments)
 
 	// Clean out empty keys for specific cloud integrations
-	cloudPrefixes := []string{"aws.s3", "gcp.storage", "azure.blob"}
+	cloudPrefixes := []string{"aws.s3", "gcp.storage", "azure.blob", "sftp"}
 	removeUncommentedLeaves(&doc, "", cloudPrefixes)
 
 	var buf bytes.Buffer
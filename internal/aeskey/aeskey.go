@@ -0,0 +1,120 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aeskey
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aerospike/backup-go"
+)
+
+// aes128KeySize is the size of the AES-128 key in bytes.
+const aes128KeySize = 16
+
+// Read loads the private key set in the encryption policy and derives the AES key from it,
+// the same way as on backup: the SHA-256 of the PKCS #1 encoded RSA private key,
+// truncated to 16 bytes for AES128.
+func Read(
+	ctx context.Context,
+	policy *backup.EncryptionPolicy,
+	saConfig *backup.SecretAgentConfig,
+) ([]byte, error) {
+	pemData, err := readPEM(ctx, policy, saConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parsePrivateKey(pemData)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.Mode == backup.EncryptAES128 {
+		key = key[:aes128KeySize]
+	}
+
+	return key, nil
+}
+
+// readPEM reads the PEM encoded private key from a file, an environment variable or the secret agent.
+// Keys from an environment variable or the secret agent are Base64 encoded.
+func readPEM(ctx context.Context, policy *backup.EncryptionPolicy, saConfig *backup.SecretAgentConfig) ([]byte, error) {
+	var (
+		encoded string
+		err     error
+	)
+
+	switch {
+	case policy.KeyFile != nil:
+		data, err := os.ReadFile(*policy.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+		}
+
+		return data, nil
+	case policy.KeyEnv != nil:
+		encoded = os.Getenv(*policy.KeyEnv)
+		if encoded == "" {
+			return nil, fmt.Errorf("environment variable %s is empty", *policy.KeyEnv)
+		}
+	case policy.KeySecret != nil:
+		encoded, err = backup.ParseSecret(ctx, saConfig, *policy.KeySecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key secret: %w", err)
+		}
+	default:
+		return nil, errors.New("encryption key is not set")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption key: %w", err)
+	}
+
+	return data, nil
+}
+
+// parsePrivateKey parses a PKCS #1 or PKCS #8 RSA private key and returns its SHA-256 hash.
+func parsePrivateKey(pemData []byte) ([]byte, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block containing the encryption key")
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if pkcs8Err != nil {
+			return nil, fmt.Errorf("failed to parse encryption key: %w", errors.Join(err, pkcs8Err))
+		}
+
+		var ok bool
+		if privateKey, ok = parsed.(*rsa.PrivateKey); !ok {
+			return nil, errors.New("encryption key is not an RSA private key")
+		}
+	}
+
+	sum := sha256.Sum256(x509.MarshalPKCS1PrivateKey(privateKey))
+
+	return sum[:], nil
+}
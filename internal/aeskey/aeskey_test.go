@@ -0,0 +1,111 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aeskey
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/backup-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPrivateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return key
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	t.Parallel()
+
+	key := newTestPrivateKey(t)
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	fromPKCS1, err := parsePrivateKey(pkcs1)
+	require.NoError(t, err)
+	assert.Len(t, fromPKCS1, 32)
+
+	fromPKCS8, err := parsePrivateKey(pkcs8)
+	require.NoError(t, err)
+	assert.Equal(t, fromPKCS1, fromPKCS8)
+
+	_, err = parsePrivateKey([]byte("not a key"))
+	require.ErrorContains(t, err, "failed to decode PEM block")
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	key := newTestPrivateKey(t)
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(keyFile, pemData, 0o600))
+
+	want, err := parsePrivateKey(pemData)
+	require.NoError(t, err)
+
+	aes256, err := Read(t.Context(), &backup.EncryptionPolicy{
+		Mode:    backup.EncryptAES256,
+		KeyFile: &keyFile,
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, aes256)
+
+	aes128, err := Read(t.Context(), &backup.EncryptionPolicy{
+		Mode:    backup.EncryptAES128,
+		KeyFile: &keyFile,
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want[:aes128KeySize], aes128)
+
+	_, err = Read(t.Context(), &backup.EncryptionPolicy{Mode: backup.EncryptAES256}, nil)
+	require.ErrorContains(t, err, "encryption key is not set")
+}
+
+func TestRead_Env(t *testing.T) {
+	key := newTestPrivateKey(t)
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	keyEnv := "ABSCTL_TEST_INSPECT_KEY"
+	t.Setenv(keyEnv, base64.StdEncoding.EncodeToString(pemData))
+
+	want, err := parsePrivateKey(pemData)
+	require.NoError(t, err)
+
+	got, err := Read(t.Context(), &backup.EncryptionPolicy{
+		Mode:   backup.EncryptAES256,
+		KeyEnv: &keyEnv,
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
@@ -19,10 +19,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/export"
 	"github.com/aerospike/absctl/internal/logging"
+	"github.com/aerospike/absctl/internal/metrics"
 	"github.com/aerospike/absctl/internal/models"
 	"github.com/aerospike/absctl/internal/storage"
 	"github.com/aerospike/aerospike-client-go/v8"
@@ -32,6 +36,9 @@ import (
 	iModels "github.com/aerospike/backup-go/pkg/asinfo/models"
 )
 
+// operationName is used in progress reports and metric names.
+const operationName = "backup"
+
 var xdrSupportedVersion = iModels.AerospikeVersion{Major: 8}
 
 // Service represents a struct that encapsulates components for backup and logging functionalities.
@@ -41,16 +48,48 @@ type Service struct {
 	backupClient *backup.Client
 	config       *backup.ConfigBackup
 	configXdr    *backup.ConfigBackupXDR
+	// serviceConfig is used to list the files in the backup directory.
+	serviceConfig *config.BackupServiceConfig
 
 	writer backup.Writer
+	// fanOut are the writers of the backups written to several destinations at once.
+	fanOut []*storage.FanOutWriter
 	// reader is used to read a state file.
 	reader backup.StreamingReader
+	// state tracks the saves of the state file, nil if the backup doesn't save its state.
+	state *stateWriter
+	// manifest tracks written files, nil if the manifest is not saved.
+	manifest *ManifestWriter
+	// startTime is the start time of the continued backup, zero if the backup is not continued.
+	startTime time.Time
+	// incremental links the backup to the previous backups, if incremental-from is set.
+	incremental *models.ManifestIncremental
+	// namespaces are backed up one by one instead of config, if several namespaces are set.
+	namespaces []*namespaceBackup
+	// plan is printed instead of running the backup, if dry-run is set.
+	plan *models.BackupPlan
 
 	// Additional params.
 	isEstimate       bool
 	estimatesSamples int64
+	// resumable is true if the backup is continued automatically by the next run.
+	resumable bool
+	// checkpoint is true if the state file is saved only to continue the backup after a signal.
+	checkpoint bool
 
 	reportToLog bool
+	// progressInterval is the interval between progress reports, 0 if progress is not reported.
+	progressInterval time.Duration
+	// progressEstimate is true if the backup size can be estimated to report the percent done.
+	progressEstimate bool
+	// metrics exports live stats, nil if metrics are not enabled.
+	metrics *metrics.Exporter
+	// stats of all started backups, summed up for the run report.
+	stats []*bModels.BackupStats
+
+	// Build info saved to the manifest.
+	appVersion string
+	commitHash string
 
 	logger *slog.Logger
 }
@@ -62,12 +101,37 @@ func NewService(
 	cfg *config.BackupServiceConfig,
 	logger *slog.Logger,
 ) (*Service, error) {
+	if err := applyResumable(ctx, cfg, logger); err != nil {
+		return nil, err
+	}
+
+	checkpoint := applyCheckpoint(cfg, logger)
+
+	if cfg.IsDryRun() {
+		return newDryRunService(ctx, cfg, logger)
+	}
+
+	if cfg.Backup != nil && cfg.Backup.IsMultiNamespace() {
+		return newMultiNamespaceService(ctx, cfg, logger)
+	}
+
 	// Initializations.
 	backupConfig, backupXDRConfig, err := config.NewBackupConfigs(cfg, logger)
 	if err != nil {
 		return nil, err
 	}
 
+	if backupConfig != nil && backupConfig.Continue {
+		if err = checkFingerprint(ctx, cfg, backupConfig, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	incremental, err := applyIncrementalFrom(ctx, cfg, backupConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	// We don't need a writer for estimates.
 	var writer backup.Writer
 	if cfg.SkipWriterInit() {
@@ -82,11 +146,46 @@ func NewService(
 		}
 	}
 
+	// The writer is wrapped below, so the fan-out writer is kept for the destinations report.
+	var fanOut []*storage.FanOutWriter
+	if w, ok := writer.(*storage.FanOutWriter); ok {
+		fanOut = append(fanOut, w)
+	}
+
+	// The manifest is saved only for directory backups, as for a single file
+	// or stdout it would overwrite the backup itself.
+	var manifest *ManifestWriter
+	if writer != nil && cfg.Backup != nil && cfg.Backup.Directory != "" {
+		if manifest, err = newManifestWriter(ctx, writer, backupConfig); err != nil {
+			return nil, err
+		}
+
+		writer = manifest
+	}
+
+	// The state writer sees the names of the exported files, to remove them after an interruption.
+	var state *stateWriter
+	if writer != nil && backupConfig.StateFile != "" {
+		state = newStateWriter(writer, backupConfig.StateFile)
+		writer = state
+	}
+
+	if writer != nil {
+		writer = newExportWriter(cfg, backupConfig, writer)
+	}
+
 	reader, err := storage.NewStateReader(ctx, cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize state reader: %w", err)
 	}
 
+	var startTime time.Time
+	if backupConfig.Continue && manifest != nil {
+		if startTime, err = continueManifest(ctx, cfg, backupConfig, manifest, logger); err != nil {
+			return nil, err
+		}
+	}
+
 	aerospikeClient, err := storage.NewAerospikeClient(
 		cfg.ClientConfig,
 		cfg.ClientPolicy,
@@ -118,18 +217,33 @@ func NewService(
 	}
 
 	asb := &Service{
-		backupClient: backupClient,
-		config:       backupConfig,
-		configXdr:    backupXDRConfig,
-		writer:       writer,
-		reader:       reader,
-		logger:       logger,
-		reportToLog:  cfg.App.LogJSON || cfg.App.LogFile != "",
+		backupClient:  backupClient,
+		serviceConfig: cfg,
+		config:        backupConfig,
+		configXdr:     backupXDRConfig,
+		writer:        writer,
+		fanOut:        fanOut,
+		reader:        reader,
+		state:         state,
+		manifest:      manifest,
+		startTime:     startTime,
+		incremental:   incremental,
+		logger:        logger,
+		reportToLog:   cfg.App.LogJSON || cfg.App.LogFile != "",
+		checkpoint:    checkpoint,
+	}
+
+	// Estimates don't back up any data, so there is nothing to export.
+	if cfg.Backup == nil || !cfg.Backup.Estimate {
+		asb.metrics = metrics.NewExporter(operationName, cfg.App, logger)
 	}
 
 	if cfg.Backup != nil {
 		asb.isEstimate = cfg.Backup.Estimate
 		asb.estimatesSamples = cfg.Backup.EstimateSamples
+		asb.resumable = cfg.Backup.Resumable
+		asb.progressInterval = time.Duration(cfg.Backup.ProgressInterval) * time.Second
+		asb.progressEstimate = !cfg.Backup.HasFilter() && !cfg.Backup.NoRecords
 	}
 
 	return asb, nil
@@ -202,7 +316,24 @@ func (s *Service) Run(ctx context.Context) error {
 		return nil
 	}
 
+	if err := s.metrics.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start metrics exporter: %w", err)
+	}
+
+	err := s.run(ctx)
+	s.metrics.Stop(err)
+
+	return err
+}
+
+func (s *Service) run(ctx context.Context) error {
 	switch {
+	case s.plan != nil:
+		logging.ReportBackupPlan(s.plan, s.reportToLog, s.logger)
+
+		if !s.plan.Passed() {
+			return models.ErrDryRunFailed
+		}
 	case s.isEstimate:
 		s.logger.Info("calculating backup estimate")
 		// Calculating estimates.
@@ -225,6 +356,9 @@ func (s *Service) Run(ctx context.Context) error {
 			return fmt.Errorf("failed to start backup of indexes and udfs: %w", err)
 		}
 
+		s.trackStats(s.configXdr.Namespace, metrics.TypeXDR, hXdr.GetStats())
+		s.trackStats(s.config.Namespace, metrics.TypeScan, h.GetStats())
+
 		if err = hXdr.Wait(ctx); err != nil {
 			return fmt.Errorf("failed to xdr backup: %w", err)
 		}
@@ -235,24 +369,146 @@ func (s *Service) Run(ctx context.Context) error {
 
 		stats := bModels.SumBackupStats(h.GetStats(), hXdr.GetStats())
 		logging.ReportBackup(stats, true, s.reportToLog, s.logger)
+	case len(s.namespaces) > 0:
+		return s.runNamespaces(ctx)
 	default:
+		total := s.estimateProgressTotal(ctx, s.config)
+
 		s.logger.Info("starting scan backup")
+
+		backupCtx, stop := s.backupContext(ctx)
+		defer stop()
+
+		startTime := time.Now()
+
 		// Running ordinary backup.
-		h, err := s.backupClient.Backup(ctx, s.config, s.writer, s.reader)
+		h, err := s.backupClient.Backup(backupCtx, s.config, s.writer, s.reader)
 		if err != nil {
 			return fmt.Errorf("failed to start backup: %w", errHumanize(err))
 		}
 
-		if err = h.Wait(ctx); err != nil {
+		// The backup goes on without it, only the manifest of a continued backup would have a later start time.
+		if err = s.saveStartTime(ctx, startTime); err != nil {
+			s.logger.Warn("failed to save backup start time and options", slog.Any("error", err))
+		}
+
+		s.trackStats(s.config.Namespace, metrics.TypeScan, h.GetStats())
+
+		stopProgress := s.startProgress(ctx, h.GetStats(), total)
+		err = h.Wait(backupCtx)
+
+		stopProgress()
+
+		if err != nil {
+			if iErr := s.interruptedError(ctx); iErr != nil {
+				return iErr
+			}
+
 			return fmt.Errorf("failed to backup: %w", err)
 		}
 
 		logging.ReportBackup(h.GetStats(), false, s.reportToLog, s.logger)
+		logging.ReportDestinations(s.Destinations(), s.reportToLog, s.logger)
+
+		// The state file is removed first, so it is not listed in the manifest.
+		if err = s.removeStateFile(ctx); err != nil {
+			return err
+		}
+
+		if err = s.saveManifest(ctx, s.config, s.manifest, s.incremental, h.GetStats()); err != nil {
+			return fmt.Errorf("failed to save backup manifest: %w", err)
+		}
 	}
 
 	return nil
 }
 
+// SetBuildInfo sets the absctl version and commit that are saved to the backup manifest.
+func (s *Service) SetBuildInfo(appVersion, commitHash string) {
+	// If backup was called with --remove-artifacts, it would be nil.
+	if s == nil {
+		return
+	}
+
+	s.appVersion = appVersion
+	s.commitHash = commitHash
+}
+
+// Stats returns the total stats of all backups started by the service, or nil if none was started.
+// Stats are available even if the backup failed.
+func (s *Service) Stats() *bModels.BackupStats {
+	if s == nil {
+		return nil
+	}
+
+	var total *bModels.BackupStats
+
+	for _, stats := range s.stats {
+		if total == nil {
+			total = stats
+			continue
+		}
+
+		total = bModels.SumBackupStats(total, stats)
+	}
+
+	return total
+}
+
+// Destinations returns the stats of each destination of all backups started by the service,
+// or nil if the backups are not written to several destinations.
+func (s *Service) Destinations() []models.DestinationStats {
+	if s == nil {
+		return nil
+	}
+
+	var total []models.DestinationStats
+	for _, w := range s.fanOut {
+		total = models.SumDestinationStats(total, w.Stats())
+	}
+
+	return total
+}
+
+// trackStats registers the stats of a started backup for metrics and the run report.
+func (s *Service) trackStats(namespace, kind string, stats *bModels.BackupStats) {
+	s.metrics.AddBackup(namespace, kind, stats)
+	s.stats = append(s.stats, stats)
+}
+
+// saveManifest writes the manifest with the list of backup files next to them.
+func (s *Service) saveManifest(
+	ctx context.Context,
+	cfg *backup.ConfigBackup,
+	mw *ManifestWriter,
+	incremental *models.ManifestIncremental,
+	stats *bModels.BackupStats,
+) error {
+	if mw == nil {
+		return nil
+	}
+
+	manifest := newManifest(cfg, stats, mw.Files(), s.appVersion, s.commitHash)
+	manifest.Incremental = incremental
+
+	// A continued backup holds the records read since the first run started.
+	if !s.startTime.IsZero() {
+		manifest.StartTime = s.startTime
+	}
+
+	if err := mw.Save(ctx, manifest); err != nil {
+		return err
+	}
+
+	s.logger.Info("backup manifest saved",
+		slog.String("namespace", cfg.Namespace),
+		slog.String("file", models.ManifestFileName),
+		slog.Int("files", len(manifest.Files)),
+	)
+
+	return nil
+}
+
 func stopXDR(ctx context.Context, infoClient *asinfo.Client, dc, namespace string) error {
 	nodes := infoClient.GetNodesNames()
 
@@ -313,3 +569,19 @@ func errHumanize(err error) error {
 
 	return err
 }
+
+// newExportWriter wraps the writer to export records to NDJSON or Parquet, if another output format than asb is set.
+// The export writer is the outermost one, so the manifest lists the exported files.
+func newExportWriter(
+	cfg *config.BackupServiceConfig,
+	backupConfig *backup.ConfigBackup,
+	w backup.Writer,
+) backup.Writer {
+	if !cfg.Backup.IsExport() {
+		return w
+	}
+
+	// The state file is saved as is, so the export can be continued.
+	return export.NewWriter(w, cfg.Backup.OutputFormat,
+		path.Base(backupConfig.StateFile), path.Base(startTimeFile(backupConfig.StateFile)))
+}
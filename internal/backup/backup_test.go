@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/export"
 	"github.com/aerospike/absctl/internal/models"
 	"github.com/aerospike/absctl/internal/storage"
 	"github.com/aerospike/aerospike-client-go/v8"
@@ -254,6 +255,25 @@ func Test_RunNilService(t *testing.T) {
 	require.NoError(t, s.Run(t.Context()))
 }
 
+func Test_NewExportWriter(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writer := newTestManifestWriter(t, dir)
+	backupConfig := &backup.ConfigBackup{StateFile: path.Join(dir, testStateFile)}
+
+	cfg := &config.BackupServiceConfig{Backup: &models.Backup{OutputFormat: models.OutputFormatASB}}
+	require.Same(t, writer, newExportWriter(cfg, backupConfig, writer))
+
+	cfg.Backup.OutputFormat = models.OutputFormatParquet
+	exportWriter := newExportWriter(cfg, backupConfig, writer)
+	require.IsType(t, &export.Writer{}, exportWriter)
+
+	// The state file is written as is.
+	writeTestFile(t, exportWriter, testStateFile, []byte("state"))
+	require.FileExists(t, path.Join(dir, testStateFile))
+}
+
 func Test_ErrHumanize(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,89 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/backup-go"
+)
+
+// applyIncrementalFrom reads the manifest of the previous backup and sets its start time as modified-after.
+// Returns the chain link that must be saved to the new manifest, or nil if incremental-from is not set.
+func applyIncrementalFrom(
+	ctx context.Context,
+	cfg *config.BackupServiceConfig,
+	backupConfig *backup.ConfigBackup,
+	logger *slog.Logger,
+) (*models.ManifestIncremental, error) {
+	if cfg.Backup == nil || cfg.Backup.IncrementalFrom == "" {
+		return nil, nil
+	}
+
+	from := cfg.Backup.IncrementalFrom
+
+	reader, err := storage.NewReader(ctx, cfg.PrimaryStorage(), from, "", "", "", 0, false, true, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader for previous backup %s: %w", from, err)
+	}
+
+	parent, err := storage.ReadManifest(ctx, reader, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous backup %s: %w", from, err)
+	}
+
+	incremental, err := newIncremental(from, parent, backupConfig.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	backupConfig.ModAfter = &incremental.ParentStartTime
+
+	logger.Info("initialized incremental backup",
+		slog.String("incremental-from", from),
+		slog.Time("modified-after", incremental.ParentStartTime),
+		slog.Int("chain-length", len(incremental.Chain)),
+	)
+
+	return incremental, nil
+}
+
+// newIncremental builds the chain link of a backup made on top of the parent backup stored at path.
+func newIncremental(path string, parent *models.Manifest, namespace string) (*models.ManifestIncremental, error) {
+	if parent.Config.Namespace != namespace {
+		return nil, fmt.Errorf("previous backup %s is of namespace %s, not %s",
+			path, parent.Config.Namespace, namespace)
+	}
+
+	if parent.StartTime.IsZero() {
+		return nil, fmt.Errorf("previous backup %s has no start time", path)
+	}
+
+	var chain []string
+	if parent.Incremental != nil {
+		chain = slices.Clone(parent.Incremental.Chain)
+	}
+
+	return &models.ManifestIncremental{
+		Chain:           append(chain, path),
+		ParentStartTime: parent.StartTime,
+	}, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/backup-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIncremental(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		parent    *models.Manifest
+		namespace string
+		want      *models.ManifestIncremental
+		wantErr   string
+	}{
+		{
+			name: "parent is full backup",
+			parent: &models.Manifest{
+				StartTime: start,
+				Config:    models.ManifestConfig{Namespace: testNamespace},
+			},
+			namespace: testNamespace,
+			want: &models.ManifestIncremental{
+				Chain:           []string{"/backup/inc"},
+				ParentStartTime: start,
+			},
+		},
+		{
+			name: "parent is incremental backup",
+			parent: &models.Manifest{
+				StartTime: start,
+				Config:    models.ManifestConfig{Namespace: testNamespace},
+				Incremental: &models.ManifestIncremental{
+					Chain: []string{"/backup/full", "/backup/inc1"},
+				},
+			},
+			namespace: testNamespace,
+			want: &models.ManifestIncremental{
+				Chain:           []string{"/backup/full", "/backup/inc1", "/backup/inc"},
+				ParentStartTime: start,
+			},
+		},
+		{
+			name: "namespace mismatch",
+			parent: &models.Manifest{
+				StartTime: start,
+				Config:    models.ManifestConfig{Namespace: "other"},
+			},
+			namespace: testNamespace,
+			wantErr:   "previous backup /backup/inc is of namespace other, not test",
+		},
+		{
+			name: "no start time",
+			parent: &models.Manifest{
+				Config: models.ManifestConfig{Namespace: testNamespace},
+			},
+			namespace: testNamespace,
+			wantErr:   "previous backup /backup/inc has no start time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newIncremental("/backup/inc", tt.parent, tt.namespace)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApplyIncrementalFrom(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	content, err := json.Marshal(&models.Manifest{
+		FormatVersion: models.ManifestFormatVersion,
+		StartTime:     start,
+		Config:        models.ManifestConfig{Namespace: testNamespace},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, models.ManifestFileName), content, 0o600))
+
+	cfg := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			IncrementalFrom: dir,
+		},
+		ServiceConfigCommon: config.ServiceConfigCommon{
+			AwsS3:      &models.AwsS3{},
+			GcpStorage: &models.GcpStorage{},
+			AzureBlob:  &models.AzureBlob{},
+		},
+	}
+
+	backupConfig := &backup.ConfigBackup{Namespace: testNamespace}
+
+	incremental, err := applyIncrementalFrom(t.Context(), cfg, backupConfig, slog.Default())
+	require.NoError(t, err)
+	require.NotNil(t, backupConfig.ModAfter)
+	assert.Equal(t, start, *backupConfig.ModAfter)
+	assert.Equal(t, []string{dir}, incremental.Chain)
+	assert.Equal(t, dir, incremental.Base())
+}
@@ -0,0 +1,236 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/backup-go"
+)
+
+// metadataFilePrefix is the prefix of the file with secondary indexes and UDFs.
+// Its state is not saved, so the backup doesn't wait for it to be closed.
+const metadataFilePrefix = "metadata_"
+
+// stateWriter wraps backup.Writer and tracks the files being written and the saves of the backup state,
+// so a backup interrupted by a signal can be stopped once the state of its open files is saved.
+type stateWriter struct {
+	backup.Writer
+
+	// directory of the backup, names of its state file, start time file and config fingerprint file.
+	directory  string
+	stateFile  string
+	startFile  string
+	configFile string
+
+	mu sync.Mutex
+	// open are the data files being written.
+	open map[string]struct{}
+	// pending are the files that were open when the flush started and are not closed yet.
+	pending map[string]struct{}
+	// saves is the number of states saved since the flush started, and wanted the number to wait for.
+	saves  int
+	wanted int
+	// flushing is set when flush starts, and stopped when it returns.
+	flushing bool
+	stopped  bool
+	// unsaved are the files opened after the flush, their records are not in the saved state.
+	unsaved []string
+	// changed is closed and replaced when a file is closed or the state is saved.
+	changed chan struct{}
+}
+
+// newStateWriter returns a stateWriter for the backup that saves its state to stateFile.
+func newStateWriter(w backup.Writer, stateFile string) *stateWriter {
+	return &stateWriter{
+		Writer:     w,
+		directory:  path.Dir(stateFile),
+		stateFile:  path.Base(stateFile),
+		startFile:  path.Base(startTimeFile(stateFile)),
+		configFile: path.Base(configFile(stateFile)),
+		open:       make(map[string]struct{}),
+		changed:    make(chan struct{}),
+	}
+}
+
+// NewWriter opens a file on the wrapped writer and tracks when it is closed.
+func (w *stateWriter) NewWriter(ctx context.Context, filename string) (io.WriteCloser, error) {
+	wc, err := w.Writer.NewWriter(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	name := path.Base(filename)
+
+	switch {
+	case name == w.stateFile:
+		return &closeNotifier{WriteCloser: wc, onClose: w.stateSaved}, nil
+	case name == w.startFile || name == w.configFile || strings.HasPrefix(name, metadataFilePrefix):
+		return wc, nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.open[filename] = struct{}{}
+
+	if w.stopped {
+		w.unsaved = append(w.unsaved, filename)
+	}
+
+	return &closeNotifier{WriteCloser: wc, onClose: func() { w.fileClosed(filename) }}, nil
+}
+
+func (w *stateWriter) fileClosed(filename string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.open, filename)
+	delete(w.pending, filename)
+	w.notify()
+}
+
+func (w *stateWriter) stateSaved() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.flushing {
+		w.saves++
+	}
+
+	w.notify()
+}
+
+// notify wakes up flush. Must be called with the lock held.
+func (w *stateWriter) notify() {
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// flush waits for the files open at the time of the call to be closed and the state to be saved after them.
+// The files opened later are removed by removeUnsaved. Returns ctx.Err() if ctx is done first.
+func (w *stateWriter) flush(ctx context.Context) error {
+	w.mu.Lock()
+	w.flushing = true
+	w.pending = make(map[string]struct{}, len(w.open))
+
+	for filename := range w.open {
+		w.pending[filename] = struct{}{}
+	}
+
+	// The state is saved once for every file closed by the size limit.
+	w.wanted = len(w.pending)
+	w.mu.Unlock()
+
+	defer w.stop()
+
+	for {
+		w.mu.Lock()
+		done := len(w.pending) == 0 && w.saves >= w.wanted
+		changed := w.changed
+		w.mu.Unlock()
+
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// stop marks the files open now and opened later as unsaved.
+func (w *stateWriter) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopped = true
+
+	for filename := range w.open {
+		w.unsaved = append(w.unsaved, filename)
+	}
+}
+
+// removeUnsaved deletes the files written after the last save of the state. Their records are backed up again
+// when the backup is continued, and their content may be incomplete.
+func (w *stateWriter) removeUnsaved(ctx context.Context) error {
+	w.mu.Lock()
+	unsaved := w.unsaved
+	w.mu.Unlock()
+
+	for _, filename := range unsaved {
+		if err := w.Remove(ctx, path.Join(w.directory, filename)); err != nil {
+			return fmt.Errorf("failed to remove incomplete file %s: %w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// closeNotifier calls onClose after the file is closed.
+type closeNotifier struct {
+	io.WriteCloser
+
+	onClose func()
+}
+
+func (c *closeNotifier) Close() error {
+	if err := c.WriteCloser.Close(); err != nil {
+		return err
+	}
+
+	c.onClose()
+
+	return nil
+}
+
+// backupContext returns the context to run a backup that saves its state.
+// When ctx is cancelled by a signal, the backup context is cancelled only after the files being written
+// are closed and their state is saved, so the records already read are not lost.
+// The returned function must be called when the backup is finished.
+func (s *Service) backupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.state == nil {
+		return ctx, func() {}
+	}
+
+	backupCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+
+	stop := context.AfterFunc(ctx, func() {
+		if errors.Is(context.Cause(ctx), models.ErrInterrupted) {
+			s.logger.Info("waiting for the open files to be closed and the state to be saved")
+
+			if err := s.state.flush(backupCtx); err == nil {
+				s.logger.Info("backup state saved, stopping backup")
+			}
+		}
+
+		cancel(context.Cause(ctx))
+	})
+
+	return backupCtx, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStateWriter(t *testing.T, dir string) *stateWriter {
+	t.Helper()
+
+	params := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: dir,
+			},
+		},
+		ServiceConfigCommon: config.ServiceConfigCommon{
+			AwsS3:      &models.AwsS3{},
+			GcpStorage: &models.GcpStorage{},
+			AzureBlob:  &models.AzureBlob{},
+			Local:      &models.Local{},
+		},
+	}
+
+	writer, err := storage.NewBackupWriter(t.Context(), params, slog.Default())
+	require.NoError(t, err)
+
+	return newStateWriter(writer, filepath.Join(dir, models.ResumableStateFileName))
+}
+
+func openTestFile(t *testing.T, w *stateWriter, name string) io.WriteCloser {
+	t.Helper()
+
+	wc, err := w.NewWriter(t.Context(), name)
+	require.NoError(t, err)
+
+	_, err = wc.Write([]byte("data"))
+	require.NoError(t, err)
+
+	return wc
+}
+
+func TestStateWriter_Flush(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w := newTestStateWriter(t, dir)
+
+	first := openTestFile(t, w, "0_test.asb")
+	// The state of the metadata file is not saved, so flush doesn't wait for it.
+	metadata := openTestFile(t, w, "metadata_test.asb")
+
+	flushed := make(chan error, 1)
+
+	go func() {
+		flushed <- w.flush(t.Context())
+	}()
+
+	require.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		return w.flushing
+	}, time.Second, time.Millisecond)
+
+	// The file is closed, but its state is not saved yet.
+	require.NoError(t, first.Close())
+
+	second := openTestFile(t, w, "0_test(1).asb")
+
+	require.Never(t, func() bool { return len(flushed) > 0 }, 50*time.Millisecond, time.Millisecond)
+
+	require.NoError(t, openTestFile(t, w, models.ResumableStateFileName).Close())
+	require.NoError(t, <-flushed)
+
+	// Opened after the state was saved.
+	third := openTestFile(t, w, "1_test.asb")
+
+	require.NoError(t, second.Close())
+	require.NoError(t, third.Close())
+	require.NoError(t, metadata.Close())
+
+	require.NoError(t, w.removeUnsaved(t.Context()))
+
+	for _, name := range []string{"0_test.asb", "metadata_test.asb", models.ResumableStateFileName} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+
+	for _, name := range []string{"0_test(1).asb", "1_test.asb"} {
+		assert.NoFileExists(t, filepath.Join(dir, name))
+	}
+}
+
+func TestStateWriter_FlushCanceled(t *testing.T) {
+	t.Parallel()
+
+	w := newTestStateWriter(t, t.TempDir())
+	file := openTestFile(t, w, "0_test.asb")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.ErrorIs(t, w.flush(ctx), context.Canceled)
+	require.NoError(t, file.Close())
+}
@@ -0,0 +1,295 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerospike/absctl/internal/aeskey"
+	"github.com/aerospike/absctl/internal/codec"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// ManifestWriter wraps backup.Writer and records the size and SHA-256 checksum
+// of every file written through it, so they can be listed in the backup manifest.
+type ManifestWriter struct {
+	backup.Writer
+
+	mu    sync.Mutex
+	files map[string]models.ManifestFile
+	// key signs the manifest, nil if the backup is not encrypted.
+	key []byte
+}
+
+// NewManifestWriter returns a ManifestWriter that records the files written through w.
+func NewManifestWriter(w backup.Writer) *ManifestWriter {
+	return &ManifestWriter{
+		Writer: w,
+		files:  make(map[string]models.ManifestFile),
+	}
+}
+
+// SetSigningKey sets the encryption key of the backup that signs the saved manifest.
+func (w *ManifestWriter) SetSigningKey(key []byte) {
+	w.key = key
+}
+
+// NewWriter opens a file on the wrapped writer and hashes everything written to it.
+func (w *ManifestWriter) NewWriter(ctx context.Context, filename string) (io.WriteCloser, error) {
+	wc, err := w.Writer.NewWriter(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hashWriteCloser{
+		WriteCloser: wc,
+		hash:        sha256.New(),
+		onClose: func(size int64, checksum string) {
+			w.addFile(filename, size, checksum)
+		},
+	}, nil
+}
+
+func (w *ManifestWriter) addFile(name string, size int64, checksum string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// If the file is rewritten (e.g. state file), the last version wins.
+	w.files[name] = models.ManifestFile{
+		Name:   name,
+		Size:   size,
+		SHA256: checksum,
+	}
+}
+
+// Remove deletes a file through the wrapped writer, so it is not listed in the manifest either.
+func (w *ManifestWriter) Remove(ctx context.Context, filePath string) error {
+	if err := w.Writer.Remove(ctx, filePath); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for name := range w.files {
+		if path.Base(name) == path.Base(filePath) {
+			delete(w.files, name)
+		}
+	}
+
+	return nil
+}
+
+// Seed adds the files already in the directory of the reader, written by the previous runs of a continued backup.
+// The manifest and the files with skipped names are not added.
+func (w *ManifestWriter) Seed(ctx context.Context, reader backup.StreamingReader, skip ...string) error {
+	return codec.StreamFiles(ctx, reader, func(file bModels.File) error {
+		name := path.Base(file.Name)
+
+		h := sha256.New()
+		size, err := io.Copy(h, file.Reader)
+		_ = file.Reader.Close()
+
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", file.Name, err)
+		}
+
+		if name == models.ManifestFileName || slices.Contains(skip, name) {
+			return nil
+		}
+
+		w.addFile(name, size, hex.EncodeToString(h.Sum(nil)))
+
+		return nil
+	})
+}
+
+// Files returns the written files sorted by name.
+func (w *ManifestWriter) Files() []models.ManifestFile {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	files := make([]models.ManifestFile, 0, len(w.files))
+	for _, f := range w.files {
+		files = append(files, f)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	return files
+}
+
+// Save signs the manifest if a signing key is set, marshals it and writes it through the wrapped writer,
+// so the manifest itself is not listed in the manifest.
+func (w *ManifestWriter) Save(ctx context.Context, manifest *models.Manifest) error {
+	if w.key != nil {
+		if err := manifest.Sign(w.key); err != nil {
+			return fmt.Errorf("failed to sign manifest: %w", err)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	wc, err := w.Writer.NewWriter(ctx, models.ManifestFileName)
+	if err != nil {
+		return fmt.Errorf("failed to create manifest file: %w", err)
+	}
+
+	if _, err = wc.Write(data); err != nil {
+		_ = wc.Close()
+		return fmt.Errorf("failed to write manifest file: %w", err)
+	}
+
+	if err = wc.Close(); err != nil {
+		return fmt.Errorf("failed to close manifest file: %w", err)
+	}
+
+	return nil
+}
+
+// hashWriteCloser counts and hashes the bytes passed to the underlying writer.
+type hashWriteCloser struct {
+	io.WriteCloser
+
+	hash    hash.Hash
+	size    int64
+	onClose func(size int64, checksum string)
+}
+
+func (h *hashWriteCloser) Write(p []byte) (int, error) {
+	n, err := h.WriteCloser.Write(p)
+	// Hash only what was actually written.
+	h.hash.Write(p[:n])
+	h.size += int64(n)
+
+	return n, err
+}
+
+func (h *hashWriteCloser) Close() error {
+	if err := h.WriteCloser.Close(); err != nil {
+		return err
+	}
+
+	h.onClose(h.size, hex.EncodeToString(h.hash.Sum(nil)))
+
+	return nil
+}
+
+// newManifestWriter wraps the backup writer with a ManifestWriter that signs the manifest
+// with the encryption key of the backup, if it is encrypted.
+func newManifestWriter(ctx context.Context, w backup.Writer, cfg *backup.ConfigBackup) (*ManifestWriter, error) {
+	manifest := NewManifestWriter(w)
+
+	if cfg.EncryptionPolicy != nil {
+		key, err := aeskey.Read(ctx, cfg.EncryptionPolicy, cfg.SecretAgentConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest signing key: %w", err)
+		}
+
+		manifest.SetSigningKey(key)
+	}
+
+	return manifest, nil
+}
+
+// newManifest builds a manifest from the backup config, the stats of the run and the written files.
+func newManifest(
+	cfg *backup.ConfigBackup,
+	stats *bModels.BackupStats,
+	files []models.ManifestFile,
+	appVersion, commitHash string,
+) *models.Manifest {
+	return &models.Manifest{
+		FormatVersion: models.ManifestFormatVersion,
+		AbsctlVersion: appVersion,
+		Commit:        commitHash,
+		Created:       time.Now().UTC(),
+		StartTime:     stats.StartTime.UTC(),
+		Duration:      stats.GetDuration().String(),
+		Config:        newManifestConfig(cfg),
+		Stats: models.ManifestStats{
+			RecordsRead:  stats.GetReadRecords(),
+			SIndexes:     stats.GetSIndexes(),
+			UDFs:         stats.GetUDFs(),
+			BytesWritten: stats.GetBytesWritten(),
+			FilesWritten: stats.GetFileCount(),
+		},
+		Files: files,
+	}
+}
+
+func newManifestConfig(cfg *backup.ConfigBackup) models.ManifestConfig {
+	mc := models.ManifestConfig{
+		Namespace:      cfg.Namespace,
+		SetList:        cfg.SetList,
+		BinList:        cfg.BinList,
+		NodeList:       cfg.NodeList,
+		RackList:       cfg.RackList,
+		ModifiedAfter:  cfg.ModAfter,
+		ModifiedBefore: cfg.ModBefore,
+		NoRecords:      cfg.NoRecords,
+		NoIndexes:      cfg.NoIndexes,
+		NoUDFs:         cfg.NoUDFs,
+		Compression:    backup.CompressNone,
+		Encryption:     backup.EncryptNone,
+	}
+
+	if cfg.CompressionPolicy != nil {
+		mc.Compression = strings.ToUpper(cfg.CompressionPolicy.Mode)
+	}
+
+	if cfg.EncryptionPolicy != nil {
+		mc.Encryption = strings.ToUpper(cfg.EncryptionPolicy.Mode)
+	}
+
+	for _, pf := range cfg.PartitionFilters {
+		if pf == nil {
+			continue
+		}
+
+		f := models.ManifestPartitionFilter{
+			Begin: pf.Begin,
+			Count: pf.Count,
+		}
+
+		if len(pf.Digest) > 0 {
+			f.Digest = base64.StdEncoding.EncodeToString(pf.Digest)
+		}
+
+		mc.PartitionFilters = append(mc.PartitionFilters, f)
+	}
+
+	return mc
+}
@@ -0,0 +1,270 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManifestWriter(t *testing.T, dir string) *ManifestWriter {
+	t.Helper()
+
+	params := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: dir,
+			},
+		},
+		ServiceConfigCommon: config.ServiceConfigCommon{
+			AwsS3:      &models.AwsS3{},
+			GcpStorage: &models.GcpStorage{},
+			AzureBlob:  &models.AzureBlob{},
+			Local:      &models.Local{},
+		},
+	}
+
+	writer, err := storage.NewBackupWriter(t.Context(), params, slog.Default())
+	require.NoError(t, err)
+
+	return NewManifestWriter(writer)
+}
+
+func writeTestFile(t *testing.T, w backup.Writer, name string, data []byte) {
+	t.Helper()
+
+	wc, err := w.NewWriter(t.Context(), name)
+	require.NoError(t, err)
+
+	_, err = wc.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+}
+
+func TestManifestWriter_RecordsFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mw := newTestManifestWriter(t, dir)
+
+	writeTestFile(t, mw, "b.asb", []byte("second"))
+	writeTestFile(t, mw, "a.asb", []byte("first"))
+	// Rewritten file must keep only the last version.
+	writeTestFile(t, mw, "b.asb", []byte("second, rewritten"))
+
+	files := mw.Files()
+	require.Len(t, files, 2)
+
+	for i, want := range []struct {
+		name string
+		data []byte
+	}{
+		{"a.asb", []byte("first")},
+		{"b.asb", []byte("second, rewritten")},
+	} {
+		sum := sha256.Sum256(want.data)
+		assert.Equal(t, want.name, files[i].Name)
+		assert.Equal(t, int64(len(want.data)), files[i].Size)
+		assert.Equal(t, hex.EncodeToString(sum[:]), files[i].SHA256)
+	}
+}
+
+func TestManifestWriter_Remove(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mw := newTestManifestWriter(t, dir)
+
+	writeTestFile(t, mw, "a.asb", []byte("data"))
+	writeTestFile(t, mw, models.ResumableStateFileName, []byte("state"))
+
+	require.NoError(t, mw.Remove(t.Context(), filepath.Join(dir, models.ResumableStateFileName)))
+
+	files := mw.Files()
+	require.Len(t, files, 1)
+	assert.Equal(t, "a.asb", files[0].Name)
+	assert.NoFileExists(t, filepath.Join(dir, models.ResumableStateFileName))
+	assert.FileExists(t, filepath.Join(dir, "a.asb"))
+}
+
+func TestManifestWriter_Seed(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	// The writer is created first, as the backup directory must be empty.
+	mw := newTestManifestWriter(t, dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.asb"), []byte("first run"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.asb"), []byte("first run"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, models.ResumableStateFileName), []byte("state"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, models.ManifestFileName), []byte("{}"), 0o600))
+
+	reader, err := storage.NewReader(t.Context(), newTestResumableConfig(dir).PrimaryStorage(),
+		dir, "", "", "", 0, false, true, slog.Default())
+	require.NoError(t, err)
+
+	require.NoError(t, mw.Seed(t.Context(), reader, models.ResumableStateFileName))
+
+	// The file rewritten by the continued run keeps only the last version.
+	writeTestFile(t, mw, "b.asb", []byte("second run"))
+
+	files := mw.Files()
+	require.Len(t, files, 2)
+
+	for i, want := range []struct {
+		name string
+		data []byte
+	}{
+		{"a.asb", []byte("first run")},
+		{"b.asb", []byte("second run")},
+	} {
+		sum := sha256.Sum256(want.data)
+		assert.Equal(t, want.name, files[i].Name)
+		assert.Equal(t, hex.EncodeToString(sum[:]), files[i].SHA256)
+	}
+}
+
+func TestManifestWriter_Save(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mw := newTestManifestWriter(t, dir)
+
+	writeTestFile(t, mw, "a.asb", []byte("data"))
+
+	manifest := newManifest(
+		&backup.ConfigBackup{Namespace: "test"},
+		bModels.NewBackupStats(),
+		mw.Files(),
+		"v1.0.0",
+		"abc123",
+	)
+
+	require.NoError(t, mw.Save(t.Context(), manifest))
+	// The manifest itself must not be listed.
+	require.Len(t, mw.Files(), 1)
+
+	data, err := os.ReadFile(filepath.Join(dir, models.ManifestFileName))
+	require.NoError(t, err)
+
+	var result models.Manifest
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, models.ManifestFormatVersion, result.FormatVersion)
+	assert.Equal(t, "v1.0.0", result.AbsctlVersion)
+	assert.Equal(t, "abc123", result.Commit)
+	assert.Equal(t, "test", result.Config.Namespace)
+	assert.Equal(t, mw.Files(), result.Files)
+}
+
+func TestManifestWriter_SaveSigned(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mw := newTestManifestWriter(t, dir)
+	key := []byte("0123456789abcdef")
+	mw.SetSigningKey(key)
+
+	writeTestFile(t, mw, "a.asb", []byte("data"))
+
+	manifest := newManifest(&backup.ConfigBackup{Namespace: "test"}, bModels.NewBackupStats(), mw.Files(), "", "")
+	require.NoError(t, mw.Save(t.Context(), manifest))
+
+	data, err := os.ReadFile(filepath.Join(dir, models.ManifestFileName))
+	require.NoError(t, err)
+
+	var result models.Manifest
+	require.NoError(t, json.Unmarshal(data, &result))
+	require.NoError(t, result.CheckSignature(key))
+}
+
+type failingWriteCloser struct {
+	written []byte
+}
+
+func (f *failingWriteCloser) Write(p []byte) (int, error) {
+	// Accept only the first byte to emulate a short write.
+	f.written = append(f.written, p[:1]...)
+	return 1, io.ErrShortWrite
+}
+
+func (f *failingWriteCloser) Close() error {
+	return nil
+}
+
+func TestHashWriteCloser_HashesWrittenBytesOnly(t *testing.T) {
+	t.Parallel()
+
+	var (
+		size     int64
+		checksum string
+	)
+
+	h := &hashWriteCloser{
+		WriteCloser: &failingWriteCloser{},
+		hash:        sha256.New(),
+		onClose: func(s int64, c string) {
+			size = s
+			checksum = c
+		},
+	}
+
+	n, err := h.Write([]byte("abc"))
+	require.ErrorIs(t, err, io.ErrShortWrite)
+	require.Equal(t, 1, n)
+	require.NoError(t, h.Close())
+
+	sum := sha256.Sum256([]byte("a"))
+	assert.Equal(t, int64(1), size)
+	assert.Equal(t, hex.EncodeToString(sum[:]), checksum)
+}
+
+func TestNewManifestConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &backup.ConfigBackup{
+		Namespace: "test",
+		SetList:   []string{"set1"},
+		PartitionFilters: []*aerospike.PartitionFilter{
+			{Begin: 0, Count: 2048},
+			{Begin: 10, Count: 1, Digest: []byte{1, 2, 3}},
+		},
+		CompressionPolicy: &backup.CompressionPolicy{Mode: "zstd"},
+	}
+
+	mc := newManifestConfig(cfg)
+	assert.Equal(t, "test", mc.Namespace)
+	assert.Equal(t, []string{"set1"}, mc.SetList)
+	assert.Equal(t, "ZSTD", mc.Compression)
+	assert.Equal(t, backup.EncryptNone, mc.Encryption)
+	assert.Equal(t, []models.ManifestPartitionFilter{
+		{Begin: 0, Count: 2048},
+		{Begin: 10, Count: 1, Digest: "AQID"},
+	}, mc.PartitionFilters)
+}
@@ -0,0 +1,285 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/logging"
+	"github.com/aerospike/absctl/internal/metrics"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// infoNamespaces is the info command that returns the list of namespaces.
+const infoNamespaces = "namespaces"
+
+// namespaceBackup contains the config and the writer to back up one namespace
+// into its own subdirectory.
+type namespaceBackup struct {
+	namespace   string
+	config      *backup.ConfigBackup
+	writer      backup.Writer
+	fanOut      *storage.FanOutWriter
+	manifest    *ManifestWriter
+	incremental *models.ManifestIncremental
+}
+
+// newMultiNamespaceService initializes a Service that backs up several namespaces one by one,
+// sharing one aerospike client and one backup client.
+func newMultiNamespaceService(
+	ctx context.Context,
+	cfg *config.BackupServiceConfig,
+	logger *slog.Logger,
+) (*Service, error) {
+	var racks []int
+
+	if cfg.Backup.RackList != "" {
+		list, err := cfg.Backup.Racks()
+		if err != nil {
+			return nil, err
+		}
+
+		racks = list
+	}
+
+	aerospikeClient, err := storage.NewAerospikeClient(
+		cfg.ClientConfig,
+		cfg.ClientPolicy,
+		racks,
+		0,
+		logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aerospike client: %w", err)
+	}
+
+	infoPolicy, retryInfoPolicy := getInfoPolicies(cfg)
+
+	namespaces := cfg.Backup.Namespaces()
+	if cfg.Backup.IsAllNamespaces() {
+		namespaces, err = discoverNamespaces(aerospikeClient, infoPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover namespaces: %w", err)
+		}
+
+		logger.Info("discovered namespaces", slog.Any("namespaces", namespaces))
+	}
+
+	nsBackups := make([]*namespaceBackup, 0, len(namespaces))
+
+	var fanOut []*storage.FanOutWriter
+
+	for _, namespace := range namespaces {
+		nb, err := newNamespaceBackup(ctx, cfg, namespace, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize backup of namespace %s: %w", namespace, err)
+		}
+
+		nsBackups = append(nsBackups, nb)
+
+		if nb.fanOut != nil {
+			fanOut = append(fanOut, nb.fanOut)
+		}
+	}
+
+	logger.Info("initializing backup client")
+
+	backupClient, err := backup.NewClient(
+		aerospikeClient,
+		backup.WithLogger(logger),
+		backup.WithInfoPolicies(infoPolicy, retryInfoPolicy),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup client: %w", err)
+	}
+
+	return &Service{
+		backupClient:     backupClient,
+		namespaces:       nsBackups,
+		fanOut:           fanOut,
+		estimatesSamples: cfg.Backup.EstimateSamples,
+		logger:           logger,
+		reportToLog:      cfg.App.LogJSON || cfg.App.LogFile != "",
+		progressInterval: time.Duration(cfg.Backup.ProgressInterval) * time.Second,
+		progressEstimate: !cfg.Backup.HasFilter() && !cfg.Backup.NoRecords,
+		metrics:          metrics.NewExporter(operationName, cfg.App, logger),
+	}, nil
+}
+
+// newNamespaceBackup maps the service config to the backup config of a single namespace
+// and initializes a writer for the namespace subdirectory.
+func newNamespaceBackup(
+	ctx context.Context,
+	cfg *config.BackupServiceConfig,
+	namespace string,
+	logger *slog.Logger,
+) (*namespaceBackup, error) {
+	nsCfg := namespaceConfig(cfg, namespace)
+
+	backupConfig, _, err := config.NewBackupConfigs(nsCfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	incremental, err := applyIncrementalFrom(ctx, nsCfg, backupConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := storage.NewBackupWriter(ctx, nsCfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := newManifestWriter(ctx, writer, backupConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	fanOut, _ := writer.(*storage.FanOutWriter)
+
+	return &namespaceBackup{
+		namespace:   namespace,
+		config:      backupConfig,
+		writer:      newExportWriter(nsCfg, backupConfig, manifest),
+		fanOut:      fanOut,
+		manifest:    manifest,
+		incremental: incremental,
+	}, nil
+}
+
+// namespaceConfig returns a copy of the service config that backs up only the namespace
+// into its own subdirectory.
+func namespaceConfig(cfg *config.BackupServiceConfig, namespace string) *config.BackupServiceConfig {
+	nsBackup := *cfg.Backup
+	nsBackup.Namespace = namespace
+	nsBackup.Directory = path.Join(cfg.Backup.Directory, namespace)
+
+	if cfg.Backup.IncrementalFrom != "" {
+		nsBackup.IncrementalFrom = path.Join(cfg.Backup.IncrementalFrom, namespace)
+	}
+
+	nsCfg := *cfg
+	nsCfg.Backup = &nsBackup
+
+	return &nsCfg
+}
+
+// discoverNamespaces returns the sorted list of namespaces configured on the cluster nodes.
+func discoverNamespaces(client *aerospike.Client, policy *aerospike.InfoPolicy) ([]string, error) {
+	nodes := client.GetNodes()
+	if len(nodes) == 0 {
+		return nil, errors.New("no nodes available")
+	}
+
+	var namespaces []string
+
+	for _, node := range nodes {
+		info, err := node.RequestInfo(policy, infoNamespaces)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespaces from node %s: %w", node.GetName(), err)
+		}
+
+		namespaces = append(namespaces, parseNamespaces(info[infoNamespaces])...)
+	}
+
+	slices.Sort(namespaces)
+
+	namespaces = slices.Compact(namespaces)
+	if len(namespaces) == 0 {
+		return nil, errors.New("no namespaces found")
+	}
+
+	return namespaces, nil
+}
+
+// parseNamespaces parses the response of the namespaces info command, e.g. "test;bar".
+func parseNamespaces(resp string) []string {
+	result := make([]string, 0)
+
+	for ns := range strings.SplitSeq(resp, ";") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			result = append(result, ns)
+		}
+	}
+
+	return result
+}
+
+// runNamespaces backs up the namespaces one by one and prints a report with the total stats.
+func (s *Service) runNamespaces(ctx context.Context) error {
+	var total *bModels.BackupStats
+
+	for _, nb := range s.namespaces {
+		progressTotal := s.estimateProgressTotal(ctx, nb.config)
+
+		s.logger.Info("starting scan backup", slog.String("namespace", nb.namespace))
+
+		h, err := s.backupClient.Backup(ctx, nb.config, nb.writer, nil)
+		if err != nil {
+			return fmt.Errorf("failed to start backup of namespace %s: %w", nb.namespace, errHumanize(err))
+		}
+
+		s.trackStats(nb.namespace, metrics.TypeScan, h.GetStats())
+
+		stopProgress := s.startProgress(ctx, h.GetStats(), progressTotal)
+		err = h.Wait(ctx)
+
+		stopProgress()
+
+		if err != nil {
+			return fmt.Errorf("failed to backup namespace %s: %w", nb.namespace, err)
+		}
+
+		stats := h.GetStats()
+
+		s.logger.Info("namespace backup completed",
+			slog.String("namespace", nb.namespace),
+			slog.Duration("duration", stats.GetDuration()),
+			slog.Uint64("records-read", stats.GetReadRecords()),
+			slog.Uint64("bytes-written", stats.GetBytesWritten()),
+			slog.Uint64("files-written", stats.GetFileCount()),
+		)
+
+		if err = s.saveManifest(ctx, nb.config, nb.manifest, nb.incremental, stats); err != nil {
+			return fmt.Errorf("failed to save backup manifest of namespace %s: %w", nb.namespace, err)
+		}
+
+		if total == nil {
+			total = stats
+			continue
+		}
+
+		total = bModels.SumBackupStats(total, stats)
+	}
+
+	if total != nil {
+		logging.ReportBackup(total, false, s.reportToLog, s.logger)
+		logging.ReportDestinations(s.Destinations(), s.reportToLog, s.logger)
+	}
+
+	return nil
+}
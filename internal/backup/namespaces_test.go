@@ -0,0 +1,97 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNamespaces(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		resp string
+		want []string
+	}{
+		{
+			name: "several namespaces",
+			resp: "test;bar",
+			want: []string{"test", "bar"},
+		},
+		{
+			name: "trailing separator",
+			resp: "test;",
+			want: []string{"test"},
+		},
+		{
+			name: "empty response",
+			resp: "",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, parseNamespaces(tt.resp))
+		})
+	}
+}
+
+func TestNewNamespaceBackup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	cfg := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: dir,
+				Namespace: "test,bar",
+				Parallel:  1,
+			},
+		},
+		ServiceConfigCommon: config.ServiceConfigCommon{
+			App:        &models.App{},
+			AwsS3:      &models.AwsS3{},
+			GcpStorage: &models.GcpStorage{},
+			AzureBlob:  &models.AzureBlob{},
+			Local:      &models.Local{},
+		},
+	}
+
+	nb, err := newNamespaceBackup(t.Context(), cfg, "bar", slog.Default())
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", nb.namespace)
+	assert.Equal(t, "bar", nb.config.Namespace)
+	assert.NotNil(t, nb.manifest)
+	assert.Equal(t, nb.manifest, nb.writer)
+
+	// The original config must stay untouched.
+	assert.Equal(t, "test,bar", cfg.Backup.Namespace)
+	assert.Equal(t, dir, cfg.Backup.Directory)
+
+	writeTestFile(t, nb.writer, "bar_0.asb", []byte("data"))
+	assert.FileExists(t, filepath.Join(dir, "bar", "bar_0.asb"))
+}
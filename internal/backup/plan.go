@@ -0,0 +1,337 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/report"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+)
+
+// newDryRunService initializes a Service that only prints the backup plan.
+// Writers are not created, so nothing is written or removed.
+func newDryRunService(
+	ctx context.Context,
+	cfg *config.BackupServiceConfig,
+	logger *slog.Logger,
+) (*Service, error) {
+	plan, err := newBackupPlan(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		plan:        plan,
+		logger:      logger,
+		reportToLog: cfg.App.LogJSON || cfg.App.LogFile != "",
+	}, nil
+}
+
+// newBackupPlan resolves the backup config of every namespace and checks it against the live cluster.
+func newBackupPlan(
+	ctx context.Context,
+	cfg *config.BackupServiceConfig,
+	logger *slog.Logger,
+) (*models.BackupPlan, error) {
+	var racks []int
+
+	if cfg.Backup.RackList != "" {
+		list, err := cfg.Backup.Racks()
+		if err != nil {
+			return nil, err
+		}
+
+		racks = list
+	}
+
+	aerospikeClient, err := storage.NewAerospikeClient(
+		cfg.ClientConfig,
+		cfg.ClientPolicy,
+		racks,
+		0,
+		logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aerospike client: %w", err)
+	}
+	defer aerospikeClient.Close()
+
+	infoPolicy, _ := getInfoPolicies(cfg)
+
+	clusterNamespaces, err := discoverNamespaces(aerospikeClient, infoPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover namespaces: %w", err)
+	}
+
+	namespaces := cfg.Backup.Namespaces()
+	if cfg.Backup.IsAllNamespaces() {
+		namespaces = clusterNamespaces
+	}
+
+	target := cfg.Backup.Directory
+	if cfg.Backup.OutputFile != "" {
+		target = cfg.Backup.OutputFile
+	}
+
+	plan := &models.BackupPlan{
+		Storage:    report.NewStorage(cfg.PrimaryStorage(), target),
+		Namespaces: make([]models.NamespacePlan, 0, len(namespaces)),
+	}
+
+	for _, namespace := range namespaces {
+		nsCfg := cfg
+		if cfg.Backup.IsMultiNamespace() {
+			nsCfg = namespaceConfig(cfg, namespace)
+		}
+
+		nsPlan, err := newNamespacePlan(ctx, nsCfg, aerospikeClient, infoPolicy, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan backup of namespace %s: %w", namespace, err)
+		}
+
+		if !slices.Contains(clusterNamespaces, namespace) {
+			nsPlan.Problems = append(nsPlan.Problems,
+				fmt.Sprintf("namespace %s is not configured on the cluster", namespace))
+		}
+
+		plan.Namespaces = append(plan.Namespaces, *nsPlan)
+	}
+
+	return plan, nil
+}
+
+// newNamespacePlan resolves the backup config of a single namespace the same way as the backup does.
+func newNamespacePlan(
+	ctx context.Context,
+	cfg *config.BackupServiceConfig,
+	client *aerospike.Client,
+	infoPolicy *aerospike.InfoPolicy,
+	logger *slog.Logger,
+) (*models.NamespacePlan, error) {
+	backupConfig, _, err := config.NewBackupConfigs(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	incremental, err := applyIncrementalFrom(ctx, cfg, backupConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.NamespacePlan{
+		Config:          newManifestConfig(backupConfig),
+		Incremental:     incremental,
+		Directory:       cfg.Backup.Directory,
+		OutputFile:      cfg.Backup.OutputFile,
+		FilePrefix:      backupConfig.OutputFilePrefix,
+		FileLimit:       backupConfig.FileLimit,
+		ParallelRead:    backupConfig.ParallelRead,
+		ParallelWrite:   backupConfig.ParallelWrite,
+		StateFile:       backupConfig.StateFile,
+		RemoveFiles:     cfg.Backup.ShouldClearTarget(),
+		RemoveArtifacts: cfg.Backup.RemoveArtifacts,
+	}
+
+	var problems []string
+
+	plan.Nodes, problems = resolveNodes(client, backupConfig)
+	plan.Problems = append(plan.Problems, problems...)
+
+	problems, err = checkSets(client, infoPolicy, backupConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Problems = append(plan.Problems, problems...)
+
+	// Only a directory backup can be blocked by existing files, a single file is overwritten.
+	if cfg.Backup.Directory == "" || cfg.IsStdout() {
+		return plan, nil
+	}
+
+	plan.ExistingFiles, err = listExistingFiles(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plan.ExistingFiles) > 0 && !plan.RemoveFiles && cfg.Backup.Continue == "" {
+		plan.Problems = append(plan.Problems,
+			fmt.Sprintf("directory %s is not empty, use --remove-files to replace the existing backup",
+				cfg.Backup.Directory))
+	}
+
+	return plan, nil
+}
+
+// resolveNodes returns the cluster nodes that records would be read from,
+// and the problems for nodes and racks that are not found in the cluster.
+func resolveNodes(client *aerospike.Client, cfg *backup.ConfigBackup) ([]models.PlanNode, []string) {
+	var (
+		nodes    []models.PlanNode
+		problems []string
+	)
+
+	clusterNodes := client.GetNodes()
+
+	switch {
+	case len(cfg.NodeList) > 0:
+		for _, name := range cfg.NodeList {
+			node := findNode(clusterNodes, name)
+			if node == nil {
+				problems = append(problems, fmt.Sprintf("node %s is not in the cluster", name))
+				continue
+			}
+
+			nodes = append(nodes, newPlanNode(node, cfg.Namespace))
+		}
+	case len(cfg.RackList) > 0:
+		for _, rack := range cfg.RackList {
+			found := false
+
+			for _, node := range clusterNodes {
+				if nodeRack, err := node.Rack(cfg.Namespace); err == nil && nodeRack == rack {
+					nodes = append(nodes, newPlanNode(node, cfg.Namespace))
+					found = true
+				}
+			}
+
+			if !found {
+				problems = append(problems, fmt.Sprintf("rack %d has no nodes for namespace %s", rack, cfg.Namespace))
+			}
+		}
+	default:
+		for _, node := range clusterNodes {
+			nodes = append(nodes, newPlanNode(node, cfg.Namespace))
+		}
+	}
+
+	return nodes, problems
+}
+
+// findNode returns the node with the given name or address, nil if it is not in the cluster.
+func findNode(nodes []*aerospike.Node, nameOrAddress string) *aerospike.Node {
+	for _, node := range nodes {
+		if node.GetName() == nameOrAddress || node.GetHost().String() == nameOrAddress {
+			return node
+		}
+	}
+
+	return nil
+}
+
+func newPlanNode(node *aerospike.Node, namespace string) models.PlanNode {
+	n := models.PlanNode{
+		Name:    node.GetName(),
+		Address: node.GetHost().String(),
+	}
+
+	// Racks are known only if the client is rack aware, i.e. --rack-list is set.
+	if rack, err := node.Rack(namespace); err == nil {
+		n.Rack = &rack
+	}
+
+	return n
+}
+
+// checkSets returns the problems for the sets that are not found in the namespace on any node.
+func checkSets(
+	client *aerospike.Client,
+	policy *aerospike.InfoPolicy,
+	cfg *backup.ConfigBackup,
+) ([]string, error) {
+	if len(cfg.SetList) == 0 {
+		return nil, nil
+	}
+
+	command := "sets/" + cfg.Namespace
+
+	var sets []string
+
+	for _, node := range client.GetNodes() {
+		info, err := node.RequestInfo(policy, command)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sets from node %s: %w", node.GetName(), err)
+		}
+
+		sets = append(sets, parseSets(info[command])...)
+	}
+
+	var problems []string
+
+	for _, set := range cfg.SetList {
+		if !slices.Contains(sets, set) {
+			problems = append(problems, fmt.Sprintf("set %s is not found in namespace %s", set, cfg.Namespace))
+		}
+	}
+
+	return problems, nil
+}
+
+// parseSets parses the response of the sets info command,
+// e.g. "ns=test:set=demo:objects=10;ns=test:set=users:objects=5;".
+func parseSets(resp string) []string {
+	result := make([]string, 0)
+
+	for entry := range strings.SplitSeq(resp, ";") {
+		for field := range strings.SplitSeq(entry, ":") {
+			if set, ok := strings.CutPrefix(field, "set="); ok && set != "" {
+				result = append(result, set)
+			}
+		}
+	}
+
+	return result
+}
+
+// listExistingFiles returns the files in the backup directory, or nil if it doesn't exist.
+func listExistingFiles(
+	ctx context.Context,
+	cfg *config.BackupServiceConfig,
+	logger *slog.Logger,
+) ([]string, error) {
+	directory := cfg.Backup.Directory
+
+	reader, err := storage.NewReader(ctx, cfg.PrimaryStorage(), directory, "", "", "", 0, false, true, logger)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to create reader for %s: %w", directory, err)
+	}
+
+	files, err := reader.ListObjects(ctx, directory)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to list files in %s: %w", directory, err)
+	}
+
+	slices.Sort(files)
+
+	return files, nil
+}
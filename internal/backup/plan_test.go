@@ -0,0 +1,76 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"testing"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		resp string
+		want []string
+	}{
+		{
+			name: "several sets",
+			resp: "ns=test:set=demo:objects=10:tombstones=0;ns=test:set=users:objects=5:tombstones=0;",
+			want: []string{"demo", "users"},
+		},
+		{
+			name: "empty response",
+			resp: "",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, parseSets(tt.resp))
+		})
+	}
+}
+
+func TestNamespaceConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Namespace: "test,bar",
+				Directory: "backups",
+			},
+			IncrementalFrom: "previous",
+			DryRun:          true,
+		},
+	}
+
+	nsCfg := namespaceConfig(cfg, "bar")
+
+	assert.Equal(t, "bar", nsCfg.Backup.Namespace)
+	assert.Equal(t, "backups/bar", nsCfg.Backup.Directory)
+	assert.Equal(t, "previous/bar", nsCfg.Backup.IncrementalFrom)
+	assert.True(t, nsCfg.IsDryRun())
+	// The original config must not be changed.
+	assert.Equal(t, "test,bar", cfg.Backup.Namespace)
+	assert.Equal(t, "backups", cfg.Backup.Directory)
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aerospike/absctl/internal/logging"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// estimateProgressTotal returns the estimated backup size used to calculate the percent done and ETA.
+// It returns 0 if progress is not reported or the size can't be estimated.
+func (s *Service) estimateProgressTotal(ctx context.Context, cfg *backup.ConfigBackup) uint64 {
+	// The estimate doesn't take filters into account, so the percent would be wrong.
+	if s.progressInterval <= 0 || !s.progressEstimate {
+		return 0
+	}
+
+	total, err := s.backupClient.Estimate(ctx, cfg, s.estimatesSamples)
+	if err != nil {
+		s.logger.Warn("failed to estimate backup size, progress is reported without percent done",
+			slog.String("namespace", cfg.Namespace),
+			slog.Any("error", err),
+		)
+
+		return 0
+	}
+
+	return total
+}
+
+// startProgress reports the backup progress until the returned function is called.
+func (s *Service) startProgress(ctx context.Context, stats *bModels.BackupStats, total uint64) func() {
+	return logging.NewProgress(operationName, s.progressInterval, func() (uint64, uint64, uint64) {
+		return stats.GetReadRecords(), stats.GetBytesWritten(), total
+	}, s.reportToLog, s.logger).Start(ctx)
+}
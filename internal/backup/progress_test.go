@@ -0,0 +1,36 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateProgressTotal_Skipped(t *testing.T) {
+	t.Parallel()
+
+	cfg := &backup.ConfigBackup{Namespace: testNamespace}
+
+	// Without a backup client, the estimate would panic if it was called.
+	disabled := &Service{progressEstimate: true}
+	assert.Zero(t, disabled.estimateProgressTotal(t.Context(), cfg))
+
+	filtered := &Service{progressInterval: time.Second}
+	assert.Zero(t, filtered.estimateProgressTotal(t.Context(), cfg))
+}
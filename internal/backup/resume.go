@@ -0,0 +1,415 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/backup-go"
+)
+
+// startTimeFileExt is the extension of the file kept next to the state file with the start time of the backup,
+// so the manifest of a continued backup has the start time of its first run.
+const startTimeFileExt = ".start"
+
+// configFileExt is the extension of the file kept next to the start time file with the fingerprint
+// of the backup options, so a backup is not continued with options that select or encode other data.
+const configFileExt = ".config"
+
+// startTimeFile returns the path of the start time file of the state file.
+func startTimeFile(stateFile string) string {
+	return stateFile + startTimeFileExt
+}
+
+// configFile returns the path of the config fingerprint file of the state file.
+func configFile(stateFile string) string {
+	return stateFile + configFileExt
+}
+
+// backupFingerprint returns the hash of the options that select and encode the backed up data.
+// Options that only change how fast the backup runs are not part of it.
+func backupFingerprint(cfg *config.BackupServiceConfig) (string, error) {
+	b := cfg.Backup
+
+	options := map[string]any{
+		"namespace":          b.Namespace,
+		"set-list":           b.SetList,
+		"bin-list":           b.BinList,
+		"no-records":         b.NoRecords,
+		"no-indexes":         b.NoIndexes,
+		"no-udfs":            b.NoUDFs,
+		"no-bins":            b.NoBins,
+		"no-ttl-only":        b.NoTTLOnly,
+		"modified-before":    b.ModifiedBefore,
+		"modified-after":     b.ModifiedAfter,
+		"after-digest":       b.AfterDigest,
+		"partition-list":     b.PartitionList,
+		"node-list":          b.NodeList,
+		"rack-list":          b.RackList,
+		"filter-exp":         b.FilterExpression,
+		"max-records":        b.MaxRecords,
+		"compact":            b.Compact,
+		"output-format":      b.OutputFormat,
+		"output-file-prefix": b.OutputFilePrefix,
+		"file-limit":         b.FileLimit,
+	}
+
+	if cfg.Compression != nil {
+		options["compress"] = cfg.Compression.Mode
+		options["compression-level"] = cfg.Compression.Level
+	}
+
+	if cfg.Encryption != nil {
+		options["encrypt"] = cfg.Encryption.Mode
+	}
+
+	// Map keys are marshaled in sorted order, so the same options always have the same fingerprint.
+	content, err := json.Marshal(options)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal backup options: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// checkFingerprint refuses to continue a backup whose options differ from the options of its first run,
+// as the files of both runs would hold different data. Backups started without a fingerprint file are continued.
+func checkFingerprint(
+	ctx context.Context,
+	cfg *config.BackupServiceConfig,
+	backupConfig *backup.ConfigBackup,
+	logger *slog.Logger,
+) error {
+	name := path.Base(configFile(backupConfig.StateFile))
+
+	found, err := hasFile(ctx, cfg, name, logger)
+	if err != nil {
+		return fmt.Errorf("failed to check for the config fingerprint file: %w", err)
+	}
+
+	if !found {
+		logger.Warn("options of the continued backup not found, they are not checked",
+			slog.String("file", name),
+		)
+
+		return nil
+	}
+
+	directory := cfg.Backup.Directory
+
+	reader, err := storage.NewReader(ctx, cfg.PrimaryStorage(), directory, "", "", "", 0, false, true, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create reader for %s: %w", directory, err)
+	}
+
+	saved, err := readSmallFile(ctx, reader, configFile(backupConfig.StateFile))
+	if err != nil {
+		return err
+	}
+
+	fingerprint, err := backupFingerprint(cfg)
+	if err != nil {
+		return err
+	}
+
+	if saved != fingerprint {
+		return fmt.Errorf("backup options differ from the options of the backup being continued, "+
+			"run it with the same options or remove %s to start a new backup", backupConfig.StateFile)
+	}
+
+	return nil
+}
+
+// applyResumable sets the state file options of a resumable backup.
+// If the backup directory contains the state file of an incomplete backup, the backup is continued from it,
+// otherwise a new backup is started that saves its state to the file.
+func applyResumable(ctx context.Context, cfg *config.BackupServiceConfig, logger *slog.Logger) error {
+	if !cfg.IsResumable() {
+		return nil
+	}
+
+	found, err := hasFile(ctx, cfg, models.ResumableStateFileName, logger)
+	if err != nil {
+		return fmt.Errorf("failed to check for an incomplete backup: %w", err)
+	}
+
+	stateFile := path.Join(cfg.Backup.Directory, models.ResumableStateFileName)
+
+	if !found {
+		logger.Info("starting resumable backup", slog.String("state-file", stateFile))
+
+		cfg.Backup.StateFileDst = models.ResumableStateFileName
+
+		return nil
+	}
+
+	logger.Info("found incomplete backup, continuing", slog.String("state-file", stateFile))
+
+	// Continue also keeps the files of the incomplete backup, even with remove-files.
+	cfg.Backup.Continue = models.ResumableStateFileName
+
+	return nil
+}
+
+// hasFile checks if the backup directory contains the file.
+func hasFile(ctx context.Context, cfg *config.BackupServiceConfig, name string, logger *slog.Logger) (bool, error) {
+	files, err := listExistingFiles(ctx, cfg, logger)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(files, func(file string) bool {
+		return path.Base(file) == name
+	}), nil
+}
+
+// continueManifest adds the files written by the previous runs of a continued backup to the manifest,
+// so they are listed with the files of this run. Returns the start time of the first run,
+// or zero if it was not saved.
+func continueManifest(
+	ctx context.Context,
+	cfg *config.BackupServiceConfig,
+	backupConfig *backup.ConfigBackup,
+	manifest *ManifestWriter,
+	logger *slog.Logger,
+) (time.Time, error) {
+	directory := cfg.Backup.Directory
+
+	// Skip the file checks, so all files of the directory are streamed.
+	reader, err := storage.NewReader(ctx, cfg.PrimaryStorage(), directory, "", "", "", 0, false, true, logger)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create reader for %s: %w", directory, err)
+	}
+
+	stateFile := path.Base(backupConfig.StateFile)
+	startFile := path.Base(startTimeFile(backupConfig.StateFile))
+	fingerprintFile := path.Base(configFile(backupConfig.StateFile))
+
+	if err = manifest.Seed(ctx, reader, stateFile, startFile, fingerprintFile); err != nil {
+		return time.Time{}, fmt.Errorf("failed to list the files of the continued backup: %w", err)
+	}
+
+	found, err := hasFile(ctx, cfg, startFile, logger)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to check for the start time file: %w", err)
+	}
+
+	if !found {
+		logger.Warn("start time of the continued backup not found, the manifest has the start time of this run",
+			slog.String("file", startFile),
+		)
+
+		return time.Time{}, nil
+	}
+
+	content, err := readSmallFile(ctx, reader, startTimeFile(backupConfig.StateFile))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	startTime, err := time.Parse(time.RFC3339Nano, content)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse start time file %s: %w", startFile, err)
+	}
+
+	return startTime, nil
+}
+
+// readSmallFile reads a file kept next to the state file, without surrounding white space.
+func readSmallFile(ctx context.Context, reader backup.StreamingReader, object string) (string, error) {
+	file, err := storage.OpenFile(ctx, reader, object)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file %s: %w", object, err)
+	}
+	defer file.Reader.Close()
+
+	content, err := io.ReadAll(file.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", object, err)
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
+// saveStartTime writes the start time file and the config fingerprint file of a new backup that saves its state.
+// A continued backup keeps the files of its first run.
+func (s *Service) saveStartTime(ctx context.Context, startTime time.Time) error {
+	if s.config.StateFile == "" || s.config.Continue {
+		return nil
+	}
+
+	if err := s.writeSmallFile(ctx, startTimeFile(s.config.StateFile),
+		startTime.UTC().Format(time.RFC3339Nano)); err != nil {
+		return err
+	}
+
+	fingerprint, err := backupFingerprint(s.serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	return s.writeSmallFile(ctx, configFile(s.config.StateFile), fingerprint)
+}
+
+// writeSmallFile writes a file next to the state file.
+func (s *Service) writeSmallFile(ctx context.Context, object, content string) error {
+	name := path.Base(object)
+
+	wc, err := s.writer.NewWriter(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", name, err)
+	}
+
+	if _, err = io.WriteString(wc, content); err != nil {
+		_ = wc.Close()
+		return fmt.Errorf("failed to write file %s: %w", name, err)
+	}
+
+	if err = wc.Close(); err != nil {
+		return fmt.Errorf("failed to close file %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// applyCheckpoint saves the state of a scan backup to its directory by default, so the backup can be
+// continued after it is interrupted by a signal. Backups that can't save their state are logged.
+// Returns true if the state file must be removed after the backup succeeded.
+func applyCheckpoint(cfg *config.BackupServiceConfig, logger *slog.Logger) bool {
+	if cfg.Backup == nil || cfg.Backup.DryRun || cfg.Backup.Estimate {
+		return false
+	}
+
+	// A continued checkpoint keeps saving its state to the same file.
+	if cfg.Backup.Continue == models.ResumableStateFileName {
+		return true
+	}
+
+	if cfg.Backup.StateFileDst != "" || cfg.Backup.Continue != "" || cfg.Backup.NoCheckpoint {
+		return false
+	}
+
+	if reason := checkpointUnsupported(cfg.Backup); reason != "" {
+		logger.Warn("backup state is not saved, the backup can't be continued if it is interrupted",
+			slog.String("reason", reason),
+		)
+
+		return false
+	}
+
+	cfg.Backup.StateFileDst = models.ResumableStateFileName
+
+	return true
+}
+
+// checkpointUnsupported returns why the state of the backup can't be saved, empty if it can.
+func checkpointUnsupported(b *models.Backup) string {
+	switch {
+	case b.Directory == "":
+		return "the state file is kept in the backup directory, set --directory"
+	case b.FileLimit == 0:
+		return "the state is saved when a file is closed, set --file-limit"
+	case b.NodeList != "" || b.RackList != "":
+		return "the state is not saved with --node-list or --rack-list"
+	case b.IsMultiNamespace():
+		return "the state is not saved for multiple namespaces"
+	default:
+		return ""
+	}
+}
+
+// interruptedError returns the error for a backup stopped by a signal, telling how to continue it.
+// The files written after the last save of the state are removed.
+// Returns nil if the backup wasn't interrupted or its state is not saved.
+func (s *Service) interruptedError(ctx context.Context) error {
+	if s.state == nil || !errors.Is(context.Cause(ctx), models.ErrInterrupted) {
+		return nil
+	}
+
+	// ctx is cancelled by the signal.
+	ctx = context.WithoutCancel(ctx)
+
+	if err := s.state.removeUnsaved(ctx); err != nil {
+		return err
+	}
+
+	found, err := hasFile(ctx, s.serviceConfig, path.Base(s.config.StateFile), s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to check for the state file: %w", err)
+	}
+
+	if !found {
+		s.logger.Warn("backup interrupted before its state was saved",
+			slog.String("state-file", s.config.StateFile),
+		)
+
+		return nil
+	}
+
+	hint := "run the same command again"
+	if !s.resumable {
+		hint = fmt.Sprintf("run it again with --continue %s", path.Base(s.config.StateFile))
+	}
+
+	s.logger.Warn("backup interrupted, state saved",
+		slog.String("state-file", s.config.StateFile),
+		slog.String("resume", hint),
+	)
+
+	return fmt.Errorf("%w to %s, %s to continue", models.ErrBackupInterrupted, s.config.StateFile, hint)
+}
+
+// removeStateFile deletes the state file of a resumable backup or a checkpoint after the backup succeeded,
+// so the next run starts a new backup. The start time and config fingerprint files of any backup that saves
+// its state are removed too.
+func (s *Service) removeStateFile(ctx context.Context) error {
+	if s.config.StateFile == "" {
+		return nil
+	}
+
+	for _, file := range []string{startTimeFile(s.config.StateFile), configFile(s.config.StateFile)} {
+		if err := s.writer.Remove(ctx, file); err != nil {
+			return fmt.Errorf("failed to remove file %s: %w", file, err)
+		}
+	}
+
+	if !s.resumable && !s.checkpoint {
+		return nil
+	}
+
+	if err := s.writer.Remove(ctx, s.config.StateFile); err != nil {
+		return fmt.Errorf("failed to remove state file %s: %w", s.config.StateFile, err)
+	}
+
+	s.logger.Info("backup completed, state file removed", slog.String("state-file", s.config.StateFile))
+
+	return nil
+}
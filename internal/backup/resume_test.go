@@ -0,0 +1,309 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/backup-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestResumableConfig(dir string) *config.BackupServiceConfig {
+	return &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: dir,
+			},
+			RemoveFiles: true,
+			Resumable:   true,
+		},
+		ServiceConfigCommon: config.ServiceConfigCommon{
+			AwsS3:      &models.AwsS3{},
+			GcpStorage: &models.GcpStorage{},
+			AzureBlob:  &models.AzureBlob{},
+			Local:      &models.Local{},
+		},
+	}
+}
+
+func TestApplyResumable_NewBackup(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestResumableConfig(t.TempDir())
+
+	require.NoError(t, applyResumable(t.Context(), cfg, slog.Default()))
+	assert.Equal(t, models.ResumableStateFileName, cfg.Backup.StateFileDst)
+	assert.Empty(t, cfg.Backup.Continue)
+	assert.True(t, cfg.Backup.ShouldClearTarget())
+}
+
+func TestApplyResumable_MissingDirectory(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestResumableConfig(filepath.Join(t.TempDir(), "missing"))
+
+	require.NoError(t, applyResumable(t.Context(), cfg, slog.Default()))
+	assert.Equal(t, models.ResumableStateFileName, cfg.Backup.StateFileDst)
+	assert.Empty(t, cfg.Backup.Continue)
+}
+
+func TestApplyResumable_IncompleteBackup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test_0.asb"), []byte("data"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, models.ResumableStateFileName), []byte("state"), 0o600))
+
+	cfg := newTestResumableConfig(dir)
+
+	require.NoError(t, applyResumable(t.Context(), cfg, slog.Default()))
+	assert.Equal(t, models.ResumableStateFileName, cfg.Backup.Continue)
+	assert.Empty(t, cfg.Backup.StateFileDst)
+	// Files of the incomplete backup must not be removed.
+	assert.False(t, cfg.Backup.ShouldClearTarget())
+}
+
+func TestApplyResumable_NotResumable(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestResumableConfig(t.TempDir())
+	cfg.Backup.Resumable = false
+
+	require.NoError(t, applyResumable(t.Context(), cfg, slog.Default()))
+	assert.Empty(t, cfg.Backup.StateFileDst)
+	assert.Empty(t, cfg.Backup.Continue)
+}
+
+func TestApplyCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestResumableConfig(t.TempDir())
+	cfg.Backup.Resumable = false
+	cfg.Backup.FileLimit = 250
+
+	// The state of a scan backup is saved by default.
+	assert.True(t, applyCheckpoint(cfg, slog.Default()))
+	assert.Equal(t, models.ResumableStateFileName, cfg.Backup.StateFileDst)
+}
+
+func TestApplyCheckpoint_NoCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestResumableConfig(t.TempDir())
+	cfg.Backup.Resumable = false
+	cfg.Backup.FileLimit = 250
+	cfg.Backup.NoCheckpoint = true
+
+	assert.False(t, applyCheckpoint(cfg, slog.Default()))
+	assert.Empty(t, cfg.Backup.StateFileDst)
+}
+
+func TestApplyCheckpoint_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		update func(b *models.Backup)
+	}{
+		{name: "without file limit", update: func(*models.Backup) {}},
+		{name: "to output file", update: func(b *models.Backup) {
+			b.FileLimit = 250
+			b.Directory = ""
+			b.OutputFile = "backup.asb"
+		}},
+		{name: "with rack list", update: func(b *models.Backup) {
+			b.FileLimit = 250
+			b.RackList = "1"
+		}},
+		{name: "estimate", update: func(b *models.Backup) {
+			b.FileLimit = 250
+			b.Estimate = true
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestResumableConfig(t.TempDir())
+			cfg.Backup.Resumable = false
+			tt.update(cfg.Backup)
+
+			assert.False(t, applyCheckpoint(cfg, slog.Default()))
+			assert.Empty(t, cfg.Backup.StateFileDst)
+		})
+	}
+}
+
+func TestApplyCheckpoint_ContinuedCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestResumableConfig(t.TempDir())
+	cfg.Backup.Resumable = false
+	cfg.Backup.FileLimit = 250
+	cfg.Backup.Continue = models.ResumableStateFileName
+
+	assert.True(t, applyCheckpoint(cfg, slog.Default()))
+	assert.Empty(t, cfg.Backup.StateFileDst)
+}
+
+func TestApplyCheckpoint_StateFileSet(t *testing.T) {
+	t.Parallel()
+
+	cfg := newTestResumableConfig(t.TempDir())
+	cfg.Backup.Resumable = false
+	cfg.Backup.FileLimit = 250
+	cfg.Backup.StateFileDst = "custom.state"
+
+	assert.False(t, applyCheckpoint(cfg, slog.Default()))
+	assert.Equal(t, "custom.state", cfg.Backup.StateFileDst)
+}
+
+func TestService_InterruptedError(t *testing.T) {
+	t.Parallel()
+
+	newService := func(t *testing.T, saved, resumable bool) *Service {
+		t.Helper()
+
+		dir := t.TempDir()
+		stateFile := filepath.Join(dir, models.ResumableStateFileName)
+
+		if saved {
+			require.NoError(t, os.WriteFile(stateFile, []byte("state"), 0o600))
+		}
+
+		return &Service{
+			config:        &backup.ConfigBackup{StateFile: stateFile},
+			serviceConfig: newTestResumableConfig(dir),
+			state:         newStateWriter(nil, stateFile),
+			resumable:     resumable,
+			logger:        slog.Default(),
+		}
+	}
+
+	interrupted, cancel := context.WithCancelCause(t.Context())
+	cancel(models.ErrInterrupted)
+
+	canceled, cancelCanceled := context.WithCancel(t.Context())
+	cancelCanceled()
+
+	err := newService(t, true, false).interruptedError(interrupted)
+	require.ErrorIs(t, err, models.ErrBackupInterrupted)
+	assert.Contains(t, err.Error(), "--continue "+models.ResumableStateFileName)
+
+	err = newService(t, true, true).interruptedError(interrupted)
+	require.ErrorIs(t, err, models.ErrBackupInterrupted)
+	assert.Contains(t, err.Error(), "run the same command again")
+
+	// The state file was not saved before the interruption.
+	require.NoError(t, newService(t, false, false).interruptedError(interrupted))
+	// The backup doesn't save its state.
+	svc := newService(t, true, false)
+	svc.state = nil
+	require.NoError(t, svc.interruptedError(interrupted))
+	// Canceled for another reason.
+	require.NoError(t, newService(t, true, false).interruptedError(canceled))
+}
+
+func TestContinueManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, models.ResumableStateFileName)
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	cfg := newTestResumableConfig(dir)
+
+	// The writers are created first, as the backup directory must be empty.
+	svc := &Service{
+		serviceConfig: cfg,
+		config:        &backup.ConfigBackup{StateFile: stateFile},
+		writer:        newTestManifestWriter(t, dir),
+	}
+	manifest := newTestManifestWriter(t, dir)
+	continued := newTestManifestWriter(t, dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0_test.asb"), []byte("data"), 0o600))
+	require.NoError(t, os.WriteFile(stateFile, []byte("state"), 0o600))
+
+	backupConfig := &backup.ConfigBackup{StateFile: stateFile, Continue: true}
+
+	// The start time file is missing, the start time of this run is used.
+	startTime, err := continueManifest(t.Context(), cfg, backupConfig, manifest, slog.Default())
+	require.NoError(t, err)
+	assert.True(t, startTime.IsZero())
+
+	require.NoError(t, svc.saveStartTime(t.Context(), start))
+
+	startTime, err = continueManifest(t.Context(), cfg, backupConfig, continued, slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, start, startTime)
+
+	// The state file, the start time file and the config fingerprint file are not part of the backup.
+	files := continued.Files()
+	require.Len(t, files, 1)
+	assert.Equal(t, "0_test.asb", files[0].Name)
+
+	// The start time file and the config fingerprint file are removed with the state file.
+	require.NoError(t, svc.removeStateFile(t.Context()))
+	assert.NoFileExists(t, startTimeFile(stateFile))
+	assert.NoFileExists(t, configFile(stateFile))
+}
+
+func TestCheckFingerprint(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, models.ResumableStateFileName)
+	backupConfig := &backup.ConfigBackup{StateFile: stateFile, Continue: true}
+
+	cfg := newTestResumableConfig(dir)
+	cfg.Backup.Namespace = "test"
+	cfg.Backup.SetList = "set1"
+
+	// Backups started without a fingerprint file are continued.
+	require.NoError(t, checkFingerprint(t.Context(), cfg, backupConfig, slog.Default()))
+
+	svc := &Service{
+		serviceConfig: cfg,
+		config:        &backup.ConfigBackup{StateFile: stateFile},
+		writer:        newTestManifestWriter(t, dir),
+	}
+	require.NoError(t, svc.saveStartTime(t.Context(), time.Now()))
+
+	require.NoError(t, checkFingerprint(t.Context(), cfg, backupConfig, slog.Default()))
+
+	// Options that don't change the backed up data can differ.
+	cfg.Backup.Parallel = 8
+	require.NoError(t, checkFingerprint(t.Context(), cfg, backupConfig, slog.Default()))
+
+	cfg.Backup.SetList = "set1,set2"
+	err := checkFingerprint(t.Context(), cfg, backupConfig, slog.Default())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "remove "+stateFile)
+
+	cfg.Backup.SetList = "set1"
+	cfg.Compression = &models.Compression{Mode: "ZSTD", Level: 3}
+	require.Error(t, checkFingerprint(t.Context(), cfg, backupConfig, slog.Default()))
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"errors"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	"github.com/aerospike/backup-go"
+)
+
+// handledCodes are the result codes of records that the restore counts instead of failing,
+// so they are not written again when the restore is resumed.
+var handledCodes = []types.ResultCode{
+	// Records that exist with unique, or that are fresher than the backup.
+	types.KEY_EXISTS_ERROR,
+	types.GENERATION_ERROR,
+}
+
+// ignoredCodes are the result codes of records that are ignored with ignore-record-error.
+var ignoredCodes = []types.ResultCode{
+	types.RECORD_TOO_BIG,
+	types.KEY_MISMATCH,
+	types.BIN_NAME_TOO_LONG,
+	types.ALWAYS_FORBIDDEN,
+	types.FAIL_FORBIDDEN,
+	types.BIN_TYPE_ERROR,
+	types.BIN_NOT_FOUND,
+}
+
+// Client wraps backup.AerospikeClient and confirms the written records to the tracker.
+type Client struct {
+	backup.AerospikeClient
+
+	tracker *Tracker
+	handled map[types.ResultCode]bool
+}
+
+// NewClient returns a Client that confirms the records written with c. If ignoreRecordError is set,
+// the records that fail with the ignored errors are confirmed too, as the restore skips them.
+func NewClient(c backup.AerospikeClient, tracker *Tracker, ignoreRecordError bool) *Client {
+	handled := make(map[types.ResultCode]bool, len(handledCodes)+len(ignoredCodes))
+	for _, code := range handledCodes {
+		handled[code] = true
+	}
+
+	if ignoreRecordError {
+		for _, code := range ignoredCodes {
+			handled[code] = true
+		}
+	}
+
+	return &Client{
+		AerospikeClient: c,
+		tracker:         tracker,
+		handled:         handled,
+	}
+}
+
+// Put writes the record and confirms it, unless it failed with an error that fails the restore.
+func (c *Client) Put(policy *aerospike.WritePolicy, key *aerospike.Key, bins aerospike.BinMap) aerospike.Error {
+	err := c.AerospikeClient.Put(policy, key, bins)
+
+	var ae *aerospike.AerospikeError
+	if err == nil || errors.As(err, &ae) && c.handled[ae.ResultCode] {
+		c.tracker.Applied(key.Digest())
+	}
+
+	return err
+}
+
+// BatchOperate writes the records and confirms those that are written or handled,
+// the others are retried or fail the restore.
+func (c *Client) BatchOperate(policy *aerospike.BatchPolicy, records []aerospike.BatchRecordIfc) aerospike.Error {
+	err := c.AerospikeClient.BatchOperate(policy, records)
+
+	for _, rec := range records {
+		br := rec.BatchRec()
+		if br.Key != nil && (br.ResultCode == types.OK || c.handled[br.ResultCode]) {
+			c.tracker.Applied(br.Key.Digest())
+		}
+	}
+
+	return err
+}
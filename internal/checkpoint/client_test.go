@@ -0,0 +1,124 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"testing"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	"github.com/aerospike/backup-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient is a backup.AerospikeClient that fails single writes with an error,
+// and batch writes with the result codes of the records.
+type testClient struct {
+	backup.AerospikeClient
+
+	err   a.Error
+	codes []types.ResultCode
+}
+
+func (c *testClient) Put(_ *a.WritePolicy, _ *a.Key, _ a.BinMap) a.Error {
+	return c.err
+}
+
+func (c *testClient) BatchOperate(_ *a.BatchPolicy, records []a.BatchRecordIfc) a.Error {
+	for i, rec := range records {
+		rec.BatchRec().ResultCode = c.codes[i]
+	}
+
+	return c.err
+}
+
+// trackedRecord returns a key and registers its record with the tracker.
+func trackedRecord(t *testing.T, tracker *Tracker, key int) *a.Key {
+	t.Helper()
+
+	k, err := a.NewKey("test", "users", key)
+	require.NoError(t, err)
+
+	tracker.Passed("test_0.asb", &Record{Digest: k.Digest()})
+
+	return k
+}
+
+func TestClient_Put(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		err               a.Error
+		ignoreRecordError bool
+		applied           bool
+	}{
+		{name: "Success", applied: true},
+		{name: "Existing record", err: &a.AerospikeError{ResultCode: types.KEY_EXISTS_ERROR}, applied: true},
+		{name: "Fresher record", err: &a.AerospikeError{ResultCode: types.GENERATION_ERROR}, applied: true},
+		{name: "Record error", err: &a.AerospikeError{ResultCode: types.RECORD_TOO_BIG}},
+		{
+			name:              "Ignored record error",
+			err:               &a.AerospikeError{ResultCode: types.RECORD_TOO_BIG},
+			ignoreRecordError: true,
+			applied:           true,
+		},
+		{name: "Timeout", err: &a.AerospikeError{ResultCode: types.TIMEOUT}, ignoreRecordError: true},
+		{name: "Network error", err: a.ErrNetwork, ignoreRecordError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tracker := newTestTracker(t)
+			tracker.Open("test_0.asb")
+
+			client := NewClient(&testClient{err: tt.err}, tracker, tt.ignoreRecordError)
+			key := trackedRecord(t, tracker, 1)
+
+			aErr := client.Put(a.NewWritePolicy(0, 0), key, a.BinMap{"age": 1})
+			assert.Equal(t, tt.err, aErr)
+
+			if tt.applied {
+				assert.Equal(t, FileState{Records: 1}, tracker.State().Files["test_0.asb"])
+			} else {
+				assert.Empty(t, tracker.State().Files)
+			}
+		})
+	}
+}
+
+func TestClient_BatchOperate(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+	tracker.Open("test_0.asb")
+
+	client := NewClient(&testClient{
+		codes: []types.ResultCode{types.OK, types.KEY_EXISTS_ERROR, types.TIMEOUT},
+	}, tracker, false)
+
+	records := make([]a.BatchRecordIfc, 3)
+	for i := range records {
+		records[i] = a.NewBatchWrite(nil, trackedRecord(t, tracker, i), a.PutOp(a.NewBin("age", i)))
+	}
+
+	require.NoError(t, client.BatchOperate(a.NewBatchPolicy(), records))
+
+	// The record that timed out is retried, so it is not restored.
+	assert.Equal(t, FileState{Records: 2}, tracker.State().Files["test_0.asb"])
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"sync"
+
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// Dir is a restored directory, or the directory of a restored file, with the reader of its files.
+type Dir struct {
+	// Path is the directory of the files, they are tracked by their path in it.
+	Path   string
+	Reader backup.StreamingReader
+}
+
+// Reader wraps backup.StreamingReader and skips the files that were restored by earlier runs.
+// The other files are registered with the tracker by their paths, as files of different directories
+// may have the same name.
+type Reader struct {
+	backup.StreamingReader
+
+	// dirs are streamed one by one, so the directory of every file is known.
+	dirs    []Dir
+	tracker *Tracker
+	logger  *slog.Logger
+
+	mu sync.Mutex
+	// paths are the paths of the files passed to the restore.
+	paths []string
+}
+
+// NewReader returns a Reader that streams the files of dirs that are not restored yet.
+// r is the reader of all the dirs, it serves everything but the streaming of files.
+func NewReader(r backup.StreamingReader, dirs []Dir, tracker *Tracker, logger *slog.Logger) *Reader {
+	return &Reader{
+		StreamingReader: r,
+		dirs:            dirs,
+		tracker:         tracker,
+		logger:          logger,
+	}
+}
+
+// StreamFiles streams the files of the directories that were not restored by earlier runs.
+func (r *Reader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) {
+	defer close(readersCh)
+
+	for _, dir := range r.dirs {
+		if !r.streamDir(ctx, dir, readersCh, errorsCh, skipPrefixes) {
+			return
+		}
+	}
+}
+
+// streamDir streams the files of the directory that were not restored by earlier runs.
+// It returns false if ctx is done.
+func (r *Reader) streamDir(
+	ctx context.Context, dir Dir, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) bool {
+	filesCh := make(chan bModels.File)
+
+	go dir.Reader.StreamFiles(ctx, filesCh, errorsCh, skipPrefixes)
+
+	for file := range filesCh {
+		filePath := path.Join(dir.Path, file.Name)
+
+		if _, done := r.tracker.Restored(filePath); done {
+			r.logger.Debug("skipping restored file", slog.String("file", filePath))
+			_ = file.Reader.Close()
+
+			continue
+		}
+
+		r.tracker.Open(filePath)
+
+		r.mu.Lock()
+		r.paths = append(r.paths, filePath)
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			_ = file.Reader.Close()
+			return false
+		case readersCh <- file:
+		}
+	}
+
+	return true
+}
+
+// GetSkipped returns the files skipped by the prefixes set on StreamFiles, in all directories.
+func (r *Reader) GetSkipped() []string {
+	var skipped []string
+	for _, dir := range r.dirs {
+		skipped = append(skipped, dir.Reader.GetSkipped()...)
+	}
+
+	return skipped
+}
+
+// Done marks the files streamed by the reader as restored, once their restore succeeded.
+func (r *Reader) Done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, filePath := range r.paths {
+		r.tracker.Done(filePath)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFilesReader streams empty files.
+type testFilesReader struct {
+	backup.StreamingReader
+
+	names []string
+}
+
+func (r *testFilesReader) StreamFiles(
+	_ context.Context, readersCh chan<- bModels.File, _ chan<- error, _ []string,
+) {
+	defer close(readersCh)
+
+	for _, name := range r.names {
+		readersCh <- bModels.File{Name: name, Reader: io.NopCloser(strings.NewReader(""))}
+	}
+}
+
+// streamNames streams the files of the reader and returns their names.
+func streamNames(t *testing.T, reader *Reader) []string {
+	t.Helper()
+
+	readersCh := make(chan bModels.File)
+	go reader.StreamFiles(t.Context(), readersCh, make(chan error, 1), nil)
+
+	var names []string
+	for file := range readersCh {
+		names = append(names, file.Name)
+	}
+
+	return names
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+	tracker.Open("backup/test_0.asb")
+	tracker.Done("backup/test_0.asb")
+	require.NoError(t, tracker.Save(t.Context()))
+
+	resumed := resumeTestTracker(t, tracker)
+
+	files := &testFilesReader{names: []string{"test_0.asb", "test_1.asb"}}
+	reader := NewReader(files, []Dir{{Path: "backup", Reader: files}}, resumed, slog.Default())
+
+	// The file restored by the earlier run is skipped.
+	assert.Equal(t, []string{"test_1.asb"}, streamNames(t, reader))
+	assert.Equal(t, map[string]FileState{"backup/test_0.asb": {Done: true}}, resumed.State().Files)
+
+	reader.Done()
+	assert.Equal(t, map[string]FileState{
+		"backup/test_0.asb": {Done: true},
+		"backup/test_1.asb": {Done: true},
+	}, resumed.State().Files)
+}
+
+// Files with the same name in different directories are tracked apart.
+func TestReader_Directories(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+	tracker.Open("full/test_0.asb")
+	tracker.Done("full/test_0.asb")
+	require.NoError(t, tracker.Save(t.Context()))
+
+	resumed := resumeTestTracker(t, tracker)
+
+	full := &testFilesReader{names: []string{"test_0.asb", "test_1.asb"}}
+	incremental := &testFilesReader{names: []string{"test_0.asb"}}
+	reader := NewReader(full, []Dir{
+		{Path: "full", Reader: full},
+		{Path: "incremental", Reader: incremental},
+	}, resumed, slog.Default())
+
+	// Only the file of the full backup was restored by the earlier run.
+	assert.Equal(t, []string{"test_1.asb", "test_0.asb"}, streamNames(t, reader))
+	assert.Equal(t, "full/test_1.asb", resumed.Claim("test_1.asb"))
+	assert.Equal(t, "incremental/test_0.asb", resumed.Claim("test_0.asb"))
+
+	reader.Done()
+	assert.Equal(t, map[string]FileState{
+		"full/test_0.asb":        {Done: true},
+		"full/test_1.asb":        {Done: true},
+		"incremental/test_0.asb": {Done: true},
+	}, resumed.State().Files)
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/aerospike/backup-go"
+)
+
+// stateVersion is the version of the state file format.
+const stateVersion = 1
+
+// State is the progress of a restore, saved to its state file.
+type State struct {
+	Version int `json:"version"`
+	// Source is the directory, file or directory list that is restored.
+	Source string `json:"source"`
+	// Files are the restored files by path.
+	Files map[string]FileState `json:"files"`
+}
+
+// FileState is the progress of the restore of a file.
+type FileState struct {
+	// Done is set when all the records of the file are restored.
+	Done bool `json:"done,omitempty"`
+	// Records is the number of records from the start of the file that are restored.
+	Records uint64 `json:"records,omitempty"`
+}
+
+// ReadState reads the state file at statePath from r. The state file is rewritten in place on some storages,
+// so a longer earlier state may follow the state, it is ignored.
+func ReadState(r io.Reader, statePath string) (*State, error) {
+	var state State
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", statePath, err)
+	}
+
+	if state.Version != stateVersion {
+		return nil, fmt.Errorf("unsupported version %d of state file %s", state.Version, statePath)
+	}
+
+	return &state, nil
+}
+
+// writeState writes the state to the state file at statePath, through the writer of its directory.
+func writeState(ctx context.Context, writer backup.Writer, statePath string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	wc, err := writer.NewWriter(ctx, path.Base(statePath))
+	if err != nil {
+		return fmt.Errorf("failed to create state file %s: %w", statePath, err)
+	}
+
+	if _, err = wc.Write(data); err != nil {
+		_ = wc.Close()
+		return fmt.Errorf("failed to write state file %s: %w", statePath, err)
+	}
+
+	if err = wc.Close(); err != nil {
+		return fmt.Errorf("failed to close state file %s: %w", statePath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,360 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	"github.com/aerospike/backup-go"
+)
+
+// Record is a record of a tracked file that is passed to the restore, as it is written.
+type Record struct {
+	Digest []byte
+	Set    string
+	Bins   []string
+	// VoidTime is the expiration in seconds since the Aerospike epoch, 0 if the record never expires.
+	VoidTime int64
+}
+
+// Drops are the options of the restore that drop records without writing them.
+// Dropped records are never confirmed by the client, so they are restored as soon as they are read.
+type Drops struct {
+	NoRecords bool
+	// Sets and Bins are the restored sets and bins, all of them are restored if empty.
+	Sets []string
+	Bins []string
+	// ExtraTTL is added to the expiration of the records.
+	ExtraTTL int64
+}
+
+// dropped checks if the restore drops the record. A record is only dropped if the restore is sure to drop it,
+// a record that is kept is restored once it is confirmed.
+func (d *Drops) dropped(rec *Record, now time.Time) bool {
+	switch {
+	case d.NoRecords:
+		return true
+	case len(d.Sets) > 0 && !slices.Contains(d.Sets, rec.Set):
+		return true
+	case len(d.Bins) > 0 && len(rec.Bins) > 0 && !slices.ContainsFunc(rec.Bins, func(bin string) bool {
+		return slices.Contains(d.Bins, bin)
+	}):
+		return true
+	case rec.VoidTime > 0:
+		// The restore checks the expiration later, so records expired now are expired then.
+		return rec.VoidTime+max(d.ExtraTTL, 0) < now.Unix()-types.CITRUSLEAF_EPOCH
+	default:
+		return false
+	}
+}
+
+// Tracker tracks the progress of a restore and saves it to a state file. Files are identified by their paths,
+// and are restored once their restore succeeds. The records of tracked files are restored once the client
+// confirms them, so the restore of a tracked file can be resumed after its last record that was restored
+// with all the records before it.
+// Tracker is safe for concurrent use.
+type Tracker struct {
+	// Drops must be set before the restore starts.
+	Drops Drops
+
+	writer backup.Writer
+	path   string
+	source string
+	// previous is the state the restore is resumed from.
+	previous map[string]FileState
+
+	mu    sync.Mutex
+	files map[string]*file
+	// unclaimed are the paths of the opened files that are not claimed yet by name, in the order they are opened.
+	unclaimed map[string][]string
+	// inFlight are the positions of the records that are passed to the restore and not confirmed, by digest.
+	inFlight map[[20]byte][]position
+	now      func() time.Time
+}
+
+// file is the progress of a file in this run.
+type file struct {
+	// skip is the number of records restored by earlier runs, they are not read again.
+	skip uint64
+	// read is the number of records read in this run. applied is the number of them, from the start of the file,
+	// that are restored. later are the restored records that follow a record that is not restored yet.
+	read    uint64
+	applied uint64
+	later   map[uint64]struct{}
+	// tracked is set if the records of the file are tracked, otherwise the file is restored with its restore.
+	tracked bool
+	// eof is set once all the records of the file are read.
+	eof  bool
+	done bool
+}
+
+// position is the position of a record in a file.
+type position struct {
+	file  *file
+	index uint64
+}
+
+// NewTracker returns a Tracker that saves the progress of the restore of source to the state file at statePath,
+// through the writer of the directory of the state file. If previous is set, the restore continues from it,
+// it must be the state of the same source.
+func NewTracker(writer backup.Writer, statePath, source string, previous *State) (*Tracker, error) {
+	t := &Tracker{
+		writer:    writer,
+		path:      statePath,
+		source:    source,
+		previous:  make(map[string]FileState),
+		files:     make(map[string]*file),
+		unclaimed: make(map[string][]string),
+		inFlight:  make(map[[20]byte][]position),
+		now:       time.Now,
+	}
+
+	if previous == nil {
+		return t, nil
+	}
+
+	if previous.Source != source {
+		return nil, fmt.Errorf("state file %s is the state of the restore of %s, not %s",
+			statePath, previous.Source, source)
+	}
+
+	if previous.Files != nil {
+		t.previous = previous.Files
+	}
+
+	return t, nil
+}
+
+// Path returns the path of the state file.
+func (t *Tracker) Path() string {
+	return t.path
+}
+
+// Resumed returns the number of files that were restored by earlier runs,
+// and the number of records restored from the files that were not.
+func (t *Tracker) Resumed() (files int, records uint64) {
+	for _, state := range t.previous {
+		if state.Done {
+			files++
+		}
+
+		records += state.Records
+	}
+
+	return files, records
+}
+
+// Restored returns the number of records from the start of the file at the path that were restored
+// by earlier runs, and whether the whole file was restored.
+func (t *Tracker) Restored(filePath string) (records uint64, done bool) {
+	state := t.previous[filePath]
+
+	return state.Records, state.Done
+}
+
+// Open registers the file at the path that is passed to the restore.
+func (t *Tracker) Open(filePath string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.files[filePath] = &file{skip: t.previous[filePath].Records}
+
+	name := path.Base(filePath)
+	t.unclaimed[name] = append(t.unclaimed[name], filePath)
+}
+
+// Claim returns the path of the first opened file with the name that is not claimed yet, so the records
+// of a file that is passed on by its name are tracked by its path. Files with the same name must be claimed
+// in the order they are opened. The name is returned if no such file is opened.
+func (t *Tracker) Claim(name string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	paths := t.unclaimed[name]
+	if len(paths) == 0 {
+		return name
+	}
+
+	if len(paths) == 1 {
+		delete(t.unclaimed, name)
+	} else {
+		t.unclaimed[name] = paths[1:]
+	}
+
+	return paths[0]
+}
+
+// Removed registers a record of a tracked file that is removed before it is passed to the restore.
+func (t *Tracker) Removed(filePath string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if f := t.trackedFile(filePath); f != nil {
+		f.apply(f.next())
+	}
+}
+
+// Passed registers a record of a tracked file that is passed to the restore. It is restored when the client
+// confirms it, or as soon as it is read if the restore drops it.
+func (t *Tracker) Passed(filePath string, rec *Record) {
+	dropped := t.Drops.dropped(rec, t.now())
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f := t.trackedFile(filePath)
+	if f == nil {
+		return
+	}
+
+	index := f.next()
+
+	switch {
+	case dropped:
+		f.apply(index)
+		return
+	case len(rec.Digest) != len([20]byte{}):
+		// The record can't be confirmed, so the file is restored with its restore.
+		return
+	}
+
+	digest := [20]byte(rec.Digest)
+	t.inFlight[digest] = append(t.inFlight[digest], position{file: f, index: index})
+}
+
+// EOF registers that all the records of a tracked file are read.
+func (t *Tracker) EOF(filePath string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if f := t.trackedFile(filePath); f != nil {
+		f.eof = true
+	}
+}
+
+// Applied confirms that a record is written. The oldest record in flight with the digest is restored.
+func (t *Tracker) Applied(digest []byte) {
+	if len(digest) != len([20]byte{}) {
+		return
+	}
+
+	key := [20]byte(digest)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	positions := t.inFlight[key]
+	if len(positions) == 0 {
+		return
+	}
+
+	positions[0].file.apply(positions[0].index)
+
+	if len(positions) == 1 {
+		delete(t.inFlight, key)
+	} else {
+		t.inFlight[key] = positions[1:]
+	}
+}
+
+// Done marks a file as restored, once the restore it was passed to succeeded.
+func (t *Tracker) Done(filePath string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if f, ok := t.files[filePath]; ok {
+		f.done = true
+	}
+}
+
+// Save saves the progress of the restore to the state file.
+func (t *Tracker) Save(ctx context.Context) error {
+	return writeState(ctx, t.writer, t.path, t.State())
+}
+
+// State returns the progress of the restore, with the progress of the earlier runs.
+func (t *Tracker) State() *State {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	files := make(map[string]FileState, len(t.previous)+len(t.files))
+	for name, state := range t.previous {
+		files[name] = state
+	}
+
+	for name, f := range t.files {
+		switch {
+		case f.done || f.tracked && f.eof && f.applied == f.read:
+			files[name] = FileState{Done: true}
+		case f.skip+f.applied > 0:
+			files[name] = FileState{Records: f.skip + f.applied}
+		}
+	}
+
+	return &State{
+		Version: stateVersion,
+		Source:  t.source,
+		Files:   files,
+	}
+}
+
+// trackedFile returns the opened file at the path, which is tracked from now on.
+func (t *Tracker) trackedFile(filePath string) *file {
+	f, ok := t.files[filePath]
+	if !ok {
+		return nil
+	}
+
+	f.tracked = true
+
+	return f
+}
+
+// next returns the index of the next record read from the file.
+func (f *file) next() uint64 {
+	f.read++
+
+	return f.read - 1
+}
+
+// apply restores the record at index.
+func (f *file) apply(index uint64) {
+	if index != f.applied {
+		if f.later == nil {
+			f.later = make(map[uint64]struct{})
+		}
+
+		f.later[index] = struct{}{}
+
+		return
+	}
+
+	f.applied++
+
+	for {
+		if _, ok := f.later[f.applied]; !ok {
+			return
+		}
+
+		delete(f.later, f.applied)
+		f.applied++
+	}
+}
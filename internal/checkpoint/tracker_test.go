@@ -0,0 +1,286 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	"github.com/aerospike/backup-go/io/storage/local"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDigest returns a digest filled with b.
+func testDigest(b byte) []byte {
+	digest := make([]byte, 20)
+	for i := range digest {
+		digest[i] = b
+	}
+
+	return digest
+}
+
+// newTestTracker returns a tracker of a new restore of the backup source.
+func newTestTracker(t *testing.T) *Tracker {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	writer, err := local.NewWriter(t.Context(), options.WithDir(dir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	tracker, err := NewTracker(writer, filepath.Join(dir, "restore.state"), "backup", nil)
+	require.NoError(t, err)
+
+	return tracker
+}
+
+// resumeTestTracker returns a tracker of the restore resumed from the saved state of the tracker.
+func resumeTestTracker(t *testing.T, tracker *Tracker) *Tracker {
+	t.Helper()
+
+	file, err := os.Open(tracker.Path())
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	state, err := ReadState(file, tracker.Path())
+	require.NoError(t, err)
+
+	resumed, err := NewTracker(tracker.writer, tracker.Path(), "backup", state)
+	require.NoError(t, err)
+
+	return resumed
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+	tracker.Open("test_0.asb")
+
+	for b := byte(1); b <= 4; b++ {
+		tracker.Passed("test_0.asb", &Record{Digest: testDigest(b)})
+	}
+
+	// Records are restored from the start of the file, when all the records before them are.
+	tracker.Applied(testDigest(2))
+	assert.Empty(t, tracker.State().Files)
+
+	tracker.Applied(testDigest(1))
+	assert.Equal(t, FileState{Records: 2}, tracker.State().Files["test_0.asb"])
+
+	tracker.Applied(testDigest(4))
+	tracker.Applied(testDigest(3))
+	assert.Equal(t, FileState{Records: 4}, tracker.State().Files["test_0.asb"])
+
+	// The file is restored once all its records are read.
+	tracker.EOF("test_0.asb")
+	assert.Equal(t, FileState{Done: true}, tracker.State().Files["test_0.asb"])
+}
+
+func TestTracker_SameDigest(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+	tracker.Open("a/test_0.asb")
+	tracker.Open("b/test_0.asb")
+
+	tracker.Passed("a/test_0.asb", &Record{Digest: testDigest(1)})
+	tracker.Passed("b/test_0.asb", &Record{Digest: testDigest(1)})
+
+	// The oldest record with the digest is restored first.
+	tracker.Applied(testDigest(1))
+	assert.Equal(t, map[string]FileState{"a/test_0.asb": {Records: 1}}, tracker.State().Files)
+
+	tracker.Applied(testDigest(1))
+	assert.Equal(t, map[string]FileState{
+		"a/test_0.asb": {Records: 1},
+		"b/test_0.asb": {Records: 1},
+	}, tracker.State().Files)
+}
+
+func TestTracker_Claim(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+	tracker.Open("a/test_0.asb")
+	tracker.Open("b/test_0.asb")
+
+	// Files are claimed in the order they are opened, a name that is not opened is its own path.
+	assert.Equal(t, "a/test_0.asb", tracker.Claim("test_0.asb"))
+	assert.Equal(t, "b/test_0.asb", tracker.Claim("test_0.asb"))
+	assert.Equal(t, "test_0.asb", tracker.Claim("test_0.asb"))
+}
+
+func TestTracker_Removed(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+	tracker.Open("test_0.asb")
+
+	tracker.Passed("test_0.asb", &Record{Digest: testDigest(1)})
+	tracker.Removed("test_0.asb")
+	tracker.EOF("test_0.asb")
+
+	assert.Empty(t, tracker.State().Files)
+
+	tracker.Applied(testDigest(1))
+	assert.Equal(t, FileState{Done: true}, tracker.State().Files["test_0.asb"])
+}
+
+func TestTracker_Done(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+
+	// Records of files that are not opened are not tracked.
+	tracker.Passed("test_0.asbx", &Record{Digest: testDigest(1)})
+	tracker.Done("test_0.asbx")
+	assert.Empty(t, tracker.State().Files)
+
+	// Files whose records are not tracked are restored with their restore.
+	tracker.Open("test_0.asbx")
+	assert.Empty(t, tracker.State().Files)
+
+	tracker.Done("test_0.asbx")
+	assert.Equal(t, FileState{Done: true}, tracker.State().Files["test_0.asbx"])
+}
+
+func TestTracker_Resume(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+	tracker.Open("test_0.asb")
+	tracker.Open("test_1.asb")
+	tracker.Passed("test_1.asb", &Record{Digest: testDigest(1)})
+	tracker.Passed("test_1.asb", &Record{Digest: testDigest(2)})
+	tracker.Applied(testDigest(1))
+	tracker.Done("test_0.asb")
+	require.NoError(t, tracker.Save(t.Context()))
+
+	resumed := resumeTestTracker(t, tracker)
+
+	files, records := resumed.Resumed()
+	assert.Equal(t, 1, files)
+	assert.Equal(t, uint64(1), records)
+
+	records, done := resumed.Restored("test_0.asb")
+	assert.True(t, done)
+	assert.Zero(t, records)
+
+	records, done = resumed.Restored("test_1.asb")
+	assert.False(t, done)
+	assert.Equal(t, uint64(1), records)
+
+	// Records restored by this run follow those of the earlier runs.
+	resumed.Open("test_1.asb")
+	resumed.Passed("test_1.asb", &Record{Digest: testDigest(2)})
+	resumed.Applied(testDigest(2))
+	assert.Equal(t, map[string]FileState{
+		"test_0.asb": {Done: true},
+		"test_1.asb": {Records: 2},
+	}, resumed.State().Files)
+}
+
+func TestNewTracker_OtherSource(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewTracker(nil, "restore.state", "backup", &State{Version: stateVersion, Source: "other"})
+	require.ErrorContains(t, err, "is the state of the restore of other, not backup")
+}
+
+func TestReadState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: `{"version": 1, "source": "backup"}`},
+		// A longer earlier state follows the state when the file is rewritten in place.
+		{name: "earlier state", content: `{"version": 1, "source": "backup"}"source": "other"}`},
+		{name: "version", content: `{"version": 2, "source": "backup"}`, wantErr: "unsupported version 2"},
+		{name: "invalid", content: `{`, wantErr: "failed to parse state file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			state, err := ReadState(strings.NewReader(tt.content), "restore.state")
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "backup", state.Source)
+		})
+	}
+}
+
+func TestDrops_Dropped(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(types.CITRUSLEAF_EPOCH+1000, 0)
+
+	tests := []struct {
+		name    string
+		drops   Drops
+		rec     Record
+		dropped bool
+	}{
+		{name: "No drops", rec: Record{Set: "users", Bins: []string{"age"}}},
+		{name: "No records", drops: Drops{NoRecords: true}, rec: Record{Set: "users"}, dropped: true},
+		{name: "Restored set", drops: Drops{Sets: []string{"users"}}, rec: Record{Set: "users"}},
+		{name: "Other set", drops: Drops{Sets: []string{"users"}}, rec: Record{Set: "orders"}, dropped: true},
+		{name: "Restored bin", drops: Drops{Bins: []string{"age"}}, rec: Record{Bins: []string{"name", "age"}}},
+		{name: "Other bins", drops: Drops{Bins: []string{"age"}}, rec: Record{Bins: []string{"name"}}, dropped: true},
+		{name: "Never expires", rec: Record{}},
+		{name: "Not expired", rec: Record{VoidTime: 1001}},
+		{name: "Expires now", rec: Record{VoidTime: 1000}},
+		{name: "Expired", rec: Record{VoidTime: 999}, dropped: true},
+		{name: "Extra TTL", drops: Drops{ExtraTTL: 10}, rec: Record{VoidTime: 999}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.dropped, tt.drops.dropped(&tt.rec, now))
+		})
+	}
+}
+
+func TestTracker_Dropped(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTestTracker(t)
+	tracker.Drops = Drops{Sets: []string{"users"}}
+	tracker.Open("test_0.asb")
+
+	// Dropped records are never confirmed, they are restored when they are read.
+	tracker.Passed("test_0.asb", &Record{Digest: testDigest(1), Set: "orders"})
+	tracker.Passed("test_0.asb", &Record{Digest: testDigest(2), Set: "users"})
+	assert.Equal(t, FileState{Records: 1}, tracker.State().Files["test_0.asb"])
+}
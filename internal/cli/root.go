@@ -75,6 +75,11 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 	// Add subcommands - they will initialize their own operation-specific flags
 	backupCmd, _ := scan.NewBackupCmd(c.flagsRoot, appVersion, commitHash, buildTime)
 	restoreCmd, _ := scan.NewRestoreCmd(c.flagsRoot, appVersion, commitHash, buildTime)
+	verifyCmd, _ := scan.NewVerifyCmd(c.flagsRoot, appVersion, commitHash, buildTime)
+	inspectCmd, _ := scan.NewInspectCmd(c.flagsRoot, appVersion, commitHash, buildTime)
+	consolidateCmd, _ := scan.NewConsolidateCmd(c.flagsRoot, appVersion, commitHash, buildTime)
+	transcodeCmd, _ := scan.NewTranscodeCmd(c.flagsRoot, appVersion, commitHash, buildTime)
+	copyCmd, _ := scan.NewCopyCmd(c.flagsRoot, appVersion, commitHash, buildTime)
 
 	// Comment it for now, as they belong to not released features.
 	// serverCmd := server.NewCmd(c.flagsRoot, appVersion, commitHash, buildTime)
@@ -84,6 +89,11 @@ func NewCmd(appVersion, commitHash, buildTime string) (*cobra.Command, *Cmd) {
 
 	rootCmd.AddCommand(backupCmd)
 	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(consolidateCmd)
+	rootCmd.AddCommand(transcodeCmd)
+	rootCmd.AddCommand(copyCmd)
 
 	helpFunc := newHelpFunction(rootFlagSet)
 
@@ -125,8 +135,13 @@ func newHelpFunction(flagSet *pflag.FlagSet) func() {
 		fmt.Println("\nUsage:")
 		fmt.Println("  absctl [command] [flags]")
 		fmt.Println("\nAvailable Commands:")
-		fmt.Println("  backup    Aerospike backup command")
-		fmt.Println("  restore   Aerospike restore command")
+		fmt.Println("  backup       Aerospike backup command")
+		fmt.Println("  restore      Aerospike restore command")
+		fmt.Println("  verify       Verify backup files against the backup manifest")
+		fmt.Println("  inspect      Print the content of backup files as NDJSON")
+		fmt.Println("  consolidate  Merge a full backup and its incremental backups into a new full backup")
+		fmt.Println("  transcode    Rewrite backup files with another compression or encryption key")
+		fmt.Println("  copy         Copy a backup between storages and verify the copy")
 		//nolint:gocritic // This lines are commented as they belong to not released features.
 		// fmt.Println("  server    Manage server-integrated backups and restores")
 		// fmt.Println("  service   Interact with Aerospike Backup Service REST API")
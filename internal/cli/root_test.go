@@ -90,7 +90,7 @@ func TestNewCmd_Subcommands(t *testing.T) {
 	rootCmd, _ := NewCmd(testAppVersion, testCommitHash, testBuildTime)
 
 	assert.ElementsMatch(t,
-		[]string{"backup", "restore"},
+		[]string{"backup", "restore", "verify", "inspect", "consolidate", "transcode", "copy"},
 		subcommandNames(rootCmd),
 	)
 }
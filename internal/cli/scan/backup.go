@@ -20,10 +20,13 @@ import (
 	"log"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/aerospike/absctl/internal/backup"
 	"github.com/aerospike/absctl/internal/config"
 	"github.com/aerospike/absctl/internal/flags"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/report"
 	"github.com/aerospike/absctl/internal/subcmd"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -38,10 +41,15 @@ type backupRunner struct {
 	flagsBackup *flags.Backup
 	flagsCommon *flags.Common
 	flagsLocal  *flags.Local
+	flagsSftp   *flags.Sftp
 
 	commonFlagSet *pflag.FlagSet
 	backupFlagSet *pflag.FlagSet
 	localFlagSet  *pflag.FlagSet
+	sftpFlagSet   *pflag.FlagSet
+
+	appVersion string
+	commitHash string
 }
 
 // NewBackupCmd builds the top-level "backup" command for scan-based backups.
@@ -51,6 +59,9 @@ func NewBackupCmd(
 	r := &backupRunner{
 		flagsBackup: flags.NewBackup(),
 		flagsLocal:  flags.NewLocal(flags.OperationBackup),
+		flagsSftp:   flags.NewSftp(flags.OperationBackup),
+		appVersion:  appVersion,
+		commitHash:  commitHash,
 	}
 	r.flagsCommon = flags.NewCommon(&r.flagsBackup.Common, flags.OperationBackup)
 
@@ -65,11 +76,13 @@ func (r *backupRunner) FlagSets() []*pflag.FlagSet {
 	r.commonFlagSet = r.flagsCommon.NewFlagSet()
 	r.backupFlagSet = r.flagsBackup.NewFlagSet()
 	r.localFlagSet = r.flagsLocal.NewFlagSet()
+	r.sftpFlagSet = r.flagsSftp.NewFlagSet()
 
 	return []*pflag.FlagSet{
 		r.commonFlagSet,
 		r.backupFlagSet,
 		r.localFlagSet,
+		r.sftpFlagSet,
 	}
 }
 
@@ -94,6 +107,7 @@ func (r *backupRunner) SetHelpUsage(cmd *cobra.Command, shared *subcmd.SharedFla
 		shared.Aws,
 		shared.Gcp,
 		shared.Azure,
+		r.sftpFlagSet,
 		r.localFlagSet,
 	)
 
@@ -129,6 +143,7 @@ func (r *backupRunner) NewServiceConfig(ctx context.Context, shared *subcmd.Shar
 		shared.Aws.GetAwsS3(),
 		shared.Gcp.GetGcpStorage(),
 		shared.Azure.GetAzureBlob(),
+		r.flagsSftp.GetSftp(),
 		r.flagsLocal.GetLocal(),
 	)
 	if err != nil {
@@ -140,17 +155,57 @@ func (r *backupRunner) NewServiceConfig(ctx context.Context, shared *subcmd.Shar
 
 func (r *backupRunner) RunService(ctx context.Context, cfg subcmd.ServiceConfig, logger *slog.Logger) error {
 	backupCfg := cfg.(*config.BackupServiceConfig)
+	start := time.Now()
+
+	asb, err := r.runBackup(ctx, backupCfg, logger)
+
+	// Dry runs don't back up anything, so there is nothing to report.
+	app := backupCfg.GetApp()
+	if app == nil || app.ReportFile == "" || backupCfg.IsDryRun() {
+		return err
+	}
+
+	rep, reportErr := report.NewBackup(backupCfg, asb.Stats(), asb.Destinations(), err, start,
+		r.appVersion, r.commitHash)
 
-	asb, err := backup.NewService(ctx, backupCfg, logger)
+	return writeReport(app.ReportFile, rep, reportErr, err, logger)
+}
+
+// runBackup runs the backup and returns the service, so the stats can be reported even if the backup failed.
+func (r *backupRunner) runBackup(ctx context.Context, cfg *config.BackupServiceConfig, logger *slog.Logger,
+) (*backup.Service, error) {
+	asb, err := backup.NewService(ctx, cfg, logger)
 	if err != nil {
-		return fmt.Errorf("backup initialization failed: %w", err)
+		return nil, fmt.Errorf("backup initialization failed: %w", err)
 	}
 
+	asb.SetBuildInfo(r.appVersion, r.commitHash)
+
 	if err = asb.Run(ctx); err != nil {
-		return fmt.Errorf("backup failed: %w", err)
+		return asb, fmt.Errorf("backup failed: %w", err)
 	}
 
-	return nil
+	return asb, nil
+}
+
+// writeReport writes the run report built with reportErr to path.
+// A report failure is returned only if the run succeeded, otherwise it is logged and runErr is returned.
+func writeReport(path string, rep *models.RunReport, reportErr, runErr error, logger *slog.Logger) error {
+	if reportErr == nil {
+		reportErr = report.Write(path, rep)
+	}
+
+	if reportErr == nil {
+		logger.Info("run report written", slog.String("path", path))
+		return runErr
+	}
+
+	if runErr != nil {
+		logger.Error("failed to write run report", slog.Any("error", reportErr))
+		return runErr
+	}
+
+	return reportErr
 }
 
 func newBackupHelpFunction(
@@ -165,6 +220,7 @@ func newBackupHelpFunction(
 	awsFlagSet,
 	gcpFlagSet,
 	azureFlagSet,
+	sftpFlagSet,
 	localFlagSet *pflag.FlagSet,
 ) func() {
 	return func() {
@@ -213,5 +269,9 @@ func newBackupHelpFunction(
 		// Print section: Azure Flags
 		fmt.Println(flags.SectionTextAzure)
 		azureFlagSet.PrintDefaults()
+
+		// Print section: SFTP Flags
+		fmt.Println(flags.SectionTextSftp)
+		sftpFlagSet.PrintDefaults()
 	}
 }
@@ -121,11 +121,12 @@ func TestNewBackupCmd_RunnerImplementsRunner(t *testing.T) {
 	r := &backupRunner{
 		flagsBackup: flags.NewBackup(),
 		flagsLocal:  flags.NewLocal(flags.OperationBackup),
+		flagsSftp:   flags.NewSftp(flags.OperationBackup),
 	}
 	r.flagsCommon = flags.NewCommon(&r.flagsBackup.Common, flags.OperationBackup)
 
 	sets := r.FlagSets()
-	require.Len(t, sets, 3)
+	require.Len(t, sets, 4)
 	for _, fs := range sets {
 		assert.NotNil(t, fs)
 	}
@@ -142,7 +143,7 @@ func TestNewBackupHelpFunction_Output(t *testing.T) {
 	helpFn := newBackupHelpFunction(
 		appFS, appFS, appFS,
 		appFS, appFS, appFS, appFS, appFS,
-		appFS, appFS, appFS, appFS,
+		appFS, appFS, appFS, appFS, appFS,
 	)
 
 	out := captureStdout(t, helpFn)
@@ -0,0 +1,179 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/consolidate"
+	"github.com/aerospike/absctl/internal/flags"
+	"github.com/aerospike/absctl/internal/subcmd"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	consolidateWelcomeMessage      = "Welcome to the Aerospike backup consolidate CLI tool!"
+	consolidateWelcomeMessageShort = "Aerospike backup consolidate CLI tool"
+)
+
+type consolidateRunner struct {
+	flagsConsolidate *flags.Consolidate
+
+	consolidateFlagSet *pflag.FlagSet
+
+	appVersion string
+	commitHash string
+}
+
+// NewConsolidateCmd builds the top-level "consolidate" command that merges a full backup
+// and its incremental backups into a new full backup.
+func NewConsolidateCmd(
+	flagsRoot *flags.Root, appVersion, commitHash, buildTime string,
+) (*cobra.Command, *subcmd.SharedFlags) {
+	r := &consolidateRunner{
+		flagsConsolidate: flags.NewConsolidate(),
+		appVersion:       appVersion,
+		commitHash:       commitHash,
+	}
+
+	// The consolidated backup is written with the backup storage and compression flags.
+	return subcmd.BuildCommand(
+		"consolidate", consolidateWelcomeMessageShort, consolidateWelcomeMessage,
+		flagsRoot, appVersion, commitHash, buildTime,
+		flags.OperationBackup, r,
+	)
+}
+
+func (r *consolidateRunner) FlagSets() []*pflag.FlagSet {
+	r.consolidateFlagSet = r.flagsConsolidate.NewFlagSet()
+
+	return []*pflag.FlagSet{
+		r.consolidateFlagSet,
+	}
+}
+
+func (r *consolidateRunner) PostRegistration(_ *cobra.Command) {}
+
+func (r *consolidateRunner) SetHelpUsage(cmd *cobra.Command, shared *subcmd.SharedFlagSets) {
+	helpFunc := newConsolidateHelpFunction(
+		shared.App,
+		r.consolidateFlagSet,
+		shared.Compression,
+		shared.Encryption,
+		shared.SecretAgent,
+		shared.Aws,
+		shared.Gcp,
+		shared.Azure,
+	)
+
+	cmd.SetUsageFunc(func(_ *cobra.Command) error {
+		helpFunc()
+		return nil
+	})
+	cmd.SetHelpFunc(func(_ *cobra.Command, _ []string) {
+		helpFunc()
+	})
+}
+
+func (r *consolidateRunner) NewServiceConfig(_ context.Context, shared *subcmd.SharedFlags,
+) (subcmd.ServiceConfig, error) {
+	app := shared.App.GetApp()
+	if app != nil && app.ConfigFilePath != "" {
+		return nil, errors.New("config file is not supported by consolidate command")
+	}
+
+	return config.NewConsolidateServiceConfig(
+		app,
+		r.flagsConsolidate.GetConsolidate(),
+		shared.Compression.GetCompression(),
+		shared.Encryption.GetEncryption(),
+		shared.SecretAgent.GetSecretAgent(),
+		shared.Aws.GetAwsS3(),
+		shared.Gcp.GetGcpStorage(),
+		shared.Azure.GetAzureBlob(),
+	), nil
+}
+
+func (r *consolidateRunner) RunService(ctx context.Context, cfg subcmd.ServiceConfig, logger *slog.Logger) error {
+	consolidateCfg := cfg.(*config.ConsolidateServiceConfig)
+
+	c, err := consolidate.NewService(ctx, consolidateCfg, logger)
+	if err != nil {
+		return fmt.Errorf("consolidate initialization failed: %w", err)
+	}
+
+	c.SetBuildInfo(r.appVersion, r.commitHash)
+
+	if err = c.Run(ctx); err != nil {
+		return fmt.Errorf("consolidate failed: %w", err)
+	}
+
+	return nil
+}
+
+func newConsolidateHelpFunction(
+	appFlagSet,
+	consolidateFlagSet,
+	compressionFlagSet,
+	encryptionFlagSet,
+	secretAgentFlagSet,
+	awsFlagSet,
+	gcpFlagSet,
+	azureFlagSet *pflag.FlagSet,
+) func() {
+	return func() {
+		fmt.Println(consolidateWelcomeMessage)
+		fmt.Println(strings.Repeat("-", len(consolidateWelcomeMessage)))
+		fmt.Println(flags.SectionTextUsageConsolidate)
+
+		// Print section: App Flags
+		fmt.Println(flags.SectionTextGeneral)
+		appFlagSet.PrintDefaults()
+
+		// Print section: Consolidate Flags
+		fmt.Println(flags.SectionTextConsolidate)
+		consolidateFlagSet.PrintDefaults()
+
+		// Print section: Compression Flags
+		fmt.Println(flags.SectionTextCompression)
+		compressionFlagSet.PrintDefaults()
+
+		// Print section: Encryption Flags
+		fmt.Println(flags.SectionTextEncryption)
+		encryptionFlagSet.PrintDefaults()
+
+		// Print section: Secret Agent Flags
+		fmt.Println(flags.SectionTextSecretAgentBackup)
+		secretAgentFlagSet.PrintDefaults()
+
+		// Print section: AWS Flags
+		fmt.Println(flags.SectionTextAWS)
+		awsFlagSet.PrintDefaults()
+
+		// Print section: GCP Flags
+		fmt.Println(flags.SectionTextGCP)
+		gcpFlagSet.PrintDefaults()
+
+		// Print section: Azure Flags
+		fmt.Println(flags.SectionTextAzure)
+		azureFlagSet.PrintDefaults()
+	}
+}
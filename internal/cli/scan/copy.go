@@ -0,0 +1,164 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/copier"
+	"github.com/aerospike/absctl/internal/flags"
+	"github.com/aerospike/absctl/internal/subcmd"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	copyWelcomeMessage      = "Welcome to the Aerospike backup copy CLI tool!"
+	copyWelcomeMessageShort = "Aerospike backup copy CLI tool"
+)
+
+type copyRunner struct {
+	flagsCopy *flags.Copy
+
+	copyFlagSet *pflag.FlagSet
+}
+
+// NewCopyCmd builds the top-level "copy" command that copies a backup from one storage to another.
+func NewCopyCmd(
+	flagsRoot *flags.Root, appVersion, commitHash, buildTime string,
+) (*cobra.Command, *subcmd.SharedFlags) {
+	r := &copyRunner{
+		flagsCopy: flags.NewCopy(),
+	}
+
+	// Both storages are configured with the backup cloud flags, the buckets are taken from the paths.
+	return subcmd.BuildCommand(
+		"copy", copyWelcomeMessageShort, copyWelcomeMessage,
+		flagsRoot, appVersion, commitHash, buildTime,
+		flags.OperationBackup, r,
+	)
+}
+
+func (r *copyRunner) FlagSets() []*pflag.FlagSet {
+	r.copyFlagSet = r.flagsCopy.NewFlagSet()
+
+	return []*pflag.FlagSet{
+		r.copyFlagSet,
+	}
+}
+
+func (r *copyRunner) PostRegistration(_ *cobra.Command) {}
+
+func (r *copyRunner) SetHelpUsage(cmd *cobra.Command, shared *subcmd.SharedFlagSets) {
+	helpFunc := newCopyHelpFunction(
+		shared.App,
+		r.copyFlagSet,
+		shared.Encryption,
+		shared.SecretAgent,
+		shared.Aws,
+		shared.Gcp,
+		shared.Azure,
+	)
+
+	cmd.SetUsageFunc(func(_ *cobra.Command) error {
+		helpFunc()
+		return nil
+	})
+	cmd.SetHelpFunc(func(_ *cobra.Command, _ []string) {
+		helpFunc()
+	})
+}
+
+func (r *copyRunner) NewServiceConfig(_ context.Context, shared *subcmd.SharedFlags,
+) (subcmd.ServiceConfig, error) {
+	app := shared.App.GetApp()
+	if app != nil && app.ConfigFilePath != "" {
+		return nil, errors.New("config file is not supported by copy command")
+	}
+
+	return config.NewCopyServiceConfig(
+		app,
+		r.flagsCopy.GetCopy(),
+		shared.Encryption.GetEncryption(),
+		shared.SecretAgent.GetSecretAgent(),
+		shared.Aws.GetAwsS3(),
+		shared.Gcp.GetGcpStorage(),
+		shared.Azure.GetAzureBlob(),
+	), nil
+}
+
+func (r *copyRunner) RunService(ctx context.Context, cfg subcmd.ServiceConfig, logger *slog.Logger) error {
+	copyCfg := cfg.(*config.CopyServiceConfig)
+
+	c, err := copier.NewService(ctx, copyCfg, logger)
+	if err != nil {
+		return fmt.Errorf("copy initialization failed: %w", err)
+	}
+
+	if err = c.Run(ctx); err != nil {
+		return fmt.Errorf("copy failed: %w", err)
+	}
+
+	return nil
+}
+
+func newCopyHelpFunction(
+	appFlagSet,
+	copyFlagSet,
+	encryptionFlagSet,
+	secretAgentFlagSet,
+	awsFlagSet,
+	gcpFlagSet,
+	azureFlagSet *pflag.FlagSet,
+) func() {
+	return func() {
+		fmt.Println(copyWelcomeMessage)
+		fmt.Println(strings.Repeat("-", len(copyWelcomeMessage)))
+		fmt.Println(flags.SectionTextUsageCopy)
+
+		// Print section: App Flags
+		fmt.Println(flags.SectionTextGeneral)
+		appFlagSet.PrintDefaults()
+
+		// Print section: Copy Flags
+		fmt.Println(flags.SectionTextCopy)
+		copyFlagSet.PrintDefaults()
+
+		// Print section: Encryption Flags
+		fmt.Println(flags.SectionTextEncryption)
+		encryptionFlagSet.PrintDefaults()
+
+		// Print section: Secret Agent Flags
+		fmt.Println(flags.SectionTextSecretAgentBackup)
+		secretAgentFlagSet.PrintDefaults()
+
+		// Print section: AWS Flags
+		fmt.Println(flags.SectionTextAWS)
+		awsFlagSet.PrintDefaults()
+
+		// Print section: GCP Flags
+		fmt.Println(flags.SectionTextGCP)
+		gcpFlagSet.PrintDefaults()
+
+		// Print section: Azure Flags
+		fmt.Println(flags.SectionTextAzure)
+		azureFlagSet.PrintDefaults()
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/flags"
+	"github.com/aerospike/absctl/internal/inspect"
+	"github.com/aerospike/absctl/internal/subcmd"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	inspectWelcomeMessage      = "Welcome to the Aerospike backup inspect CLI tool!"
+	inspectWelcomeMessageShort = "Aerospike backup inspect CLI tool"
+)
+
+type inspectRunner struct {
+	flagsInspect *flags.Inspect
+
+	inspectFlagSet *pflag.FlagSet
+}
+
+// NewInspectCmd builds the top-level "inspect" command that decodes backup files and prints them as NDJSON.
+func NewInspectCmd(
+	flagsRoot *flags.Root, appVersion, commitHash, buildTime string,
+) (*cobra.Command, *subcmd.SharedFlags) {
+	r := &inspectRunner{
+		flagsInspect: flags.NewInspect(),
+	}
+
+	return subcmd.BuildCommand(
+		"inspect", inspectWelcomeMessageShort, inspectWelcomeMessage,
+		flagsRoot, appVersion, commitHash, buildTime,
+		flags.OperationRestore, r,
+	)
+}
+
+func (r *inspectRunner) FlagSets() []*pflag.FlagSet {
+	r.inspectFlagSet = r.flagsInspect.NewFlagSet()
+
+	return []*pflag.FlagSet{
+		r.inspectFlagSet,
+	}
+}
+
+func (r *inspectRunner) PostRegistration(_ *cobra.Command) {}
+
+func (r *inspectRunner) SetHelpUsage(cmd *cobra.Command, shared *subcmd.SharedFlagSets) {
+	helpFunc := newInspectHelpFunction(
+		shared.App,
+		r.inspectFlagSet,
+		shared.Compression,
+		shared.Encryption,
+		shared.SecretAgent,
+		shared.Aws,
+		shared.Gcp,
+		shared.Azure,
+	)
+
+	cmd.SetUsageFunc(func(_ *cobra.Command) error {
+		helpFunc()
+		return nil
+	})
+	cmd.SetHelpFunc(func(_ *cobra.Command, _ []string) {
+		helpFunc()
+	})
+}
+
+func (r *inspectRunner) NewServiceConfig(_ context.Context, shared *subcmd.SharedFlags,
+) (subcmd.ServiceConfig, error) {
+	app := shared.App.GetApp()
+	if app != nil && app.ConfigFilePath != "" {
+		return nil, errors.New("config file is not supported by inspect command")
+	}
+
+	return config.NewInspectServiceConfig(
+		app,
+		r.flagsInspect.GetInspect(),
+		shared.Compression.GetCompression(),
+		shared.Encryption.GetEncryption(),
+		shared.SecretAgent.GetSecretAgent(),
+		shared.Aws.GetAwsS3(),
+		shared.Gcp.GetGcpStorage(),
+		shared.Azure.GetAzureBlob(),
+	), nil
+}
+
+func (r *inspectRunner) RunService(ctx context.Context, cfg subcmd.ServiceConfig, logger *slog.Logger) error {
+	inspectCfg := cfg.(*config.InspectServiceConfig)
+
+	i, err := inspect.NewService(ctx, inspectCfg, logger)
+	if err != nil {
+		return fmt.Errorf("inspect initialization failed: %w", err)
+	}
+
+	if err = i.Run(ctx); err != nil {
+		return fmt.Errorf("inspect failed: %w", err)
+	}
+
+	return nil
+}
+
+func newInspectHelpFunction(
+	appFlagSet,
+	inspectFlagSet,
+	compressionFlagSet,
+	encryptionFlagSet,
+	secretAgentFlagSet,
+	awsFlagSet,
+	gcpFlagSet,
+	azureFlagSet *pflag.FlagSet,
+) func() {
+	return func() {
+		fmt.Println(inspectWelcomeMessage)
+		fmt.Println(strings.Repeat("-", len(inspectWelcomeMessage)))
+		fmt.Println(flags.SectionTextUsageInspect)
+
+		// Print section: App Flags
+		fmt.Println(flags.SectionTextGeneral)
+		appFlagSet.PrintDefaults()
+
+		// Print section: Inspect Flags
+		fmt.Println(flags.SectionTextInspect)
+		inspectFlagSet.PrintDefaults()
+
+		// Print section: Compression Flags
+		fmt.Println(flags.SectionTextCompression)
+		compressionFlagSet.PrintDefaults()
+
+		// Print section: Encryption Flags
+		fmt.Println(flags.SectionTextEncryption)
+		encryptionFlagSet.PrintDefaults()
+
+		// Print section: Secret Agent Flags
+		fmt.Println(flags.SectionTextSecretAgentRestore)
+		secretAgentFlagSet.PrintDefaults()
+
+		// Print section: AWS Flags
+		fmt.Println(flags.SectionTextAWS)
+		awsFlagSet.PrintDefaults()
+
+		// Print section: GCP Flags
+		fmt.Println(flags.SectionTextGCP)
+		gcpFlagSet.PrintDefaults()
+
+		// Print section: Azure Flags
+		fmt.Println(flags.SectionTextAzure)
+		azureFlagSet.PrintDefaults()
+	}
+}
@@ -20,10 +20,12 @@ import (
 	"log"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/aerospike/absctl/internal/config"
 	"github.com/aerospike/absctl/internal/flags"
 	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/report"
 	"github.com/aerospike/absctl/internal/restore"
 	"github.com/aerospike/absctl/internal/subcmd"
 	"github.com/spf13/cobra"
@@ -38,9 +40,14 @@ const (
 type restoreRunner struct {
 	flagsRestore *flags.Restore
 	flagsCommon  *flags.Common
+	flagsSftp    *flags.Sftp
 
 	commonFlagSet  *pflag.FlagSet
 	restoreFlagSet *pflag.FlagSet
+	sftpFlagSet    *pflag.FlagSet
+
+	appVersion string
+	commitHash string
 }
 
 // NewRestoreCmd builds the top-level "restore" command for scan-based restores.
@@ -49,6 +56,9 @@ func NewRestoreCmd(
 ) (*cobra.Command, *subcmd.SharedFlags) {
 	r := &restoreRunner{
 		flagsRestore: flags.NewRestore(),
+		flagsSftp:    flags.NewSftp(flags.OperationRestore),
+		appVersion:   appVersion,
+		commitHash:   commitHash,
 	}
 	r.flagsCommon = flags.NewCommon(&r.flagsRestore.Common, flags.OperationRestore)
 
@@ -62,10 +72,12 @@ func NewRestoreCmd(
 func (r *restoreRunner) FlagSets() []*pflag.FlagSet {
 	r.commonFlagSet = r.flagsCommon.NewFlagSet()
 	r.restoreFlagSet = r.flagsRestore.NewFlagSet()
+	r.sftpFlagSet = r.flagsSftp.NewFlagSet()
 
 	return []*pflag.FlagSet{
 		r.commonFlagSet,
 		r.restoreFlagSet,
+		r.sftpFlagSet,
 	}
 }
 
@@ -90,6 +102,7 @@ func (r *restoreRunner) SetHelpUsage(cmd *cobra.Command, shared *subcmd.SharedFl
 		shared.Aws,
 		shared.Gcp,
 		shared.Azure,
+		r.sftpFlagSet,
 	)
 
 	cmd.SetUsageFunc(func(_ *cobra.Command) error {
@@ -126,6 +139,7 @@ func (r *restoreRunner) NewServiceConfig(ctx context.Context, shared *subcmd.Sha
 			shared.Aws.GetAwsS3(),
 			shared.Gcp.GetGcpStorage(),
 			shared.Azure.GetAzureBlob(),
+			r.flagsSftp.GetSftp(),
 		)
 		if err != nil {
 			return nil, err
@@ -141,22 +155,39 @@ func (r *restoreRunner) NewServiceConfig(ctx context.Context, shared *subcmd.Sha
 
 func (r *restoreRunner) RunService(ctx context.Context, cfg subcmd.ServiceConfig, logger *slog.Logger) error {
 	restoreCfg := cfg.(*config.RestoreServiceConfig)
+	start := time.Now()
+
+	asr, err := runRestore(ctx, restoreCfg, logger)
+
+	app := restoreCfg.GetApp()
+	if app == nil || app.ReportFile == "" {
+		return err
+	}
 
+	rep, reportErr := report.NewRestore(restoreCfg, asr.Stats(), asr.Results(), err,
+		start, r.appVersion, r.commitHash)
+
+	return writeReport(app.ReportFile, rep, reportErr, err, logger)
+}
+
+// runRestore runs the restore and returns the service, so the stats can be reported even if the restore failed.
+func runRestore(ctx context.Context, cfg *config.RestoreServiceConfig, logger *slog.Logger,
+) (*restore.Service, error) {
 	logMsg := "restore"
-	if restoreCfg.Restore.ValidateOnly {
+	if cfg.Restore.ValidateOnly {
 		logMsg = "validation"
 	}
 
-	asr, err := restore.NewService(ctx, restoreCfg, logger)
+	asr, err := restore.NewService(ctx, cfg, logger)
 	if err != nil {
-		return fmt.Errorf("%s initialization failed: %w", logMsg, err)
+		return nil, fmt.Errorf("%s initialization failed: %w", logMsg, err)
 	}
 
 	if err = asr.Run(ctx); err != nil {
-		return fmt.Errorf("%s failed: %w", logMsg, err)
+		return asr, fmt.Errorf("%s failed: %w", logMsg, err)
 	}
 
-	return nil
+	return asr, nil
 }
 
 func newRestoreHelpFunction(
@@ -170,7 +201,8 @@ func newRestoreHelpFunction(
 	secretAgentFlagSet,
 	awsFlagSet,
 	gcpFlagSet,
-	azureFlagSet *pflag.FlagSet,
+	azureFlagSet,
+	sftpFlagSet *pflag.FlagSet,
 ) func() {
 	return func() {
 		fmt.Println(restoreWelcomeMessage)
@@ -214,5 +246,9 @@ func newRestoreHelpFunction(
 		// Print section: Azure Flags
 		fmt.Println(flags.SectionTextAzure)
 		azureFlagSet.PrintDefaults()
+
+		// Print section: SFTP Flags
+		fmt.Println(flags.SectionTextSftp)
+		sftpFlagSet.PrintDefaults()
 	}
 }
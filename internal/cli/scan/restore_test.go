@@ -78,11 +78,12 @@ func TestNewRestoreCmd_RunnerImplementsRunner(t *testing.T) {
 
 	r := &restoreRunner{
 		flagsRestore: flags.NewRestore(),
+		flagsSftp:    flags.NewSftp(flags.OperationRestore),
 	}
 	r.flagsCommon = flags.NewCommon(&r.flagsRestore.Common, flags.OperationRestore)
 
 	sets := r.FlagSets()
-	require.Len(t, sets, 2)
+	require.Len(t, sets, 3)
 	for _, fs := range sets {
 		assert.NotNil(t, fs)
 	}
@@ -99,7 +100,7 @@ func TestNewRestoreHelpFunction_Output(t *testing.T) {
 	helpFn := newRestoreHelpFunction(
 		appFS, appFS, appFS,
 		appFS, appFS, appFS, appFS, appFS,
-		appFS, appFS, appFS,
+		appFS, appFS, appFS, appFS,
 	)
 
 	out := captureStdout(t, helpFn)
@@ -0,0 +1,179 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/flags"
+	"github.com/aerospike/absctl/internal/subcmd"
+	"github.com/aerospike/absctl/internal/transcode"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	transcodeWelcomeMessage      = "Welcome to the Aerospike backup transcode CLI tool!"
+	transcodeWelcomeMessageShort = "Aerospike backup transcode CLI tool"
+)
+
+type transcodeRunner struct {
+	flagsTranscode *flags.Transcode
+
+	transcodeFlagSet *pflag.FlagSet
+
+	appVersion string
+	commitHash string
+}
+
+// NewTranscodeCmd builds the top-level "transcode" command that rewrites the files of a backup
+// with another compression or encryption key.
+func NewTranscodeCmd(
+	flagsRoot *flags.Root, appVersion, commitHash, buildTime string,
+) (*cobra.Command, *subcmd.SharedFlags) {
+	r := &transcodeRunner{
+		flagsTranscode: flags.NewTranscode(),
+		appVersion:     appVersion,
+		commitHash:     commitHash,
+	}
+
+	// The transcoded backup is written with the backup storage, compression and encryption flags.
+	return subcmd.BuildCommand(
+		"transcode", transcodeWelcomeMessageShort, transcodeWelcomeMessage,
+		flagsRoot, appVersion, commitHash, buildTime,
+		flags.OperationBackup, r,
+	)
+}
+
+func (r *transcodeRunner) FlagSets() []*pflag.FlagSet {
+	r.transcodeFlagSet = r.flagsTranscode.NewFlagSet()
+
+	return []*pflag.FlagSet{
+		r.transcodeFlagSet,
+	}
+}
+
+func (r *transcodeRunner) PostRegistration(_ *cobra.Command) {}
+
+func (r *transcodeRunner) SetHelpUsage(cmd *cobra.Command, shared *subcmd.SharedFlagSets) {
+	helpFunc := newTranscodeHelpFunction(
+		shared.App,
+		r.transcodeFlagSet,
+		shared.Compression,
+		shared.Encryption,
+		shared.SecretAgent,
+		shared.Aws,
+		shared.Gcp,
+		shared.Azure,
+	)
+
+	cmd.SetUsageFunc(func(_ *cobra.Command) error {
+		helpFunc()
+		return nil
+	})
+	cmd.SetHelpFunc(func(_ *cobra.Command, _ []string) {
+		helpFunc()
+	})
+}
+
+func (r *transcodeRunner) NewServiceConfig(_ context.Context, shared *subcmd.SharedFlags,
+) (subcmd.ServiceConfig, error) {
+	app := shared.App.GetApp()
+	if app != nil && app.ConfigFilePath != "" {
+		return nil, errors.New("config file is not supported by transcode command")
+	}
+
+	return config.NewTranscodeServiceConfig(
+		app,
+		r.flagsTranscode.GetTranscode(),
+		shared.Compression.GetCompression(),
+		shared.Encryption.GetEncryption(),
+		shared.SecretAgent.GetSecretAgent(),
+		shared.Aws.GetAwsS3(),
+		shared.Gcp.GetGcpStorage(),
+		shared.Azure.GetAzureBlob(),
+	), nil
+}
+
+func (r *transcodeRunner) RunService(ctx context.Context, cfg subcmd.ServiceConfig, logger *slog.Logger) error {
+	transcodeCfg := cfg.(*config.TranscodeServiceConfig)
+
+	t, err := transcode.NewService(ctx, transcodeCfg, logger)
+	if err != nil {
+		return fmt.Errorf("transcode initialization failed: %w", err)
+	}
+
+	t.SetBuildInfo(r.appVersion, r.commitHash)
+
+	if err = t.Run(ctx); err != nil {
+		return fmt.Errorf("transcode failed: %w", err)
+	}
+
+	return nil
+}
+
+func newTranscodeHelpFunction(
+	appFlagSet,
+	transcodeFlagSet,
+	compressionFlagSet,
+	encryptionFlagSet,
+	secretAgentFlagSet,
+	awsFlagSet,
+	gcpFlagSet,
+	azureFlagSet *pflag.FlagSet,
+) func() {
+	return func() {
+		fmt.Println(transcodeWelcomeMessage)
+		fmt.Println(strings.Repeat("-", len(transcodeWelcomeMessage)))
+		fmt.Println(flags.SectionTextUsageTranscode)
+
+		// Print section: App Flags
+		fmt.Println(flags.SectionTextGeneral)
+		appFlagSet.PrintDefaults()
+
+		// Print section: Transcode Flags
+		fmt.Println(flags.SectionTextTranscode)
+		transcodeFlagSet.PrintDefaults()
+
+		// Print section: Compression Flags
+		fmt.Println(flags.SectionTextCompression)
+		compressionFlagSet.PrintDefaults()
+
+		// Print section: Encryption Flags
+		fmt.Println(flags.SectionTextEncryption)
+		encryptionFlagSet.PrintDefaults()
+
+		// Print section: Secret Agent Flags
+		fmt.Println(flags.SectionTextSecretAgentBackup)
+		secretAgentFlagSet.PrintDefaults()
+
+		// Print section: AWS Flags
+		fmt.Println(flags.SectionTextAWS)
+		awsFlagSet.PrintDefaults()
+
+		// Print section: GCP Flags
+		fmt.Println(flags.SectionTextGCP)
+		gcpFlagSet.PrintDefaults()
+
+		// Print section: Azure Flags
+		fmt.Println(flags.SectionTextAzure)
+		azureFlagSet.PrintDefaults()
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/flags"
+	"github.com/aerospike/absctl/internal/subcmd"
+	"github.com/aerospike/absctl/internal/verify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	verifyWelcomeMessage      = "Welcome to the Aerospike backup verify CLI tool!"
+	verifyWelcomeMessageShort = "Aerospike backup verify CLI tool"
+)
+
+type verifyRunner struct {
+	flagsVerify *flags.Verify
+
+	verifyFlagSet *pflag.FlagSet
+}
+
+// NewVerifyCmd builds the top-level "verify" command that checks backup files against the backup manifest.
+func NewVerifyCmd(
+	flagsRoot *flags.Root, appVersion, commitHash, buildTime string,
+) (*cobra.Command, *subcmd.SharedFlags) {
+	r := &verifyRunner{
+		flagsVerify: flags.NewVerify(),
+	}
+
+	return subcmd.BuildCommand(
+		"verify", verifyWelcomeMessageShort, verifyWelcomeMessage,
+		flagsRoot, appVersion, commitHash, buildTime,
+		flags.OperationRestore, r,
+	)
+}
+
+func (r *verifyRunner) FlagSets() []*pflag.FlagSet {
+	r.verifyFlagSet = r.flagsVerify.NewFlagSet()
+
+	return []*pflag.FlagSet{
+		r.verifyFlagSet,
+	}
+}
+
+func (r *verifyRunner) PostRegistration(_ *cobra.Command) {}
+
+func (r *verifyRunner) SetHelpUsage(cmd *cobra.Command, shared *subcmd.SharedFlagSets) {
+	helpFunc := newVerifyHelpFunction(
+		shared.App,
+		r.verifyFlagSet,
+		shared.Compression,
+		shared.Encryption,
+		shared.SecretAgent,
+		shared.Aws,
+		shared.Gcp,
+		shared.Azure,
+	)
+
+	cmd.SetUsageFunc(func(_ *cobra.Command) error {
+		helpFunc()
+		return nil
+	})
+	cmd.SetHelpFunc(func(_ *cobra.Command, _ []string) {
+		helpFunc()
+	})
+}
+
+func (r *verifyRunner) NewServiceConfig(_ context.Context, shared *subcmd.SharedFlags,
+) (subcmd.ServiceConfig, error) {
+	app := shared.App.GetApp()
+	if app != nil && app.ConfigFilePath != "" {
+		return nil, errors.New("config file is not supported by verify command")
+	}
+
+	return config.NewVerifyServiceConfig(
+		app,
+		r.flagsVerify.GetVerify(),
+		shared.Compression.GetCompression(),
+		shared.Encryption.GetEncryption(),
+		shared.SecretAgent.GetSecretAgent(),
+		shared.Aws.GetAwsS3(),
+		shared.Gcp.GetGcpStorage(),
+		shared.Azure.GetAzureBlob(),
+	), nil
+}
+
+func (r *verifyRunner) RunService(ctx context.Context, cfg subcmd.ServiceConfig, logger *slog.Logger) error {
+	verifyCfg := cfg.(*config.VerifyServiceConfig)
+
+	v, err := verify.NewService(ctx, verifyCfg, logger)
+	if err != nil {
+		return fmt.Errorf("verify initialization failed: %w", err)
+	}
+
+	if err = v.Run(ctx); err != nil {
+		return fmt.Errorf("verify failed: %w", err)
+	}
+
+	return nil
+}
+
+func newVerifyHelpFunction(
+	appFlagSet,
+	verifyFlagSet,
+	compressionFlagSet,
+	encryptionFlagSet,
+	secretAgentFlagSet,
+	awsFlagSet,
+	gcpFlagSet,
+	azureFlagSet *pflag.FlagSet,
+) func() {
+	return func() {
+		fmt.Println(verifyWelcomeMessage)
+		fmt.Println(strings.Repeat("-", len(verifyWelcomeMessage)))
+		fmt.Println(flags.SectionTextUsageVerify)
+
+		// Print section: App Flags
+		fmt.Println(flags.SectionTextGeneral)
+		appFlagSet.PrintDefaults()
+
+		// Print section: Verify Flags
+		fmt.Println(flags.SectionTextVerify)
+		verifyFlagSet.PrintDefaults()
+
+		// Print section: Compression Flags
+		fmt.Println(flags.SectionTextCompression)
+		compressionFlagSet.PrintDefaults()
+
+		// Print section: Encryption Flags
+		fmt.Println(flags.SectionTextEncryption)
+		encryptionFlagSet.PrintDefaults()
+
+		// Print section: Secret Agent Flags
+		fmt.Println(flags.SectionTextSecretAgentRestore)
+		secretAgentFlagSet.PrintDefaults()
+
+		// Print section: AWS Flags
+		fmt.Println(flags.SectionTextAWS)
+		awsFlagSet.PrintDefaults()
+
+		// Print section: GCP Flags
+		fmt.Println(flags.SectionTextGCP)
+		gcpFlagSet.PrintDefaults()
+
+		// Print section: Azure Flags
+		fmt.Println(flags.SectionTextAzure)
+		azureFlagSet.PrintDefaults()
+	}
+}
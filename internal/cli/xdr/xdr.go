@@ -148,6 +148,7 @@ func (c *Cmd) run(cmd *cobra.Command, _ []string) error {
 		c.flagsAws.GetAwsS3(),
 		c.flagsGcp.GetGcpStorage(),
 		c.flagsAzure.GetAzureBlob(),
+		nil,
 		c.flagsLocal.GetLocal(),
 	)
 	if err != nil {
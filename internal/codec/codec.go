@@ -0,0 +1,122 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	"github.com/aerospike/backup-go/io/encryption"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+// NewReader decrypts and decompresses a backup file, in the reverse order of backup.
+// encryptionKey is nil if the file is not encrypted. ZSTD is the only compression mode.
+// Closing the returned reader releases the decompression decoder, it doesn't close r.
+func NewReader(r io.Reader, encryptionKey []byte, compressed bool) (io.ReadCloser, error) {
+	if encryptionKey != nil {
+		// The caller closes r, the decryption reader is not closed.
+		decrypted, err := encryption.NewEncryptedReader(io.NopCloser(r), encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create decryption reader: %w", err)
+		}
+
+		r = decrypted
+	}
+
+	if compressed {
+		decompressed, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create decompression reader: %w", err)
+		}
+
+		// Closing it closes the decoder, which stops its goroutines.
+		return decompressed.IOReadCloser(), nil
+	}
+
+	return io.NopCloser(r), nil
+}
+
+// asbxFileNumberEnd is the end of the file number in the .asbx header, it follows the version byte.
+const asbxFileNumberEnd = 9
+
+// NewASBXDecoder returns a decoder of a decrypted and decompressed .asbx file.
+// The decoder checks the file number of the header, it is read from the header itself,
+// so files are decoded whatever their name.
+func NewASBXDecoder(r io.Reader, name string) (*asbx.Decoder[*bModels.ASBXToken], error) {
+	buffered := bufio.NewReader(r)
+
+	head, err := buffered.Peek(asbxFileNumberEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s header: %w", name, err)
+	}
+
+	return asbx.NewDecoder[*bModels.ASBXToken](buffered, binary.BigEndian.Uint64(head[1:asbxFileNumberEnd]), name)
+}
+
+// NewWriter compresses and encrypts a backup file, in the same order as on backup.
+// encryptionKey is nil if the file is not encrypted, compression is nil if it is not compressed.
+// Closing the returned writer flushes the compressed data and closes w.
+func NewWriter(w io.WriteCloser, encryptionKey []byte, compression *backup.CompressionPolicy) (io.WriteCloser, error) {
+	file := &writeCloser{Writer: w, closer: w}
+
+	if encryptionKey != nil {
+		encrypted, err := encryption.NewWriter(w, encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create encryption writer: %w", err)
+		}
+
+		// The encryption writer closes w.
+		file.Writer, file.closer = encrypted, encrypted
+	}
+
+	if compression != nil {
+		compressed, err := zstd.NewWriter(file.Writer,
+			zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(compression.Level)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create compression writer: %w", err)
+		}
+
+		file.Writer, file.compressor = compressed, compressed
+	}
+
+	return file, nil
+}
+
+// writeCloser is a compressing and encrypting writer.
+type writeCloser struct {
+	io.Writer
+
+	// compressor is nil if the file is not compressed.
+	compressor *zstd.Encoder
+	// closer closes the encryption writer or the underlying writer.
+	closer io.Closer
+}
+
+// Close flushes the compressed data and closes the underlying writer.
+func (w *writeCloser) Close() error {
+	var err error
+	if w.compressor != nil {
+		err = w.compressor.Close()
+	}
+
+	return errors.Join(err, w.closer.Close())
+}
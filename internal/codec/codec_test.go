@@ -0,0 +1,106 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/require"
+)
+
+// nopWriteCloser counts the calls to Close.
+type nopWriteCloser struct {
+	bytes.Buffer
+
+	closed int
+}
+
+func (w *nopWriteCloser) Close() error {
+	w.closed++
+	return nil
+}
+
+func TestNewWriter_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("Version 3.1\n# namespace test\n# first-file\n")
+
+	tests := []struct {
+		name        string
+		compression *backup.CompressionPolicy
+	}{
+		{
+			name: "Plain",
+		},
+		{
+			name:        "Compressed",
+			compression: backup.NewCompressionPolicy("ZSTD", 3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := &nopWriteCloser{}
+
+			w, err := NewWriter(out, nil, tt.compression)
+			require.NoError(t, err)
+
+			_, err = w.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			require.Equal(t, 1, out.closed)
+
+			r, err := NewReader(bytes.NewReader(out.Bytes()), nil, tt.compression != nil)
+			require.NoError(t, err)
+			defer r.Close()
+
+			got, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, data, got)
+		})
+	}
+}
+
+func TestNewASBXDecoder(t *testing.T) {
+	t.Parallel()
+
+	key, aErr := aerospike.NewKey("test", "set1", "key1")
+	require.NoError(t, aErr)
+
+	encoder := asbx.NewEncoder[*bModels.ASBXToken]("test")
+
+	// The file number in the name does not match the header, the header is used.
+	data := bytes.NewBuffer(encoder.GetHeader(7, false))
+	require.NoError(t, encoder.EncodeToken(bModels.NewASBXToken(key, []byte("payload")), data))
+
+	decoder, err := NewASBXDecoder(data, "test_0.asbx")
+	require.NoError(t, err)
+
+	token, err := decoder.NextToken()
+	require.NoError(t, err)
+	require.Equal(t, key.Digest(), token.Key.Digest())
+	require.Equal(t, []byte("payload"), token.Payload)
+
+	_, err = decoder.NextToken()
+	require.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// StreamingReader wraps backup.StreamingReader and decrypts and decompresses the files it streams,
+// so they are read as plain backup files.
+type StreamingReader struct {
+	backup.StreamingReader
+
+	// encryptionKey is nil if the files are not encrypted.
+	encryptionKey []byte
+	compressed    bool
+}
+
+// NewStreamingReader returns a StreamingReader that decodes the files of r.
+// encryptionKey is nil if the files are not encrypted. ZSTD is the only compression mode.
+func NewStreamingReader(r backup.StreamingReader, encryptionKey []byte, compressed bool) *StreamingReader {
+	return &StreamingReader{
+		StreamingReader: r,
+		encryptionKey:   encryptionKey,
+		compressed:      compressed,
+	}
+}
+
+// StreamFiles streams the decoded files of the wrapped reader.
+func (r *StreamingReader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) {
+	defer close(readersCh)
+
+	filesCh := make(chan bModels.File)
+
+	go r.StreamingReader.StreamFiles(ctx, filesCh, errorsCh, skipPrefixes)
+
+	r.decodeFiles(ctx, filesCh, readersCh, errorsCh)
+}
+
+// StreamFile streams the decoded file at the path.
+func (r *StreamingReader) StreamFile(
+	ctx context.Context, filename string, readersCh chan<- bModels.File, errorsCh chan<- error,
+) {
+	filesCh := make(chan bModels.File, 1)
+
+	go func() {
+		defer close(filesCh)
+
+		r.StreamingReader.StreamFile(ctx, filename, filesCh, errorsCh)
+	}()
+
+	r.decodeFiles(ctx, filesCh, readersCh, errorsCh)
+}
+
+// decodeFiles sends the files of filesCh to readersCh, with readers of their decoded content.
+// After an error the other files are closed, so the wrapped reader is not blocked.
+func (r *StreamingReader) decodeFiles(
+	ctx context.Context, filesCh <-chan bModels.File, readersCh chan<- bModels.File, errorsCh chan<- error,
+) {
+	var failed bool
+
+	for file := range filesCh {
+		if failed {
+			_ = file.Reader.Close()
+			continue
+		}
+
+		decoded, err := NewReader(file.Reader, r.encryptionKey, r.compressed)
+		if err != nil {
+			_ = file.Reader.Close()
+			failed = true
+
+			select {
+			case <-ctx.Done():
+			case errorsCh <- fmt.Errorf("failed to decode file %s: %w", file.Name, err):
+			}
+
+			continue
+		}
+
+		file.Reader = &readCloser{ReadCloser: decoded, file: file.Reader}
+
+		select {
+		case <-ctx.Done():
+			_ = file.Reader.Close()
+			failed = true
+		case readersCh <- file:
+		}
+	}
+}
+
+// readCloser reads the decoded content of a file, and closes the decoder and the file.
+type readCloser struct {
+	io.ReadCloser
+
+	file io.Closer
+}
+
+func (r *readCloser) Close() error {
+	return errors.Join(r.ReadCloser.Close(), r.file.Close())
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/require"
+)
+
+// filesReader streams the files in the map.
+type filesReader struct {
+	backup.StreamingReader
+
+	files map[string][]byte
+}
+
+func (r *filesReader) StreamFiles(ctx context.Context, readersCh chan<- bModels.File, _ chan<- error, _ []string) {
+	defer close(readersCh)
+
+	for name := range r.files {
+		r.StreamFile(ctx, name, readersCh, nil)
+	}
+}
+
+func (r *filesReader) StreamFile(_ context.Context, filename string, readersCh chan<- bModels.File, _ chan<- error) {
+	readersCh <- bModels.File{Name: filename, Reader: io.NopCloser(bytes.NewReader(r.files[filename]))}
+}
+
+func TestStreamingReader(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("Version 3.1\n# namespace test\n# first-file\n")
+
+	out := &nopWriteCloser{}
+	w, err := NewWriter(out, nil, backup.NewCompressionPolicy("ZSTD", 3))
+	require.NoError(t, err)
+
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	r := NewStreamingReader(&filesReader{files: map[string][]byte{
+		"a.asb": out.Bytes(),
+		"b.asb": out.Bytes(),
+	}}, nil, true)
+
+	readersCh := make(chan bModels.File)
+	errorsCh := make(chan error, 1)
+
+	go r.StreamFiles(t.Context(), readersCh, errorsCh, nil)
+
+	var names []string
+
+	for file := range readersCh {
+		got, err := io.ReadAll(file.Reader)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+		require.NoError(t, file.Reader.Close())
+
+		names = append(names, file.Name)
+	}
+
+	require.ElementsMatch(t, []string{"a.asb", "b.asb"}, names)
+	require.Empty(t, errorsCh)
+
+	// A single file is decoded as well.
+	fileCh := make(chan bModels.File, 1)
+	r.StreamFile(t.Context(), "a.asb", fileCh, errorsCh)
+
+	got, err := io.ReadAll((<-fileCh).Reader)
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+}
+
+func TestStreamingReader_Error(t *testing.T) {
+	t.Parallel()
+
+	// The file is not a zstd stream.
+	r := NewStreamingReader(&filesReader{files: map[string][]byte{"a.asb": []byte("Version 3.1\n")}}, nil, true)
+
+	readersCh := make(chan bModels.File, 1)
+	errorsCh := make(chan error, 1)
+
+	r.StreamFile(t.Context(), "a.asb", readersCh, errorsCh)
+
+	select {
+	case err := <-errorsCh:
+		require.ErrorContains(t, err, "failed to decode file a.asb")
+	case file := <-readersCh:
+		_, err := io.ReadAll(file.Reader)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// Backup file extensions.
+const (
+	ExtASB  = ".asb"
+	ExtASBX = ".asbx"
+)
+
+// IsBackupFile checks if the file is an .asb or .asbx file.
+func IsBackupFile(name string) bool {
+	ext := path.Ext(name)
+	return ext == ExtASB || ext == ExtASBX
+}
+
+// IsASBX checks if the file is an .asbx file.
+func IsASBX(name string) bool {
+	return path.Ext(name) == ExtASBX
+}
+
+// TokenDecoder decodes tokens from .asb or .asbx files.
+type TokenDecoder[T any] interface {
+	NextToken() (T, error)
+}
+
+// DecodeTokens passes the tokens of the decoder to fn, until the end of the file or an error.
+func DecodeTokens[T any](decoder TokenDecoder[T], fn func(T) error) error {
+	for {
+		token, err := decoder.NextToken()
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to decode: %w", err)
+		}
+
+		if err = fn(token); err != nil {
+			return err
+		}
+	}
+}
+
+// StreamFiles streams the files of the reader one by one to fn, until fn returns an error.
+// The error of fn is returned as is.
+func StreamFiles(ctx context.Context, reader backup.StreamingReader, fn func(bModels.File) error) error {
+	// Stop streaming files on error.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	readersCh := make(chan bModels.File)
+	errorsCh := make(chan error, 1)
+
+	go reader.StreamFiles(ctx, readersCh, errorsCh, nil)
+
+	for readersCh != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err, ok := <-errorsCh:
+			if !ok {
+				errorsCh = nil
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+		case file, ok := <-readersCh:
+			if !ok {
+				readersCh = nil
+				continue
+			}
+
+			if err := fn(file); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceDecoder decodes the tokens of the slice.
+type sliceDecoder struct {
+	tokens []int
+}
+
+func (d *sliceDecoder) NextToken() (int, error) {
+	if len(d.tokens) == 0 {
+		return 0, io.EOF
+	}
+
+	token := d.tokens[0]
+	d.tokens = d.tokens[1:]
+
+	return token, nil
+}
+
+func TestIsBackupFile(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsBackupFile("dir/test_0.asb"))
+	assert.True(t, IsBackupFile("0_test_1.asbx"))
+	assert.False(t, IsBackupFile("manifest.json"))
+	assert.False(t, IsBackupFile("test.asb.state"))
+
+	assert.True(t, IsASBX("dir/0_test_1.asbx"))
+	assert.False(t, IsASBX("test_0.asb"))
+}
+
+func TestDecodeTokens(t *testing.T) {
+	t.Parallel()
+
+	var tokens []int
+
+	err := DecodeTokens(&sliceDecoder{tokens: []int{1, 2, 3}}, func(token int) error {
+		tokens = append(tokens, token)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, tokens)
+
+	errStop := errors.New("stop")
+
+	tokens = nil
+	err = DecodeTokens(&sliceDecoder{tokens: []int{1, 2, 3}}, func(token int) error {
+		tokens = append(tokens, token)
+		if token == 2 {
+			return errStop
+		}
+
+		return nil
+	})
+	require.ErrorIs(t, err, errStop)
+	assert.Equal(t, []int{1, 2}, tokens)
+}
+
+func TestStreamFiles(t *testing.T) {
+	t.Parallel()
+
+	reader := &filesReader{files: map[string][]byte{"test_0.asb": nil, "test_1.asb": nil}}
+
+	var names []string
+
+	err := StreamFiles(t.Context(), reader, func(file bModels.File) error {
+		names = append(names, file.Name)
+		return file.Reader.Close()
+	})
+	require.NoError(t, err)
+
+	slices.Sort(names)
+	assert.Equal(t, []string{"test_0.asb", "test_1.asb"}, names)
+
+	// The error of fn stops streaming and is returned as is.
+	errStop := errors.New("stop")
+	err = StreamFiles(t.Context(), reader, func(bModels.File) error {
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+}
@@ -19,8 +19,10 @@ import (
 	"log/slog"
 	"path"
 	"runtime"
+	"slices"
 	"time"
 
+	"github.com/aerospike/absctl/internal/hooks"
 	"github.com/aerospike/absctl/internal/models"
 	"github.com/aerospike/backup-go"
 	"github.com/aerospike/tools-common-go/client"
@@ -57,6 +59,7 @@ func NewBackupServiceConfig(
 	awsS3 *models.AwsS3,
 	gcpStorage *models.GcpStorage,
 	azureBlob *models.AzureBlob,
+	sftp *models.Sftp,
 	local *models.Local,
 ) (*BackupServiceConfig, error) {
 	serviceConfig := &BackupServiceConfig{
@@ -72,6 +75,7 @@ func NewBackupServiceConfig(
 			awsS3,
 			gcpStorage,
 			azureBlob,
+			sftp,
 			local,
 		),
 	}
@@ -90,6 +94,11 @@ func (b *BackupServiceConfig) IsContinue() bool {
 	return b.Backup != nil && b.Backup.Continue != ""
 }
 
+// IsResumable checks if the backup keeps a state file to be continued automatically after an interruption.
+func (b *BackupServiceConfig) IsResumable() bool {
+	return b.Backup != nil && b.Backup.Resumable
+}
+
 // IsStopXDR checks if the backup operation should stop XDR by verifying that BackupXDR is non-nil and StopXDR is true.
 func (b *BackupServiceConfig) IsStopXDR() bool {
 	return b.BackupXDR != nil && b.BackupXDR.StopXDR
@@ -121,23 +130,100 @@ func (b *BackupServiceConfig) IsStdout() bool {
 	return false
 }
 
+// IsDryRun checks if the backup operation should only print the backup plan.
+func (b *BackupServiceConfig) IsDryRun() bool {
+	return b.Backup != nil && b.Backup.DryRun
+}
+
 // Validate validates the backup configuration and returns an error if any validation fails.
 func (b *BackupServiceConfig) Validate() error {
 	if err := b.Backup.Validate(); err != nil {
 		return err
 	}
 
+	// Exported files are read by analytics tools, that can't decompress or decrypt them.
+	if b.Backup.IsExport() && (b.Compression.Policy() != nil || b.Encryption.Policy() != nil) {
+		return fmt.Errorf("output-format %s is not allowed with compression or encryption", b.Backup.OutputFormat)
+	}
+
 	if err := b.BackupXDR.Validate(); err != nil {
 		return err
 	}
 
-	if err := b.ServiceConfigCommon.Validate(true); err != nil {
+	common := &b.ServiceConfigCommon
+
+	if destinations := b.Backup.DestinationList(); len(destinations) > 0 {
+		if err := b.validateDestinations(destinations); err != nil {
+			return err
+		}
+
+		// The storages are validated by destination, the rest of the configuration is validated once.
+		common = b.WithStorage(models.StorageLocal)
+	}
+
+	if err := common.Validate(true); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// validateDestinations validates the storage of each destination alone, as several cloud providers
+// may be configured to write the backup to all of them.
+func (b *BackupServiceConfig) validateDestinations(destinations []string) error {
+	for _, kind := range []string{models.StorageS3, models.StorageGCP, models.StorageAzure, models.StorageSftp} {
+		if b.isStorageConfigured(kind) && !slices.Contains(destinations, kind) {
+			return fmt.Errorf("%s storage is configured, but it is not in destinations", kind)
+		}
+	}
+
+	for _, kind := range destinations {
+		cfg := b.WithStorage(kind)
+		if !cfg.isStorageConfigured(kind) {
+			return fmt.Errorf("destination %s is not configured", kind)
+		}
+
+		if err := validateStorages(true, cfg.AwsS3, cfg.GcpStorage, cfg.AzureBlob, cfg.Sftp, cfg.Local); err != nil {
+			return fmt.Errorf("invalid destination %s: %w", kind, err)
+		}
+	}
+
+	return nil
+}
+
+// PrimaryStorage returns the configuration of the storage that existing files are read from,
+// like the state file or a previous backup. It is the first destination if destinations are set.
+func (b *BackupServiceConfig) PrimaryStorage() *ServiceConfigCommon {
+	if destinations := b.Backup.DestinationList(); len(destinations) > 0 {
+		return b.WithStorage(destinations[0])
+	}
+
+	return &b.ServiceConfigCommon
+}
+
+// GetHooks returns the hooks to run around the backup,
+// or nil for XDR backups and dry runs that have no hooks.
+func (b *BackupServiceConfig) GetHooks() *hooks.Config {
+	if b.Backup == nil || b.Backup.DryRun {
+		return nil
+	}
+
+	directory := b.Backup.Directory
+	if b.Backup.OutputFile != "" {
+		directory = b.Backup.OutputFile
+	}
+
+	return &hooks.Config{
+		PreHook:       b.Backup.PreHook,
+		PostHook:      b.Backup.PostHook,
+		OnFailureHook: b.Backup.OnFailureHook,
+		Operation:     "backup",
+		Namespace:     b.Backup.Namespace,
+		Directory:     directory,
+		StatsFile:     b.reportFile(),
+	}
+}
+
 // NewBackupConfigs creates and returns a new ConfigBackup and ConfigBackupXDR object,
 // initialized with given backup parameters.
 // This function sets various backup parameters including namespace, file limits, parallelism options, bandwidth,
@@ -70,6 +70,7 @@ func TestNewBackupServiceConfig_WithoutConfigFile(t *testing.T) {
 	awsS3 := &models.AwsS3{}
 	gcpStorage := &models.GcpStorage{}
 	azureBlob := &models.AzureBlob{}
+	sftp := &models.Sftp{}
 	local := &models.Local{}
 
 	config, _ := NewBackupServiceConfig(
@@ -84,6 +85,7 @@ func TestNewBackupServiceConfig_WithoutConfigFile(t *testing.T) {
 		awsS3,
 		gcpStorage,
 		azureBlob,
+		sftp,
 		local,
 	)
 
@@ -377,6 +379,94 @@ func TestBackupServiceConfig_IsStdout(t *testing.T) {
 	}
 }
 
+func TestBackupServiceConfig_Validate_OutputFormat(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(compression *models.Compression, encryption *models.Encryption) *BackupServiceConfig {
+		return &BackupServiceConfig{
+			Backup: &models.Backup{
+				OutputFormat: models.OutputFormatNDJSON,
+				Common:       models.Common{Namespace: "test", Directory: "/backups"},
+			},
+			ServiceConfigCommon: ServiceConfigCommon{
+				Compression: compression,
+				Encryption:  encryption,
+			},
+		}
+	}
+
+	err := newConfig(testCompression(), nil).Validate()
+	require.ErrorContains(t, err, "output-format ndjson is not allowed with compression or encryption")
+
+	err = newConfig(nil, testEncryption()).Validate()
+	require.ErrorContains(t, err, "output-format ndjson is not allowed with compression or encryption")
+
+	err = newConfig(&models.Compression{Mode: "NONE"}, nil).Validate()
+	require.NoError(t, err)
+}
+
+func TestBackupServiceConfig_Validate_Destinations(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(destinations string) *BackupServiceConfig {
+		return &BackupServiceConfig{
+			Backup: &models.Backup{
+				Destinations: destinations,
+				Common:       models.Common{Namespace: "test", Directory: "/backups"},
+			},
+			ServiceConfigCommon: ServiceConfigCommon{
+				AwsS3: &models.AwsS3{BucketName: "bucket", ChunkSize: 5},
+				AzureBlob: &models.AzureBlob{
+					ContainerName:     "container",
+					Endpoint:          "http://127.0.0.1:10000/devstoreaccount1",
+					BlockSize:         5,
+					UploadConcurrency: 1,
+				},
+			},
+		}
+	}
+
+	// Several cloud providers can be configured only as destinations.
+	err := newConfig("").Validate()
+	require.ErrorContains(t, err, "only one cloud provider can be configured")
+
+	err = newConfig("local,s3").Validate()
+	require.ErrorContains(t, err, "azure storage is configured, but it is not in destinations")
+
+	err = newConfig("s3,azure,gcp").Validate()
+	require.ErrorContains(t, err, "destination gcp is not configured")
+
+	cfg := newConfig("s3,azure")
+	cfg.AwsS3.ChunkSize = 1
+	require.ErrorContains(t, cfg.Validate(), "invalid destination s3: failed to validate aws s3")
+
+	// The options shared by the destinations are validated too.
+	cfg = newConfig("s3,azure")
+	cfg.Compression = &models.Compression{Mode: "LZ4"}
+	require.ErrorContains(t, cfg.Validate(), "invalid compression mode: LZ4")
+
+	require.NoError(t, newConfig("s3,azure").Validate())
+}
+
+func TestBackupServiceConfig_PrimaryStorage(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BackupServiceConfig{
+		Backup: &models.Backup{Destinations: "azure,s3"},
+		ServiceConfigCommon: ServiceConfigCommon{
+			AwsS3:     &models.AwsS3{BucketName: "bucket"},
+			AzureBlob: &models.AzureBlob{ContainerName: "container"},
+		},
+	}
+
+	primary := cfg.PrimaryStorage()
+	assert.Nil(t, primary.AwsS3)
+	assert.Equal(t, "container", primary.AzureBlob.ContainerName)
+
+	cfg.Backup.Destinations = ""
+	assert.Same(t, &cfg.ServiceConfigCommon, cfg.PrimaryStorage())
+}
+
 func TestNewBackupConfigs_RegularBackup(t *testing.T) {
 	t.Parallel()
 
@@ -32,6 +32,7 @@ type ServiceConfigCommon struct {
 	AwsS3        *models.AwsS3
 	GcpStorage   *models.GcpStorage
 	AzureBlob    *models.AzureBlob
+	Sftp         *models.Sftp
 	Local        *models.Local
 }
 
@@ -47,6 +48,7 @@ func NewServiceConfigCommon(
 	awsS3 *models.AwsS3,
 	gcpStorage *models.GcpStorage,
 	azureBlob *models.AzureBlob,
+	sftp *models.Sftp,
 	local *models.Local,
 ) *ServiceConfigCommon {
 	return &ServiceConfigCommon{
@@ -59,15 +61,66 @@ func NewServiceConfigCommon(
 		AwsS3:        awsS3,
 		GcpStorage:   gcpStorage,
 		AzureBlob:    azureBlob,
+		Sftp:         sftp,
 		Local:        local,
 	}
 }
 
+// reportFile returns the path to the run report, empty if the report is not written.
+func (r *ServiceConfigCommon) reportFile() string {
+	if r.App == nil {
+		return ""
+	}
+
+	return r.App.ReportFile
+}
+
 // GetApp returns the App configuration.
 func (r *ServiceConfigCommon) GetApp() *models.App {
 	return r.App
 }
 
+// WithStorage returns a copy of the configuration with only the storage of the kind configured,
+// one of models.StorageLocal, models.StorageS3, models.StorageGCP, models.StorageAzure or models.StorageSftp.
+func (r *ServiceConfigCommon) WithStorage(kind string) *ServiceConfigCommon {
+	cfg := *r
+
+	if kind != models.StorageS3 {
+		cfg.AwsS3 = nil
+	}
+
+	if kind != models.StorageGCP {
+		cfg.GcpStorage = nil
+	}
+
+	if kind != models.StorageAzure {
+		cfg.AzureBlob = nil
+	}
+
+	if kind != models.StorageSftp {
+		cfg.Sftp = nil
+	}
+
+	return &cfg
+}
+
+// isStorageConfigured checks if the remote storage of the kind is configured.
+// The local storage needs no configuration.
+func (r *ServiceConfigCommon) isStorageConfigured(kind string) bool {
+	switch kind {
+	case models.StorageS3:
+		return r.AwsS3.IsConfigured()
+	case models.StorageGCP:
+		return r.GcpStorage.IsConfigured()
+	case models.StorageAzure:
+		return r.AzureBlob.IsConfigured()
+	case models.StorageSftp:
+		return r.Sftp.IsConfigured()
+	default:
+		return true
+	}
+}
+
 // Validate validates the backup configuration and returns an error if any validation fails.
 func (r *ServiceConfigCommon) Validate(isBackup bool) error {
 	if err := validateStorages(
@@ -75,6 +128,7 @@ func (r *ServiceConfigCommon) Validate(isBackup bool) error {
 		r.AwsS3,
 		r.GcpStorage,
 		r.AzureBlob,
+		r.Sftp,
 		r.Local,
 	); err != nil {
 		return err
@@ -96,12 +150,13 @@ func (r *ServiceConfigCommon) Validate(isBackup bool) error {
 }
 
 // validateStorages performs storages validation.
-// As we allow only one cloud provider to be configured, we can check it here.
+// As we allow only one cloud provider or SFTP server to be configured, we can check it here.
 func validateStorages(
 	isBackup bool,
 	awsS3 *models.AwsS3,
 	gcpStorage *models.GcpStorage,
 	azureBlob *models.AzureBlob,
+	sftp *models.Sftp,
 	local *models.Local,
 ) error {
 	var count int
@@ -136,6 +191,14 @@ func validateStorages(
 		count++
 	}
 
+	if sftp.IsConfigured() {
+		if err := sftp.Validate(isBackup); err != nil {
+			return fmt.Errorf("failed to validate sftp: %w", err)
+		}
+
+		count++
+	}
+
 	if count > 1 {
 		return fmt.Errorf("only one cloud provider can be configured")
 	}
@@ -35,6 +35,7 @@ func TestValidateStorages(t *testing.T) {
 		awsS3      *models.AwsS3
 		gcpStorage *models.GcpStorage
 		azureBlob  *models.AzureBlob
+		sftp       *models.Sftp
 		local      *models.Local
 		wantErr    bool
 	}{
@@ -212,13 +213,52 @@ func TestValidateStorages(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:     "Valid SFTP configuration only",
+			isBackup: true,
+			sftp: &models.Sftp{
+				Host:       "sftp.example.com",
+				Port:       22,
+				User:       "backup",
+				KeyFile:    "/home/backup/.ssh/id_ed25519",
+				Path:       "/backups",
+				BufferSize: 4,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "SFTP without credentials",
+			isBackup: false,
+			sftp: &models.Sftp{
+				Host: "sftp.example.com",
+				Port: 22,
+				User: "backup",
+			},
+			wantErr: true,
+		},
+		{
+			name:     "SFTP and AWS S3 configured",
+			isBackup: false,
+			awsS3: &models.AwsS3{
+				Region:              "us-west-2",
+				BucketName:          testBucket,
+				RestorePollDuration: 1,
+			},
+			sftp: &models.Sftp{
+				Host:     "sftp.example.com",
+				Port:     22,
+				User:     "backup",
+				Password: "secret",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			err := validateStorages(tt.isBackup, tt.awsS3, tt.gcpStorage, tt.azureBlob, tt.local)
+			err := validateStorages(tt.isBackup, tt.awsS3, tt.gcpStorage, tt.azureBlob, tt.sftp, tt.local)
 			if tt.wantErr {
 				assert.Error(t, err, "Expected error but got none")
 			} else {
@@ -0,0 +1,69 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "github.com/aerospike/absctl/internal/models"
+
+// ConsolidateServiceConfig contains configuration settings for the consolidate service.
+// Backup files are merged offline, so no Aerospike client settings are required.
+type ConsolidateServiceConfig struct {
+	Consolidate *models.Consolidate
+
+	ServiceConfigCommon
+}
+
+// NewConsolidateServiceConfig creates and returns a new ConsolidateServiceConfig
+// initialized with the provided parameters.
+func NewConsolidateServiceConfig(
+	app *models.App,
+	consolidate *models.Consolidate,
+	compression *models.Compression,
+	encryption *models.Encryption,
+	secretAgent *models.SecretAgent,
+	awsS3 *models.AwsS3,
+	gcpStorage *models.GcpStorage,
+	azureBlob *models.AzureBlob,
+) *ConsolidateServiceConfig {
+	return &ConsolidateServiceConfig{
+		Consolidate: consolidate,
+		ServiceConfigCommon: *NewServiceConfigCommon(
+			app,
+			nil,
+			nil,
+			compression,
+			encryption,
+			secretAgent,
+			awsS3,
+			gcpStorage,
+			azureBlob,
+			nil,
+			nil,
+		),
+	}
+}
+
+// Validate validates the consolidate configuration and returns an error if any validation fails.
+func (c *ConsolidateServiceConfig) Validate() error {
+	if err := c.Consolidate.Validate(); err != nil {
+		return err
+	}
+
+	// The consolidated backup is written, so storages are validated as for backup.
+	if err := c.ServiceConfigCommon.Validate(true); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/aerospike/absctl/internal/models"
+)
+
+// CopyServiceConfig contains configuration settings for the copy service.
+// Backup files are copied as they are, so no Aerospike client or compression settings are required.
+// The encryption key is only used to check the signature of the manifest.
+type CopyServiceConfig struct {
+	Copy *models.Copy
+
+	ServiceConfigCommon
+}
+
+// NewCopyServiceConfig creates and returns a new CopyServiceConfig
+// initialized with the provided parameters.
+func NewCopyServiceConfig(
+	app *models.App,
+	cp *models.Copy,
+	encryption *models.Encryption,
+	secretAgent *models.SecretAgent,
+	awsS3 *models.AwsS3,
+	gcpStorage *models.GcpStorage,
+	azureBlob *models.AzureBlob,
+) *CopyServiceConfig {
+	return &CopyServiceConfig{
+		Copy: cp,
+		ServiceConfigCommon: *NewServiceConfigCommon(
+			app,
+			nil,
+			nil,
+			nil,
+			encryption,
+			secretAgent,
+			awsS3,
+			gcpStorage,
+			azureBlob,
+			nil,
+			nil,
+		),
+	}
+}
+
+// Validate validates the copy configuration and returns an error if any validation fails.
+// The storages of both paths are validated, each one alone, as they may be of different clouds.
+// Both are validated as for backup, as the command has the backup storage flags.
+func (c *CopyServiceConfig) Validate() error {
+	if err := c.Copy.Validate(); err != nil {
+		return err
+	}
+
+	from, to, err := c.Paths()
+	if err != nil {
+		return err
+	}
+
+	if err = c.StorageConfig(from).Validate(true); err != nil {
+		return fmt.Errorf("invalid from storage: %w", err)
+	}
+
+	if err = c.StorageConfig(to).Validate(true); err != nil {
+		return fmt.Errorf("invalid to storage: %w", err)
+	}
+
+	return nil
+}
+
+// Paths returns the parsed storage paths of the backup and of its copy.
+func (c *CopyServiceConfig) Paths() (from, to *models.StoragePath, err error) {
+	if from, err = models.ParseStoragePath(c.Copy.From); err != nil {
+		return nil, nil, fmt.Errorf("invalid from: %w", err)
+	}
+
+	if to, err = models.ParseStoragePath(c.Copy.To); err != nil {
+		return nil, nil, fmt.Errorf("invalid to: %w", err)
+	}
+
+	return from, to, nil
+}
+
+// StorageConfig returns the configuration of the storage of the path: only the cloud of the path
+// is configured, with the bucket or container of the path.
+func (c *CopyServiceConfig) StorageConfig(p *models.StoragePath) *ServiceConfigCommon {
+	cfg := c.ServiceConfigCommon
+	cfg.AwsS3, cfg.GcpStorage, cfg.AzureBlob = nil, nil, nil
+
+	switch p.Kind {
+	case models.StorageS3:
+		var awsS3 models.AwsS3
+		if c.AwsS3 != nil {
+			awsS3 = *c.AwsS3
+		}
+
+		awsS3.BucketName = p.Bucket
+		cfg.AwsS3 = &awsS3
+	case models.StorageGCP:
+		var gcpStorage models.GcpStorage
+		if c.GcpStorage != nil {
+			gcpStorage = *c.GcpStorage
+		}
+
+		gcpStorage.BucketName = p.Bucket
+		cfg.GcpStorage = &gcpStorage
+	case models.StorageAzure:
+		var azureBlob models.AzureBlob
+		if c.AzureBlob != nil {
+			azureBlob = *c.AzureBlob
+		}
+
+		azureBlob.ContainerName = p.Bucket
+		cfg.AzureBlob = &azureBlob
+	}
+
+	return &cfg
+}
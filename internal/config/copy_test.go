@@ -0,0 +1,62 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyServiceConfig_StorageConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewCopyServiceConfig(
+		&models.App{},
+		&models.Copy{From: "s3://source/backups/full", To: "az://copies/full", Parallel: 1},
+		&models.Encryption{},
+		&models.SecretAgent{},
+		&models.AwsS3{Region: "eu-west-1", ChunkSize: 5},
+		&models.GcpStorage{},
+		&models.AzureBlob{AccountName: "account"},
+	)
+
+	from, to, err := cfg.Paths()
+	require.NoError(t, err)
+
+	source := cfg.StorageConfig(from)
+	require.NotNil(t, source.AwsS3)
+	assert.Equal(t, "source", source.AwsS3.BucketName)
+	assert.Equal(t, "eu-west-1", source.AwsS3.Region)
+	assert.Nil(t, source.GcpStorage)
+	assert.Nil(t, source.AzureBlob)
+
+	destination := cfg.StorageConfig(to)
+	require.NotNil(t, destination.AzureBlob)
+	assert.Equal(t, "copies", destination.AzureBlob.ContainerName)
+	assert.Equal(t, "account", destination.AzureBlob.AccountName)
+	assert.Nil(t, destination.AwsS3)
+
+	// The flags are not changed.
+	assert.Empty(t, cfg.AwsS3.BucketName)
+	assert.Empty(t, cfg.AzureBlob.ContainerName)
+
+	local := cfg.StorageConfig(&models.StoragePath{Kind: models.StorageLocal, Path: "/backups"})
+	assert.Nil(t, local.AwsS3)
+	assert.Nil(t, local.GcpStorage)
+	assert.Nil(t, local.AzureBlob)
+}
@@ -38,6 +38,7 @@ type Backup struct {
 	Azure struct {
 		Blob AzureBlob `yaml:"blob"`
 	} `yaml:"azure"`
+	Sftp  Sftp `yaml:"sftp"`
 	Local struct {
 		Disk Local `yaml:"disk"`
 	} `yaml:"local"`
@@ -61,6 +62,7 @@ func DefaultBackup() *Backup {
 		Azure: struct {
 			Blob AzureBlob `yaml:"blob"`
 		}{Blob: defaultAzureBlob()},
+		Sftp: defaultSftp(),
 		Local: struct {
 			Disk Local `yaml:"disk"`
 		}{Disk: defaultLocal()},
@@ -92,12 +94,18 @@ func (b *Backup) ToModelBackup() *models.Backup {
 			InfoRetriesMultiplier:         derefFloat64(b.Backup.InfoRetriesMultiplier),
 			InfoRetryIntervalMilliseconds: derefInt64(b.Backup.InfoRetryIntervalMilliseconds),
 			StdBufferSize:                 derefInt(b.Backup.StdBufferSize),
+			ProgressInterval:              derefInt64(b.Backup.ProgressInterval),
+			PreHook:                       derefString(b.Backup.PreHook),
+			PostHook:                      derefString(b.Backup.PostHook),
+			OnFailureHook:                 derefString(b.Backup.OnFailureHook),
 		},
 		MaxRetries:          derefInt(b.Backup.MaxRetries),
 		OutputFile:          derefString(b.Backup.OutputFile),
+		OutputFormat:        derefString(b.Backup.OutputFormat),
 		RemoveFiles:         derefBool(b.Backup.RemoveFiles),
 		ModifiedBefore:      derefString(b.Backup.ModifiedBefore),
 		ModifiedAfter:       derefString(b.Backup.ModifiedAfter),
+		IncrementalFrom:     derefString(b.Backup.IncrementalFrom),
 		FileLimit:           derefUint64(b.Backup.FileLimit),
 		AfterDigest:         derefString(b.Backup.AfterDigest),
 		MaxRecords:          derefInt64(b.Backup.MaxRecords),
@@ -112,11 +120,16 @@ func (b *Backup) ToModelBackup() *models.Backup {
 		PartitionList:       strings.Join(b.Backup.PartitionList, ","),
 		Estimate:            derefBool(b.Backup.Estimate),
 		EstimateSamples:     derefInt64(b.Backup.EstimateSamples),
+		DryRun:              derefBool(b.Backup.DryRun),
 		StateFileDst:        derefString(b.Backup.StateFileDst),
 		Continue:            derefString(b.Backup.Continue),
+		Resumable:           derefBool(b.Backup.Resumable),
+		NoCheckpoint:        derefBool(b.Backup.NoCheckpoint),
 		ScanPageSize:        derefInt64(b.Backup.ScanPageSize),
 		OutputFilePrefix:    derefString(b.Backup.OutputFilePrefix),
 		RackList:            strings.Join(b.Backup.RackList, ","),
+		Destinations:        strings.Join(b.Backup.Destinations, ","),
+		MinDestinations:     derefInt(b.Backup.MinDestinations),
 	}
 }
 
@@ -130,7 +143,7 @@ func (b *Backup) LoadSecrets(ctx context.Context) error {
 	}
 
 	fields := collectSecretableFields(&b.Cluster, &b.Encryption,
-		&b.Aws.S3, &b.Azure.Blob, &b.Gcp.Storage)
+		&b.Aws.S3, &b.Azure.Blob, &b.Gcp.Storage, &b.Sftp)
 
 	return resolveSecretFields(ctx, saCfg, fields...)
 }
@@ -150,9 +163,11 @@ type BackupConfig struct {
 	SocketTimeout                 *int64   `yaml:"socket-timeout"`
 	Bandwidth                     *int64   `yaml:"bandwidth"`
 	OutputFile                    *string  `yaml:"output-file"`
+	OutputFormat                  *string  `yaml:"output-format"`
 	RemoveFiles                   *bool    `yaml:"remove-files"`
 	ModifiedBefore                *string  `yaml:"modified-before"`
 	ModifiedAfter                 *string  `yaml:"modified-after"`
+	IncrementalFrom               *string  `yaml:"incremental-from"`
 	FileLimit                     *uint64  `yaml:"file-limit"`
 	AfterDigest                   *string  `yaml:"after-digest"`
 	MaxRecords                    *int64   `yaml:"max-records"`
@@ -167,16 +182,25 @@ type BackupConfig struct {
 	PartitionList                 []string `yaml:"partition-list"`
 	Estimate                      *bool    `yaml:"estimate"`
 	EstimateSamples               *int64   `yaml:"estimate-samples"`
+	DryRun                        *bool    `yaml:"dry-run"`
 	StateFileDst                  *string  `yaml:"state-file-dst"`
 	Continue                      *string  `yaml:"continue"`
+	Resumable                     *bool    `yaml:"resumable"`
+	NoCheckpoint                  *bool    `yaml:"no-checkpoint"`
 	ScanPageSize                  *int64   `yaml:"scan-page-size"`
 	OutputFilePrefix              *string  `yaml:"output-file-prefix"`
 	RackList                      []string `yaml:"rack-list"`
+	Destinations                  []string `yaml:"destinations"`
+	MinDestinations               *int     `yaml:"min-destinations"`
 	InfoTimeout                   *int64   `yaml:"info-timeout"`
 	InfoMaxRetries                *uint    `yaml:"info-max-retries"`
 	InfoRetriesMultiplier         *float64 `yaml:"info-retry-multiplier"`
 	InfoRetryIntervalMilliseconds *int64   `yaml:"info-retry-interval"`
 	StdBufferSize                 *int     `yaml:"std-buffer"`
+	ProgressInterval              *int64   `yaml:"progress-interval"`
+	PreHook                       *string  `yaml:"pre-hook"`
+	PostHook                      *string  `yaml:"post-hook"`
+	OnFailureHook                 *string  `yaml:"on-failure-hook"`
 }
 
 func defaultBackupConfig() BackupConfig {
@@ -197,10 +221,16 @@ func defaultBackupConfig() BackupConfig {
 		InfoRetryIntervalMilliseconds: new(models.DefaultCommonInfoRetryInterval),
 		Bandwidth:                     new(models.DefaultCommonBandwidth),
 		StdBufferSize:                 new(models.DefaultCommonStdBufferSize),
+		ProgressInterval:              new(models.DefaultCommonProgressInterval),
+		PreHook:                       new(models.DefaultCommonPreHook),
+		PostHook:                      new(models.DefaultCommonPostHook),
+		OnFailureHook:                 new(models.DefaultCommonOnFailureHook),
 		OutputFile:                    new(models.DefaultBackupOutputFile),
+		OutputFormat:                  new(models.DefaultBackupOutputFormat),
 		RemoveFiles:                   new(models.DefaultBackupRemoveFiles),
 		ModifiedBefore:                new(models.DefaultBackupModifiedBefore),
 		ModifiedAfter:                 new(models.DefaultBackupModifiedAfter),
+		IncrementalFrom:               new(models.DefaultBackupIncrementalFrom),
 		FileLimit:                     new(models.DefaultBackupFileLimit),
 		AfterDigest:                   new(models.DefaultBackupAfterDigest),
 		MaxRecords:                    new(models.DefaultBackupMaxRecords),
@@ -215,11 +245,16 @@ func defaultBackupConfig() BackupConfig {
 		PartitionList:                 []string{},
 		Estimate:                      new(models.DefaultBackupEstimate),
 		EstimateSamples:               new(models.DefaultBackupEstimateSamples),
+		DryRun:                        new(models.DefaultBackupDryRun),
 		StateFileDst:                  new(models.DefaultBackupStateFileDst),
 		Continue:                      new(models.DefaultBackupContinue),
+		Resumable:                     new(models.DefaultBackupResumable),
+		NoCheckpoint:                  new(models.DefaultBackupNoCheckpoint),
 		ScanPageSize:                  new(models.DefaultBackupScanPageSize),
 		OutputFilePrefix:              new(models.DefaultBackupOutputFilePrefix),
 		RackList:                      []string{},
+		Destinations:                  []string{},
+		MinDestinations:               new(models.DefaultBackupMinDestinations),
 		TotalTimeout:                  new(models.DefaultBackupTotalTimeout),
 		Parallel:                      new(models.DefaultBackupParallel),
 	}
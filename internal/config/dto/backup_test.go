@@ -58,10 +58,16 @@ func TestDefaultBackupConfig(t *testing.T) {
 	assert.Equal(t, models.DefaultCommonInfoRetryInterval, derefInt64(config.InfoRetryIntervalMilliseconds))
 	assert.Equal(t, models.DefaultCommonBandwidth, derefInt64(config.Bandwidth))
 	assert.Equal(t, models.DefaultCommonStdBufferSize, derefInt(config.StdBufferSize))
+	assert.Equal(t, models.DefaultCommonProgressInterval, derefInt64(config.ProgressInterval))
+	assert.Equal(t, models.DefaultCommonPreHook, derefString(config.PreHook))
+	assert.Equal(t, models.DefaultCommonPostHook, derefString(config.PostHook))
+	assert.Equal(t, models.DefaultCommonOnFailureHook, derefString(config.OnFailureHook))
 	assert.Equal(t, models.DefaultBackupOutputFile, derefString(config.OutputFile))
+	assert.Equal(t, models.DefaultBackupOutputFormat, derefString(config.OutputFormat))
 	assert.Equal(t, models.DefaultBackupRemoveFiles, derefBool(config.RemoveFiles))
 	assert.Equal(t, models.DefaultBackupModifiedBefore, derefString(config.ModifiedBefore))
 	assert.Equal(t, models.DefaultBackupModifiedAfter, derefString(config.ModifiedAfter))
+	assert.Equal(t, models.DefaultBackupIncrementalFrom, derefString(config.IncrementalFrom))
 	assert.Equal(t, models.DefaultBackupFileLimit, derefUint64(config.FileLimit))
 	assert.Equal(t, models.DefaultBackupAfterDigest, derefString(config.AfterDigest))
 	assert.Equal(t, models.DefaultBackupMaxRecords, derefInt64(config.MaxRecords))
@@ -76,8 +82,11 @@ func TestDefaultBackupConfig(t *testing.T) {
 	assert.Empty(t, config.PartitionList)
 	assert.Equal(t, models.DefaultBackupEstimate, derefBool(config.Estimate))
 	assert.Equal(t, models.DefaultBackupEstimateSamples, derefInt64(config.EstimateSamples))
+	assert.Equal(t, models.DefaultBackupDryRun, derefBool(config.DryRun))
 	assert.Equal(t, models.DefaultBackupStateFileDst, derefString(config.StateFileDst))
 	assert.Equal(t, models.DefaultBackupContinue, derefString(config.Continue))
+	assert.Equal(t, models.DefaultBackupResumable, derefBool(config.Resumable))
+	assert.Equal(t, models.DefaultBackupNoCheckpoint, derefBool(config.NoCheckpoint))
 	assert.Equal(t, models.DefaultBackupScanPageSize, derefInt64(config.ScanPageSize))
 	assert.Equal(t, models.DefaultBackupOutputFilePrefix, derefString(config.OutputFilePrefix))
 	assert.Empty(t, config.RackList)
@@ -104,10 +113,16 @@ func TestBackupConfig_ToModelBackup(t *testing.T) {
 		InfoRetriesMultiplier:         new(1.5),
 		InfoRetryIntervalMilliseconds: new(int64(1000)),
 		StdBufferSize:                 new(4096),
+		ProgressInterval:              new(int64(30)),
+		PreHook:                       new("pre.sh"),
+		PostHook:                      new("post.sh"),
+		OnFailureHook:                 new("failure.sh"),
 		OutputFile:                    new("output.asb"),
+		OutputFormat:                  new("ndjson"),
 		RemoveFiles:                   new(true),
 		ModifiedBefore:                new("2024-01-01"),
 		ModifiedAfter:                 new("2023-01-01"),
+		IncrementalFrom:               new("/backup/full"),
 		FileLimit:                     new(uint64(100)),
 		AfterDigest:                   new("digest123"),
 		MaxRecords:                    new(int64(1000000)),
@@ -122,8 +137,11 @@ func TestBackupConfig_ToModelBackup(t *testing.T) {
 		PartitionList:                 []string{"0", "1", "2"},
 		Estimate:                      new(false),
 		EstimateSamples:               new(int64(5000)),
+		DryRun:                        new(true),
 		StateFileDst:                  new("/state"),
 		Continue:                      new("/cont"),
+		Resumable:                     new(true),
+		NoCheckpoint:                  new(true),
 		ScanPageSize:                  new(int64(2500)),
 		OutputFilePrefix:              new("prefix-"),
 		RackList:                      []string{"rack-a"},
@@ -152,10 +170,16 @@ func TestBackupConfig_ToModelBackup(t *testing.T) {
 	assert.InEpsilon(t, 1.5, model.InfoRetriesMultiplier, 0.0)
 	assert.Equal(t, int64(1000), model.InfoRetryIntervalMilliseconds)
 	assert.Equal(t, 4096, model.StdBufferSize)
+	assert.Equal(t, int64(30), model.ProgressInterval)
+	assert.Equal(t, "pre.sh", model.PreHook)
+	assert.Equal(t, "post.sh", model.PostHook)
+	assert.Equal(t, "failure.sh", model.OnFailureHook)
 	assert.Equal(t, "output.asb", model.OutputFile)
+	assert.Equal(t, "ndjson", model.OutputFormat)
 	assert.True(t, model.RemoveFiles)
 	assert.Equal(t, "2024-01-01", model.ModifiedBefore)
 	assert.Equal(t, "2023-01-01", model.ModifiedAfter)
+	assert.Equal(t, "/backup/full", model.IncrementalFrom)
 	assert.Equal(t, uint64(100), model.FileLimit)
 	assert.Equal(t, "digest123", model.AfterDigest)
 	assert.Equal(t, int64(1000000), model.MaxRecords)
@@ -170,8 +194,11 @@ func TestBackupConfig_ToModelBackup(t *testing.T) {
 	assert.Equal(t, "0,1,2", model.PartitionList)
 	assert.False(t, model.Estimate)
 	assert.Equal(t, int64(5000), model.EstimateSamples)
+	assert.True(t, model.DryRun)
 	assert.Equal(t, "/state", model.StateFileDst)
 	assert.Equal(t, "/cont", model.Continue)
+	assert.True(t, model.Resumable)
+	assert.True(t, model.NoCheckpoint)
 	assert.Equal(t, int64(2500), model.ScanPageSize)
 	assert.Equal(t, "prefix-", model.OutputFilePrefix)
 	assert.Equal(t, "rack-a", model.RackList)
@@ -228,10 +255,14 @@ func TestBackup_ToModelBackup_DefaultToModel(t *testing.T) {
 	assert.InEpsilon(t, models.DefaultCommonInfoRetriesMultiplier, model.InfoRetriesMultiplier, 0.0)
 	assert.Equal(t, models.DefaultCommonInfoRetryInterval, model.InfoRetryIntervalMilliseconds)
 	assert.Equal(t, models.DefaultCommonStdBufferSize, model.StdBufferSize)
+	assert.Equal(t, models.DefaultCommonProgressInterval, model.ProgressInterval)
+	assert.Empty(t, model.PreHook)
 	assert.Equal(t, models.DefaultBackupOutputFile, model.OutputFile)
+	assert.Equal(t, models.DefaultBackupOutputFormat, model.OutputFormat)
 	assert.Equal(t, models.DefaultBackupRemoveFiles, model.RemoveFiles)
 	assert.Equal(t, models.DefaultBackupModifiedBefore, model.ModifiedBefore)
 	assert.Equal(t, models.DefaultBackupModifiedAfter, model.ModifiedAfter)
+	assert.Equal(t, models.DefaultBackupIncrementalFrom, model.IncrementalFrom)
 	assert.Equal(t, models.DefaultBackupFileLimit, model.FileLimit)
 	assert.Equal(t, models.DefaultBackupAfterDigest, model.AfterDigest)
 	assert.Equal(t, models.DefaultBackupMaxRecords, model.MaxRecords)
@@ -243,8 +274,11 @@ func TestBackup_ToModelBackup_DefaultToModel(t *testing.T) {
 	assert.Equal(t, models.DefaultBackupNoTTLOnly, model.NoTTLOnly)
 	assert.Equal(t, models.DefaultBackupEstimate, model.Estimate)
 	assert.Equal(t, models.DefaultBackupEstimateSamples, model.EstimateSamples)
+	assert.Equal(t, models.DefaultBackupDryRun, model.DryRun)
 	assert.Equal(t, models.DefaultBackupStateFileDst, model.StateFileDst)
 	assert.Equal(t, models.DefaultBackupContinue, model.Continue)
+	assert.Equal(t, models.DefaultBackupResumable, model.Resumable)
+	assert.Equal(t, models.DefaultBackupNoCheckpoint, model.NoCheckpoint)
 	assert.Equal(t, models.DefaultBackupScanPageSize, model.ScanPageSize)
 	assert.Equal(t, models.DefaultBackupOutputFilePrefix, model.OutputFilePrefix)
 }
@@ -31,6 +31,10 @@ type App struct {
 	LogLevel *string `yaml:"log-level"`
 	LogJSON  *bool   `yaml:"log-json"`
 	LogFile  *string `yaml:"log-file"`
+
+	MetricsListen   *string `yaml:"metrics-listen"`
+	MetricsTextfile *string `yaml:"metrics-textfile"`
+	ReportFile      *string `yaml:"report-file"`
 }
 
 // defaultApp creates a new App with default values.
@@ -40,6 +44,10 @@ func defaultApp() App {
 		LogLevel: new(models.DefaultAppLogLevel),
 		LogJSON:  new(models.DefaultAppLogJSON),
 		LogFile:  new(models.DefaultAppLogFile),
+
+		MetricsListen:   new(models.DefaultAppMetricsListen),
+		MetricsTextfile: new(models.DefaultAppMetricsTextfile),
+		ReportFile:      new(models.DefaultAppReportFile),
 	}
 }
 
@@ -49,6 +57,10 @@ func (a *App) ToModelApp() *models.App {
 		LogLevel: derefString(a.LogLevel),
 		LogJSON:  derefBool(a.LogJSON),
 		LogFile:  derefString(a.LogFile),
+
+		MetricsListen:   derefString(a.MetricsListen),
+		MetricsTextfile: derefString(a.MetricsTextfile),
+		ReportFile:      derefString(a.ReportFile),
 	}
 }
 
@@ -586,6 +598,56 @@ func (a *AzureBlob) ToModelAzureBlob() *models.AzureBlob {
 	}
 }
 
+type Sftp struct {
+	Host           *string `yaml:"host"`
+	Port           *int    `yaml:"port"`
+	User           *string `yaml:"user"`
+	Password       *string `yaml:"password"` // #nosec G117
+	KeyFile        *string `yaml:"key-file"`
+	Key            *string `yaml:"key"`
+	KeyPassphrase  *string `yaml:"key-passphrase"`
+	KnownHosts     *string `yaml:"known-hosts"`
+	Path           *string `yaml:"path"`
+	ConnectTimeout *int64  `yaml:"connect-timeout"`
+	BufferSize     *int    `yaml:"buffer-size"`
+}
+
+func defaultSftp() Sftp {
+	return Sftp{
+		Host:           new(models.DefaultSftpHost),
+		Port:           new(models.DefaultSftpPort),
+		User:           new(models.DefaultSftpUser),
+		Password:       new(models.DefaultSftpPassword),
+		KeyFile:        new(models.DefaultSftpKeyFile),
+		Key:            new(models.DefaultSftpKey),
+		KeyPassphrase:  new(models.DefaultSftpKeyPassphrase),
+		KnownHosts:     new(models.DefaultSftpKnownHosts),
+		Path:           new(models.DefaultSftpPath),
+		ConnectTimeout: new(models.DefaultSftpConnectTimeout),
+		BufferSize:     new(models.DefaultSftpBufferSize),
+	}
+}
+
+func (s *Sftp) ToModelSftp() *models.Sftp {
+	if s == nil {
+		return nil
+	}
+
+	return &models.Sftp{
+		Host:           derefString(s.Host),
+		Port:           derefInt(s.Port),
+		User:           derefString(s.User),
+		Password:       derefString(s.Password),
+		KeyFile:        derefString(s.KeyFile),
+		Key:            derefString(s.Key),
+		KeyPassphrase:  derefString(s.KeyPassphrase),
+		KnownHosts:     derefString(s.KnownHosts),
+		Path:           derefString(s.Path),
+		ConnectTimeout: derefInt64(s.ConnectTimeout),
+		BufferSize:     derefInt(s.BufferSize),
+	}
+}
+
 type Local struct {
 	BufferSize int `yaml:"buffer-size"`
 }
@@ -17,6 +17,7 @@ package dto
 import (
 	"testing"
 
+	"github.com/aerospike/absctl/internal/models"
 	"github.com/aerospike/tools-common-go/flags"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -389,3 +390,44 @@ func TestCluster_ToModelClientPolicy(t *testing.T) {
 		assert.Equal(t, int64(0), policy.LoginTimeout)
 	})
 }
+
+func TestSftp_ToModelSftp(t *testing.T) {
+	t.Run("nil sftp returns nil", func(t *testing.T) {
+		var s *Sftp
+
+		assert.Nil(t, s.ToModelSftp())
+	})
+
+	t.Run("default sftp", func(t *testing.T) {
+		s := defaultSftp()
+
+		model := s.ToModelSftp()
+
+		require.NotNil(t, model)
+		assert.False(t, model.IsConfigured())
+		assert.Equal(t, models.DefaultSftpPort, model.Port)
+		assert.Equal(t, models.DefaultSftpConnectTimeout, model.ConnectTimeout)
+		assert.Equal(t, models.DefaultSftpBufferSize, model.BufferSize)
+	})
+
+	t.Run("sftp with values", func(t *testing.T) {
+		s := &Sftp{
+			Host:    new("sftp.example.com"),
+			Port:    new(2222),
+			User:    new("backup"),
+			KeyFile: new("/home/backup/.ssh/id_ed25519"),
+			Path:    new("/backups"),
+		}
+
+		model := s.ToModelSftp()
+
+		require.NotNil(t, model)
+		assert.Equal(t, "sftp.example.com", model.Host)
+		assert.Equal(t, 2222, model.Port)
+		assert.Equal(t, "backup", model.User)
+		assert.Equal(t, "/home/backup/.ssh/id_ed25519", model.KeyFile)
+		assert.Equal(t, "/backups", model.Path)
+		assert.Empty(t, model.Password)
+		assert.Zero(t, model.BufferSize)
+	})
+}
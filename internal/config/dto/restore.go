@@ -38,6 +38,7 @@ type Restore struct {
 	Azure struct {
 		Blob AzureBlob `yaml:"blob"`
 	} `yaml:"azure"`
+	Sftp Sftp `yaml:"sftp"`
 }
 
 // DefaultRestore returns a Restore with default values.
@@ -58,6 +59,7 @@ func DefaultRestore() *Restore {
 		Azure: struct {
 			Blob AzureBlob `yaml:"blob"`
 		}{Blob: defaultAzureBlob()},
+		Sftp: defaultSftp(),
 	}
 }
 
@@ -86,10 +88,16 @@ func (r *Restore) ToModelRestore() *models.Restore {
 			InfoRetriesMultiplier:         derefFloat64(r.Restore.InfoRetriesMultiplier),
 			InfoRetryIntervalMilliseconds: derefInt64(r.Restore.InfoRetryIntervalMilliseconds),
 			StdBufferSize:                 derefInt(r.Restore.StdBufferSize),
+			ProgressInterval:              derefInt64(r.Restore.ProgressInterval),
+			PreHook:                       derefString(r.Restore.PreHook),
+			PostHook:                      derefString(r.Restore.PostHook),
+			OnFailureHook:                 derefString(r.Restore.OnFailureHook),
 		},
 		InputFile:          derefString(r.Restore.InputFile),
 		DirectoryList:      strings.Join(r.Restore.DirectoryList, ","),
 		ParentDirectory:    derefString(r.Restore.ParentDirectory),
+		BackupRoot:         derefString(r.Restore.BackupRoot),
+		PointInTime:        derefString(r.Restore.PointInTime),
 		DisableBatchWrites: derefBool(r.Restore.DisableBatchWrites),
 		BatchSize:          derefInt(r.Restore.BatchSize),
 		MaxAsyncBatches:    derefInt(r.Restore.MaxAsyncBatches),
@@ -104,6 +112,19 @@ func (r *Restore) ToModelRestore() *models.Restore {
 		RetryMaxAttempts:   derefUint(r.Restore.RetryMaxAttempts),
 		ValidateOnly:       derefBool(r.Restore.ValidateOnly),
 		ApplyMetadataLast:  derefBool(r.Restore.ApplyMetadataLast),
+		FilterExpression:   derefString(r.Restore.FilterExpression),
+		SetMap:             derefString(r.Restore.SetMap),
+		BinMap:             derefString(r.Restore.BinMap),
+		DigestFile:         derefString(r.Restore.DigestFile),
+		KeyFile:            derefString(r.Restore.KeyFile),
+		RejectDir:          derefString(r.Restore.RejectDir),
+		StateFileDst:       derefString(r.Restore.StateFileDst),
+		Continue:           derefString(r.Restore.Continue),
+		InputFormat:        derefString(r.Restore.InputFormat),
+		KeyField:           derefString(r.Restore.KeyField),
+		SetField:           derefString(r.Restore.SetField),
+		SetName:            derefString(r.Restore.SetName),
+		BinFields:          derefString(r.Restore.BinFields),
 	}
 }
 
@@ -117,7 +138,7 @@ func (r *Restore) LoadSecrets(ctx context.Context) error {
 	}
 
 	fields := collectSecretableFields(&r.Cluster, &r.Encryption,
-		&r.Aws.S3, &r.Azure.Blob, &r.Gcp.Storage)
+		&r.Aws.S3, &r.Azure.Blob, &r.Gcp.Storage, &r.Sftp)
 
 	return resolveSecretFields(ctx, saCfg, fields...)
 }
@@ -138,6 +159,8 @@ type RestoreConfig struct {
 	InputFile                     *string  `yaml:"input-file"`
 	DirectoryList                 []string `yaml:"directory-list"`
 	ParentDirectory               *string  `yaml:"parent-directory"`
+	BackupRoot                    *string  `yaml:"backup-root"`
+	PointInTime                   *string  `yaml:"point-in-time"`
 	DisableBatchWrites            *bool    `yaml:"disable-batch-writes"`
 	BatchSize                     *int     `yaml:"batch-size"`
 	MaxAsyncBatches               *int     `yaml:"max-async-batches"`
@@ -156,7 +179,24 @@ type RestoreConfig struct {
 	InfoRetriesMultiplier         *float64 `yaml:"info-retry-multiplier"`
 	InfoRetryIntervalMilliseconds *int64   `yaml:"info-retry-interval"`
 	ApplyMetadataLast             *bool    `yaml:"apply-metadata-last"`
+	FilterExpression              *string  `yaml:"filter-exp"`
+	SetMap                        *string  `yaml:"set-map"`
+	BinMap                        *string  `yaml:"bin-map"`
+	DigestFile                    *string  `yaml:"digest-file"`
+	KeyFile                       *string  `yaml:"key-file"`
+	RejectDir                     *string  `yaml:"reject-dir"`
+	StateFileDst                  *string  `yaml:"state-file-dst"`
+	Continue                      *string  `yaml:"continue"`
+	InputFormat                   *string  `yaml:"input-format"`
+	KeyField                      *string  `yaml:"key-field"`
+	SetField                      *string  `yaml:"set-field"`
+	SetName                       *string  `yaml:"set-name"`
+	BinFields                     *string  `yaml:"bin-fields"`
 	StdBufferSize                 *int     `yaml:"std-buffer"`
+	ProgressInterval              *int64   `yaml:"progress-interval"`
+	PreHook                       *string  `yaml:"pre-hook"`
+	PostHook                      *string  `yaml:"post-hook"`
+	OnFailureHook                 *string  `yaml:"on-failure-hook"`
 }
 
 func defaultRestoreConfig() RestoreConfig {
@@ -176,11 +216,17 @@ func defaultRestoreConfig() RestoreConfig {
 		InfoRetryIntervalMilliseconds: new(models.DefaultCommonInfoRetryInterval),
 		Bandwidth:                     new(models.DefaultCommonBandwidth),
 		StdBufferSize:                 new(models.DefaultCommonStdBufferSize),
+		ProgressInterval:              new(models.DefaultCommonProgressInterval),
+		PreHook:                       new(models.DefaultCommonPreHook),
+		PostHook:                      new(models.DefaultCommonPostHook),
+		OnFailureHook:                 new(models.DefaultCommonOnFailureHook),
 		TotalTimeout:                  new(models.DefaultRestoreTotalTimeout),
 		Parallel:                      new(models.DefaultRestoreParallel),
 		InputFile:                     new(models.DefaultRestoreInputFile),
 		DirectoryList:                 []string{},
 		ParentDirectory:               new(models.DefaultRestoreParentDirectory),
+		BackupRoot:                    new(models.DefaultRestoreBackupRoot),
+		PointInTime:                   new(models.DefaultRestorePointInTime),
 		DisableBatchWrites:            new(models.DefaultRestoreDisableBatchWrites),
 		BatchSize:                     new(models.DefaultRestoreBatchSize),
 		MaxAsyncBatches:               new(models.DefaultRestoreMaxAsyncBatches),
@@ -195,5 +241,18 @@ func defaultRestoreConfig() RestoreConfig {
 		RetryMaxAttempts:              new(models.DefaultRestoreRetryMaxAttempts),
 		ValidateOnly:                  new(models.DefaultRestoreValidateOnly),
 		ApplyMetadataLast:             new(models.DefaultRestoreApplyMetadataLast),
+		FilterExpression:              new(models.DefaultRestoreFilterExpression),
+		SetMap:                        new(models.DefaultRestoreSetMap),
+		BinMap:                        new(models.DefaultRestoreBinMap),
+		DigestFile:                    new(models.DefaultRestoreDigestFile),
+		KeyFile:                       new(models.DefaultRestoreKeyFile),
+		RejectDir:                     new(models.DefaultRestoreRejectDir),
+		StateFileDst:                  new(models.DefaultRestoreStateFileDst),
+		Continue:                      new(models.DefaultRestoreContinue),
+		InputFormat:                   new(models.DefaultRestoreInputFormat),
+		KeyField:                      new(models.DefaultRestoreKeyField),
+		SetField:                      new(models.DefaultRestoreSetField),
+		SetName:                       new(models.DefaultRestoreSetName),
+		BinFields:                     new(models.DefaultRestoreBinFields),
 	}
 }
@@ -56,10 +56,16 @@ func TestDefaultRestoreConfig(t *testing.T) {
 	assert.Equal(t, models.DefaultCommonInfoRetryInterval, derefInt64(config.InfoRetryIntervalMilliseconds))
 	assert.Equal(t, models.DefaultCommonBandwidth, derefInt64(config.Bandwidth))
 	assert.Equal(t, models.DefaultCommonStdBufferSize, derefInt(config.StdBufferSize))
+	assert.Equal(t, models.DefaultCommonProgressInterval, derefInt64(config.ProgressInterval))
+	assert.Equal(t, models.DefaultCommonPreHook, derefString(config.PreHook))
+	assert.Equal(t, models.DefaultCommonPostHook, derefString(config.PostHook))
+	assert.Equal(t, models.DefaultCommonOnFailureHook, derefString(config.OnFailureHook))
 	assert.Equal(t, models.DefaultRestoreTotalTimeout, derefInt64(config.TotalTimeout))
 	assert.Equal(t, models.DefaultRestoreInputFile, derefString(config.InputFile))
 	assert.Empty(t, config.DirectoryList)
 	assert.Equal(t, models.DefaultRestoreParentDirectory, derefString(config.ParentDirectory))
+	assert.Equal(t, models.DefaultRestoreBackupRoot, derefString(config.BackupRoot))
+	assert.Equal(t, models.DefaultRestorePointInTime, derefString(config.PointInTime))
 	assert.Equal(t, models.DefaultRestoreDisableBatchWrites, derefBool(config.DisableBatchWrites))
 	assert.Equal(t, models.DefaultRestoreBatchSize, derefInt(config.BatchSize))
 	assert.Equal(t, models.DefaultRestoreMaxAsyncBatches, derefInt(config.MaxAsyncBatches))
@@ -74,6 +80,15 @@ func TestDefaultRestoreConfig(t *testing.T) {
 	assert.Equal(t, models.DefaultRestoreRetryMaxAttempts, derefUint(config.RetryMaxAttempts))
 	assert.Equal(t, models.DefaultRestoreValidateOnly, derefBool(config.ValidateOnly))
 	assert.Equal(t, models.DefaultRestoreApplyMetadataLast, derefBool(config.ApplyMetadataLast))
+	assert.Equal(t, models.DefaultRestoreFilterExpression, derefString(config.FilterExpression))
+	assert.Equal(t, models.DefaultRestoreSetMap, derefString(config.SetMap))
+	assert.Equal(t, models.DefaultRestoreBinMap, derefString(config.BinMap))
+	assert.Equal(t, models.DefaultRestoreDigestFile, derefString(config.DigestFile))
+	assert.Equal(t, models.DefaultRestoreKeyFile, derefString(config.KeyFile))
+	assert.Equal(t, models.DefaultRestoreRejectDir, derefString(config.RejectDir))
+	assert.Equal(t, models.DefaultRestoreStateFileDst, derefString(config.StateFileDst))
+	assert.Equal(t, models.DefaultRestoreContinue, derefString(config.Continue))
+	assert.Equal(t, models.DefaultRestoreInputFormat, derefString(config.InputFormat))
 }
 
 func TestRestoreConfig_ToModelRestore(t *testing.T) {
@@ -95,9 +110,15 @@ func TestRestoreConfig_ToModelRestore(t *testing.T) {
 		InfoRetriesMultiplier:         new(1.5),
 		InfoRetryIntervalMilliseconds: new(int64(1000)),
 		StdBufferSize:                 new(4096),
+		ProgressInterval:              new(int64(30)),
+		PreHook:                       new("pre.sh"),
+		PostHook:                      new("post.sh"),
+		OnFailureHook:                 new("failure.sh"),
 		InputFile:                     new("input.asb"),
 		DirectoryList:                 []string{"dir1", "dir2"},
 		ParentDirectory:               new("/parent"),
+		BackupRoot:                    new("/backups"),
+		PointInTime:                   new("2024-01-02_15:04:05"),
 		DisableBatchWrites:            new(true),
 		BatchSize:                     new(100),
 		MaxAsyncBatches:               new(32),
@@ -112,6 +133,17 @@ func TestRestoreConfig_ToModelRestore(t *testing.T) {
 		RetryMaxAttempts:              new(uint(10)),
 		ValidateOnly:                  new(false),
 		ApplyMetadataLast:             new(true),
+		FilterExpression:              new("kwGTUQKhYQE="),
+		SetMap:                        new("prod:qa"),
+		BinMap:                        new("a:b"),
+		DigestFile:                    new("digests.txt"),
+		KeyFile:                       new("keys.txt"),
+		RejectDir:                     new("rejects"),
+		StateFileDst:                  new("restore.state"),
+		InputFormat:                   new("ndjson"),
+		KeyField:                      new("id"),
+		SetField:                      new("type"),
+		BinFields:                     new("name,age:int"),
 	}
 
 	restore := &Restore{Restore: config}
@@ -136,9 +168,15 @@ func TestRestoreConfig_ToModelRestore(t *testing.T) {
 	assert.InEpsilon(t, 1.5, model.InfoRetriesMultiplier, 0.0)
 	assert.Equal(t, int64(1000), model.InfoRetryIntervalMilliseconds)
 	assert.Equal(t, 4096, model.StdBufferSize)
+	assert.Equal(t, int64(30), model.ProgressInterval)
+	assert.Equal(t, "pre.sh", model.PreHook)
+	assert.Equal(t, "post.sh", model.PostHook)
+	assert.Equal(t, "failure.sh", model.OnFailureHook)
 	assert.Equal(t, "input.asb", model.InputFile)
 	assert.Equal(t, "dir1,dir2", model.DirectoryList)
 	assert.Equal(t, "/parent", model.ParentDirectory)
+	assert.Equal(t, "/backups", model.BackupRoot)
+	assert.Equal(t, "2024-01-02_15:04:05", model.PointInTime)
 	assert.True(t, model.DisableBatchWrites)
 	assert.Equal(t, 100, model.BatchSize)
 	assert.Equal(t, 32, model.MaxAsyncBatches)
@@ -153,6 +191,17 @@ func TestRestoreConfig_ToModelRestore(t *testing.T) {
 	assert.Equal(t, uint(10), model.RetryMaxAttempts)
 	assert.False(t, model.ValidateOnly)
 	assert.True(t, model.ApplyMetadataLast)
+	assert.Equal(t, "kwGTUQKhYQE=", model.FilterExpression)
+	assert.Equal(t, "prod:qa", model.SetMap)
+	assert.Equal(t, "a:b", model.BinMap)
+	assert.Equal(t, "digests.txt", model.DigestFile)
+	assert.Equal(t, "keys.txt", model.KeyFile)
+	assert.Equal(t, "rejects", model.RejectDir)
+	assert.Equal(t, "restore.state", model.StateFileDst)
+	assert.Equal(t, "ndjson", model.InputFormat)
+	assert.Equal(t, "id", model.KeyField)
+	assert.Equal(t, "type", model.SetField)
+	assert.Equal(t, "name,age:int", model.BinFields)
 }
 
 func TestRestore_ToModelRestore_NilHandling(t *testing.T) {
@@ -199,8 +248,12 @@ func TestRestore_ToModelRestore_DefaultToModel(t *testing.T) {
 	assert.InEpsilon(t, models.DefaultCommonInfoRetriesMultiplier, model.InfoRetriesMultiplier, 0.0)
 	assert.Equal(t, models.DefaultCommonInfoRetryInterval, model.InfoRetryIntervalMilliseconds)
 	assert.Equal(t, models.DefaultCommonStdBufferSize, model.StdBufferSize)
+	assert.Equal(t, models.DefaultCommonProgressInterval, model.ProgressInterval)
+	assert.Empty(t, model.PreHook)
 	assert.Equal(t, models.DefaultRestoreInputFile, model.InputFile)
 	assert.Equal(t, models.DefaultRestoreParentDirectory, model.ParentDirectory)
+	assert.Equal(t, models.DefaultRestoreBackupRoot, model.BackupRoot)
+	assert.Equal(t, models.DefaultRestorePointInTime, model.PointInTime)
 	assert.Equal(t, models.DefaultRestoreDisableBatchWrites, model.DisableBatchWrites)
 	assert.Equal(t, models.DefaultRestoreBatchSize, model.BatchSize)
 	assert.Equal(t, models.DefaultRestoreMaxAsyncBatches, model.MaxAsyncBatches)
@@ -215,4 +268,13 @@ func TestRestore_ToModelRestore_DefaultToModel(t *testing.T) {
 	assert.Equal(t, models.DefaultRestoreRetryMaxAttempts, model.RetryMaxAttempts)
 	assert.Equal(t, models.DefaultRestoreValidateOnly, model.ValidateOnly)
 	assert.Equal(t, models.DefaultRestoreApplyMetadataLast, model.ApplyMetadataLast)
+	assert.Equal(t, models.DefaultRestoreFilterExpression, model.FilterExpression)
+	assert.Equal(t, models.DefaultRestoreSetMap, model.SetMap)
+	assert.Equal(t, models.DefaultRestoreBinMap, model.BinMap)
+	assert.Equal(t, models.DefaultRestoreDigestFile, model.DigestFile)
+	assert.Equal(t, models.DefaultRestoreKeyFile, model.KeyFile)
+	assert.Equal(t, models.DefaultRestoreRejectDir, model.RejectDir)
+	assert.Equal(t, models.DefaultRestoreStateFileDst, model.StateFileDst)
+	assert.Equal(t, models.DefaultRestoreContinue, model.Continue)
+	assert.Equal(t, models.DefaultRestoreInputFormat, model.InputFormat)
 }
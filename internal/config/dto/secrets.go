@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/aerospike/absctl/internal/secrets"
 	"github.com/aerospike/backup-go"
 )
 
@@ -32,6 +33,7 @@ func collectSecretableFields(
 	s3 *AwsS3,
 	azure *AzureBlob,
 	gcp *GcpStorage,
+	sftp *Sftp,
 ) []*string {
 	var fields []*string
 
@@ -75,6 +77,15 @@ func collectSecretableFields(
 		fields = append(fields, gcp.KeyFile, gcp.BucketName, gcp.EndpointOverride)
 	}
 
+	// SFTP.
+	if sftp != nil {
+		fields = append(fields,
+			sftp.Host, sftp.User,
+			sftp.Password, sftp.KeyFile,
+			sftp.Key, sftp.KeyPassphrase, sftp.Path,
+		)
+	}
+
 	return fields
 }
 
@@ -90,7 +101,7 @@ func resolveSecretFields(ctx context.Context, saCfg *backup.SecretAgentConfig, f
 			continue
 		}
 
-		resolved, err := backup.ParseSecret(ctx, saCfg, *field)
+		resolved, err := secrets.Resolve(ctx, saCfg, *field)
 		if err != nil {
 			return fmt.Errorf("failed to resolve secret %q: %w", *field, err)
 		}
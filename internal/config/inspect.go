@@ -0,0 +1,67 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "github.com/aerospike/absctl/internal/models"
+
+// InspectServiceConfig contains configuration settings for the inspect service.
+// Backup files are decoded offline, so no Aerospike client settings are required.
+type InspectServiceConfig struct {
+	Inspect *models.Inspect
+
+	ServiceConfigCommon
+}
+
+// NewInspectServiceConfig creates and returns a new InspectServiceConfig initialized with the provided parameters.
+func NewInspectServiceConfig(
+	app *models.App,
+	inspect *models.Inspect,
+	compression *models.Compression,
+	encryption *models.Encryption,
+	secretAgent *models.SecretAgent,
+	awsS3 *models.AwsS3,
+	gcpStorage *models.GcpStorage,
+	azureBlob *models.AzureBlob,
+) *InspectServiceConfig {
+	return &InspectServiceConfig{
+		Inspect: inspect,
+		ServiceConfigCommon: *NewServiceConfigCommon(
+			app,
+			nil,
+			nil,
+			compression,
+			encryption,
+			secretAgent,
+			awsS3,
+			gcpStorage,
+			azureBlob,
+			nil,
+			nil,
+		),
+	}
+}
+
+// Validate validates the inspect configuration and returns an error if any validation fails.
+func (i *InspectServiceConfig) Validate() error {
+	if err := i.Inspect.Validate(); err != nil {
+		return err
+	}
+
+	if err := i.ServiceConfigCommon.Validate(false); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -15,10 +15,12 @@
 package config
 
 import (
+	"fmt"
 	"log/slog"
 	"runtime"
 	"strings"
 
+	"github.com/aerospike/absctl/internal/hooks"
 	"github.com/aerospike/absctl/internal/models"
 	"github.com/aerospike/backup-go"
 	"github.com/aerospike/tools-common-go/client"
@@ -48,6 +50,7 @@ func NewRestoreServiceConfig(
 	awsS3 *models.AwsS3,
 	gcpStorage *models.GcpStorage,
 	azureBlob *models.AzureBlob,
+	sftp *models.Sftp,
 ) (*RestoreServiceConfig, error) {
 	serviceConfig := &RestoreServiceConfig{
 		Restore: restore,
@@ -61,6 +64,7 @@ func NewRestoreServiceConfig(
 			awsS3,
 			gcpStorage,
 			azureBlob,
+			sftp,
 			nil,
 		),
 	}
@@ -84,6 +88,11 @@ func (r *RestoreServiceConfig) Validate() error {
 		return err
 	}
 
+	// Imported files are converted before the restore decompresses or decrypts them.
+	if r.Restore.IsImport() && (r.Compression.Policy() != nil || r.Encryption.Policy() != nil) {
+		return fmt.Errorf("input-format %s is not allowed with compression or encryption", r.Restore.InputFormat)
+	}
+
 	if err := r.ServiceConfigCommon.Validate(false); err != nil {
 		return err
 	}
@@ -91,6 +100,36 @@ func (r *RestoreServiceConfig) Validate() error {
 	return nil
 }
 
+// GetHooks returns the hooks to run around the restore.
+func (r *RestoreServiceConfig) GetHooks() *hooks.Config {
+	if r.Restore == nil {
+		return nil
+	}
+
+	var directory string
+
+	switch {
+	case r.Restore.InputFile != "":
+		directory = r.Restore.InputFile
+	case r.Restore.DirectoryList != "":
+		directory = r.Restore.DirectoryList
+	case r.Restore.BackupRoot != "":
+		directory = r.Restore.BackupRoot
+	default:
+		directory = r.Restore.Directory
+	}
+
+	return &hooks.Config{
+		PreHook:       r.Restore.PreHook,
+		PostHook:      r.Restore.PostHook,
+		OnFailureHook: r.Restore.OnFailureHook,
+		Operation:     "restore",
+		Namespace:     r.Restore.Namespace,
+		Directory:     directory,
+		StatsFile:     r.reportFile(),
+	}
+}
+
 // NewRestoreConfig creates and returns a new ConfigRestore object, initialized with given restore parameters.
 func NewRestoreConfig(config *RestoreServiceConfig, logger *slog.Logger) *backup.ConfigRestore {
 	logger.Info("initializing restore config")
@@ -102,8 +141,9 @@ func NewRestoreConfig(config *RestoreServiceConfig, logger *slog.Logger) *backup
 
 	c := backup.NewDefaultRestoreConfig()
 	c.Namespace = config.Restore.NamespaceConfig()
-	c.SetList = config.Restore.Sets()
-	c.BinList = config.Restore.Bins()
+	// Sets and bins are renamed before the records are filtered by the restore, so the lists use the new names.
+	c.SetList = renamed(config.Restore.Sets(), config.Restore.SetRenames())
+	c.BinList = renamed(config.Restore.Bins(), config.Restore.BinRenames())
 	c.NoRecords = config.Restore.NoRecords
 	c.NoIndexes = config.Restore.NoIndexes
 	c.NoUDFs = config.Restore.NoUDFs
@@ -114,7 +154,8 @@ func NewRestoreConfig(config *RestoreServiceConfig, logger *slog.Logger) *backup
 	c.Bandwidth = config.Restore.Bandwidth * 1024 * 1024
 	c.ExtraTTL = config.Restore.ExtraTTL
 	c.IgnoreRecordError = config.Restore.IgnoreRecordError
-	c.DisableBatchWrites = config.Restore.DisableBatchWrites
+	// Rejected records are saved from single record writes, batch writes don't expose the bins of failed records.
+	c.DisableBatchWrites = config.Restore.DisableBatchWrites || config.Restore.RejectDir != ""
 	c.BatchSize = config.Restore.BatchSize
 	c.MaxAsyncBatches = config.Restore.MaxAsyncBatches
 	c.MetricsEnabled = true
@@ -133,6 +174,24 @@ func NewRestoreConfig(config *RestoreServiceConfig, logger *slog.Logger) *backup
 	return c
 }
 
+// renamed returns the names with the renamed ones replaced by their new names.
+func renamed(names []string, renames map[string]string) []string {
+	if len(renames) == 0 {
+		return names
+	}
+
+	result := make([]string, len(names))
+
+	for i, name := range names {
+		result[i] = name
+		if to, ok := renames[name]; ok {
+			result[i] = to
+		}
+	}
+
+	return result
+}
+
 func logRestoreConfig(logger *slog.Logger, params *RestoreServiceConfig, restoreConfig *backup.ConfigRestore) {
 	logger.Info("initialized restore config",
 		getNamespaceLog(restoreConfig),
@@ -40,6 +40,7 @@ func TestNewRestoreServiceConfig_WithoutConfigFile(t *testing.T) {
 	awsS3 := &models.AwsS3{}
 	gcpStorage := &models.GcpStorage{}
 	azureBlob := &models.AzureBlob{}
+	sftp := &models.Sftp{}
 
 	config, _ := NewRestoreServiceConfig(
 		app,
@@ -52,6 +53,7 @@ func TestNewRestoreServiceConfig_WithoutConfigFile(t *testing.T) {
 		awsS3,
 		gcpStorage,
 		azureBlob,
+		sftp,
 	)
 	config.Restore.Mode = models.RestoreModeASB
 
@@ -113,6 +115,27 @@ func TestRestoreServiceConfig_IsStdin(t *testing.T) {
 	}
 }
 
+func TestRestoreServiceConfig_Validate_InputFormat(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(compression *models.Compression) *RestoreServiceConfig {
+		return &RestoreServiceConfig{
+			Restore: &models.Restore{
+				Mode:        models.RestoreModeASB,
+				InputFormat: models.InputFormatCSV,
+				KeyField:    "id",
+				Common:      models.Common{Namespace: "test", Directory: "/import"},
+			},
+			ServiceConfigCommon: ServiceConfigCommon{Compression: compression},
+		}
+	}
+
+	err := newConfig(&models.Compression{Mode: "ZSTD"}).Validate()
+	require.ErrorContains(t, err, "input-format csv is not allowed with compression or encryption")
+
+	require.NoError(t, newConfig(nil).Validate())
+}
+
 func TestNewRestoreConfig_DefaultValues(t *testing.T) {
 	t.Parallel()
 
@@ -495,6 +518,52 @@ func TestNewRestoreConfig_EmptyStringLists(t *testing.T) {
 	assert.Nil(t, config.BinList)
 }
 
+func TestNewRestoreConfig_Renames(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	serviceConfig := &RestoreServiceConfig{
+		Restore: &models.Restore{
+			Common: models.Common{
+				SetList: "prod,other",
+				BinList: "a,b",
+			},
+			SetMap: "prod:qa",
+			BinMap: "a:c",
+		},
+		ServiceConfigCommon: ServiceConfigCommon{
+			Compression: &models.Compression{},
+			Encryption:  &models.Encryption{},
+			SecretAgent: &models.SecretAgent{},
+		},
+	}
+
+	config := NewRestoreConfig(serviceConfig, logger)
+
+	assert.Equal(t, []string{"qa", "other"}, config.SetList)
+	assert.Equal(t, []string{"c", "b"}, config.BinList)
+}
+
+func TestNewRestoreConfig_RejectDir(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	serviceConfig := &RestoreServiceConfig{
+		Restore: &models.Restore{
+			RejectDir: "rejects",
+		},
+		ServiceConfigCommon: ServiceConfigCommon{
+			Compression: &models.Compression{},
+			Encryption:  &models.Encryption{},
+			SecretAgent: &models.SecretAgent{},
+		},
+	}
+
+	config := NewRestoreConfig(serviceConfig, logger)
+
+	assert.True(t, config.DisableBatchWrites)
+}
+
 func TestNewRestoreConfig_RetryPolicy(t *testing.T) {
 	t.Parallel()
 
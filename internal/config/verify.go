@@ -0,0 +1,67 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "github.com/aerospike/absctl/internal/models"
+
+// VerifyServiceConfig contains configuration settings for the verify service.
+// Verification works offline, so no Aerospike client settings are required.
+type VerifyServiceConfig struct {
+	Verify *models.Verify
+
+	ServiceConfigCommon
+}
+
+// NewVerifyServiceConfig creates and returns a new VerifyServiceConfig initialized with the provided parameters.
+func NewVerifyServiceConfig(
+	app *models.App,
+	verify *models.Verify,
+	compression *models.Compression,
+	encryption *models.Encryption,
+	secretAgent *models.SecretAgent,
+	awsS3 *models.AwsS3,
+	gcpStorage *models.GcpStorage,
+	azureBlob *models.AzureBlob,
+) *VerifyServiceConfig {
+	return &VerifyServiceConfig{
+		Verify: verify,
+		ServiceConfigCommon: *NewServiceConfigCommon(
+			app,
+			nil,
+			nil,
+			compression,
+			encryption,
+			secretAgent,
+			awsS3,
+			gcpStorage,
+			azureBlob,
+			nil,
+			nil,
+		),
+	}
+}
+
+// Validate validates the verify configuration and returns an error if any validation fails.
+func (v *VerifyServiceConfig) Validate() error {
+	if err := v.Verify.Validate(); err != nil {
+		return err
+	}
+
+	if err := v.ServiceConfigCommon.Validate(false); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -66,6 +66,7 @@ func dtoToBackupServiceConfig(dtoBackup *dto.Backup) (*BackupServiceConfig, erro
 			AwsS3:        dtoBackup.Aws.S3.ToModelAwsS3(),
 			GcpStorage:   dtoBackup.Gcp.Storage.ToModelGcpStorage(),
 			AzureBlob:    dtoBackup.Azure.Blob.ToModelAzureBlob(),
+			Sftp:         dtoBackup.Sftp.ToModelSftp(),
 			Local:        dtoBackup.Local.Disk.ToModelLocal(),
 		},
 	}, nil
@@ -114,6 +115,7 @@ func dtoToRestoreServiceConfig(dtoRestore *dto.Restore) (*RestoreServiceConfig,
 			AwsS3:        dtoRestore.Aws.S3.ToModelAwsS3(),
 			GcpStorage:   dtoRestore.Gcp.Storage.ToModelGcpStorage(),
 			AzureBlob:    dtoRestore.Azure.Blob.ToModelAzureBlob(),
+			Sftp:         dtoRestore.Sftp.ToModelSftp(),
 		},
 	}, nil
 }
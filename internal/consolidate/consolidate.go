@@ -0,0 +1,510 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consolidate
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/aerospike/absctl/internal/aeskey"
+	appBackup "github.com/aerospike/absctl/internal/backup"
+	"github.com/aerospike/absctl/internal/codec"
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/records"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// source is a backup of the chain to consolidate.
+type source struct {
+	storage.Backup
+
+	reader backup.StreamingReader
+}
+
+// compressed checks if the files of the backup are compressed with ZSTD, the only compression mode.
+func (s *source) compressed() bool {
+	return s.Manifest.Config.Compression != "" && s.Manifest.Config.Compression != backup.CompressNone
+}
+
+// encryption returns the encryption mode of the files of the backup, empty if they are not encrypted.
+func (s *source) encryption() string {
+	if s.Manifest.Config.Encryption == backup.EncryptNone {
+		return ""
+	}
+
+	return s.Manifest.Config.Encryption
+}
+
+// stats contains the counters of a consolidation.
+type stats struct {
+	// read is the number of records read from all backups.
+	read uint64
+	// written is the number of records written to the consolidated backup.
+	written uint64
+	// superseded is the number of records skipped because a newer version was written.
+	superseded uint64
+	// expired is the number of records skipped because their void time has passed.
+	expired  uint64
+	sindexes uint32
+	udfs     uint32
+}
+
+// Service merges a full backup and its incremental backups into a new full backup,
+// without connecting to a cluster.
+type Service struct {
+	// sources are ordered from the full backup to the last incremental backup.
+	sources []*source
+	output  *appBackup.ManifestWriter
+	writer  *fileWriter
+
+	encryption  *backup.EncryptionPolicy
+	secretAgent *backup.SecretAgentConfig
+	compression *backup.CompressionPolicy
+	// keys are the encryption keys of the backups by encryption mode.
+	keys map[string][]byte
+
+	// sindexSource and udfSource are the indexes of the sources the metadata is taken from, -1 if none.
+	sindexSource int
+	udfSource    int
+	metadata     []*bModels.Token
+
+	// seen holds the digests of the written records, so older versions are skipped.
+	// It holds at most maxDigests digests, 0 means no limit.
+	seen       map[[20]byte]struct{}
+	maxDigests uint64
+	stats      stats
+
+	appVersion string
+	commitHash string
+
+	logger *slog.Logger
+}
+
+// NewService reads the manifests of the backups, checks that they form a chain
+// and initializes the writer of the consolidated backup.
+func NewService(
+	ctx context.Context,
+	cfg *config.ConsolidateServiceConfig,
+	logger *slog.Logger,
+) (*Service, error) {
+	dirs := cfg.Consolidate.Directories()
+	sources := make([]*source, 0, len(dirs))
+
+	for _, dir := range dirs {
+		reader, err := storage.NewReader(ctx, &cfg.ServiceConfigCommon, dir, "", "", "", 0, false, false, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reader for backup %s: %w", dir, err)
+		}
+
+		manifest, err := storage.ReadManifest(ctx, reader, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest of backup %s: %w", dir, err)
+		}
+
+		sources = append(sources, &source{
+			Backup: storage.Backup{Path: dir, Manifest: manifest},
+			reader: reader,
+		})
+	}
+
+	if err := validateChain(sources); err != nil {
+		return nil, err
+	}
+
+	if err := checkDigests(sources, cfg.Consolidate.MaxDigests); err != nil {
+		return nil, err
+	}
+
+	writer, err := storage.NewBackupWriter(ctx, &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: cfg.Consolidate.Directory,
+			},
+		},
+		ServiceConfigCommon: cfg.ServiceConfigCommon,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		sources:      sources,
+		output:       appBackup.NewManifestWriter(writer),
+		encryption:   cfg.Encryption.Policy(),
+		secretAgent:  cfg.SecretAgent.Config(),
+		compression:  cfg.Compression.Policy(),
+		keys:         make(map[string][]byte),
+		sindexSource: -1,
+		udfSource:    -1,
+		seen:         make(map[[20]byte]struct{}),
+		maxDigests:   cfg.Consolidate.MaxDigests,
+		logger:       logger,
+	}
+
+	// Each backup holds all secondary indexes and UDFs at its start, so they are taken from the last one.
+	for i, src := range sources {
+		if !src.Manifest.Config.NoIndexes {
+			s.sindexSource = i
+		}
+
+		if !src.Manifest.Config.NoUDFs {
+			s.udfSource = i
+		}
+	}
+
+	var encryptionKey []byte
+	if s.encryption != nil {
+		encryptionKey, err = s.key(ctx, s.encryption.Mode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.output.SetSigningKey(encryptionKey)
+
+	s.writer = newFileWriter(
+		s.output,
+		sources[0].Manifest.Config.Namespace,
+		s.compression,
+		encryptionKey,
+		cfg.Consolidate.FileLimit*1024*1024,
+	)
+
+	return s, nil
+}
+
+// SetBuildInfo sets the absctl version and commit that are saved to the manifest of the consolidated backup.
+func (s *Service) SetBuildInfo(appVersion, commitHash string) {
+	s.appVersion = appVersion
+	s.commitHash = commitHash
+}
+
+// Run writes the newest version of each record of the chain to the consolidated backup and saves its manifest.
+func (s *Service) Run(ctx context.Context) error {
+	s.logger.Info("starting backup consolidation", slog.Int("backups", len(s.sources)))
+
+	start := time.Now()
+
+	// The newest backup is read first, so each record is written from the newest backup it is found in.
+	for i := len(s.sources) - 1; i >= 0; i-- {
+		src := s.sources[i]
+
+		s.logger.Info("consolidating backup", slog.String("path", src.Path))
+
+		if err := s.consolidate(ctx, i, start); err != nil {
+			_ = s.writer.Close()
+			return fmt.Errorf("failed to consolidate backup %s: %w", src.Path, err)
+		}
+	}
+
+	if err := s.writer.WriteMetadata(ctx, s.metadata); err != nil {
+		return err
+	}
+
+	manifest := s.newManifest(start)
+	if err := s.output.Save(ctx, manifest); err != nil {
+		return err
+	}
+
+	s.logger.Info("backup consolidation finished",
+		slog.Uint64("records-read", s.stats.read),
+		slog.Uint64("records-written", s.stats.written),
+		slog.Uint64("records-superseded", s.stats.superseded),
+		slog.Uint64("records-expired", s.stats.expired),
+		slog.Uint64("sindexes", uint64(s.stats.sindexes)),
+		slog.Uint64("udfs", uint64(s.stats.udfs)),
+		slog.Int("files", len(manifest.Files)),
+		slog.String("duration", manifest.Duration),
+	)
+
+	return nil
+}
+
+// consolidate reads the files of the source with the index one by one.
+func (s *Service) consolidate(ctx context.Context, index int, now time.Time) error {
+	return codec.StreamFiles(ctx, s.sources[index].reader, func(file bModels.File) error {
+		if err := s.consolidateFile(ctx, index, file, now); err != nil {
+			return fmt.Errorf("failed to consolidate file %s: %w", file.Name, err)
+		}
+
+		return nil
+	})
+}
+
+// consolidateFile decodes a single file and writes the records that are not superseded or expired.
+func (s *Service) consolidateFile(ctx context.Context, index int, file bModels.File, now time.Time) error {
+	defer file.Reader.Close()
+
+	name := path.Base(file.Name)
+
+	reader, err := s.newFileReader(ctx, s.sources[index], file.Reader)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	decoder, err := asb.NewDecoder[*bModels.Token](reader, name, false, s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+
+	// Digests of the full backup are not tracked, as no older backup is read after it.
+	track := index > 0
+
+	return codec.DecodeTokens(decoder, func(token *bModels.Token) error {
+		switch token.Type {
+		case bModels.TokenTypeRecord:
+			return s.consolidateRecord(ctx, token.Record, track, now)
+		case bModels.TokenTypeSIndex:
+			if index == s.sindexSource {
+				s.metadata = append(s.metadata, token)
+				s.stats.sindexes++
+			}
+		case bModels.TokenTypeUDF:
+			if index == s.udfSource {
+				s.metadata = append(s.metadata, token)
+				s.stats.udfs++
+			}
+		}
+
+		return nil
+	})
+}
+
+// consolidateRecord writes the record, unless a newer version was written or it has expired.
+func (s *Service) consolidateRecord(ctx context.Context, record *bModels.Record, track bool, now time.Time) error {
+	s.stats.read++
+
+	digest := [20]byte(record.Key.Digest())
+	if _, ok := s.seen[digest]; ok {
+		s.stats.superseded++
+		return nil
+	}
+
+	// An expired record is still tracked, so an older version of it is not written either.
+	if track {
+		// The manifests are checked before, this only guards against stats that don't match the files.
+		if s.maxDigests > 0 && uint64(len(s.seen)) >= s.maxDigests {
+			return fmt.Errorf("more than %d records in the incremental backups, raise --max-digests", s.maxDigests)
+		}
+
+		s.seen[digest] = struct{}{}
+	}
+
+	if expired(record.VoidTime, now) {
+		s.stats.expired++
+		return nil
+	}
+
+	if err := s.writer.WriteRecord(ctx, bModels.NewRecordToken(record, 0, nil)); err != nil {
+		return err
+	}
+
+	s.stats.written++
+
+	return nil
+}
+
+// newFileReader decrypts and decompresses the file, as set in the manifest of its backup.
+func (s *Service) newFileReader(ctx context.Context, src *source, r io.Reader) (io.ReadCloser, error) {
+	var key []byte
+
+	if mode := src.encryption(); mode != "" {
+		var err error
+
+		key, err = s.key(ctx, mode)
+		if err != nil {
+			return nil, fmt.Errorf("backup is encrypted with %s: %w", mode, err)
+		}
+	}
+
+	return codec.NewReader(r, key, src.compressed())
+}
+
+// key returns the encryption key for the mode, derived from the key set by the encryption flags.
+func (s *Service) key(ctx context.Context, mode string) ([]byte, error) {
+	if key, ok := s.keys[mode]; ok {
+		return key, nil
+	}
+
+	if s.encryption == nil {
+		return nil, errors.New("encryption key is not set")
+	}
+
+	policy := *s.encryption
+	policy.Mode = mode
+
+	key, err := aeskey.Read(ctx, &policy, s.secretAgent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key: %w", err)
+	}
+
+	s.keys[mode] = key
+
+	return key, nil
+}
+
+// newManifest builds the manifest of the consolidated backup. It starts when the last backup of the chain
+// started, so incremental backups of the chain can continue from it.
+func (s *Service) newManifest(start time.Time) *models.Manifest {
+	full := s.sources[0].Manifest
+	last := s.sources[len(s.sources)-1].Manifest
+
+	cfg := full.Config
+	cfg.ModifiedBefore = last.Config.ModifiedBefore
+	cfg.NoIndexes = s.sindexSource < 0
+	cfg.NoUDFs = s.udfSource < 0
+	cfg.Compression = backup.CompressNone
+	cfg.Encryption = backup.EncryptNone
+
+	if s.compression != nil {
+		cfg.Compression = s.compression.Mode
+	}
+
+	if s.encryption != nil {
+		cfg.Encryption = s.encryption.Mode
+	}
+
+	files := s.output.Files()
+
+	var size uint64
+	for _, f := range files {
+		size += uint64(f.Size)
+	}
+
+	return &models.Manifest{
+		FormatVersion: models.ManifestFormatVersion,
+		AbsctlVersion: s.appVersion,
+		Commit:        s.commitHash,
+		Created:       time.Now().UTC(),
+		StartTime:     last.StartTime,
+		Duration:      time.Since(start).String(),
+		Config:        cfg,
+		Stats: models.ManifestStats{
+			RecordsRead:  s.stats.written,
+			SIndexes:     s.stats.sindexes,
+			UDFs:         s.stats.udfs,
+			BytesWritten: size,
+			FilesWritten: uint64(len(files)),
+		},
+		Files: files,
+	}
+}
+
+// validateChain checks that the backups are a full backup of a namespace followed by its incremental backups,
+// in order and without a gap, with the same filters, so the consolidated backup holds every record of the last backup.
+func validateChain(sources []*source) error {
+	full := sources[0]
+	if !full.Manifest.IsFull() {
+		return fmt.Errorf("first backup %s is not a full backup", full.Path)
+	}
+
+	for i, src := range sources {
+		if src.Manifest.Config.NoRecords {
+			return fmt.Errorf("backup %s has no records", src.Path)
+		}
+
+		if src.Manifest.Config.Namespace != full.Manifest.Config.Namespace {
+			return fmt.Errorf("backup %s is of namespace %s, backup %s is of namespace %s",
+				src.Path, src.Manifest.Config.Namespace, full.Path, full.Manifest.Config.Namespace)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		if option := filterDiff(&src.Manifest.Config, &full.Manifest.Config); option != "" {
+			return fmt.Errorf("backup %s has another %s than backup %s, backups must select the same records",
+				src.Path, option, full.Path)
+		}
+
+		prev := sources[i-1]
+
+		switch {
+		case src.Manifest.IsFull():
+			return fmt.Errorf("backup %s is a full backup, only the first backup can be", src.Path)
+		case !src.Manifest.StartTime.After(prev.Manifest.StartTime):
+			return fmt.Errorf("backup %s does not start after backup %s, backups must be in order",
+				src.Path, prev.Path)
+		case !src.Manifest.Continues(prev.Manifest):
+			return fmt.Errorf("backup %s holds the records modified since %s, but backup %s ends at %s: "+
+				"the records modified in between are in no backup",
+				src.Path, src.Manifest.ModifiedSince().Format(time.RFC3339),
+				prev.Path, prev.Manifest.ModifiedUntil().Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// filterDiff returns the name of the first filter that selects other records in the backup configs,
+// empty if they select the same records. The order of the sets and bins doesn't matter.
+func filterDiff(a, b *models.ManifestConfig) string {
+	switch {
+	case !sameElements(a.SetList, b.SetList):
+		return "set list"
+	case !sameElements(a.BinList, b.BinList):
+		return "bin list"
+	case !slices.Equal(a.PartitionFilters, b.PartitionFilters):
+		return "partition filter"
+	case !sameElements(a.NodeList, b.NodeList):
+		return "node list"
+	case !sameElements(a.RackList, b.RackList):
+		return "rack list"
+	default:
+		return ""
+	}
+}
+
+// sameElements checks if the lists hold the same elements, in any order.
+func sameElements[T cmp.Ordered](a, b []T) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
+
+// checkDigests fails before any file is read if the digests of the records of the incremental backups,
+// as counted by their manifests, don't fit in the limit. The full backup is read last, so its digests are not kept.
+func checkDigests(sources []*source, limit uint64) error {
+	if limit == 0 {
+		return nil
+	}
+
+	var total uint64
+	for _, src := range sources[1:] {
+		total += src.Manifest.Stats.RecordsRead
+	}
+
+	if total > limit {
+		return fmt.Errorf("the incremental backups hold %d records, more than the %d digests allowed in memory "+
+			"by --max-digests: consolidate fewer incremental backups at once, or raise the limit", total, limit)
+	}
+
+	return nil
+}
+
+// expired checks if the void time of a record has passed.
+func expired(voidTime int64, now time.Time) bool {
+	return voidTime != 0 && records.TTL(voidTime, now) == 0
+}
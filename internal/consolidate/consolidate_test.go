@@ -0,0 +1,197 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consolidate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testNamespace = "test"
+
+var chainStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// chainDay returns the time of the given day after chainStart.
+func chainDay(day int) time.Time {
+	return chainStart.AddDate(0, 0, day)
+}
+
+func fullSource(path string, day int) *source {
+	return &source{Backup: storage.Backup{Path: path, Manifest: &models.Manifest{
+		StartTime: chainDay(day),
+		Config:    models.ManifestConfig{Namespace: testNamespace},
+	}}}
+}
+
+func incrementalSource(path string, day, parentDay int) *source {
+	return &source{Backup: storage.Backup{Path: path, Manifest: &models.Manifest{
+		StartTime: chainDay(day),
+		Config:    models.ManifestConfig{Namespace: testNamespace},
+		Incremental: &models.ManifestIncremental{
+			Chain:           []string{"full"},
+			ParentStartTime: chainDay(parentDay),
+		},
+	}}}
+}
+
+func TestValidateChain(t *testing.T) {
+	t.Parallel()
+
+	noRecords := incrementalSource("no-records", 2, 1)
+	noRecords.Manifest.Config.NoRecords = true
+
+	otherNamespace := incrementalSource("other", 2, 1)
+	otherNamespace.Manifest.Config.Namespace = "other"
+
+	sets := fullSource("full", 0)
+	sets.Manifest.Config.SetList = []string{"set1", "set2"}
+
+	sameSets := incrementalSource("inc-1", 1, 0)
+	sameSets.Manifest.Config.SetList = []string{"set2", "set1"}
+
+	otherSets := incrementalSource("inc-1", 1, 0)
+	otherSets.Manifest.Config.SetList = []string{"set1"}
+
+	otherBins := incrementalSource("inc-1", 1, 0)
+	otherBins.Manifest.Config.BinList = []string{"bin1"}
+
+	otherPartitions := incrementalSource("inc-1", 1, 0)
+	otherPartitions.Manifest.Config.PartitionFilters = []models.ManifestPartitionFilter{{Begin: 0, Count: 2048}}
+
+	tests := []struct {
+		name    string
+		sources []*source
+		errMsg  string
+	}{
+		{
+			name:    "Full backup only",
+			sources: []*source{fullSource("full", 0)},
+		},
+		{
+			name: "Full and incremental backups",
+			sources: []*source{
+				fullSource("full", 0),
+				incrementalSource("inc-1", 1, 0),
+				incrementalSource("inc-2", 2, 1),
+			},
+		},
+		{
+			name:    "Incremental backup first",
+			sources: []*source{incrementalSource("inc-1", 1, 0)},
+			errMsg:  "first backup inc-1 is not a full backup",
+		},
+		{
+			name:    "Two full backups",
+			sources: []*source{fullSource("full", 0), fullSource("full-1", 1)},
+			errMsg:  "backup full-1 is a full backup, only the first backup can be",
+		},
+		{
+			name:    "Backup without records",
+			sources: []*source{fullSource("full", 0), incrementalSource("inc-1", 1, 0), noRecords},
+			errMsg:  "backup no-records has no records",
+		},
+		{
+			name:    "Backup of another namespace",
+			sources: []*source{fullSource("full", 0), incrementalSource("inc-1", 1, 0), otherNamespace},
+			errMsg:  "backup other is of namespace other, backup full is of namespace test",
+		},
+		{
+			name:    "Same sets in another order",
+			sources: []*source{sets, sameSets},
+		},
+		{
+			name:    "Other set list",
+			sources: []*source{sets, otherSets},
+			errMsg:  "backup inc-1 has another set list than backup full, backups must select the same records",
+		},
+		{
+			name:    "Other bin list",
+			sources: []*source{fullSource("full", 0), otherBins},
+			errMsg:  "backup inc-1 has another bin list than backup full, backups must select the same records",
+		},
+		{
+			name:    "Other partition filter",
+			sources: []*source{fullSource("full", 0), otherPartitions},
+			errMsg:  "backup inc-1 has another partition filter than backup full, backups must select the same records",
+		},
+		{
+			name: "Backups out of order",
+			sources: []*source{
+				fullSource("full", 0),
+				incrementalSource("inc-2", 2, 0),
+				incrementalSource("inc-1", 1, 0),
+			},
+			errMsg: "backup inc-1 does not start after backup inc-2, backups must be in order",
+		},
+		{
+			name:    "Gap in chain",
+			sources: []*source{fullSource("full", 0), incrementalSource("inc-2", 2, 1)},
+			errMsg: "backup inc-2 holds the records modified since 2024-01-02T00:00:00Z, " +
+				"but backup full ends at 2024-01-01T00:00:00Z: the records modified in between are in no backup",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateChain(tt.sources)
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckDigests(t *testing.T) {
+	t.Parallel()
+
+	full := fullSource("full", 0)
+	full.Manifest.Stats.RecordsRead = 1000
+
+	inc1 := incrementalSource("inc-1", 1, 0)
+	inc1.Manifest.Stats.RecordsRead = 60
+
+	inc2 := incrementalSource("inc-2", 2, 1)
+	inc2.Manifest.Stats.RecordsRead = 40
+
+	sources := []*source{full, inc1, inc2}
+
+	// The records of the full backup are not counted.
+	require.NoError(t, checkDigests(sources, 100))
+	require.NoError(t, checkDigests(sources, 0))
+	require.EqualError(t, checkDigests(sources, 99),
+		"the incremental backups hold 100 records, more than the 99 digests allowed in memory "+
+			"by --max-digests: consolidate fewer incremental backups at once, or raise the limit")
+}
+
+func TestExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(types.CITRUSLEAF_EPOCH+1000, 0)
+
+	require.False(t, expired(0, now))
+	require.False(t, expired(1001, now))
+	require.True(t, expired(1000, now))
+	require.True(t, expired(1, now))
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consolidate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aerospike/absctl/internal/codec"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// fileWriter writes the consolidated backup to asb files, compressed and encrypted as configured.
+// Records are written to files numbered from 1 and a new file is started when the file limit is reached.
+// Secondary indexes and UDFs are written last, to the first file of the backup.
+type fileWriter struct {
+	writer    backup.Writer
+	namespace string
+
+	// compression is nil if the files are not compressed.
+	compression *backup.CompressionPolicy
+	// encryptionKey is nil if the files are not encrypted.
+	encryptionKey []byte
+	// fileLimit is the size in bytes at which a new file is started, 0 means no limit.
+	fileLimit uint64
+
+	encoder *asb.Encoder[*bModels.Token]
+	// firstHeader is the header of the first file. The encoder marks the first header it returns
+	// as the first file, so it is taken before the headers of the files of records.
+	firstHeader []byte
+	buf         bytes.Buffer
+
+	file  *asbFile
+	index int
+}
+
+func newFileWriter(
+	writer backup.Writer,
+	namespace string,
+	compression *backup.CompressionPolicy,
+	encryptionKey []byte,
+	fileLimit uint64,
+) *fileWriter {
+	encoder := asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig(namespace, false, false))
+
+	return &fileWriter{
+		writer:        writer,
+		namespace:     namespace,
+		compression:   compression,
+		encryptionKey: encryptionKey,
+		fileLimit:     fileLimit,
+		encoder:       encoder,
+		firstHeader:   encoder.GetHeader(0, true),
+	}
+}
+
+// WriteRecord writes a record token, starting a new file if needed.
+func (w *fileWriter) WriteRecord(ctx context.Context, record *bModels.Token) error {
+	if w.file == nil || (w.fileLimit > 0 && w.file.size.n >= w.fileLimit) {
+		if err := w.closeFile(); err != nil {
+			return err
+		}
+
+		w.index++
+
+		file, err := w.open(ctx, w.index)
+		if err != nil {
+			return err
+		}
+
+		w.file = file
+	}
+
+	if err := w.write(w.file, record); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMetadata closes the last file of records and writes the first file, with the secondary index
+// and UDF tokens. The first file is written even without metadata, as on backup.
+func (w *fileWriter) WriteMetadata(ctx context.Context, metadata []*bModels.Token) error {
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+
+	file, err := w.open(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range metadata {
+		if err = w.write(file, token); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("failed to write metadata: %w", err)
+		}
+	}
+
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	return nil
+}
+
+// write encodes the token to the file.
+func (w *fileWriter) write(file io.Writer, token *bModels.Token) error {
+	w.buf.Reset()
+
+	if err := w.encoder.EncodeToken(token, &w.buf); err != nil {
+		return err
+	}
+
+	_, err := file.Write(w.buf.Bytes())
+
+	return err
+}
+
+// Close closes the current file, so it is uploaded to cloud storages.
+func (w *fileWriter) Close() error {
+	return w.closeFile()
+}
+
+func (w *fileWriter) closeFile() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	if err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	return nil
+}
+
+// open creates the file with the index and writes the asb header, the file with index 0 is the first file.
+func (w *fileWriter) open(ctx context.Context, index int) (*asbFile, error) {
+	name := fmt.Sprintf("%s_%d.asb", w.namespace, index)
+
+	out, err := w.writer.NewWriter(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", name, err)
+	}
+
+	size := &countingWriter{WriteCloser: out}
+
+	encoded, err := codec.NewWriter(size, w.encryptionKey, w.compression)
+	if err != nil {
+		_ = out.Close()
+		return nil, err
+	}
+
+	file := &asbFile{WriteCloser: encoded, size: size}
+
+	header := w.firstHeader
+	if index > 0 {
+		header = w.encoder.GetHeader(uint64(index), true)
+	}
+
+	if _, err = file.Write(header); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to write header of %s: %w", name, err)
+	}
+
+	return file, nil
+}
+
+// asbFile is an open file of the consolidated backup.
+type asbFile struct {
+	io.WriteCloser
+
+	// size counts the bytes written to the storage.
+	size *countingWriter
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	io.WriteCloser
+
+	n uint64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	c.n += uint64(n)
+
+	return n, err
+}
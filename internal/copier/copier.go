@@ -0,0 +1,418 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package copier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"path"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aerospike/absctl/internal/aeskey"
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/backup-go"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+)
+
+// errMismatch is returned if a copied file differs from the file of the backup.
+var errMismatch = errors.New("copy does not match the backup")
+
+// Service copies the files of a backup from one storage to another, checking the size and checksum
+// of every copied file. Files already copied by an interrupted copy are checked and skipped.
+type Service struct {
+	from, to *models.StoragePath
+
+	// key checks the signature of the manifest, nil if no encryption key is given.
+	key []byte
+
+	source backup.StreamingReader
+	// destination reads back the copied files.
+	destination backup.StreamingReader
+	writer      backup.Writer
+
+	parallel int
+	// limiter limits the bandwidth of all reads, nil if not limited.
+	limiter *rate.Limiter
+
+	copied  atomic.Uint64
+	skipped atomic.Uint64
+	bytes   atomic.Uint64
+
+	logger *slog.Logger
+}
+
+// NewService initializes the readers of both storages and the writer of the copy.
+func NewService(
+	ctx context.Context,
+	cfg *config.CopyServiceConfig,
+	logger *slog.Logger,
+) (*Service, error) {
+	from, to, err := cfg.Paths()
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip the file checks, so all files are copied.
+	source, err := storage.NewReader(ctx, cfg.StorageConfig(from), from.Path, "", "", "", 0, false, true, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader for %s: %w", from, err)
+	}
+
+	toCfg := cfg.StorageConfig(to)
+
+	writer, err := storage.NewCopyWriter(ctx, toCfg, to.Path, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := storage.NewReader(ctx, toCfg, to.Path, "", "", "", 0, false, true, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader for %s: %w", to, err)
+	}
+
+	s := &Service{
+		from:        from,
+		to:          to,
+		source:      source,
+		destination: destination,
+		writer:      writer,
+		parallel:    cfg.Copy.Parallel,
+		logger:      logger,
+	}
+
+	if policy := cfg.Encryption.Policy(); policy != nil {
+		if s.key, err = aeskey.Read(ctx, policy, cfg.SecretAgent.Config()); err != nil {
+			return nil, fmt.Errorf("failed to read encryption key: %w", err)
+		}
+	}
+
+	if cfg.Copy.Bandwidth > 0 {
+		bytesPerSecond := int(cfg.Copy.Bandwidth * 1024 * 1024)
+		s.limiter = rate.NewLimiter(rate.Limit(bytesPerSecond), bytesPerSecond)
+	}
+
+	return s, nil
+}
+
+// Run copies the files concurrently and the manifest last, so the copy is complete only when it has
+// a manifest, like the backup.
+func (s *Service) Run(ctx context.Context) error {
+	s.logger.Info("starting backup copy",
+		slog.String("from", s.from.String()),
+		slog.String("to", s.to.String()),
+		slog.Int("parallel", s.parallel),
+	)
+
+	start := time.Now()
+
+	files, err := s.listFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	copied, err := s.listCopied(ctx)
+	if err != nil {
+		return err
+	}
+
+	var manifest *models.Manifest
+	if _, ok := files[models.ManifestFileName]; ok {
+		if manifest, err = storage.ReadManifest(ctx, s.source, s.from.Path); err != nil {
+			return err
+		}
+	}
+
+	expected := make(map[string]models.ManifestFile)
+	if manifest != nil {
+		if err = s.checkManifest(manifest, files); err != nil {
+			return err
+		}
+
+		for _, file := range manifest.Files {
+			expected[file.Name] = file
+		}
+	}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(s.parallel)
+
+	for name, object := range files {
+		if name == models.ManifestFileName {
+			continue
+		}
+
+		g.Go(func() error {
+			return s.copyFile(gCtx, name, object, copied[name], expected[name])
+		})
+	}
+
+	if err = g.Wait(); err != nil {
+		return err
+	}
+
+	if manifest != nil {
+		object := files[models.ManifestFileName]
+		if err = s.copyFile(ctx, models.ManifestFileName, object, copied[models.ManifestFileName],
+			models.ManifestFile{}); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("backup copy finished",
+		slog.Int("files", len(files)),
+		slog.Uint64("copied", s.copied.Load()),
+		slog.Uint64("skipped", s.skipped.Load()),
+		slog.Uint64("bytes", s.bytes.Load()),
+		slog.Bool("manifest", manifest != nil),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	return nil
+}
+
+// checkManifest checks the signature of the manifest and that every file it lists is in the backup,
+// before any file is copied.
+func (s *Service) checkManifest(manifest *models.Manifest, files map[string]string) error {
+	switch {
+	case s.key != nil:
+		if err := manifest.CheckSignature(s.key); err != nil {
+			return fmt.Errorf("%w: %w", errMismatch, err)
+		}
+	case manifest.Signature != "":
+		s.logger.Warn("manifest signature not checked", slog.String("reason", "no encryption key given"))
+	}
+
+	var missing []string
+
+	for _, file := range manifest.Files {
+		if _, ok := files[file.Name]; !ok {
+			missing = append(missing, file.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: files listed in the manifest are missing from the backup: %s",
+			errMismatch, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// listFiles returns the objects of the backup by their path relative to the backup directory.
+func (s *Service) listFiles(ctx context.Context) (map[string]string, error) {
+	objects, err := s.source.ListObjects(ctx, s.from.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects of %s: %w", s.from, err)
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no files found in %s", s.from)
+	}
+
+	files := make(map[string]string, len(objects))
+	for _, object := range objects {
+		files[relativeName(s.from.Path, object)] = object
+	}
+
+	return files, nil
+}
+
+// listCopied returns the files already in the copy, from an interrupted copy.
+func (s *Service) listCopied(ctx context.Context) (map[string]bool, error) {
+	objects, err := s.destination.ListObjects(ctx, s.to.Path)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// The local directory of the copy is created with the first file.
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to list objects of %s: %w", s.to, err)
+	}
+
+	copied := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		copied[relativeName(s.to.Path, object)] = true
+	}
+
+	return copied, nil
+}
+
+// copyFile copies a file and checks that the copy has the size and checksum of the file. A file that is
+// already in the copy is checked first and copied again only if it differs. expected is the file listed
+// in the manifest of the backup, empty if it is not listed. The file is read once: it is hashed while it is
+// copied, and a copy that doesn't match the manifest is removed, so a corrupt file of the backup is not kept.
+func (s *Service) copyFile(ctx context.Context, name, object string, exists bool, expected models.ManifestFile) error {
+	if exists {
+		skip, err := s.isCopied(ctx, name, object, expected)
+		if err != nil {
+			return err
+		}
+
+		if skip {
+			s.logger.Debug("file already copied", slog.String("file", name))
+			s.skipped.Add(1)
+
+			return nil
+		}
+	}
+
+	file, err := storage.OpenFile(ctx, s.source, object)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", object, err)
+	}
+	defer file.Reader.Close()
+
+	w, err := s.writer.NewWriter(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(w, hash), s.limit(ctx, file.Reader))
+	if err != nil {
+		_ = w.Close()
+		return fmt.Errorf("failed to copy %s: %w", name, err)
+	}
+
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", name, err)
+	}
+
+	want := models.ManifestFile{Name: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	if expected.SHA256 != "" && !sameFile(want, expected) {
+		if err = s.writer.Remove(ctx, path.Join(s.to.Path, name)); err != nil {
+			s.logger.Warn("failed to remove copied file", slog.String("file", name), slog.Any("error", err))
+		}
+
+		return fmt.Errorf("%w: %s has size %d and checksum %s in the backup, but %d and %s in its manifest",
+			errMismatch, name, want.Size, want.SHA256, expected.Size, expected.SHA256)
+	}
+
+	got, err := s.hashFile(ctx, s.destination, path.Join(s.to.Path, name))
+	if err != nil {
+		return fmt.Errorf("failed to read copied %s: %w", name, err)
+	}
+
+	if !sameFile(got, want) {
+		return fmt.Errorf("%w: copied %s has size %d and checksum %s, expected %d and %s",
+			errMismatch, name, got.Size, got.SHA256, want.Size, want.SHA256)
+	}
+
+	s.logger.Debug("file copied", slog.String("file", name), slog.Int64("size", size))
+	s.copied.Add(1)
+	s.bytes.Add(uint64(size))
+
+	return nil
+}
+
+// isCopied checks if the file in the copy has the size and checksum of the file of the backup, taken from
+// the manifest of the backup if the file is listed in it.
+func (s *Service) isCopied(ctx context.Context, name, object string, expected models.ManifestFile) (bool, error) {
+	got, err := s.hashFile(ctx, s.destination, path.Join(s.to.Path, name))
+	if err != nil {
+		return false, fmt.Errorf("failed to read copied %s: %w", name, err)
+	}
+
+	want := expected
+	if want.SHA256 == "" {
+		if want, err = s.hashFile(ctx, s.source, object); err != nil {
+			return false, fmt.Errorf("failed to read %s: %w", object, err)
+		}
+	}
+
+	return sameFile(got, want), nil
+}
+
+// hashFile reads the file and returns its size and checksum.
+func (s *Service) hashFile(
+	ctx context.Context, reader backup.StreamingReader, object string,
+) (models.ManifestFile, error) {
+	file, err := storage.OpenFile(ctx, reader, object)
+	if err != nil {
+		return models.ManifestFile{}, err
+	}
+	defer file.Reader.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, s.limit(ctx, file.Reader))
+	if err != nil {
+		return models.ManifestFile{}, err
+	}
+
+	return models.ManifestFile{Name: object, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// limit limits the bandwidth of the reader, shared by all files.
+func (s *Service) limit(ctx context.Context, r io.Reader) io.Reader {
+	if s.limiter == nil {
+		return r
+	}
+
+	return &limitedReader{ctx: ctx, reader: r, limiter: s.limiter}
+}
+
+// sameFile checks if the files have the same size and checksum.
+func sameFile(a, b models.ManifestFile) bool {
+	return a.Size == b.Size && a.SHA256 == b.SHA256
+}
+
+// relativeName returns the path of the object relative to the directory.
+func relativeName(directory, object string) string {
+	if directory == "" {
+		return object
+	}
+
+	if name, ok := strings.CutPrefix(object, strings.TrimSuffix(directory, "/")+"/"); ok {
+		return name
+	}
+
+	return path.Base(object)
+}
+
+// limitedReader waits for the limiter before returning the bytes read.
+type limitedReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *rate.Limiter
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	// A single wait can't exceed the burst of the limiter.
+	if burst := l.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+
+	n, err := l.reader.Read(p)
+	if n > 0 {
+		if waitErr := l.limiter.WaitN(l.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,245 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package copier
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+// writeTestBackup writes the files and a manifest listing them to dir.
+// The checksums of the manifest are taken from listed, by file name.
+func writeTestBackup(t *testing.T, dir string, files, listed map[string][]byte) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+
+	manifest := models.Manifest{FormatVersion: 1}
+
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+
+		sum := sha256.Sum256(listed[name])
+		manifest.Files = append(manifest.Files, models.ManifestFile{
+			Name:   name,
+			Size:   int64(len(listed[name])),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, models.ManifestFileName), data, 0o600))
+}
+
+func newTestService(t *testing.T, from, to string) *Service {
+	t.Helper()
+
+	cfg := &config.CopyServiceConfig{
+		Copy: &models.Copy{From: from, To: to, Parallel: 2},
+	}
+
+	s, err := NewService(t.Context(), cfg, slog.Default())
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestService_Run(t *testing.T) {
+	t.Parallel()
+
+	// The backup is in a subdirectory, not in the working directory.
+	root := t.TempDir()
+	from := filepath.Join(root, "backups", "test")
+	to := filepath.Join(root, "copies", "test")
+
+	files := map[string][]byte{
+		"test_0.asb": []byte("records 0"),
+		"test_1.asb": []byte("records 1"),
+	}
+	writeTestBackup(t, from, files, files)
+
+	require.NoError(t, newTestService(t, from, to).Run(t.Context()))
+
+	for name, data := range files {
+		got, err := os.ReadFile(filepath.Join(to, name))
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	}
+
+	require.FileExists(t, filepath.Join(to, models.ManifestFileName))
+
+	// The copy is resumed, all files are checked and skipped.
+	s := newTestService(t, from, to)
+	require.NoError(t, s.Run(t.Context()))
+	require.Equal(t, uint64(0), s.copied.Load())
+	require.Equal(t, uint64(3), s.skipped.Load())
+}
+
+func TestService_RunCorruptSource(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	from := filepath.Join(root, "backups", "test")
+	to := filepath.Join(root, "copies", "test")
+
+	writeTestBackup(t, from,
+		map[string][]byte{"test_0.asb": []byte("corrupt")},
+		map[string][]byte{"test_0.asb": []byte("records 0")},
+	)
+
+	err := newTestService(t, from, to).Run(t.Context())
+	require.ErrorIs(t, err, errMismatch)
+
+	// The corrupt copy is removed, the manifest is not written.
+	require.NoFileExists(t, filepath.Join(to, "test_0.asb"))
+	require.NoFileExists(t, filepath.Join(to, models.ManifestFileName))
+}
+
+func TestService_RunMissingFile(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	from := filepath.Join(root, "backups", "test")
+	to := filepath.Join(root, "copies", "test")
+
+	files := map[string][]byte{
+		"test_0.asb": []byte("records 0"),
+		"test_1.asb": []byte("records 1"),
+	}
+	writeTestBackup(t, from, files, files)
+	require.NoError(t, os.Remove(filepath.Join(from, "test_1.asb")))
+
+	err := newTestService(t, from, to).Run(t.Context())
+	require.ErrorIs(t, err, errMismatch)
+	require.ErrorContains(t, err, "test_1.asb")
+
+	// Nothing is copied.
+	require.NoDirExists(t, to)
+}
+
+func TestService_RunSignature(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	from := filepath.Join(root, "backups", "test")
+	key := []byte("0123456789abcdef")
+
+	files := map[string][]byte{"test_0.asb": []byte("records 0")}
+	writeTestBackup(t, from, files, files)
+
+	// Unsigned manifests are not copied when a key is given.
+	s := newTestService(t, from, filepath.Join(root, "unsigned"))
+	s.key = key
+	require.ErrorIs(t, s.Run(t.Context()), models.ErrManifestUnsigned)
+
+	manifestFile := filepath.Join(from, models.ManifestFileName)
+	data, err := os.ReadFile(manifestFile)
+	require.NoError(t, err)
+
+	var manifest models.Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.NoError(t, manifest.Sign(key))
+
+	data, err = json.Marshal(&manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestFile, data, 0o600))
+
+	s = newTestService(t, from, filepath.Join(root, "other-key"))
+	s.key = []byte("fedcba9876543210")
+	require.ErrorIs(t, s.Run(t.Context()), models.ErrManifestSignature)
+	require.NoDirExists(t, filepath.Join(root, "other-key"))
+
+	s = newTestService(t, from, filepath.Join(root, "signed"))
+	s.key = key
+	require.NoError(t, s.Run(t.Context()))
+	require.FileExists(t, filepath.Join(root, "signed", "test_0.asb"))
+
+	// Without a key the signature is not checked.
+	require.NoError(t, newTestService(t, from, filepath.Join(root, "no-key")).Run(t.Context()))
+}
+
+func TestRelativeName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		directory string
+		object    string
+		expected  string
+	}{
+		{name: "Root directory", directory: "", object: "ns_1.asb", expected: "ns_1.asb"},
+		{name: "File in directory", directory: "backups/b1", object: "backups/b1/ns_1.asb", expected: "ns_1.asb"},
+		{name: "Trailing slash", directory: "backups/b1/", object: "backups/b1/ns_1.asb", expected: "ns_1.asb"},
+		{name: "Nested file", directory: "backups", object: "backups/ns/ns_1.asb", expected: "ns/ns_1.asb"},
+		{name: "Other directory", directory: "backups", object: "other/ns_1.asb", expected: "ns_1.asb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, relativeName(tt.directory, tt.object))
+		})
+	}
+}
+
+func TestLimitedReader(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte("a"), 100)
+	r := &limitedReader{
+		ctx:     context.Background(),
+		reader:  bytes.NewReader(data),
+		limiter: rate.NewLimiter(rate.Inf, 10),
+	}
+
+	buf := make([]byte, 50)
+	n, err := r.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, 10, n)
+
+	rest, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Len(t, rest, 90)
+}
+
+func TestLimitedReader_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := &limitedReader{
+		ctx:     ctx,
+		reader:  bytes.NewReader([]byte("data")),
+		limiter: rate.NewLimiter(1, 1),
+	}
+
+	_, err := r.Read(make([]byte, 4))
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,351 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/aerospike/absctl/internal/records"
+)
+
+const (
+	parquetMagic = "PAR1"
+	// parquetRowGroupSize is the number of records buffered before a row group is written.
+	parquetRowGroupSize = 10000
+	parquetCreatedBy    = "absctl"
+)
+
+// Parquet format enums, as defined in parquet.thrift.
+const (
+	parquetTypeInt64     = 2
+	parquetTypeByteArray = 6
+
+	parquetRepetitionRequired = 0
+
+	parquetConvertedTypeNone = -1
+	parquetConvertedTypeUTF8 = 0
+	parquetConvertedTypeJSON = 19
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecUncompressed = 0
+	parquetPageTypeData      = 0
+	parquetVersion           = 1
+)
+
+// parquetColumn is a column of the exported records.
+type parquetColumn struct {
+	name          string
+	physicalType  int32
+	convertedType int32
+	// appendValue appends the PLAIN encoded column value of the record.
+	appendValue func(buf []byte, line *records.RecordLine) ([]byte, error)
+}
+
+// parquetColumns are the columns of the exported records. All of them are required,
+// keys and bins are saved as JSON, with the same typed values as NDJSON.
+var parquetColumns = []parquetColumn{
+	{
+		name:          "namespace",
+		physicalType:  parquetTypeByteArray,
+		convertedType: parquetConvertedTypeUTF8,
+		appendValue: func(buf []byte, line *records.RecordLine) ([]byte, error) {
+			return appendByteArray(buf, []byte(line.Namespace)), nil
+		},
+	},
+	{
+		name:          "set",
+		physicalType:  parquetTypeByteArray,
+		convertedType: parquetConvertedTypeUTF8,
+		appendValue: func(buf []byte, line *records.RecordLine) ([]byte, error) {
+			return appendByteArray(buf, []byte(line.Set)), nil
+		},
+	},
+	{
+		name:          "key",
+		physicalType:  parquetTypeByteArray,
+		convertedType: parquetConvertedTypeJSON,
+		appendValue: func(buf []byte, line *records.RecordLine) ([]byte, error) {
+			return appendJSON(buf, line.Key)
+		},
+	},
+	{
+		name:          "digest",
+		physicalType:  parquetTypeByteArray,
+		convertedType: parquetConvertedTypeNone,
+		appendValue: func(buf []byte, line *records.RecordLine) ([]byte, error) {
+			return appendByteArray(buf, line.Digest), nil
+		},
+	},
+	{
+		name:          "generation",
+		physicalType:  parquetTypeInt64,
+		convertedType: parquetConvertedTypeNone,
+		appendValue: func(buf []byte, line *records.RecordLine) ([]byte, error) {
+			return binary.LittleEndian.AppendUint64(buf, uint64(line.Generation)), nil
+		},
+	},
+	{
+		name:          "void_time",
+		physicalType:  parquetTypeInt64,
+		convertedType: parquetConvertedTypeNone,
+		appendValue: func(buf []byte, line *records.RecordLine) ([]byte, error) {
+			return binary.LittleEndian.AppendUint64(buf, uint64(line.VoidTime)), nil
+		},
+	},
+	{
+		name:          "ttl",
+		physicalType:  parquetTypeInt64,
+		convertedType: parquetConvertedTypeNone,
+		appendValue: func(buf []byte, line *records.RecordLine) ([]byte, error) {
+			return binary.LittleEndian.AppendUint64(buf, uint64(line.TTL)), nil
+		},
+	},
+	{
+		name:          "bins",
+		physicalType:  parquetTypeByteArray,
+		convertedType: parquetConvertedTypeJSON,
+		appendValue: func(buf []byte, line *records.RecordLine) ([]byte, error) {
+			return appendJSON(buf, line.Bins)
+		},
+	},
+}
+
+// parquetChunk is the location of a column chunk in the file.
+type parquetChunk struct {
+	offset int64
+	size   int64
+}
+
+// parquetRowGroup is the location of a written row group.
+type parquetRowGroup struct {
+	numRows int64
+	chunks  []parquetChunk
+}
+
+// parquetEncoder writes records to a Parquet file. Records are buffered and written in row groups,
+// every column chunk is a single uncompressed data page.
+type parquetEncoder struct {
+	w io.Writer
+	// offset is the number of bytes written to w.
+	offset    int64
+	rows      []*records.RecordLine
+	rowGroups []parquetRowGroup
+}
+
+func newParquetEncoder(w io.Writer) *parquetEncoder {
+	return &parquetEncoder{
+		w:    w,
+		rows: make([]*records.RecordLine, 0, parquetRowGroupSize),
+	}
+}
+
+// Encode buffers the record and writes a row group when the buffer is full.
+func (e *parquetEncoder) Encode(line *records.RecordLine) error {
+	e.rows = append(e.rows, line)
+
+	if len(e.rows) < parquetRowGroupSize {
+		return nil
+	}
+
+	return e.writeRowGroup()
+}
+
+// Close writes the buffered records and the file metadata. It doesn't close the underlying writer.
+func (e *parquetEncoder) Close() error {
+	if len(e.rows) > 0 {
+		if err := e.writeRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	// A file without records still starts with the magic number.
+	if e.offset == 0 {
+		if err := e.write([]byte(parquetMagic)); err != nil {
+			return err
+		}
+	}
+
+	metadata := e.fileMetadata()
+
+	footer := binary.LittleEndian.AppendUint32(metadata, uint32(len(metadata)))
+	footer = append(footer, parquetMagic...)
+
+	return e.write(footer)
+}
+
+func (e *parquetEncoder) writeRowGroup() error {
+	if e.offset == 0 {
+		if err := e.write([]byte(parquetMagic)); err != nil {
+			return err
+		}
+	}
+
+	rowGroup := parquetRowGroup{
+		numRows: int64(len(e.rows)),
+		chunks:  make([]parquetChunk, 0, len(parquetColumns)),
+	}
+
+	var data []byte
+
+	for _, column := range parquetColumns {
+		data = data[:0]
+
+		for _, row := range e.rows {
+			var err error
+			if data, err = column.appendValue(data, row); err != nil {
+				return fmt.Errorf("failed to encode column %s: %w", column.name, err)
+			}
+		}
+
+		chunk := parquetChunk{offset: e.offset}
+
+		header := pageHeader(len(e.rows), len(data))
+		if err := e.write(header); err != nil {
+			return err
+		}
+
+		if err := e.write(data); err != nil {
+			return err
+		}
+
+		chunk.size = e.offset - chunk.offset
+		rowGroup.chunks = append(rowGroup.chunks, chunk)
+	}
+
+	e.rowGroups = append(e.rowGroups, rowGroup)
+	e.rows = e.rows[:0]
+
+	return nil
+}
+
+func (e *parquetEncoder) write(p []byte) error {
+	n, err := e.w.Write(p)
+	e.offset += int64(n)
+
+	if err != nil {
+		return fmt.Errorf("failed to write parquet file: %w", err)
+	}
+
+	return nil
+}
+
+// pageHeader encodes the PageHeader of a data page with the PLAIN encoded values.
+func pageHeader(numValues, size int) []byte {
+	t := newThriftWriter()
+	t.fieldI32(1, parquetPageTypeData)
+	t.fieldI32(2, int32(size))
+	t.fieldI32(3, int32(size))
+	// DataPageHeader.
+	t.fieldStruct(5)
+	t.fieldI32(1, int32(numValues))
+	t.fieldI32(2, parquetEncodingPlain)
+	t.fieldI32(3, parquetEncodingRLE)
+	t.fieldI32(4, parquetEncodingRLE)
+	t.endStruct()
+
+	return t.Bytes()
+}
+
+// fileMetadata encodes the FileMetaData with the schema and the written row groups.
+func (e *parquetEncoder) fileMetadata() []byte {
+	var numRows int64
+	for _, rowGroup := range e.rowGroups {
+		numRows += rowGroup.numRows
+	}
+
+	t := newThriftWriter()
+	t.fieldI32(1, parquetVersion)
+
+	// The schema is a flat list, with the root element first.
+	t.fieldList(2, thriftTypeStruct, len(parquetColumns)+1)
+	t.beginStruct()
+	t.fieldString(4, "schema")
+	t.fieldI32(5, int32(len(parquetColumns)))
+	t.endStruct()
+
+	for _, column := range parquetColumns {
+		t.beginStruct()
+		t.fieldI32(1, column.physicalType)
+		t.fieldI32(3, parquetRepetitionRequired)
+		t.fieldString(4, column.name)
+
+		if column.convertedType != parquetConvertedTypeNone {
+			t.fieldI32(6, column.convertedType)
+		}
+
+		t.endStruct()
+	}
+
+	t.fieldI64(3, numRows)
+
+	t.fieldList(4, thriftTypeStruct, len(e.rowGroups))
+
+	for _, rowGroup := range e.rowGroups {
+		var totalSize int64
+
+		t.beginStruct()
+		t.fieldList(1, thriftTypeStruct, len(rowGroup.chunks))
+
+		for i, chunk := range rowGroup.chunks {
+			totalSize += chunk.size
+
+			t.beginStruct()
+			t.fieldI64(2, chunk.offset)
+			// ColumnMetaData.
+			t.fieldStruct(3)
+			t.fieldI32(1, parquetColumns[i].physicalType)
+			t.fieldList(2, thriftTypeI32, 1)
+			t.elemI32(parquetEncodingPlain)
+			t.fieldList(3, thriftTypeBinary, 1)
+			t.elemString(parquetColumns[i].name)
+			t.fieldI32(4, parquetCodecUncompressed)
+			t.fieldI64(5, rowGroup.numRows)
+			t.fieldI64(6, chunk.size)
+			t.fieldI64(7, chunk.size)
+			t.fieldI64(9, chunk.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+
+		t.fieldI64(2, totalSize)
+		t.fieldI64(3, rowGroup.numRows)
+		t.endStruct()
+	}
+
+	t.fieldString(6, parquetCreatedBy)
+
+	return t.Bytes()
+}
+
+// appendByteArray appends a PLAIN encoded BYTE_ARRAY value.
+func appendByteArray(buf, v []byte) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
+	return append(buf, v...)
+}
+
+// appendJSON appends v marshaled to JSON as a BYTE_ARRAY value.
+func appendJSON(buf []byte, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendByteArray(buf, data), nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes the Thrift compact protocol structs written by thriftWriter.
+// Structs are decoded to maps by field id, integers to int64, lists to slices.
+type thriftReader struct {
+	t    *testing.T
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) readStruct() map[int16]any {
+	fields := make(map[int16]any)
+
+	var last int16
+
+	for {
+		header := r.data[r.pos]
+		r.pos++
+
+		if header == 0 {
+			return fields
+		}
+
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.varint())
+		}
+
+		last = id
+		fields[id] = r.readValue(header & 0x0f)
+	}
+}
+
+func (r *thriftReader) readValue(valueType byte) any {
+	switch valueType {
+	case thriftTypeI32, thriftTypeI64:
+		return r.varint()
+	case thriftTypeBinary:
+		size, n := binary.Uvarint(r.data[r.pos:])
+		r.pos += n
+		v := string(r.data[r.pos : r.pos+int(size)])
+		r.pos += int(size)
+
+		return v
+	case thriftTypeStruct:
+		return r.readStruct()
+	case thriftTypeList:
+		header := r.data[r.pos]
+		r.pos++
+
+		size := uint64(header >> 4)
+		if size == 15 {
+			var n int
+			size, n = binary.Uvarint(r.data[r.pos:])
+			r.pos += n
+		}
+
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.readValue(header & 0x0f)
+		}
+
+		return list
+	default:
+		r.t.Fatalf("unexpected thrift type %d", valueType)
+		return nil
+	}
+}
+
+func (r *thriftReader) varint() int64 {
+	v, n := binary.Varint(r.data[r.pos:])
+	r.pos += n
+
+	return v
+}
+
+func newTestLine(i int) *records.RecordLine {
+	return &records.RecordLine{
+		Type:       records.LineTypeRecord,
+		Namespace:  "test",
+		Set:        "demo",
+		Key:        &records.Value{Type: records.ValueTypeInt, Value: int64(i)},
+		Digest:     bytes.Repeat([]byte{byte(i)}, 20),
+		Generation: uint32(i),
+		VoidTime:   int64(i) * 10,
+		TTL:        records.TTLNeverExpire,
+		Bins: map[string]records.Value{
+			"bin": {Type: records.ValueTypeString, Value: "value"},
+		},
+	}
+}
+
+// readParquet checks the file layout and returns the decoded file metadata.
+func readParquet(t *testing.T, data []byte) map[int16]any {
+	t.Helper()
+
+	require.Equal(t, parquetMagic, string(data[:4]))
+	require.Equal(t, parquetMagic, string(data[len(data)-4:]))
+
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	r := &thriftReader{t: t, data: data[len(data)-8-size : len(data)-8]}
+	metadata := r.readStruct()
+	require.Equal(t, size, r.pos)
+
+	return metadata
+}
+
+// readColumn returns the raw PLAIN encoded values of the column chunk.
+func readColumn(t *testing.T, data []byte, chunk map[int16]any) []byte {
+	t.Helper()
+
+	offset := chunk[2].(int64)
+	r := &thriftReader{t: t, data: data[offset:]}
+	header := r.readStruct()
+
+	assert.Equal(t, int64(parquetPageTypeData), header[1])
+	assert.Equal(t, header[2], header[3])
+
+	size := int(header[2].(int64))
+
+	return data[offset+int64(r.pos) : offset+int64(r.pos)+int64(size)]
+}
+
+func TestParquetEncoder(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	enc := newParquetEncoder(&buf)
+	for i := range 3 {
+		require.NoError(t, enc.Encode(newTestLine(i)))
+	}
+
+	require.NoError(t, enc.Close())
+
+	data := buf.Bytes()
+	metadata := readParquet(t, data)
+
+	assert.Equal(t, int64(parquetVersion), metadata[1])
+	assert.Equal(t, int64(3), metadata[3])
+	assert.Equal(t, parquetCreatedBy, metadata[6])
+
+	schema := metadata[2].([]any)
+	require.Len(t, schema, len(parquetColumns)+1)
+	assert.Equal(t, int64(len(parquetColumns)), schema[0].(map[int16]any)[5])
+	assert.Equal(t, "key", schema[3].(map[int16]any)[4])
+	assert.Equal(t, int64(parquetConvertedTypeJSON), schema[3].(map[int16]any)[6])
+
+	rowGroups := metadata[4].([]any)
+	require.Len(t, rowGroups, 1)
+
+	chunks := rowGroups[0].(map[int16]any)[1].([]any)
+	require.Len(t, chunks, len(parquetColumns))
+
+	// Namespace.
+	namespaces := readColumn(t, data, chunks[0].(map[int16]any))
+	assert.Equal(t, bytes.Repeat(appendByteArray(nil, []byte("test")), 3), namespaces)
+
+	// Generation.
+	generations := readColumn(t, data, chunks[4].(map[int16]any))
+	require.Len(t, generations, 3*8)
+	assert.Equal(t, uint64(2), binary.LittleEndian.Uint64(generations[16:]))
+
+	// Bins.
+	bins := readColumn(t, data, chunks[7].(map[int16]any))
+	size := binary.LittleEndian.Uint32(bins)
+
+	var decoded map[string]records.Value
+	require.NoError(t, json.Unmarshal(bins[4:4+size], &decoded))
+	assert.Equal(t, "value", decoded["bin"].Value)
+}
+
+func TestParquetEncoder_RowGroups(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	enc := newParquetEncoder(&buf)
+	for i := range parquetRowGroupSize + 1 {
+		require.NoError(t, enc.Encode(newTestLine(i)))
+	}
+
+	require.NoError(t, enc.Close())
+
+	metadata := readParquet(t, buf.Bytes())
+	assert.Equal(t, int64(parquetRowGroupSize+1), metadata[3])
+
+	rowGroups := metadata[4].([]any)
+	require.Len(t, rowGroups, 2)
+	assert.Equal(t, int64(parquetRowGroupSize), rowGroups[0].(map[int16]any)[3])
+	assert.Equal(t, int64(1), rowGroups[1].(map[int16]any)[3])
+}
+
+func TestParquetEncoder_Empty(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NoError(t, newParquetEncoder(&buf).Close())
+
+	metadata := readParquet(t, buf.Bytes())
+	assert.Equal(t, int64(0), metadata[3])
+	assert.Empty(t, metadata[4])
+}
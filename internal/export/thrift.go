@@ -0,0 +1,125 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol types.
+const (
+	thriftTypeI32    = 5
+	thriftTypeI64    = 6
+	thriftTypeBinary = 8
+	thriftTypeList   = 9
+	thriftTypeStruct = 12
+)
+
+// thriftWriter encodes structs with the Thrift compact protocol, that is used by Parquet page headers
+// and file metadata.
+type thriftWriter struct {
+	buf bytes.Buffer
+	// lastField holds the last field id of every open struct, as field ids are delta encoded.
+	lastField []int16
+}
+
+// newThriftWriter returns a writer with an open top-level struct.
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{lastField: []int16{0}}
+}
+
+// Bytes closes the top-level struct and returns the encoded bytes.
+func (t *thriftWriter) Bytes() []byte {
+	t.endStruct()
+
+	return t.buf.Bytes()
+}
+
+func (t *thriftWriter) fieldI32(id int16, v int32) {
+	t.fieldHeader(id, thriftTypeI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) fieldI64(id int16, v int64) {
+	t.fieldHeader(id, thriftTypeI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) fieldString(id int16, v string) {
+	t.fieldHeader(id, thriftTypeBinary)
+	t.binary([]byte(v))
+}
+
+// fieldStruct opens a struct field, that must be closed with endStruct.
+func (t *thriftWriter) fieldStruct(id int16) {
+	t.fieldHeader(id, thriftTypeStruct)
+	t.beginStruct()
+}
+
+// fieldList writes the header of a list field, followed by size elements.
+func (t *thriftWriter) fieldList(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftTypeList)
+
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+
+	t.buf.WriteByte(0xf0 | elemType)
+	t.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+}
+
+// beginStruct opens a struct, list elements have no field header.
+func (t *thriftWriter) beginStruct() {
+	t.lastField = append(t.lastField, 0)
+}
+
+// endStruct writes the stop field and closes the struct.
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0)
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+func (t *thriftWriter) elemI32(v int32) {
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) elemString(v string) {
+	t.binary([]byte(v))
+}
+
+func (t *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	last := &t.lastField[len(t.lastField)-1]
+
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.varint(int64(id))
+	}
+
+	*last = id
+}
+
+// varint writes a zigzag encoded varint.
+func (t *thriftWriter) varint(v int64) {
+	t.buf.Write(binary.AppendVarint(nil, v))
+}
+
+func (t *thriftWriter) binary(v []byte) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(v))))
+	t.buf.Write(v)
+}
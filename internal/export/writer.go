@@ -0,0 +1,164 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aerospike/absctl/internal/codec"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/records"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// encoder writes records to an exported file.
+type encoder interface {
+	Encode(line *records.RecordLine) error
+	// Close flushes the buffered records, without closing the underlying writer.
+	Close() error
+}
+
+// ndjsonEncoder writes records one JSON object per line, as printed by inspect.
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e ndjsonEncoder) Encode(line *records.RecordLine) error {
+	return e.enc.Encode(line)
+}
+
+func (ndjsonEncoder) Close() error {
+	return nil
+}
+
+// Writer wraps backup.Writer and converts the .asb files written by the backup to NDJSON or Parquet.
+// The files keep their names with the extension of the format, so they can be split by file-limit
+// and written to any storage. Only records are exported, secondary indexes and UDFs are skipped.
+type Writer struct {
+	backup.Writer
+
+	format string
+	// skip holds the names of the files written as is, like the state file.
+	skip []string
+}
+
+// NewWriter returns a Writer that exports records to the format, one of models.OutputFormatNDJSON
+// or models.OutputFormatParquet.
+func NewWriter(w backup.Writer, format string, skip ...string) *Writer {
+	return &Writer{
+		Writer: w,
+		format: format,
+		skip:   skip,
+	}
+}
+
+// NewWriter opens the exported file on the wrapped writer. The returned writer is fed with the asb
+// encoded backup, that is decoded and exported in the background until it is closed.
+func (w *Writer) NewWriter(ctx context.Context, filename string) (io.WriteCloser, error) {
+	if slices.Contains(w.skip, path.Base(filename)) {
+		return w.Writer.NewWriter(ctx, filename)
+	}
+
+	target, err := w.Writer.NewWriter(ctx, FileName(filename, w.format))
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	ec := &exportWriteCloser{
+		PipeWriter: pw,
+		done:       make(chan error, 1),
+	}
+
+	go func() {
+		err := export(pr, target, path.Base(filename), w.format)
+		// Unblock the backup if the export failed before the whole file is read.
+		pr.CloseWithError(err)
+		ec.done <- err
+	}()
+
+	return ec, nil
+}
+
+// FileName replaces the .asb extension of a backup file with the extension of the format.
+func FileName(filename, format string) string {
+	return strings.TrimSuffix(filename, codec.ExtASB) + "." + format
+}
+
+// exportWriteCloser waits for the export to finish on Close.
+type exportWriteCloser struct {
+	*io.PipeWriter
+	done chan error
+}
+
+func (e *exportWriteCloser) Close() error {
+	if err := e.PipeWriter.Close(); err != nil {
+		return err
+	}
+
+	return <-e.done
+}
+
+// export decodes the asb records from r and writes them to target in the format.
+func export(r io.Reader, target io.WriteCloser, name, format string) error {
+	var enc encoder
+	if format == models.OutputFormatParquet {
+		enc = newParquetEncoder(target)
+	} else {
+		enc = ndjsonEncoder{enc: json.NewEncoder(target)}
+	}
+
+	err := exportRecords(r, enc, name)
+	if err == nil {
+		err = enc.Close()
+	}
+
+	if cErr := target.Close(); cErr != nil {
+		err = errors.Join(err, fmt.Errorf("failed to close exported file: %w", cErr))
+	}
+
+	return err
+}
+
+func exportRecords(r io.Reader, enc encoder, name string) error {
+	decoder, err := asb.NewDecoder[*bModels.Token](r, name, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+
+	now := time.Now()
+
+	return codec.DecodeTokens(decoder, func(token *bModels.Token) error {
+		if token.Type != bModels.TokenTypeRecord {
+			return nil
+		}
+
+		if err := enc.Encode(records.NewRecordLine(name, token.Record, now)); err != nil {
+			return fmt.Errorf("failed to export record: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/records"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testASB = "Version 3.1\n" +
+	"# namespace test\n" +
+	"# first-file\n" +
+	"+ k I 10\n" +
+	"+ n test\n" +
+	"+ d AAAAAAAAAAAAAAAAAAAAAAAAAAA=\n" +
+	"+ s demo\n" +
+	"+ g 2\n" +
+	"+ t 0\n" +
+	"+ b 1\n" +
+	"- I bin 100\n"
+
+func newTestWriter(t *testing.T, dir, format string, skip ...string) *Writer {
+	t.Helper()
+
+	params := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: dir,
+			},
+		},
+		ServiceConfigCommon: config.ServiceConfigCommon{
+			AwsS3:      &models.AwsS3{},
+			GcpStorage: &models.GcpStorage{},
+			AzureBlob:  &models.AzureBlob{},
+			Local:      &models.Local{},
+		},
+	}
+
+	writer, err := storage.NewBackupWriter(t.Context(), params, slog.Default())
+	require.NoError(t, err)
+
+	return NewWriter(writer, format, skip...)
+}
+
+func TestWriter_NDJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w := newTestWriter(t, dir, models.OutputFormatNDJSON)
+
+	wc, err := w.NewWriter(t.Context(), "test_0.asb")
+	require.NoError(t, err)
+
+	_, err = wc.Write([]byte(testASB))
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+
+	assert.NoFileExists(t, filepath.Join(dir, "test_0.asb"))
+
+	data, err := os.ReadFile(filepath.Join(dir, "test_0.ndjson"))
+	require.NoError(t, err)
+
+	var line records.RecordLine
+	require.NoError(t, json.Unmarshal(data, &line))
+	assert.Equal(t, records.LineTypeRecord, line.Type)
+	assert.Equal(t, "test_0.asb", line.File)
+	assert.Equal(t, "test", line.Namespace)
+	assert.Equal(t, "demo", line.Set)
+	assert.Equal(t, uint32(2), line.Generation)
+	assert.Equal(t, int64(records.TTLNeverExpire), line.TTL)
+	assert.Contains(t, line.Bins, "bin")
+}
+
+func TestWriter_SkippedFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w := newTestWriter(t, dir, models.OutputFormatParquet, "state")
+
+	wc, err := w.NewWriter(t.Context(), "state")
+	require.NoError(t, err)
+
+	_, err = wc.Write([]byte("state data"))
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+
+	data, err := os.ReadFile(filepath.Join(dir, "state"))
+	require.NoError(t, err)
+	assert.Equal(t, "state data", string(data))
+}
+
+func TestWriter_InvalidBackup(t *testing.T) {
+	t.Parallel()
+
+	w := newTestWriter(t, t.TempDir(), models.OutputFormatNDJSON)
+
+	wc, err := w.NewWriter(t.Context(), "test_0.asb")
+	require.NoError(t, err)
+
+	// The write fails or is buffered, depending on when the header is decoded.
+	_, _ = wc.Write([]byte("not a backup file\n"))
+	require.Error(t, wc.Close())
+}
+
+func TestFileName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "backup/test_0.ndjson", FileName("backup/test_0.asb", models.OutputFormatNDJSON))
+	assert.Equal(t, "test_0.parquet", FileName("test_0.asb", models.OutputFormatParquet))
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/secrets"
 	"github.com/aerospike/backup-go"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -49,6 +50,18 @@ func (f *App) NewFlagSet() *pflag.FlagSet {
 	flagSet.StringVar(&f.LogFile, "log-file",
 		models.DefaultAppLogFile,
 		"Path to log file. If empty, logs will be printed to stderr.")
+	flagSet.StringVar(&f.MetricsListen, "metrics-listen",
+		models.DefaultAppMetricsListen,
+		"Address to serve Prometheus metrics of backup and restore on, e.g. ':9100'.\n"+
+			"Metrics are served on the /metrics path while the run is in progress. If empty, metrics are not served.")
+	flagSet.StringVar(&f.MetricsTextfile, "metrics-textfile",
+		models.DefaultAppMetricsTextfile,
+		"Path to a file to write Prometheus metrics of backup and restore to, for the node exporter textfile collector.\n"+
+			"The file is updated while the run is in progress and after it finishes. If empty, the file is not written.")
+	flagSet.StringVar(&f.ReportFile, "report-file",
+		models.DefaultAppReportFile,
+		"Path to a file to write a JSON report of backup or restore to when the run ends, whether it succeeded or failed.\n"+
+			"The report contains stats, the configuration with secrets redacted, errors, timing and the storage target.")
 	flagSet.StringVar(&f.ConfigFilePath, "config",
 		models.DefaultAppConfigFilePath,
 		"Path to YAML configuration file.")
@@ -82,6 +95,9 @@ func (f *App) PreRun(cmd *cobra.Command, sa *models.SecretAgent) error {
 		flagAzureContainerName, flagAzureAccessTier,
 		// GCP Flags
 		flagGcpKeyPath, flagGcpBucketName, flagGcpEndpoint,
+		// SFTP Flags
+		flagSftpHost, flagSftpUser, flagSftpPassword,
+		flagSftpKeyFile, flagSftpKey, flagSftpKeyPassphrase, flagSftpPath,
 	}
 
 	fs := cmd.Flags()
@@ -114,7 +130,7 @@ func parseValueWithSecretAgent(ctx context.Context, fs *pflag.FlagSet, saCfg *ba
 		return nil
 	}
 
-	val, err := backup.ParseSecret(ctx, saCfg, curVal)
+	val, err := secrets.Resolve(ctx, saCfg, curVal)
 	if err != nil {
 		return fmt.Errorf("failed to get secret for %s: %w", name, err)
 	}
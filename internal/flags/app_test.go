@@ -33,6 +33,9 @@ func TestApp_NewFlagSet(t *testing.T) {
 		"--log-level", "error",
 		"--log-json",
 		"--log-file", "log.txt",
+		"--metrics-listen", ":9100",
+		"--metrics-textfile", "absctl.prom",
+		"--report-file", "report.json",
 		"--config", "config.yaml",
 	}
 
@@ -45,6 +48,9 @@ func TestApp_NewFlagSet(t *testing.T) {
 	assert.True(t, app.LogJSON, "Log JSON flag should be true when set")
 	assert.Equal(t, "config.yaml", app.ConfigFilePath, "Config flag should be config.yaml")
 	assert.Equal(t, "log.txt", app.LogFile, "Log file flag should be config.yaml")
+	assert.Equal(t, ":9100", app.MetricsListen, "Metrics listen flag should be :9100")
+	assert.Equal(t, "absctl.prom", app.MetricsTextfile, "Metrics textfile flag should be absctl.prom")
+	assert.Equal(t, "report.json", app.ReportFile, "Report file flag should be report.json")
 }
 
 func TestApp_NewFlagSet_DefaultValues(t *testing.T) {
@@ -63,4 +69,7 @@ func TestApp_NewFlagSet_DefaultValues(t *testing.T) {
 	assert.False(t, app.LogJSON, "Log JSON flag should default to false")
 	assert.Empty(t, app.ConfigFilePath, "Config flag should default be empty string")
 	assert.Empty(t, app.LogFile, "Log file flag should default be empty string")
+	assert.Empty(t, app.MetricsListen, "Metrics listen flag should default be empty string")
+	assert.Empty(t, app.MetricsTextfile, "Metrics textfile flag should default be empty string")
+	assert.Empty(t, app.ReportFile, "Report file flag should default be empty string")
 }
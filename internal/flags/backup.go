@@ -46,6 +46,10 @@ func (f *Backup) NewFlagSet() *pflag.FlagSet {
 		models.DefaultBackupOutputFile,
 		"Backup to a single backup file. Use '-' for stdout. Required, unless -d or -e is used.\n"+
 			"--file-limit will be ignored if this parameter is used.")
+	flagSet.StringVar(&f.OutputFormat, "output-format",
+		models.DefaultBackupOutputFormat,
+		"Format of the backup files: asb, ndjson or parquet. ndjson and parquet export records only,\n"+
+			"for analytics, and can't be restored. They require --directory and are not compressed or encrypted.")
 	flagSet.StringVarP(&f.OutputFilePrefix, "output-file-prefix", "q",
 		"",
 		"When using directory parameter, prepend a prefix to the names of the generated files.\n"+
@@ -54,6 +58,15 @@ func (f *Backup) NewFlagSet() *pflag.FlagSet {
 		models.DefaultBackupFileLimit,
 		"Rotate backup files when their size crosses the given\n"+
 			"value (in MiB). Only used when backing up to a directory.\n")
+	flagSet.StringVar(&f.Destinations, "destinations",
+		models.DefaultBackupDestinations,
+		"Comma-separated list of storages to write the backup to in one scan: local, s3, gcp, azure and sftp.\n"+
+			"Each storage is configured with its own flags and gets the same --directory or --output-file.\n"+
+			"The state file and the --incremental-from backup are read from the first one.")
+	flagSet.IntVar(&f.MinDestinations, "min-destinations",
+		models.DefaultBackupMinDestinations,
+		"The number of destinations that must succeed. A failed destination is dropped and the backup\n"+
+			"continues while this many are left. Default is 0, all destinations must succeed.")
 
 	flagSet.BoolVarP(&f.NoBins, "no-bins", "x",
 		models.DefaultBackupNoBins,
@@ -88,6 +101,12 @@ func (f *Backup) NewFlagSet() *pflag.FlagSet {
 			"Only include records that last changed before the given\n"+
 			"date and time. May combined with --modified-after to specify a range.")
 
+	flagSet.StringVar(&f.IncrementalFrom, "incremental-from",
+		models.DefaultBackupIncrementalFrom,
+		"Perform an incremental backup on top of the backup in the given directory or storage path.\n"+
+			"Records that changed after the start of that backup are included, and the backup chain\n"+
+			"is saved to the new backup manifest. Mutually exclusive with --modified-after.")
+
 	flagSet.StringVarP(&f.FilterExpression, "filter-exp", "f",
 		models.DefaultBackupFilterExpression,
 		"Base64 encoded filter expression. Use the encoded filter expression in each scan call,\n"+
@@ -159,6 +178,14 @@ func (f *Backup) NewFlagSet() *pflag.FlagSet {
 		models.DefaultBackupEstimateSamples,
 		"The number of samples to take when running a backup estimate.")
 
+	flagSet.BoolVar(&f.DryRun, "dry-run",
+		models.DefaultBackupDryRun,
+		"Print the backup plan and exit without scanning or writing any data.\n"+
+			"The plan shows partition filters, the nodes and racks to read from, sets, bins, the time window,\n"+
+			"the storage target, file naming and the existing files that --remove-files would delete.\n"+
+			"If problems are found, such as an unknown node, rack, namespace or set, or a non-empty directory,\n"+
+			"absctl exits with an error.")
+
 	flagSet.StringVar(&f.StateFileDst, "state-file-dst",
 		models.DefaultBackupStateFileDst,
 		"Name of a state file that will be saved in backup --directory.\n"+
@@ -172,11 +199,25 @@ func (f *Backup) NewFlagSet() *pflag.FlagSet {
 			"that was generated from the interrupted/failed run.\n"+
 			"--continue and --state-file-dst are mutually exclusive.")
 
+	flagSet.BoolVar(&f.Resumable, "resumable",
+		models.DefaultBackupResumable,
+		"Keep a state file in the backup --directory, so an interrupted backup is continued automatically\n"+
+			"by the next run with the same configuration. The state file is removed when the backup succeeds.\n"+
+			"Works only with --file-limit parameter. Not work with --rack-list or --node-list.\n"+
+			"--resumable is mutually exclusive with --state-file-dst and --continue.")
+
+	flagSet.BoolVar(&f.NoCheckpoint, "no-checkpoint",
+		models.DefaultBackupNoCheckpoint,
+		"Don't keep the state file absctl-resumable.state in the backup --directory.\n"+
+			"By default, backups to a directory save their state, so a backup interrupted by SIGINT or SIGTERM\n"+
+			"can be continued with --continue absctl-resumable.state. The state file is removed when the backup succeeds.\n"+
+			"The state is not saved without --file-limit, with --rack-list or --node-list, or for multiple namespaces.")
+
 	flagSet.Int64Var(&f.ScanPageSize, "scan-page-size",
 		models.DefaultBackupScanPageSize,
 		"Number of records will be read on one iteration for continuation backup.\n"+
 			"Affects size if overlap on resuming backup after an error.\n"+
-			"Used only with --state-file-dst or --continue.")
+			"Used only with --state-file-dst, --continue, --resumable or when the state is saved on interruption.")
 
 	return flagSet
 }
@@ -34,6 +34,7 @@ func TestBackup_NewFlagSet(t *testing.T) {
 		"--after-digest", "some-digest",
 		"--modified-before", "2023-09-01_12:00:00",
 		"--modified-after", "2023-09-02_12:00:00",
+		"--incremental-from", "/backup/full",
 		"--max-records", "1000",
 		"--no-bins",
 		"--max-retries", "3",
@@ -46,6 +47,12 @@ func TestBackup_NewFlagSet(t *testing.T) {
 		"--prefer-racks", "1,2,3,4",
 		"--rack-list", "1,2,3,4",
 		"--partition-list", "4000,1-236,EjRWeJq83vEjRRI0VniavN7xI0U=",
+		"--dry-run",
+		"--resumable",
+		"--no-checkpoint",
+		"--output-format", "parquet",
+		"--destinations", "local,s3",
+		"--min-destinations", "1",
 	}
 
 	err := flagSet.Parse(args)
@@ -58,6 +65,7 @@ func TestBackup_NewFlagSet(t *testing.T) {
 	assert.Equal(t, "some-digest", result.AfterDigest, "The after-digest flag should be parsed correctly")
 	assert.Equal(t, "2023-09-01_12:00:00", result.ModifiedBefore, "The modified-before flag should be parsed correctly")
 	assert.Equal(t, "2023-09-02_12:00:00", result.ModifiedAfter, "The modified-after flag should be parsed correctly")
+	assert.Equal(t, "/backup/full", result.IncrementalFrom, "The incremental-from flag should be parsed correctly")
 	assert.Equal(t, int64(1000), result.MaxRecords, "The max-records flag should be parsed correctly")
 	assert.True(t, result.NoBins, "The no-bins flag should be parsed correctly")
 	assert.Equal(t, 10, result.SleepBetweenRetries, "The sleep-between-retries flag should be parsed correctly")
@@ -70,6 +78,12 @@ func TestBackup_NewFlagSet(t *testing.T) {
 	assert.Equal(t, "1,2,3,4", result.RackList, "The rack-list flag should be parsed correctly")
 	assert.Equal(t, "4000,1-236,EjRWeJq83vEjRRI0VniavN7xI0U=", result.PartitionList, "The partition-list flag should be parsed correctly")
 	assert.Equal(t, 3, result.MaxRetries, "The max-retries flag should be parsed correctly")
+	assert.True(t, result.DryRun, "The dry-run flag should be parsed correctly")
+	assert.True(t, result.Resumable, "The resumable flag should be parsed correctly")
+	assert.True(t, result.NoCheckpoint, "The no-checkpoint flag should be parsed correctly")
+	assert.Equal(t, "parquet", result.OutputFormat, "The output-format flag should be parsed correctly")
+	assert.Equal(t, "local,s3", result.Destinations, "The destinations flag should be parsed correctly")
+	assert.Equal(t, 1, result.MinDestinations, "The min-destinations flag should be parsed correctly")
 }
 
 func TestBackup_NewFlagSet_DefaultValues(t *testing.T) {
@@ -89,6 +103,7 @@ func TestBackup_NewFlagSet_DefaultValues(t *testing.T) {
 	assert.Empty(t, result.AfterDigest, "The default value for after-digest should be an empty string")
 	assert.Empty(t, result.ModifiedBefore, "The default value for modified-before should be an empty string")
 	assert.Empty(t, result.ModifiedAfter, "The default value for modified-after should be an empty string")
+	assert.Empty(t, result.IncrementalFrom, "The default value for incremental-from should be an empty string")
 	assert.Equal(t, int64(0), result.MaxRecords, "The default value for max-records should be 0")
 	assert.False(t, result.NoBins, "The default value for no-bins should be false")
 	assert.Equal(t, 5, result.SleepBetweenRetries, "The default value for sleep-between-retries should be 5")
@@ -101,4 +116,10 @@ func TestBackup_NewFlagSet_DefaultValues(t *testing.T) {
 	assert.Empty(t, result.RackList, "The default value for rack list should be empty string")
 	assert.Empty(t, result.PartitionList, "The default value for partition-list should be empty string")
 	assert.Equal(t, 5, result.MaxRetries, "The default value for max-retries should be 5")
+	assert.False(t, result.DryRun, "The default value for dry-run should be false")
+	assert.False(t, result.Resumable, "The default value for resumable should be false")
+	assert.False(t, result.NoCheckpoint, "The default value for no-checkpoint should be false")
+	assert.Equal(t, "asb", result.OutputFormat, "The default value for output-format should be asb")
+	assert.Empty(t, result.Destinations, "The default value for destinations should be empty string")
+	assert.Equal(t, 0, result.MinDestinations, "The default value for min-destinations should be 0")
 }
@@ -29,7 +29,9 @@ const (
 )
 
 const (
-	descNamespaceBackup  = "The namespace to be backed up. Required."
+	descNamespaceBackup = "The namespace(s) to be backed up. Required.\n" +
+		"Accepts comma-separated values with no spaces: 'ns1,ns2' or 'all' to back up all namespaces.\n" +
+		"If multiple namespaces are being backed up, each one is saved into its own subdirectory of --directory."
 	descNamespaceRestore = "Used to restore to a different namespace. Example: source-ns,destination-ns"
 
 	descDirectoryBackup  = "The directory that holds the backup files. Required, unless -o or -e is used."
@@ -70,6 +72,23 @@ const (
 
 	descInfoTimeoutRestore = "Set the timeout (in ms) for asinfo commands sent from restore tool to the database.\n" +
 		"The info commands are to check version, get indexes, get udfs, count records, and check batch write support."
+
+	descPreHookBackup = "Shell command to run before the backup.\n" +
+		"If it exits with a non-zero code, the backup is not started.\n" +
+		"Hooks get the ABSCTL_OPERATION, ABSCTL_NAMESPACE, ABSCTL_DIRECTORY, ABSCTL_STATUS and\n" +
+		"ABSCTL_STATS_FILE environment variables describing the run."
+	descPreHookRestore = "Shell command to run before the restore.\n" +
+		"If it exits with a non-zero code, the restore is not started.\n" +
+		"Hooks get the ABSCTL_OPERATION, ABSCTL_NAMESPACE, ABSCTL_DIRECTORY, ABSCTL_STATUS and\n" +
+		"ABSCTL_STATS_FILE environment variables describing the run."
+
+	descPostHookBackup = "Shell command to run after the backup succeeded.\n" +
+		"If it exits with a non-zero code, absctl exits with an error."
+	descPostHookRestore = "Shell command to run after the restore succeeded.\n" +
+		"If it exits with a non-zero code, absctl exits with an error."
+
+	descOnFailureHookBackup  = "Shell command to run after the backup or the pre-hook failed."
+	descOnFailureHookRestore = "Shell command to run after the restore or the pre-hook failed."
 )
 
 type Common struct {
@@ -90,7 +109,8 @@ func (f *Common) NewFlagSet() *pflag.FlagSet {
 
 	var (
 		descNamespace, descSetList, descBinList, descNoRecords,
-		descNoIndexes, descNoUDFs, descParallel, descDirectory, descInfoTimeout string
+		descNoIndexes, descNoUDFs, descParallel, descDirectory, descInfoTimeout,
+		descPreHook, descPostHook, descOnFailureHook string
 		defaultTotalTimeout int64
 		defaultParallel     int
 	)
@@ -108,6 +128,9 @@ func (f *Common) NewFlagSet() *pflag.FlagSet {
 		defaultTotalTimeout = models.DefaultBackupTotalTimeout
 		defaultParallel = models.DefaultBackupParallel
 		descInfoTimeout = descInfoTimeoutBackup
+		descPreHook = descPreHookBackup
+		descPostHook = descPostHookBackup
+		descOnFailureHook = descOnFailureHookBackup
 	case OperationRestore:
 		descNamespace = descNamespaceRestore
 		descDirectory = descDirectoryRestore
@@ -120,6 +143,9 @@ func (f *Common) NewFlagSet() *pflag.FlagSet {
 		defaultTotalTimeout = models.DefaultRestoreTotalTimeout
 		defaultParallel = models.DefaultRestoreParallel
 		descInfoTimeout = descInfoTimeoutRestore
+		descPreHook = descPreHookRestore
+		descPostHook = descPostHookRestore
+		descOnFailureHook = descOnFailureHookRestore
 	}
 
 	flagSet.StringVarP(&f.fields.Directory, "directory", "d",
@@ -199,6 +225,24 @@ func (f *Common) NewFlagSet() *pflag.FlagSet {
 		models.DefaultCommonStdBufferSize,
 		"Buffer size in MiB for stdin and stdout operations. Used for pipelining.")
 
+	flagSet.Int64Var(&f.fields.ProgressInterval, "progress-interval",
+		models.DefaultCommonProgressInterval,
+		"Interval in seconds between progress reports with records/s, bytes/s, percent done and ETA.\n"+
+			"Progress is printed as a single line on a terminal, or logged if --log-json or --log-file is set.\n"+
+			"If 0, progress is not reported.")
+
+	flagSet.StringVar(&f.fields.PreHook, "pre-hook",
+		models.DefaultCommonPreHook,
+		descPreHook)
+
+	flagSet.StringVar(&f.fields.PostHook, "post-hook",
+		models.DefaultCommonPostHook,
+		descPostHook)
+
+	flagSet.StringVar(&f.fields.OnFailureHook, "on-failure-hook",
+		models.DefaultCommonOnFailureHook,
+		descOnFailureHook)
+
 	return flagSet
 }
 
@@ -48,6 +48,10 @@ func TestCommon_NewFlagSet(t *testing.T) {
 		"--info-retry-multiplier", "1",
 		"--info-max-retries", "1",
 		"--std-buffer", "1",
+		"--progress-interval", "10",
+		"--pre-hook", "pre.sh",
+		"--post-hook", "post.sh",
+		"--on-failure-hook", "failure.sh",
 	}
 
 	err := flagSet.Parse(args)
@@ -71,6 +75,10 @@ func TestCommon_NewFlagSet(t *testing.T) {
 	assert.InEpsilon(t, float64(1), result.InfoRetriesMultiplier, 0.0, "The info-retry-multiplier flag should be parsed correctly")
 	assert.Equal(t, uint(1), result.InfoMaxRetries, "The info-max-retries flag should be parsed correctly")
 	assert.Equal(t, 1, result.StdBufferSize, "The std-buffer flag should be parsed correctly")
+	assert.Equal(t, int64(10), result.ProgressInterval, "The progress-interval flag should be parsed correctly")
+	assert.Equal(t, "pre.sh", result.PreHook, "The pre-hook flag should be parsed correctly")
+	assert.Equal(t, "post.sh", result.PostHook, "The post-hook flag should be parsed correctly")
+	assert.Equal(t, "failure.sh", result.OnFailureHook, "The on-failure-hook flag should be parsed correctly")
 }
 
 func TestCommon_NewFlagSet_DefaultValues(t *testing.T) {
@@ -104,4 +112,8 @@ func TestCommon_NewFlagSet_DefaultValues(t *testing.T) {
 	assert.InEpsilon(t, float64(1), result.InfoRetriesMultiplier, 0.0, "The default value for info-retry-multiplier should be 1")
 	assert.Equal(t, uint(3), result.InfoMaxRetries, "The default value for info-max-retries should be 3")
 	assert.Equal(t, 4, result.StdBufferSize, "The default value for std-buffer should be 4194304")
+	assert.Equal(t, int64(0), result.ProgressInterval, "The default value for progress-interval should be 0")
+	assert.Empty(t, result.PreHook, "The default value for pre-hook should be an empty string")
+	assert.Empty(t, result.PostHook, "The default value for post-hook should be an empty string")
+	assert.Empty(t, result.OnFailureHook, "The default value for on-failure-hook should be an empty string")
 }
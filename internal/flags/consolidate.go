@@ -0,0 +1,62 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/spf13/pflag"
+)
+
+type Consolidate struct {
+	models.Consolidate
+}
+
+func NewConsolidate() *Consolidate {
+	return &Consolidate{}
+}
+
+func (f *Consolidate) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVar(&f.DirectoryList, "directory-list",
+		"",
+		"A comma-separated list of paths to the backups to consolidate: the full backup first,\n"+
+			"then its incremental backups in the order they were made. The paths may not contain commas.\n"+
+			"The backups must have the same set, bin and partition filters. Deletes and durable delete\n"+
+			"tombstones are not in incremental backups, so deleted records are kept in the consolidated backup.\n"+
+			"Example: 'absctl consolidate --directory-list /path/to/full,/path/to/inc1 -d /path/to/consolidated'")
+	flagSet.StringVar(&f.ParentDirectory, "parent-directory",
+		"",
+		"A common root path for all paths used in --directory-list.\n"+
+			"This path is prepended to all entries in --directory-list.")
+	flagSet.StringVarP(&f.Directory, "directory", "d",
+		models.DefaultCommonDirectory,
+		"The directory the consolidated full backup is written to. It must be empty.")
+	flagSet.Uint64VarP(&f.FileLimit, "file-limit", "F",
+		models.DefaultBackupFileLimit,
+		"Rotate the files of the consolidated backup when their size crosses the given\n"+
+			"value (in MiB). 0 means no limit.")
+	flagSet.Uint64Var(&f.MaxDigests, "max-digests",
+		models.DefaultConsolidateMaxDigests,
+		"The maximum number of records in the incremental backups. Their digests are kept in memory,\n"+
+			"about 40 bytes each, to skip older versions of the records. Consolidation fails before\n"+
+			"reading any file if the manifests count more records. 0 means no limit.")
+
+	return flagSet
+}
+
+func (f *Consolidate) GetConsolidate() *models.Consolidate {
+	return &f.Consolidate
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsolidate_NewFlagSet(t *testing.T) {
+	t.Parallel()
+	consolidate := NewConsolidate()
+
+	flagSet := consolidate.NewFlagSet()
+
+	args := []string{
+		"--directory-list", "full,inc1",
+		"--parent-directory", "/backups",
+		"--directory", "/backups/consolidated",
+		"--file-limit", "100",
+		"--max-digests", "1000",
+	}
+
+	err := flagSet.Parse(args)
+	require.NoError(t, err)
+
+	result := consolidate.GetConsolidate()
+
+	assert.Equal(t, "full,inc1", result.DirectoryList, "The directory-list flag should be parsed correctly")
+	assert.Equal(t, "/backups", result.ParentDirectory, "The parent-directory flag should be parsed correctly")
+	assert.Equal(t, "/backups/consolidated", result.Directory, "The directory flag should be parsed correctly")
+	assert.Equal(t, uint64(100), result.FileLimit, "The file-limit flag should be parsed correctly")
+	assert.Equal(t, uint64(1000), result.MaxDigests, "The max-digests flag should be parsed correctly")
+}
+
+func TestConsolidate_NewFlagSet_DefaultValues(t *testing.T) {
+	t.Parallel()
+	consolidate := NewConsolidate()
+
+	flagSet := consolidate.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	require.NoError(t, err)
+
+	result := consolidate.GetConsolidate()
+
+	assert.Empty(t, result.DirectoryList, "The default value for directory-list should be empty")
+	assert.Empty(t, result.ParentDirectory, "The default value for parent-directory should be empty")
+	assert.Empty(t, result.Directory, "The default value for directory should be empty")
+	assert.Equal(t, uint64(250), result.FileLimit, "The default value for file-limit should be 250")
+	assert.Equal(t, models.DefaultConsolidateMaxDigests, result.MaxDigests,
+		"The default value for max-digests should be set")
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/spf13/pflag"
+)
+
+type Copy struct {
+	models.Copy
+}
+
+func NewCopy() *Copy {
+	return &Copy{}
+}
+
+func (f *Copy) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVar(&f.From, "from",
+		"",
+		"The backup to copy: a local directory, s3://bucket/path, gs://bucket/path or az://container/path.\n"+
+			"Cloud storages are configured with the AWS, GCP and Azure flags, the bucket is taken from the path.")
+	flagSet.StringVar(&f.To, "to",
+		"",
+		"The location the backup is copied to, in the same form as --from.\n"+
+			"Files already copied by an interrupted copy are checked and skipped.")
+	flagSet.IntVarP(&f.Parallel, "parallel", "w",
+		models.DefaultCopyParallel,
+		"The number of files copied at once.")
+	flagSet.Int64VarP(&f.Bandwidth, "bandwidth", "N",
+		models.DefaultCopyBandwidth,
+		"The limit for the total bandwidth of the copy, including the checks, in MiB/s.\n"+
+			"Default is 0 (no limit).")
+
+	return flagSet
+}
+
+func (f *Copy) GetCopy() *models.Copy {
+	return &f.Copy
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopy_NewFlagSet(t *testing.T) {
+	t.Parallel()
+	cp := NewCopy()
+
+	flagSet := cp.NewFlagSet()
+
+	args := []string{
+		"--from", "/backups/full",
+		"--to", "s3://bucket/backups/full",
+		"--parallel", "8",
+		"--bandwidth", "100",
+	}
+
+	err := flagSet.Parse(args)
+	require.NoError(t, err)
+
+	result := cp.GetCopy()
+
+	assert.Equal(t, "/backups/full", result.From, "The from flag should be parsed correctly")
+	assert.Equal(t, "s3://bucket/backups/full", result.To, "The to flag should be parsed correctly")
+	assert.Equal(t, 8, result.Parallel, "The parallel flag should be parsed correctly")
+	assert.Equal(t, int64(100), result.Bandwidth, "The bandwidth flag should be parsed correctly")
+}
+
+func TestCopy_NewFlagSet_DefaultValues(t *testing.T) {
+	t.Parallel()
+	cp := NewCopy()
+
+	flagSet := cp.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	require.NoError(t, err)
+
+	result := cp.GetCopy()
+
+	assert.Empty(t, result.From, "The default value for from should be empty")
+	assert.Empty(t, result.To, "The default value for to should be empty")
+	assert.Equal(t, 4, result.Parallel, "The default value for parallel should be 4")
+	assert.Equal(t, int64(0), result.Bandwidth, "The default value for bandwidth should be 0")
+}
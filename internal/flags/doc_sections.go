@@ -17,8 +17,13 @@ package flags
 // Text for usage pretty-print.
 
 const (
-	SectionTextUsageBackup  = "\nUsage:\n  absctl backup [flags]"
-	SectionTextUsageRestore = "\nUsage:\n  absctl restore [flags]"
+	SectionTextUsageBackup      = "\nUsage:\n  absctl backup [flags]"
+	SectionTextUsageRestore     = "\nUsage:\n  absctl restore [flags]"
+	SectionTextUsageVerify      = "\nUsage:\n  absctl verify [flags]"
+	SectionTextUsageInspect     = "\nUsage:\n  absctl inspect [flags]"
+	SectionTextUsageConsolidate = "\nUsage:\n  absctl consolidate [flags]"
+	SectionTextUsageTranscode   = "\nUsage:\n  absctl transcode [flags]"
+	SectionTextUsageCopy        = "\nUsage:\n  absctl copy [flags]"
 
 	SectionTextSecretAgentBackup = "\nSecret Agent Flags:\n" +
 		"Options pertaining to the Aerospike Secret Agent.\n" +
@@ -35,8 +40,13 @@ const (
 		"To use a secret as an option, use this format: 'secrets:<resource_name>:<secret_name>' \n" +
 		"Example: absctl restore --azure-account-name secret:resource1:azaccount"
 
-	SectionTextBackup  = "\nBackup Flags:"
-	SectionTextRestore = "\nBackup Flags:"
+	SectionTextBackup      = "\nBackup Flags:"
+	SectionTextRestore     = "\nBackup Flags:"
+	SectionTextVerify      = "\nVerify Flags:"
+	SectionTextInspect     = "\nInspect Flags:"
+	SectionTextConsolidate = "\nConsolidate Flags:"
+	SectionTextTranscode   = "\nTranscode Flags:"
+	SectionTextCopy        = "\nCopy Flags:"
 
 	SectionTextGeneral     = "\nGeneral Flags:"
 	SectionTextAerospike   = "\nAerospike Client Flags:"
@@ -61,4 +71,11 @@ const (
 		"For authentication, use --azure-account-name and --azure-account-key, or \n" +
 		"--azure-tenant-id, --azure-client-id and --azure-client-secret.\n" +
 		"Any Azure parameter can be retrieved from Secret Agent."
+	SectionTextSftp = "\nSFTP Storage Flags:\n" +
+		"For SFTP storage, the server must be set with the --sftp-host and --sftp-user flags.\n" +
+		"--directory path is relative to --sftp-path, or to the login directory of the user.\n" +
+		"For authentication, use --sftp-password, or the private key with --sftp-key-file or --sftp-key\n" +
+		"and an optional --sftp-key-passphrase.\n" +
+		"The host key of the server is verified with the --sftp-known-hosts file.\n" +
+		"Any SFTP credential can be retrieved from Secret Agent."
 )
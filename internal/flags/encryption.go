@@ -20,7 +20,9 @@ import (
 )
 
 const (
-	descEncryptBackup  = "Enables encryption of backup files using the specified encryption algorithm.\n"
+	descEncryptBackup = "Enables encryption of backup files using the specified encryption algorithm.\n" +
+		"The manifest is signed with a key derived from the encryption key.\n" +
+		"Manifests of unencrypted backups are not signed.\n"
 	descEncryptRestore = "Enables decryption of backup files using the specified encryption algorithm.\n" +
 		"This must match the encryption mode used when backing up the data.\n"
 	defaultNoneVal = "NONE"
@@ -0,0 +1,56 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/spf13/pflag"
+)
+
+type Inspect struct {
+	models.Inspect
+}
+
+func NewInspect() *Inspect {
+	return &Inspect{}
+}
+
+func (f *Inspect) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVarP(&f.Directory, "directory", "d",
+		models.DefaultCommonDirectory,
+		"The directory that holds the backup files to inspect.")
+	flagSet.StringVarP(&f.InputFile, "input-file", "i",
+		"",
+		"Inspect a single backup file. --directory and --input-file are mutually exclusive.")
+	flagSet.Int64Var(&f.Limit, "limit",
+		0,
+		"The maximum number of records to print. 0 means no limit.")
+	flagSet.StringVar(&f.SetList, "set",
+		"",
+		"Only print records and secondary indexes of the given sets.\n"+
+			"The input value is a comma-separated list of sets.")
+	flagSet.StringVar(&f.DigestList, "digest",
+		"",
+		"Only print records with the given Base64 encoded digests.\n"+
+			"The input value is a comma-separated list of digests. UDFs and secondary indexes are not printed.")
+
+	return flagSet
+}
+
+func (f *Inspect) GetInspect() *models.Inspect {
+	return &f.Inspect
+}
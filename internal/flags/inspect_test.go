@@ -0,0 +1,64 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect_NewFlagSet(t *testing.T) {
+	t.Parallel()
+	inspect := NewInspect()
+
+	flagSet := inspect.NewFlagSet()
+
+	args := []string{
+		"--input-file", "/path/to/backup/test_0.asb",
+		"--limit", "10",
+		"--set", "set1,set2",
+		"--digest", "EjRWeJq83vEjRRI0VniavN7xI0U=",
+	}
+
+	err := flagSet.Parse(args)
+	require.NoError(t, err)
+
+	result := inspect.GetInspect()
+
+	assert.Equal(t, "/path/to/backup/test_0.asb", result.InputFile, "The input-file flag should be parsed correctly")
+	assert.Equal(t, int64(10), result.Limit, "The limit flag should be parsed correctly")
+	assert.Equal(t, "set1,set2", result.SetList, "The set flag should be parsed correctly")
+	assert.Equal(t, "EjRWeJq83vEjRRI0VniavN7xI0U=", result.DigestList, "The digest flag should be parsed correctly")
+}
+
+func TestInspect_NewFlagSet_DefaultValues(t *testing.T) {
+	t.Parallel()
+	inspect := NewInspect()
+
+	flagSet := inspect.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	require.NoError(t, err)
+
+	result := inspect.GetInspect()
+
+	assert.Empty(t, result.Directory, "The default value for directory should be empty")
+	assert.Empty(t, result.InputFile, "The default value for input-file should be empty")
+	assert.Equal(t, int64(0), result.Limit, "The default value for limit should be 0")
+	assert.Empty(t, result.SetList, "The default value for set should be empty")
+	assert.Empty(t, result.DigestList, "The default value for digest should be empty")
+}
@@ -33,7 +33,7 @@ func (f *Restore) NewFlagSet() *pflag.FlagSet {
 	flagSet.StringVarP(&f.InputFile, "input-file", "i",
 		models.DefaultRestoreInputFile,
 		"Restore from a single backup file. Use '-' for stdin.\n"+
-			"Required, unless --directory or --directory-list is used.\n")
+			"Required, unless --directory, --directory-list or --backup-root is used.\n")
 
 	flagSet.StringVar(&f.DirectoryList, "directory-list",
 		models.DefaultRestoreDirectoryList,
@@ -48,6 +48,19 @@ func (f *Restore) NewFlagSet() *pflag.FlagSet {
 			"Example: 'absctl restore --parent-directory /common/root/path\n"+
 			"--directory-list /path/to/dir1/,/path/to/dir2'\n")
 
+	flagSet.StringVar(&f.BackupRoot, "backup-root",
+		models.DefaultRestoreBackupRoot,
+		"Path to a directory that holds full and incremental backups in its subdirectories, found by their\n"+
+			"manifests. Restores the namespace as it was at --point-in-time: the latest full backup started\n"+
+			"before it, then the incremental backups started after the full backup, in order.\n"+
+			"Example: 'absctl restore --backup-root /backups --point-in-time 2024-01-02_15:04:05'\n")
+
+	flagSet.StringVar(&f.PointInTime, "point-in-time",
+		models.DefaultRestorePointInTime,
+		"<YYYY-MM-DD_HH:MM:SS>\n"+
+			"The local date and time to restore the namespace to, with --backup-root. The namespace is restored\n"+
+			"to its state at the start of the last backup started before the given date and time.\n")
+
 	flagSet.BoolVarP(&f.Uniq, "unique", "u",
 		models.DefaultRestoreUniq,
 		"Skip modifying records that already exist in the namespace.")
@@ -152,6 +165,66 @@ func (f *Restore) NewFlagSet() *pflag.FlagSet {
 		"Defines when to restore metadata (secondary indexes and UDFs).\n"+
 			"If set to true, metadata from separate file will be restored after all records have been processed.")
 
+	flagSet.StringVarP(&f.FilterExpression, "filter-exp", "f",
+		models.DefaultRestoreFilterExpression,
+		"Base64 encoded filter expression, evaluated against each record read from the backup files.\n"+
+			"Only matching records are restored, filtered records are counted in the restore report.\n"+
+			"The expression can be Base64 encoded through any client. Record last update time and size\n"+
+			"are not stored in backup files, so expressions using them are not supported.")
+	flagSet.StringVar(&f.SetMap, "set-map",
+		models.DefaultRestoreSetMap,
+		"Comma-separated list of sets to rename, in the old:new format. Records and secondary indexes\n"+
+			"are restored to the new sets. The digest of records with a stored key is recalculated for the new set.\n"+
+			"--set-list and --filter-exp use the old names.")
+	flagSet.StringVar(&f.BinMap, "bin-map",
+		models.DefaultRestoreBinMap,
+		"Comma-separated list of bins to rename, in the old:new format. Bins of records and secondary indexes\n"+
+			"are restored with the new names. --bin-list and --filter-exp use the old names.")
+	flagSet.StringVar(&f.DigestFile, "digest-file",
+		models.DefaultRestoreDigestFile,
+		"Path to a file of record digests, one per line in hex or Base64. Only the listed records are restored,\n"+
+			"requested records that are not found in the backup are listed in the restore report.")
+	flagSet.StringVar(&f.KeyFile, "key-file",
+		models.DefaultRestoreKeyFile,
+		"Path to a file of record keys, one per line in the <set>:<type>:<key> format, with type string, int\n"+
+			"or blob (Base64 encoded). Only the listed records are restored, requested records that are not found\n"+
+			"in the backup are listed in the restore report. Can be combined with --digest-file.")
+	flagSet.StringVar(&f.RejectDir, "reject-dir",
+		models.DefaultRestoreRejectDir,
+		"Directory where records that fail to write are saved, on the storage of the backup. Records are saved\n"+
+			"to rejected.asb, so they can be restored again, and their errors to rejected.ndjson.\n"+
+			"Use with --ignore-record-error to restore the other records. Batch writes are disabled.")
+	flagSet.StringVar(&f.StateFileDst, "state-file-dst",
+		models.DefaultRestoreStateFileDst,
+		"Path to the state file where the progress of the restore is saved, on the storage of the backup:\n"+
+			"the backup files that are restored, and the number of records restored from the others. The state\n"+
+			"is saved periodically and when the restore ends. --continue resumes a failed restore from the state file.")
+	flagSet.StringVarP(&f.Continue, "continue", "c",
+		models.DefaultRestoreContinue,
+		"Resumes an interrupted/failed restore from where it was left off, given the state file\n"+
+			"that was saved by the interrupted/failed run. The progress is saved to the same file.\n"+
+			"--continue and --state-file-dst are mutually exclusive.")
+
+	flagSet.StringVar(&f.InputFormat, "input-format",
+		models.DefaultRestoreInputFormat,
+		"Format of the restored files: asb, ndjson or csv. Rows of .ndjson or .csv files are imported as records,\n"+
+			"mapped with --key-field, --set-field or --set-name and --bin-fields. CSV files must start with a header line.")
+	flagSet.StringVar(&f.KeyField, "key-field",
+		models.DefaultRestoreKeyField,
+		"Field of the imported rows used as the record user key, in the <name>[:<type>] format.\n"+
+			"The type is string, int or blob. Required with --input-format ndjson or csv.")
+	flagSet.StringVar(&f.SetField, "set-field",
+		models.DefaultRestoreSetField,
+		"Field of the imported rows that holds the set of the record.")
+	flagSet.StringVar(&f.SetName, "set-name",
+		models.DefaultRestoreSetName,
+		"Set of all imported records. Mutually exclusive with --set-field.")
+	flagSet.StringVar(&f.BinFields, "bin-fields",
+		models.DefaultRestoreBinFields,
+		"Comma-separated list of fields of the imported rows written as bins, in the <name>[:<type>] format.\n"+
+			"Types: string, int, float, bool, blob (Base64) or json (a list or map). Without a type, NDJSON values keep\n"+
+			"their JSON type and CSV values are strings. By default, all fields except the key and set fields are imported.")
+
 	return flagSet
 }
 
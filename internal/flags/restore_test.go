@@ -37,9 +37,23 @@ func TestRestore_NewFlagSet(t *testing.T) {
 		"--extra-ttl", "3600",
 		"--directory-list", "dir1,dir2",
 		"--parent-directory", "parent-dir",
+		"--backup-root", "backups",
+		"--point-in-time", "2024-01-02_15:04:05",
 		"--warm-up", "10",
 		"--validate",
 		"--apply-metadata-last",
+		"--filter-exp", "kwGTUQKhYQE=",
+		"--set-map", "prod:qa",
+		"--bin-map", "a:b,c:d",
+		"--digest-file", "digests.txt",
+		"--key-file", "keys.txt",
+		"--reject-dir", "rejects",
+		"--state-file-dst", "restore.state",
+		"--continue", "old.state",
+		"--input-format", "csv",
+		"--key-field", "id:int",
+		"--set-name", "users",
+		"--bin-fields", "name,age:int",
 	}
 
 	err := flagSet.Parse(args)
@@ -55,9 +69,23 @@ func TestRestore_NewFlagSet(t *testing.T) {
 	assert.Equal(t, int64(3600), result.ExtraTTL, "The extra-ttl flag should be parsed correctly")
 	assert.Equal(t, "dir1,dir2", result.DirectoryList, "The directory-list flag should be parsed correctly")
 	assert.Equal(t, "parent-dir", result.ParentDirectory, "The parent-directory flag should be parsed correctly")
+	assert.Equal(t, "backups", result.BackupRoot, "The backup-root flag should be parsed correctly")
+	assert.Equal(t, "2024-01-02_15:04:05", result.PointInTime, "The point-in-time flag should be parsed correctly")
 	assert.Equal(t, 10, result.WarmUp, "The warm-up flag should be parsed correctly")
 	assert.True(t, result.ValidateOnly, "The validate flag should be parsed correctly")
 	assert.True(t, result.ApplyMetadataLast, "The apply-metadata-last flag should be parsed correctly")
+	assert.Equal(t, "kwGTUQKhYQE=", result.FilterExpression, "The filter-exp flag should be parsed correctly")
+	assert.Equal(t, "prod:qa", result.SetMap, "The set-map flag should be parsed correctly")
+	assert.Equal(t, "a:b,c:d", result.BinMap, "The bin-map flag should be parsed correctly")
+	assert.Equal(t, "digests.txt", result.DigestFile, "The digest-file flag should be parsed correctly")
+	assert.Equal(t, "keys.txt", result.KeyFile, "The key-file flag should be parsed correctly")
+	assert.Equal(t, "rejects", result.RejectDir, "The reject-dir flag should be parsed correctly")
+	assert.Equal(t, "restore.state", result.StateFileDst, "The state-file-dst flag should be parsed correctly")
+	assert.Equal(t, "old.state", result.Continue, "The continue flag should be parsed correctly")
+	assert.Equal(t, "csv", result.InputFormat, "The input-format flag should be parsed correctly")
+	assert.Equal(t, "id:int", result.KeyField, "The key-field flag should be parsed correctly")
+	assert.Equal(t, "users", result.SetName, "The set-name flag should be parsed correctly")
+	assert.Equal(t, "name,age:int", result.BinFields, "The bin-fields flag should be parsed correctly")
 }
 
 func TestRestore_NewFlagSet_DefaultValues(t *testing.T) {
@@ -81,7 +109,19 @@ func TestRestore_NewFlagSet_DefaultValues(t *testing.T) {
 	assert.Equal(t, int64(0), result.ExtraTTL, "The default value for extra-ttl should be 0")
 	assert.Empty(t, result.DirectoryList, "The directory-list flag should be an empty string")
 	assert.Empty(t, result.ParentDirectory, "The parent-directory flag should be an empty string")
+	assert.Empty(t, result.BackupRoot, "The backup-root flag should be an empty string")
+	assert.Empty(t, result.PointInTime, "The point-in-time flag should be an empty string")
 	assert.Equal(t, 0, result.WarmUp, "The warm-up flag should be 0")
 	assert.False(t, result.ValidateOnly, "The validate flag should be false")
 	assert.False(t, result.ApplyMetadataLast, "The default value for apply-metadata-last should be false")
+	assert.Empty(t, result.FilterExpression, "The default value for filter-exp should be an empty string")
+	assert.Empty(t, result.SetMap, "The default value for set-map should be an empty string")
+	assert.Empty(t, result.BinMap, "The default value for bin-map should be an empty string")
+	assert.Empty(t, result.DigestFile, "The default value for digest-file should be an empty string")
+	assert.Empty(t, result.KeyFile, "The default value for key-file should be an empty string")
+	assert.Empty(t, result.RejectDir, "The default value for reject-dir should be an empty string")
+	assert.Empty(t, result.StateFileDst, "The default value for state-file-dst should be an empty string")
+	assert.Empty(t, result.Continue, "The default value for continue should be an empty string")
+	assert.Equal(t, "asb", result.InputFormat, "The default value for input-format should be asb")
+	assert.Empty(t, result.KeyField, "The default value for key-field should be an empty string")
 }
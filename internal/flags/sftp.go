@@ -0,0 +1,104 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagSftpHost           = "sftp-host"
+	flagSftpPort           = "sftp-port"
+	flagSftpUser           = "sftp-user"
+	flagSftpPassword       = "sftp-password"
+	flagSftpKeyFile        = "sftp-key-file"
+	flagSftpKey            = "sftp-key"
+	flagSftpKeyPassphrase  = "sftp-key-passphrase"
+	flagSftpKnownHosts     = "sftp-known-hosts"
+	flagSftpPath           = "sftp-path"
+	flagSftpConnectTimeout = "sftp-connect-timeout"
+	flagSftpBufferSize     = "sftp-buffer-size"
+)
+
+type Sftp struct {
+	operation Operation
+	models.Sftp
+}
+
+func NewSftp(operation Operation) *Sftp {
+	return &Sftp{
+		operation: operation,
+	}
+}
+
+func (f *Sftp) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVar(&f.Host, flagSftpHost,
+		models.DefaultSftpHost,
+		"SFTP server host.")
+
+	flagSet.IntVar(&f.Port, flagSftpPort,
+		models.DefaultSftpPort,
+		"SFTP server port.")
+
+	flagSet.StringVar(&f.User, flagSftpUser,
+		models.DefaultSftpUser,
+		"SFTP user name.")
+
+	flagSet.StringVar(&f.Password, flagSftpPassword,
+		models.DefaultSftpPassword,
+		"SFTP password for password authorization.")
+
+	flagSet.StringVar(&f.KeyFile, flagSftpKeyFile,
+		models.DefaultSftpKeyFile,
+		"Path to the private key file for public key authorization.")
+
+	flagSet.StringVar(&f.Key, flagSftpKey,
+		models.DefaultSftpKey,
+		"PEM content of the private key for public key authorization, e.g. from the Secret Agent.\n"+
+			"Can't be used with a private key file.")
+
+	flagSet.StringVar(&f.KeyPassphrase, flagSftpKeyPassphrase,
+		models.DefaultSftpKeyPassphrase,
+		"Passphrase of the private key, if the key is encrypted.")
+
+	flagSet.StringVar(&f.KnownHosts, flagSftpKnownHosts,
+		models.DefaultSftpKnownHosts,
+		"Path to the known_hosts file the host key of the server is verified with.\n"+
+			"If not set, ~/.ssh/known_hosts is used.")
+
+	flagSet.StringVar(&f.Path, flagSftpPath,
+		models.DefaultSftpPath,
+		"Remote directory that --directory and file paths are relative to.\n"+
+			"If not set, paths are relative to the login directory of the user.")
+
+	flagSet.Int64Var(&f.ConnectTimeout, flagSftpConnectTimeout,
+		models.DefaultSftpConnectTimeout,
+		"Timeout (in ms) for establishing the SSH connection to the server. 0 means no limit.")
+
+	if f.operation == OperationBackup {
+		flagSet.IntVar(&f.BufferSize, flagSftpBufferSize,
+			models.DefaultSftpBufferSize,
+			"Buffer size in MiB for file uploads. Minimum value is 1.")
+	}
+
+	return flagSet
+}
+
+func (f *Sftp) GetSftp() *models.Sftp {
+	return &f.Sftp
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSftp_NewFlagSetBackup(t *testing.T) {
+	t.Parallel()
+	sftp := NewSftp(OperationBackup)
+
+	flagSet := sftp.NewFlagSet()
+
+	args := []string{
+		"--" + flagSftpHost, "sftp.local",
+		"--" + flagSftpPort, "2022",
+		"--" + flagSftpUser, "backup",
+		"--" + flagSftpPassword, "secret",
+		"--" + flagSftpKeyFile, "/keys/id_ed25519",
+		"--" + flagSftpKey, "key",
+		"--" + flagSftpKeyPassphrase, "passphrase",
+		"--" + flagSftpKnownHosts, "/keys/known_hosts",
+		"--" + flagSftpPath, "/backups",
+		"--" + flagSftpConnectTimeout, "5000",
+		"--" + flagSftpBufferSize, "8",
+	}
+
+	err := flagSet.Parse(args)
+	require.NoError(t, err)
+
+	result := sftp.GetSftp()
+
+	assert.Equal(t, "sftp.local", result.Host, "The sftp-host flag should be parsed correctly")
+	assert.Equal(t, 2022, result.Port, "The sftp-port flag should be parsed correctly")
+	assert.Equal(t, "backup", result.User, "The sftp-user flag should be parsed correctly")
+	assert.Equal(t, "secret", result.Password, "The sftp-password flag should be parsed correctly")
+	assert.Equal(t, "/keys/id_ed25519", result.KeyFile, "The sftp-key-file flag should be parsed correctly")
+	assert.Equal(t, "key", result.Key, "The sftp-key flag should be parsed correctly")
+	assert.Equal(t, "passphrase", result.KeyPassphrase, "The sftp-key-passphrase flag should be parsed correctly")
+	assert.Equal(t, "/keys/known_hosts", result.KnownHosts, "The sftp-known-hosts flag should be parsed correctly")
+	assert.Equal(t, "/backups", result.Path, "The sftp-path flag should be parsed correctly")
+	assert.Equal(t, int64(5000), result.ConnectTimeout, "The sftp-connect-timeout flag should be parsed correctly")
+	assert.Equal(t, 8, result.BufferSize, "The sftp-buffer-size flag should be parsed correctly")
+}
+
+func TestSftp_NewFlagSet_DefaultValues(t *testing.T) {
+	t.Parallel()
+	sftp := NewSftp(OperationRestore)
+
+	flagSet := sftp.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	require.NoError(t, err)
+
+	result := sftp.GetSftp()
+
+	assert.Empty(t, result.Host, "The default value for sftp-host should be an empty string")
+	assert.Equal(t, models.DefaultSftpPort, result.Port, "The default value for sftp-port should be 22")
+	assert.Empty(t, result.User, "The default value for sftp-user should be an empty string")
+	assert.Empty(t, result.Password, "The default value for sftp-password should be an empty string")
+	assert.Empty(t, result.KeyFile, "The default value for sftp-key-file should be an empty string")
+	assert.Empty(t, result.Key, "The default value for sftp-key should be an empty string")
+	assert.Empty(t, result.KnownHosts, "The default value for sftp-known-hosts should be an empty string")
+	assert.Empty(t, result.Path, "The default value for sftp-path should be an empty string")
+	assert.Equal(t, models.DefaultSftpConnectTimeout, result.ConnectTimeout,
+		"The default value for sftp-connect-timeout should be 30000")
+	assert.Nil(t, flagSet.Lookup(flagSftpBufferSize), "The sftp-buffer-size flag should be set only for backup")
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/spf13/pflag"
+)
+
+type Transcode struct {
+	models.Transcode
+}
+
+func NewTranscode() *Transcode {
+	return &Transcode{}
+}
+
+func (f *Transcode) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVarP(&f.Directory, "directory", "d",
+		models.DefaultCommonDirectory,
+		"The directory that holds the backup files to transcode.")
+	flagSet.StringVar(&f.OutputDirectory, "output-directory",
+		"",
+		"The directory the transcoded backup is written to. It must be empty.\n"+
+			"The files are compressed and encrypted with the --compress and --encrypt flags.")
+	flagSet.StringVar(&f.SourceCompression.Mode, "source-compress",
+		models.DefaultCompressionMode,
+		"Decompresses the backup files using the specified compression algorithm.\n"+
+			"This must match the compression mode used when backing up the data.\n"+
+			"Supported compression algorithms are: ZSTD, NONE")
+	flagSet.StringVar(&f.SourceEncryption.Mode, "source-encrypt",
+		models.DefaultEncryptionMode,
+		"Decrypts the backup files using the specified encryption algorithm.\n"+
+			"This must match the encryption mode used when backing up the data.\n"+
+			"Supported encryption algorithms are: NONE, AES128, AES256.\n"+
+			"The old private key must be given, either with the --source-encryption-key-file option or\n"+
+			"the --source-encryption-key-env option or the --source-encryption-key-secret.")
+	flagSet.StringVar(&f.SourceEncryption.KeyFile, "source-encryption-key-file",
+		models.DefaultEncryptionKeyFile,
+		"Gets the old encryption key from the given file, which must be in PEM format.")
+	flagSet.StringVar(&f.SourceEncryption.KeyEnv, "source-encryption-key-env",
+		models.DefaultEncryptionKeyEnv,
+		"Gets the old encryption key from the given environment variable, which must be Base64 encoded.")
+	flagSet.StringVar(&f.SourceEncryption.KeySecret, "source-encryption-key-secret",
+		models.DefaultEncryptionKeySecret,
+		"Gets the old encryption key from secret-agent.")
+
+	return flagSet
+}
+
+func (f *Transcode) GetTranscode() *models.Transcode {
+	return &f.Transcode
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscode_NewFlagSet(t *testing.T) {
+	t.Parallel()
+	transcode := NewTranscode()
+
+	flagSet := transcode.NewFlagSet()
+
+	args := []string{
+		"--directory", "/backups/full",
+		"--output-directory", "/backups/rotated",
+		"--source-compress", "zstd",
+		"--source-encrypt", "aes256",
+		"--source-encryption-key-file", "old.pem",
+	}
+
+	err := flagSet.Parse(args)
+	require.NoError(t, err)
+
+	result := transcode.GetTranscode()
+
+	assert.Equal(t, "/backups/full", result.Directory, "The directory flag should be parsed correctly")
+	assert.Equal(t, "/backups/rotated", result.OutputDirectory, "The output-directory flag should be parsed correctly")
+	assert.Equal(t, "zstd", result.SourceCompression.Mode, "The source-compress flag should be parsed correctly")
+	assert.Equal(t, "aes256", result.SourceEncryption.Mode, "The source-encrypt flag should be parsed correctly")
+	assert.Equal(t, "old.pem", result.SourceEncryption.KeyFile,
+		"The source-encryption-key-file flag should be parsed correctly")
+}
+
+func TestTranscode_NewFlagSet_DefaultValues(t *testing.T) {
+	t.Parallel()
+	transcode := NewTranscode()
+
+	flagSet := transcode.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	require.NoError(t, err)
+
+	result := transcode.GetTranscode()
+
+	assert.Empty(t, result.Directory, "The default value for directory should be empty")
+	assert.Empty(t, result.OutputDirectory, "The default value for output-directory should be empty")
+	assert.Equal(t, "NONE", result.SourceCompression.Mode, "The default value for source-compress should be NONE")
+	assert.Equal(t, "NONE", result.SourceEncryption.Mode, "The default value for source-encrypt should be NONE")
+	assert.Empty(t, result.SourceEncryption.KeyFile, "The default value for source-encryption-key-file should be empty")
+}
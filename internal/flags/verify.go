@@ -0,0 +1,46 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/spf13/pflag"
+)
+
+type Verify struct {
+	models.Verify
+}
+
+func NewVerify() *Verify {
+	return &Verify{}
+}
+
+func (f *Verify) NewFlagSet() *pflag.FlagSet {
+	flagSet := &pflag.FlagSet{}
+
+	flagSet.StringVarP(&f.Directory, "directory", "d",
+		models.DefaultCommonDirectory,
+		"The directory that holds the backup files and the manifest to verify.")
+	flagSet.BoolVar(&f.SkipDecode, "skip-decode",
+		false,
+		"Skip the check that backup files can be decrypted and decompressed.\n"+
+			"Only sizes and checksums from the manifest are verified.")
+
+	return flagSet
+}
+
+func (f *Verify) GetVerify() *models.Verify {
+	return &f.Verify
+}
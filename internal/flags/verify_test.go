@@ -0,0 +1,57 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify_NewFlagSet(t *testing.T) {
+	t.Parallel()
+	verify := NewVerify()
+
+	flagSet := verify.NewFlagSet()
+
+	args := []string{
+		"--directory", "/path/to/backup",
+		"--skip-decode",
+	}
+
+	err := flagSet.Parse(args)
+	require.NoError(t, err)
+
+	result := verify.GetVerify()
+
+	assert.Equal(t, "/path/to/backup", result.Directory, "The directory flag should be parsed correctly")
+	assert.True(t, result.SkipDecode, "The skip-decode flag should be parsed correctly")
+}
+
+func TestVerify_NewFlagSet_DefaultValues(t *testing.T) {
+	t.Parallel()
+	verify := NewVerify()
+
+	flagSet := verify.NewFlagSet()
+
+	err := flagSet.Parse([]string{})
+	require.NoError(t, err)
+
+	result := verify.GetVerify()
+
+	assert.Empty(t, result.Directory, "The default value for directory should be empty")
+	assert.False(t, result.SkipDecode, "The default value for skip-decode should be false")
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Status values passed to hooks in ABSCTL_STATUS.
+const (
+	StatusStarted = "started"
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// Environment variables that describe the run to hooks.
+const (
+	EnvOperation = "ABSCTL_OPERATION"
+	EnvNamespace = "ABSCTL_NAMESPACE"
+	EnvDirectory = "ABSCTL_DIRECTORY"
+	EnvStatus    = "ABSCTL_STATUS"
+	EnvStatsFile = "ABSCTL_STATS_FILE"
+	EnvError     = "ABSCTL_ERROR"
+)
+
+// Config contains the hook commands and the details of the run passed to them.
+type Config struct {
+	PreHook       string
+	PostHook      string
+	OnFailureHook string
+
+	// Operation is backup or restore.
+	Operation string
+	Namespace string
+	// Directory is the backup directory, or the backup file if a single file is used.
+	Directory string
+	// StatsFile is the path to the run report, empty if the report is not written.
+	StatsFile string
+}
+
+// RunPre runs the pre-hook. The run must not be started if it returns an error.
+// A nil ctx is replaced by the background context, as in commands run without a context.
+func (c *Config) RunPre(ctx context.Context, logger *slog.Logger) error {
+	if c == nil || c.PreHook == "" {
+		return nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := c.run(ctx, "pre-hook", c.PreHook, StatusStarted, nil, logger); err != nil {
+		return fmt.Errorf("pre-hook failed: %w", err)
+	}
+
+	return nil
+}
+
+// RunPost runs the post-hook if the run succeeded, or the on-failure hook otherwise.
+// If the run failed, runErr is returned and a failure of the on-failure hook is only logged.
+// Hooks are run even if ctx is canceled, so they can clean up after an interrupted run.
+// A nil ctx is replaced by the background context.
+func (c *Config) RunPost(ctx context.Context, runErr error, logger *slog.Logger) error {
+	if c == nil {
+		return runErr
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	if runErr != nil {
+		if c.OnFailureHook == "" {
+			return runErr
+		}
+
+		if err := c.run(ctx, "on-failure-hook", c.OnFailureHook, StatusFailure, runErr, logger); err != nil {
+			logger.Error("on-failure-hook failed", slog.Any("error", err))
+		}
+
+		return runErr
+	}
+
+	if c.PostHook == "" {
+		return nil
+	}
+
+	if err := c.run(ctx, "post-hook", c.PostHook, StatusSuccess, nil, logger); err != nil {
+		return fmt.Errorf("post-hook failed: %w", err)
+	}
+
+	return nil
+}
+
+// run executes the command in the system shell. Hook output goes to stderr,
+// as stdout may be used for backup data.
+func (c *Config) run(
+	ctx context.Context, name, command, status string, runErr error, logger *slog.Logger,
+) error {
+	logger.Info("running "+name, slog.String("command", command))
+
+	cmd := shellCommand(ctx, command)
+	cmd.Env = append(os.Environ(), c.env(status, runErr)...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	start := time.Now()
+
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	logger.Info(name+" finished", slog.Duration("duration", time.Since(start)))
+
+	return nil
+}
+
+func (c *Config) env(status string, runErr error) []string {
+	env := []string{
+		EnvOperation + "=" + c.Operation,
+		EnvNamespace + "=" + c.Namespace,
+		EnvDirectory + "=" + c.Directory,
+		EnvStatus + "=" + status,
+		EnvStatsFile + "=" + c.StatsFile,
+	}
+
+	if runErr != nil {
+		env = append(env, EnvError+"="+runErr.Error())
+	}
+
+	return env
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) //nolint:gosec // Hooks are set by the user.
+	}
+
+	return exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // Hooks are set by the user.
+}
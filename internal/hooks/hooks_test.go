@@ -0,0 +1,189 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(t *testing.T) *Config {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use sh commands")
+	}
+
+	return &Config{
+		Operation: "backup",
+		Namespace: "test",
+		Directory: "/backups/test",
+		StatsFile: "/backups/report.json",
+	}
+}
+
+// envDump returns a command that writes the hook environment to a file.
+func envDump(path string) string {
+	return "env | grep ^ABSCTL_ | sort > " + path
+}
+
+func readEnv(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	env := make(map[string]string)
+
+	for line := range strings.Lines(string(data)) {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), "=")
+		env[key] = value
+	}
+
+	return env
+}
+
+func TestConfig_Nil(t *testing.T) {
+	t.Parallel()
+
+	var c *Config
+
+	runErr := errors.New("run failed")
+
+	require.NoError(t, c.RunPre(t.Context(), slog.Default()))
+	require.NoError(t, c.RunPost(t.Context(), nil, slog.Default()))
+	require.ErrorIs(t, c.RunPost(t.Context(), runErr, slog.Default()), runErr)
+}
+
+func TestConfig_RunPre(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "env")
+
+	c := newTestConfig(t)
+	c.PreHook = envDump(out)
+
+	require.NoError(t, c.RunPre(t.Context(), slog.Default()))
+
+	assert.Equal(t, map[string]string{
+		EnvOperation: "backup",
+		EnvNamespace: "test",
+		EnvDirectory: "/backups/test",
+		EnvStatus:    StatusStarted,
+		EnvStatsFile: "/backups/report.json",
+	}, readEnv(t, out))
+}
+
+func TestConfig_RunPre_Error(t *testing.T) {
+	t.Parallel()
+
+	c := newTestConfig(t)
+	c.PreHook = "exit 3"
+
+	err := c.RunPre(t.Context(), slog.Default())
+	require.ErrorContains(t, err, "pre-hook failed: exit status 3")
+}
+
+func TestConfig_RunPost_Success(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	c := newTestConfig(t)
+	c.PostHook = envDump(filepath.Join(dir, "post"))
+	c.OnFailureHook = envDump(filepath.Join(dir, "failure"))
+
+	require.NoError(t, c.RunPost(t.Context(), nil, slog.Default()))
+
+	assert.Equal(t, StatusSuccess, readEnv(t, filepath.Join(dir, "post"))[EnvStatus])
+	assert.NoFileExists(t, filepath.Join(dir, "failure"))
+}
+
+func TestConfig_RunPost_Failure(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	runErr := errors.New("backup failed")
+
+	c := newTestConfig(t)
+	c.PostHook = envDump(filepath.Join(dir, "post"))
+	c.OnFailureHook = envDump(filepath.Join(dir, "failure"))
+
+	require.ErrorIs(t, c.RunPost(t.Context(), runErr, slog.Default()), runErr)
+
+	env := readEnv(t, filepath.Join(dir, "failure"))
+	assert.Equal(t, StatusFailure, env[EnvStatus])
+	assert.Equal(t, "backup failed", env[EnvError])
+	assert.NoFileExists(t, filepath.Join(dir, "post"))
+}
+
+func TestConfig_RunPost_HookErrors(t *testing.T) {
+	t.Parallel()
+
+	c := newTestConfig(t)
+	c.PostHook = "exit 1"
+	c.OnFailureHook = "exit 1"
+
+	// A failed post-hook fails the run.
+	require.ErrorContains(t, c.RunPost(t.Context(), nil, slog.Default()), "post-hook failed")
+
+	// A failed on-failure hook doesn't hide the run error.
+	runErr := errors.New("backup failed")
+	require.ErrorIs(t, c.RunPost(t.Context(), runErr, slog.Default()), runErr)
+}
+
+func TestConfig_RunPost_CanceledContext(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "env")
+
+	c := newTestConfig(t)
+	c.OnFailureHook = envDump(out)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	runErr := errors.New("interrupted")
+	require.ErrorIs(t, c.RunPost(ctx, runErr, slog.Default()), runErr)
+	assert.FileExists(t, out)
+}
+
+func TestConfig_NilContext(t *testing.T) {
+	t.Parallel()
+
+	pre := filepath.Join(t.TempDir(), "pre")
+	post := filepath.Join(t.TempDir(), "post")
+
+	c := newTestConfig(t)
+	c.PreHook = envDump(pre)
+	c.PostHook = envDump(post)
+
+	// Commands executed without a context have a nil context.
+	var ctx context.Context
+
+	require.NoError(t, c.RunPre(ctx, slog.Default()))
+	require.NoError(t, c.RunPost(ctx, nil, slog.Default()))
+	assert.FileExists(t, pre)
+	assert.FileExists(t, post)
+}
@@ -0,0 +1,269 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/aerospike-client-go/v8"
+)
+
+// Mapping maps the fields of imported rows to records.
+type Mapping struct {
+	Namespace string
+	Key       models.ImportField
+	// SetField is the field that holds the set name, SetName is the set of all records.
+	// Records have no set if both are empty.
+	SetField string
+	SetName  string
+	// Bins are the fields imported as bins, all fields except the key and set fields if empty.
+	Bins []models.ImportField
+}
+
+// NewMapping returns the mapping of the restore config.
+// Records are imported to the source namespace, that is mapped to the destination one by the restore.
+func NewMapping(r *models.Restore) (*Mapping, error) {
+	key, err := r.ImportKey()
+	if err != nil {
+		return nil, fmt.Errorf("invalid key-field: %w", err)
+	}
+
+	bins, err := r.ImportBins()
+	if err != nil {
+		return nil, err
+	}
+
+	var namespace string
+	if nsConfig := r.NamespaceConfig(); nsConfig != nil {
+		namespace = *nsConfig.Source
+	}
+
+	return &Mapping{
+		Namespace: namespace,
+		Key:       key,
+		SetField:  r.SetField,
+		SetName:   r.SetName,
+		Bins:      bins,
+	}, nil
+}
+
+// record is an imported row mapped to an Aerospike record.
+type record struct {
+	set string
+	// key is a string, int64 or []byte.
+	key    any
+	digest []byte
+	bins   []bin
+}
+
+type bin struct {
+	name string
+	// value is a bool, int64, float64, string, []byte, []any or map[string]any.
+	value any
+}
+
+// record maps a row to a record. Missing and null fields are not imported.
+func (m *Mapping) record(row map[string]any) (*record, error) {
+	r := &record{set: m.SetName}
+
+	if m.SetField != "" {
+		if set, ok := row[m.SetField]; ok && set != nil {
+			value, err := convertValue(set, models.ImportTypeString)
+			if err != nil {
+				return nil, fmt.Errorf("invalid set field %s: %w", m.SetField, err)
+			}
+
+			r.set = value.(string)
+		}
+	}
+
+	if err := m.setKey(r, row); err != nil {
+		return nil, err
+	}
+
+	for _, field := range m.binFields(row) {
+		value, ok := row[field.Name]
+		if !ok || value == nil {
+			continue
+		}
+
+		converted, err := convertValue(value, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field %s: %w", field.Name, err)
+		}
+
+		r.bins = append(r.bins, bin{name: field.Name, value: converted})
+	}
+
+	if len(r.bins) == 0 {
+		return nil, fmt.Errorf("no bins to import")
+	}
+
+	return r, nil
+}
+
+func (m *Mapping) setKey(r *record, row map[string]any) error {
+	value, ok := row[m.Key.Name]
+	if !ok || value == nil {
+		return fmt.Errorf("missing key field %s", m.Key.Name)
+	}
+
+	key, err := convertValue(value, m.Key.Type)
+	if err != nil {
+		return fmt.Errorf("invalid key field %s: %w", m.Key.Name, err)
+	}
+
+	switch key.(type) {
+	case string, int64, []byte:
+	default:
+		return fmt.Errorf("invalid key field %s: must be a string, integer or blob, got %T", m.Key.Name, key)
+	}
+
+	aerospikeKey, aErr := aerospike.NewKey(m.Namespace, r.set, key)
+	if aErr != nil {
+		return fmt.Errorf("failed to create key: %w", aErr)
+	}
+
+	r.key = key
+	r.digest = aerospikeKey.Digest()
+
+	return nil
+}
+
+// binFields returns the fields imported as bins, sorted by name if all fields are imported.
+func (m *Mapping) binFields(row map[string]any) []models.ImportField {
+	if len(m.Bins) > 0 {
+		return m.Bins
+	}
+
+	fields := make([]models.ImportField, 0, len(row))
+
+	for _, name := range slices.Sorted(maps.Keys(row)) {
+		if name == m.Key.Name || name == m.SetField {
+			continue
+		}
+
+		fields = append(fields, models.ImportField{Name: name})
+	}
+
+	return fields
+}
+
+// convertValue converts a field value to the type. NDJSON values are decoded with json.Number,
+// CSV values are strings.
+func convertValue(value any, typ string) (any, error) {
+	switch typ {
+	case models.ImportTypeAuto:
+		return normalize(value)
+	case models.ImportTypeString:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case json.Number:
+			return v.String(), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+	case models.ImportTypeInt:
+		switch v := value.(type) {
+		case string:
+			return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		case json.Number:
+			return v.Int64()
+		}
+	case models.ImportTypeFloat:
+		switch v := value.(type) {
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(v), 64)
+		case json.Number:
+			return v.Float64()
+		}
+	case models.ImportTypeBool:
+		switch v := value.(type) {
+		case string:
+			return strconv.ParseBool(strings.TrimSpace(v))
+		case bool:
+			return v, nil
+		}
+	case models.ImportTypeBlob:
+		if v, ok := value.(string); ok {
+			return base64.StdEncoding.DecodeString(v)
+		}
+	case models.ImportTypeJSON:
+		switch v := value.(type) {
+		case string:
+			decoder := json.NewDecoder(strings.NewReader(v))
+			decoder.UseNumber()
+
+			var decoded any
+			if err := decoder.Decode(&decoded); err != nil {
+				return nil, fmt.Errorf("failed to decode json: %w", err)
+			}
+
+			return normalize(decoded)
+		case []any, map[string]any:
+			return normalize(v)
+		}
+	}
+
+	return nil, fmt.Errorf("can't convert %T to %s", value, typ)
+}
+
+// normalize converts JSON numbers to int64 if they are integers, to float64 otherwise.
+func normalize(value any) (any, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+
+		return v.Float64()
+	case []any:
+		list := make([]any, len(v))
+
+		for i := range v {
+			item, err := normalize(v[i])
+			if err != nil {
+				return nil, err
+			}
+
+			list[i] = item
+		}
+
+		return list, nil
+	case map[string]any:
+		m := make(map[string]any, len(v))
+
+		for k, item := range v {
+			normalized, err := normalize(item)
+			if err != nil {
+				return nil, err
+			}
+
+			m[k] = normalized
+		}
+
+		return m, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNamespace = "test"
+
+func TestNewMapping(t *testing.T) {
+	t.Parallel()
+
+	mapping, err := NewMapping(&models.Restore{
+		Common:    models.Common{Namespace: "source,destination"},
+		KeyField:  "id:int",
+		SetName:   "users",
+		BinFields: "name,age:int",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &Mapping{
+		Namespace: "source",
+		Key:       models.ImportField{Name: "id", Type: models.ImportTypeInt},
+		SetName:   "users",
+		Bins: []models.ImportField{
+			{Name: "name"},
+			{Name: "age", Type: models.ImportTypeInt},
+		},
+	}, mapping)
+}
+
+func TestMapping_Record_NDJSON(t *testing.T) {
+	t.Parallel()
+
+	mapping := &Mapping{
+		Namespace: testNamespace,
+		Key:       models.ImportField{Name: "id"},
+		SetField:  "type",
+	}
+
+	row := map[string]any{
+		"id":     json.Number("42"),
+		"type":   "users",
+		"name":   "Alice",
+		"score":  json.Number("1.5"),
+		"active": true,
+		"tags":   []any{"a", json.Number("1")},
+		"note":   nil,
+	}
+
+	r, err := mapping.record(row)
+	require.NoError(t, err)
+
+	assert.Equal(t, "users", r.set)
+	assert.Equal(t, int64(42), r.key)
+
+	key, aErr := aerospike.NewKey(testNamespace, "users", int64(42))
+	require.NoError(t, aErr)
+	assert.Equal(t, key.Digest(), r.digest)
+
+	// All fields except the key and set ones, sorted by name. Null values are skipped.
+	assert.Equal(t, []bin{
+		{name: "active", value: true},
+		{name: "name", value: "Alice"},
+		{name: "score", value: 1.5},
+		{name: "tags", value: []any{"a", int64(1)}},
+	}, r.bins)
+}
+
+func TestMapping_Record_CSV(t *testing.T) {
+	t.Parallel()
+
+	mapping := &Mapping{
+		Namespace: testNamespace,
+		Key:       models.ImportField{Name: "id", Type: models.ImportTypeString},
+		SetName:   "users",
+		Bins: []models.ImportField{
+			{Name: "age", Type: models.ImportTypeInt},
+			{Name: "admin", Type: models.ImportTypeBool},
+			{Name: "avatar", Type: models.ImportTypeBlob},
+			{Name: "address", Type: models.ImportTypeJSON},
+			{Name: "missing"},
+		},
+	}
+
+	row := map[string]any{
+		"id":      "user-1",
+		"age":     " 30",
+		"admin":   "true",
+		"avatar":  "AQI=",
+		"address": `{"city":"Paris","zip":75001}`,
+	}
+
+	r, err := mapping.record(row)
+	require.NoError(t, err)
+
+	assert.Equal(t, "users", r.set)
+	assert.Equal(t, "user-1", r.key)
+	assert.Equal(t, []bin{
+		{name: "age", value: int64(30)},
+		{name: "admin", value: true},
+		{name: "avatar", value: []byte{1, 2}},
+		{name: "address", value: map[string]any{"city": "Paris", "zip": int64(75001)}},
+	}, r.bins)
+}
+
+func TestMapping_Record_Errors(t *testing.T) {
+	t.Parallel()
+
+	mapping := &Mapping{
+		Namespace: testNamespace,
+		Key:       models.ImportField{Name: "id"},
+		Bins:      []models.ImportField{{Name: "age", Type: models.ImportTypeInt}},
+	}
+
+	tests := []struct {
+		name   string
+		row    map[string]any
+		errMsg string
+	}{
+		{
+			name:   "Missing key",
+			row:    map[string]any{"age": "1"},
+			errMsg: "missing key field id",
+		},
+		{
+			name:   "Float key",
+			row:    map[string]any{"id": json.Number("1.5"), "age": "1"},
+			errMsg: "invalid key field id: must be a string, integer or blob, got float64",
+		},
+		{
+			name:   "Invalid int",
+			row:    map[string]any{"id": "1", "age": "one"},
+			errMsg: "invalid field age",
+		},
+		{
+			name:   "No bins",
+			row:    map[string]any{"id": "1"},
+			errMsg: "no bins to import",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := mapping.record(tt.row)
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// Reader wraps backup.StreamingReader and converts the NDJSON or CSV files it streams to the asb format,
+// so rows are written by the restore like backup records. Files with other extensions are skipped.
+type Reader struct {
+	backup.StreamingReader
+
+	format  string
+	mapping *Mapping
+	// encoder is shared by the files, so only the first one is marked as the first file.
+	encoder *asb.Encoder[*bModels.Token]
+	logger  *slog.Logger
+}
+
+// NewReader returns a Reader that imports files of the format, one of models.InputFormatNDJSON
+// or models.InputFormatCSV.
+func NewReader(r backup.StreamingReader, format string, mapping *Mapping, logger *slog.Logger) *Reader {
+	return &Reader{
+		StreamingReader: r,
+		format:          format,
+		mapping:         mapping,
+		encoder:         asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig(mapping.Namespace, false, false)),
+		logger:          logger,
+	}
+}
+
+// StreamFiles streams the files of the wrapped reader, converted in the background while they are read.
+func (r *Reader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) {
+	defer close(readersCh)
+
+	filesCh := make(chan bModels.File)
+
+	go r.StreamingReader.StreamFiles(ctx, filesCh, errorsCh, skipPrefixes)
+
+	for file := range filesCh {
+		if path.Ext(file.Name) != "."+r.format {
+			r.logger.Debug("skipping file", slog.String("file", file.Name))
+			_ = file.Reader.Close()
+
+			continue
+		}
+
+		converted := r.convert(file)
+
+		select {
+		case <-ctx.Done():
+			_ = converted.Reader.Close()
+			return
+		case readersCh <- converted:
+		}
+	}
+}
+
+// convert returns the file with a reader of the asb encoded rows.
+// Conversion errors are returned by the reader, so they fail the restore.
+func (r *Reader) convert(file bModels.File) bModels.File {
+	pr, pw := io.Pipe()
+	source, name := file.Reader, file.Name
+
+	go func() {
+		err := r.encode(pw, source, name)
+		_ = source.Close()
+		pw.CloseWithError(err)
+	}()
+
+	file.Reader = pr
+
+	return file
+}
+
+func (r *Reader) encode(w io.Writer, source io.Reader, name string) error {
+	rows := newRowReader(source, r.format)
+	bw := bufio.NewWriter(w)
+
+	if _, err := bw.Write(r.encoder.GetHeader(0, true)); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	for i := 1; ; i++ {
+		row, err := rows.next()
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return bw.Flush()
+		case err != nil:
+			return fmt.Errorf("failed to read row %d of %s: %w", i, name, err)
+		}
+
+		rec, err := r.mapping.record(row)
+		if err != nil {
+			return fmt.Errorf("failed to import row %d of %s: %w", i, name, err)
+		}
+
+		token, err := rec.token(r.mapping.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to import row %d of %s: %w", i, name, err)
+		}
+
+		buf.Reset()
+
+		if err = r.encoder.EncodeToken(token, &buf); err != nil {
+			return fmt.Errorf("failed to import row %d of %s: %w", i, name, err)
+		}
+
+		if _, err = bw.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+}
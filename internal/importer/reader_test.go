@@ -0,0 +1,123 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFilesReader streams files from memory.
+type testFilesReader struct {
+	backup.StreamingReader
+
+	files map[string]string
+}
+
+func (r *testFilesReader) StreamFiles(
+	_ context.Context, readersCh chan<- bModels.File, _ chan<- error, _ []string,
+) {
+	defer close(readersCh)
+
+	for name, content := range r.files {
+		readersCh <- bModels.File{Name: name, Reader: io.NopCloser(strings.NewReader(content))}
+	}
+}
+
+// readFiles streams the files of the reader and returns their content by name.
+func readFiles(t *testing.T, r backup.StreamingReader) (map[string]string, error) {
+	t.Helper()
+
+	readersCh := make(chan bModels.File)
+	errorsCh := make(chan error, 1)
+
+	go r.StreamFiles(t.Context(), readersCh, errorsCh, nil)
+
+	files := make(map[string]string)
+
+	for file := range readersCh {
+		data, err := io.ReadAll(file.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		files[file.Name] = string(data)
+	}
+
+	return files, nil
+}
+
+func TestReader_CSV(t *testing.T) {
+	t.Parallel()
+
+	source := &testFilesReader{files: map[string]string{
+		"users.csv":     "id,name\n1,Alice\n2,Bob\n",
+		"manifest.json": "{}",
+	}}
+	mapping := &Mapping{Namespace: testNamespace, Key: models.ImportField{Name: "id", Type: models.ImportTypeInt}}
+
+	files, err := readFiles(t, NewReader(source, models.InputFormatCSV, mapping, slog.Default()))
+	require.NoError(t, err)
+
+	require.Len(t, files, 1)
+	content := files["users.csv"]
+	assert.True(t, strings.HasPrefix(content, "Version 3.1\n# namespace test\n"))
+	assert.Equal(t, 2, strings.Count(content, "+ n test\n"))
+	assert.Contains(t, content, "+ k I 2\n")
+	assert.Contains(t, content, "- S name 3 Bob\n")
+}
+
+func TestReader_NDJSON_InvalidRow(t *testing.T) {
+	t.Parallel()
+
+	source := &testFilesReader{files: map[string]string{
+		"users.ndjson": "{\"id\":\"a\",\"name\":\"Alice\"}\n{\"name\":\"Bob\"}\n",
+	}}
+	mapping := &Mapping{Namespace: testNamespace, Key: models.ImportField{Name: "id"}}
+
+	_, err := readFiles(t, NewReader(source, models.InputFormatNDJSON, mapping, slog.Default()))
+	require.ErrorContains(t, err, "failed to import row 2 of users.ndjson: missing key field id")
+}
+
+func TestRowReader(t *testing.T) {
+	t.Parallel()
+
+	rows := newRowReader(strings.NewReader("id,name,age\n1,,30\n"), models.InputFormatCSV)
+
+	row, err := rows.next()
+	require.NoError(t, err)
+	// Empty CSV values are skipped.
+	assert.Equal(t, map[string]any{"id": "1", "age": "30"}, row)
+
+	_, err = rows.next()
+	require.ErrorIs(t, err, io.EOF)
+
+	rows = newRowReader(strings.NewReader("[1, 2]\n"), models.InputFormatNDJSON)
+	_, err = rows.next()
+	require.Error(t, err)
+
+	rows = newRowReader(strings.NewReader(""), models.InputFormatCSV)
+	_, err = rows.next()
+	require.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aerospike/absctl/internal/models"
+)
+
+// rowReader reads the rows of an imported file. Returns io.EOF after the last row.
+type rowReader interface {
+	next() (map[string]any, error)
+}
+
+func newRowReader(r io.Reader, format string) rowReader {
+	if format == models.InputFormatCSV {
+		reader := csv.NewReader(r)
+		reader.ReuseRecord = true
+
+		return &csvRows{reader: reader}
+	}
+
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	return &ndjsonRows{decoder: decoder}
+}
+
+// ndjsonRows reads one JSON object per line. Numbers are decoded as json.Number.
+type ndjsonRows struct {
+	decoder *json.Decoder
+}
+
+func (r *ndjsonRows) next() (map[string]any, error) {
+	var row map[string]any
+	if err := r.decoder.Decode(&row); err != nil {
+		return nil, err
+	}
+
+	if row == nil {
+		return nil, fmt.Errorf("row is not a JSON object")
+	}
+
+	return row, nil
+}
+
+// csvRows reads CSV files with a header line that holds the field names.
+// Empty values are skipped, as CSV has no null value.
+type csvRows struct {
+	reader *csv.Reader
+	header []string
+}
+
+func (r *csvRows) next() (map[string]any, error) {
+	if r.header == nil {
+		header, err := r.reader.Read()
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil, err
+		case err != nil:
+			return nil, fmt.Errorf("failed to read header: %w", err)
+		}
+
+		r.header = append([]string(nil), header...)
+	}
+
+	values, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]any, len(values))
+
+	for i, value := range values {
+		if value != "" {
+			row[r.header[i]] = value
+		}
+	}
+
+	return row, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	particleType "github.com/aerospike/aerospike-client-go/v8/types/particle_type"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// token returns the record as a backup token with a generation of 1 and no expiration,
+// so it is encoded like backup records. Lists and maps are packed as MessagePack blobs.
+func (r *record) token(namespace string) (*bModels.Token, error) {
+	key, aErr := aerospike.NewKeyWithDigest(namespace, r.set, r.key, r.digest)
+	if aErr != nil {
+		return nil, fmt.Errorf("failed to create key: %w", aErr)
+	}
+
+	bins := make(aerospike.BinMap, len(r.bins))
+
+	for _, b := range r.bins {
+		value, err := binValue(b.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode bin %s: %w", b.name, err)
+		}
+
+		bins[b.name] = value
+	}
+
+	record := &bModels.Record{
+		Record: &aerospike.Record{
+			Key:        key,
+			Bins:       bins,
+			Generation: 1,
+		},
+	}
+
+	return bModels.NewRecordToken(record, 0, nil), nil
+}
+
+// binValue returns the value as decoded from backups, lists and maps being raw blobs.
+func binValue(value any) (any, error) {
+	var pType int
+
+	switch value.(type) {
+	case []any:
+		pType = particleType.LIST
+	case map[string]any:
+		pType = particleType.MAP
+	default:
+		return value, nil
+	}
+
+	data, err := appendMsgpack(nil, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return aerospike.NewRawBlobValue(pType, data), nil
+}
+
+// appendMsgpack appends the value packed as an Aerospike list or map element.
+// Strings and blobs are prefixed with their particle type, as done by the Aerospike clients.
+func appendMsgpack(buf []byte, value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, 0xc0), nil
+	case bool:
+		if v {
+			return append(buf, 0xc3), nil
+		}
+
+		return append(buf, 0xc2), nil
+	case int64:
+		if v >= -32 && v <= 127 {
+			return append(buf, byte(v)), nil
+		}
+
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(v)), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcb), math.Float64bits(v)), nil
+	case string:
+		buf = appendMsgpackHeader(buf, len(v)+1, 0xa0, 32, 0xd9, 0xda, 0xdb)
+		buf = append(buf, particleType.STRING)
+
+		return append(buf, v...), nil
+	case []byte:
+		buf = appendMsgpackHeader(buf, len(v)+1, 0xa0, 32, 0xd9, 0xda, 0xdb)
+		buf = append(buf, particleType.BLOB)
+
+		return append(buf, v...), nil
+	case []any:
+		buf = appendMsgpackHeader(buf, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+
+		for _, item := range v {
+			var err error
+			if buf, err = appendMsgpack(buf, item); err != nil {
+				return nil, err
+			}
+		}
+
+		return buf, nil
+	case map[string]any:
+		buf = appendMsgpackHeader(buf, len(v), 0x80, 16, 0, 0xde, 0xdf)
+
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			var err error
+			if buf, err = appendMsgpack(buf, k); err != nil {
+				return nil, err
+			}
+
+			if buf, err = appendMsgpack(buf, v[k]); err != nil {
+				return nil, err
+			}
+		}
+
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// appendMsgpackHeader appends the header of a string, array or map of size elements: the fix type
+// if size is less than fixLimit, then the 8 (strings only, if not 0), 16 and 32-bit size types.
+func appendMsgpackHeader(buf []byte, size int, fixType byte, fixLimit int, type8, type16, type32 byte) []byte {
+	switch {
+	case size < fixLimit:
+		return append(buf, fixType|byte(size))
+	case type8 != 0 && size <= math.MaxUint8:
+		return append(buf, type8, byte(size))
+	case size <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, type16), uint16(size))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, type32), uint32(size))
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	particleType "github.com/aerospike/aerospike-client-go/v8/types/particle_type"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDigest() []byte {
+	digest := make([]byte, 20)
+	for i := range digest {
+		digest[i] = byte(i)
+	}
+
+	return digest
+}
+
+func TestRecord_Token(t *testing.T) {
+	t.Parallel()
+
+	r := &record{
+		set:    "users",
+		key:    "user 1",
+		digest: testDigest(),
+		bins: []bin{
+			{name: "first name", value: "Alice"},
+			{name: "age", value: int64(30)},
+			{name: "tags", value: []any{"a", int64(1)}},
+			{name: "address", value: map[string]any{"k": nil}},
+		},
+	}
+
+	token, err := r.token(testNamespace)
+	require.NoError(t, err)
+
+	require.Equal(t, bModels.TokenTypeRecord, token.Type)
+	assert.Equal(t, testNamespace, token.Record.Key.Namespace())
+	assert.Equal(t, "users", token.Record.Key.SetName())
+	assert.Equal(t, "user 1", token.Record.Key.Value().GetObject())
+	assert.Equal(t, testDigest(), token.Record.Key.Digest())
+	assert.Equal(t, uint32(1), token.Record.Generation)
+	assert.Equal(t, int64(0), token.Record.VoidTime)
+	assert.Equal(t, aerospike.BinMap{
+		"first name": "Alice",
+		"age":        int64(30),
+		"tags":       aerospike.NewRawBlobValue(particleType.LIST, []byte{0x92, 0xa2, 0x03, 'a', 0x01}),
+		"address":    aerospike.NewRawBlobValue(particleType.MAP, []byte{0x81, 0xa2, 0x03, 'k', 0xc0}),
+	}, token.Record.Bins)
+}
+
+func TestRecord_Token_Encode(t *testing.T) {
+	t.Parallel()
+
+	r := &record{
+		key:    int64(-7),
+		digest: testDigest(),
+		bins:   []bin{{name: "first name", value: "Alice"}},
+	}
+
+	token, err := r.token(testNamespace)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	encoder := asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig(testNamespace, false, false))
+	require.NoError(t, encoder.EncodeToken(token, &buf))
+
+	assert.Equal(t, "+ k I -7\n"+
+		"+ n test\n"+
+		"+ d AAECAwQFBgcICQoLDA0ODxAREhM=\n"+
+		"+ g 1\n"+
+		"+ t 0\n"+
+		"+ b 1\n"+
+		"- S first\\ name 5 Alice\n", buf.String())
+}
+
+func TestAppendMsgpack(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value any
+		want  []byte
+	}{
+		{name: "Nil", value: nil, want: []byte{0xc0}},
+		{name: "False", value: false, want: []byte{0xc2}},
+		{name: "Fix int", value: int64(5), want: []byte{0x05}},
+		{name: "Negative fix int", value: int64(-1), want: []byte{0xff}},
+		{name: "Int", value: int64(256), want: []byte{0xd3, 0, 0, 0, 0, 0, 0, 1, 0}},
+		{name: "Float", value: 1.0, want: []byte{0xcb, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0}},
+		{name: "String", value: "ab", want: []byte{0xa3, 0x03, 'a', 'b'}},
+		{name: "Blob", value: []byte{1}, want: []byte{0xa2, 0x04, 1}},
+		{name: "List", value: []any{int64(1), true}, want: []byte{0x92, 0x01, 0xc3}},
+		{
+			name:  "Sorted map",
+			value: map[string]any{"b": int64(2), "a": int64(1)},
+			want:  []byte{0x82, 0xa2, 0x03, 'a', 0x01, 0xa2, 0x03, 'b', 0x02},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := appendMsgpack(nil, tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAppendMsgpackHeader_LongString(t *testing.T) {
+	t.Parallel()
+
+	got, err := appendMsgpack(nil, string(bytes.Repeat([]byte{'a'}, 300)))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xda, 0x01, 0x2d, 0x03}, got[:4])
+	assert.Len(t, got, 4+300)
+}
@@ -0,0 +1,252 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/aerospike/absctl/internal/aeskey"
+	"github.com/aerospike/absctl/internal/codec"
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/records"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// errLimitReached stops reading files when the record limit is reached.
+var errLimitReached = errors.New("record limit reached")
+
+// Service decodes backup files and prints their content as NDJSON without connecting to a cluster.
+type Service struct {
+	reader backup.StreamingReader
+
+	// encryptionKey decrypts the files, nil if they are not encrypted.
+	encryptionKey []byte
+	// compressed is true if the files are compressed with ZSTD.
+	compressed bool
+
+	// Filters.
+	limit   int64
+	sets    []string
+	digests [][]byte
+
+	// printed is the number of printed records.
+	printed int64
+	out     *json.Encoder
+
+	logger *slog.Logger
+}
+
+// NewService initializes and returns a new inspect Service that prints to stdout.
+func NewService(
+	ctx context.Context,
+	cfg *config.InspectServiceConfig,
+	logger *slog.Logger,
+) (*Service, error) {
+	// Skip the file checks, so both .asb and .asbx files are streamed.
+	reader, err := storage.NewReader(
+		ctx,
+		&cfg.ServiceConfigCommon,
+		cfg.Inspect.Directory,
+		cfg.Inspect.InputFile,
+		"",
+		"",
+		0,
+		false,
+		true,
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader: %w", err)
+	}
+
+	digests, err := cfg.Inspect.Digests()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		reader:     reader,
+		compressed: cfg.Compression.Policy() != nil,
+		limit:      cfg.Inspect.Limit,
+		sets:       cfg.Inspect.Sets(),
+		digests:    digests,
+		out:        json.NewEncoder(os.Stdout),
+		logger:     logger,
+	}
+
+	if policy := cfg.Encryption.Policy(); policy != nil {
+		s.encryptionKey, err = aeskey.Read(ctx, policy, cfg.SecretAgent.Config())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Run decodes the backup files and prints records, secondary indexes and UDFs, one JSON object per line.
+func (s *Service) Run(ctx context.Context) error {
+	s.logger.Info("starting backup inspection")
+
+	err := codec.StreamFiles(ctx, s.reader, func(file bModels.File) error {
+		if err := s.inspectFile(file); err != nil {
+			return fmt.Errorf("failed to inspect file %s: %w", file.Name, err)
+		}
+
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, errLimitReached):
+		s.logger.Info("backup inspection finished, record limit reached", slog.Int64("records", s.printed))
+		return nil
+	case err != nil:
+		return err
+	}
+
+	s.logger.Info("backup inspection finished", slog.Int64("records", s.printed))
+
+	return nil
+}
+
+// inspectFile decodes a single file and prints its content.
+// Files other than .asb and .asbx, like the manifest, are skipped.
+func (s *Service) inspectFile(file bModels.File) error {
+	defer file.Reader.Close()
+
+	name := path.Base(file.Name)
+
+	if !codec.IsBackupFile(name) {
+		s.logger.Debug("skipping file", slog.String("file", file.Name))
+		return nil
+	}
+
+	reader, err := codec.NewReader(file.Reader, s.encryptionKey, s.compressed)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if codec.IsASBX(name) {
+		decoder, err := codec.NewASBXDecoder(reader, name)
+		if err != nil {
+			return fmt.Errorf("failed to create decoder: %w", err)
+		}
+
+		return codec.DecodeTokens(decoder, func(token *bModels.ASBXToken) error {
+			return s.printXDR(name, token)
+		})
+	}
+
+	decoder, err := asb.NewDecoder[*bModels.Token](reader, name, false, s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to create decoder: %w", err)
+	}
+
+	return codec.DecodeTokens(decoder, func(token *bModels.Token) error {
+		return s.printToken(name, token)
+	})
+}
+
+// printToken prints the token if it passes the filters.
+// Returns errLimitReached after the last record is printed.
+func (s *Service) printToken(file string, token *bModels.Token) error {
+	switch {
+	case token.Type == bModels.TokenTypeRecord:
+		if !s.matches(token.Record.Key) {
+			return nil
+		}
+
+		return s.printRecord(records.NewRecordLine(file, token.Record, time.Now()))
+	case token.Type == bModels.TokenTypeSIndex:
+		if len(s.digests) > 0 || !s.matchesSet(token.SIndex.Set) {
+			return nil
+		}
+
+		return s.print(records.NewSIndexLine(file, token.SIndex))
+	case token.Type == bModels.TokenTypeUDF:
+		if len(s.digests) > 0 || len(s.sets) > 0 {
+			return nil
+		}
+
+		return s.print(records.NewUDFLine(file, token.UDF))
+	default:
+		return nil
+	}
+}
+
+// printXDR prints the record of an .asbx file if it passes the filters.
+// Returns errLimitReached after the last record is printed.
+func (s *Service) printXDR(file string, token *bModels.ASBXToken) error {
+	if !s.matches(token.Key) {
+		return nil
+	}
+
+	return s.printRecord(records.NewXDRLine(file, token.Key, token.Payload))
+}
+
+// printRecord prints a record and returns errLimitReached after the last record is printed.
+func (s *Service) printRecord(line any) error {
+	if err := s.print(line); err != nil {
+		return err
+	}
+
+	s.printed++
+	if s.limit > 0 && s.printed >= s.limit {
+		return errLimitReached
+	}
+
+	return nil
+}
+
+func (s *Service) print(line any) error {
+	if err := s.out.Encode(line); err != nil {
+		return fmt.Errorf("failed to print: %w", err)
+	}
+
+	return nil
+}
+
+// matches checks if the record passes the set and digest filters.
+func (s *Service) matches(key *aerospike.Key) bool {
+	if !s.matchesSet(key.SetName()) {
+		return false
+	}
+
+	if len(s.digests) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(s.digests, func(digest []byte) bool {
+		return bytes.Equal(digest, key.Digest())
+	})
+}
+
+func (s *Service) matchesSet(set string) bool {
+	return len(s.sets) == 0 || slices.Contains(s.sets, set)
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/records"
+	"github.com/aerospike/aerospike-client-go/v8"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRecordToken(t *testing.T, set, key string) *bModels.Token {
+	t.Helper()
+
+	k, err := aerospike.NewKey("test", set, key)
+	require.NoError(t, err)
+
+	return &bModels.Token{
+		Type: bModels.TokenTypeRecord,
+		Record: &bModels.Record{
+			Record: &aerospike.Record{
+				Key:        k,
+				Bins:       aerospike.BinMap{"bin1": int64(1)},
+				Generation: 3,
+			},
+		},
+	}
+}
+
+func newTestService(buf *bytes.Buffer) *Service {
+	return &Service{
+		out:    json.NewEncoder(buf),
+		logger: slog.Default(),
+	}
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var lines []map[string]any
+
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+
+		lines = append(lines, m)
+	}
+
+	return lines
+}
+
+func TestService_PrintToken(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	s := newTestService(&buf)
+
+	require.NoError(t, s.printToken("test_0.asb", newTestRecordToken(t, "set1", "key1")))
+	require.NoError(t, s.printToken("test_0.asb", &bModels.Token{
+		Type: bModels.TokenTypeUDF,
+		UDF:  &bModels.UDF{UDFType: 'L', Name: "test.lua", Content: []byte("return 1")},
+	}))
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 2)
+
+	record := lines[0]
+	assert.Equal(t, records.LineTypeRecord, record["type"])
+	assert.Equal(t, "test_0.asb", record["file"])
+	assert.Equal(t, "test", record["namespace"])
+	assert.Equal(t, "set1", record["set"])
+	assert.Equal(t, map[string]any{"type": records.ValueTypeString, "value": "key1"}, record["key"])
+	assert.InDelta(t, 3, record["generation"], 0)
+	assert.InDelta(t, records.TTLNeverExpire, record["ttl"], 0)
+	assert.Equal(t, map[string]any{"bin1": map[string]any{"type": records.ValueTypeInt, "value": float64(1)}}, record["bins"])
+
+	udf := lines[1]
+	assert.Equal(t, records.LineTypeUDF, udf["type"])
+	assert.Equal(t, "test.lua", udf["name"])
+	assert.Equal(t, "L", udf["udf_type"])
+	assert.Equal(t, "return 1", udf["content"])
+	assert.Equal(t, int64(1), s.printed)
+}
+
+func TestService_PrintToken_Filters(t *testing.T) {
+	t.Parallel()
+
+	match := newTestRecordToken(t, "set1", "key1")
+	otherKey := newTestRecordToken(t, "set1", "key2")
+	otherSet := newTestRecordToken(t, "set2", "key1")
+	udf := &bModels.Token{Type: bModels.TokenTypeUDF, UDF: &bModels.UDF{Name: "test.lua"}}
+
+	var buf bytes.Buffer
+
+	s := newTestService(&buf)
+	s.sets = []string{"set1"}
+	s.digests = [][]byte{match.Record.Key.Digest()}
+
+	for _, token := range []*bModels.Token{match, otherKey, otherSet, udf} {
+		require.NoError(t, s.printToken("test_0.asb", token))
+	}
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, map[string]any{"type": records.ValueTypeString, "value": "key1"}, lines[0]["key"])
+}
+
+func TestService_PrintToken_Limit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	s := newTestService(&buf)
+	s.limit = 2
+
+	require.NoError(t, s.printToken("test_0.asb", newTestRecordToken(t, "set1", "key1")))
+	require.ErrorIs(t, s.printToken("test_0.asb", newTestRecordToken(t, "set1", "key2")), errLimitReached)
+	assert.Len(t, decodeLines(t, &buf), 2)
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aerospike/absctl/internal/models"
+)
+
+const headerBackupPlan = "Backup plan"
+
+// ReportBackupPlan prints the backup plan resolved by a dry run.
+// if toLog is true, it prints the plan to log, but logger must be passed
+func ReportBackupPlan(plan *models.BackupPlan, toLog bool, logger *slog.Logger) {
+	if toLog {
+		logBackupPlan(plan, logger)
+		return
+	}
+
+	printBackupPlan(plan)
+}
+
+func printBackupPlan(plan *models.BackupPlan) {
+	printSection(headerBackupPlan)
+
+	printMetric("Result", planResult(plan))
+	printMetric("Storage", planStorage(plan.Storage))
+
+	for i := range plan.Namespaces {
+		printNamespacePlan(&plan.Namespaces[i])
+	}
+}
+
+func printNamespacePlan(p *models.NamespacePlan) {
+	printSubSection(p.Config.Namespace)
+
+	printMetric("Sets", planList(p.Config.SetList))
+	printMetric("Bins", planList(p.Config.BinList))
+	printMetric("Partitions", planPartitions(p.Config.PartitionFilters))
+	printMetric("Modified After", planTime(p.Config.ModifiedAfter))
+	printMetric("Modified Before", planTime(p.Config.ModifiedBefore))
+
+	if p.Incremental != nil {
+		printMetric("Incremental From", strings.Join(p.Incremental.Chain, " -> "))
+	}
+
+	printMetric("Content", planContent(&p.Config))
+
+	printToOutWriter("")
+
+	if p.OutputFile != "" {
+		printMetric("Output File", p.OutputFile)
+	} else {
+		printMetric("Directory", p.Directory)
+		printMetric("File Prefix", planValue(p.FilePrefix))
+		printMetric("File Limit", planFileLimit(p.FileLimit))
+	}
+
+	if p.StateFile != "" {
+		printMetric("State File", p.StateFile)
+	}
+
+	printMetric("Parallel Read", p.ParallelRead)
+	printMetric("Parallel Write", p.ParallelWrite)
+	printMetric("Compression", p.Config.Compression)
+	printMetric("Encryption", p.Config.Encryption)
+	printMetric("Remove Files", planRemoveFiles(p))
+
+	printList("Nodes", planNodes(p.Nodes))
+	printList("Existing Files", p.ExistingFiles)
+	printList("Problems", p.Problems)
+}
+
+func logBackupPlan(plan *models.BackupPlan, logger *slog.Logger) {
+	for i := range plan.Namespaces {
+		p := &plan.Namespaces[i]
+
+		logger.Info(strings.ToLower(headerBackupPlan),
+			slog.String("result", planResult(plan)),
+			slog.String("storage", planStorage(plan.Storage)),
+			slog.String("namespace", p.Config.Namespace),
+			slog.String("sets", planList(p.Config.SetList)),
+			slog.String("bins", planList(p.Config.BinList)),
+			slog.String("partitions", planPartitions(p.Config.PartitionFilters)),
+			slog.String("modified-after", planTime(p.Config.ModifiedAfter)),
+			slog.String("modified-before", planTime(p.Config.ModifiedBefore)),
+			slog.Any("incremental-from", p.Incremental),
+			slog.String("content", planContent(&p.Config)),
+			slog.String("directory", p.Directory),
+			slog.String("output-file", p.OutputFile),
+			slog.String("file-prefix", p.FilePrefix),
+			slog.Uint64("file-limit", p.FileLimit),
+			slog.String("state-file", p.StateFile),
+			slog.Int("parallel-read", p.ParallelRead),
+			slog.Int("parallel-write", p.ParallelWrite),
+			slog.String("compression", p.Config.Compression),
+			slog.String("encryption", p.Config.Encryption),
+			slog.String("remove-files", planRemoveFiles(p)),
+			slog.Any("nodes", planNodes(p.Nodes)),
+			slog.Any("existing-files", p.ExistingFiles),
+			slog.Any("problems", p.Problems),
+		)
+	}
+}
+
+func planResult(plan *models.BackupPlan) string {
+	if plan.Passed() {
+		return "OK"
+	}
+
+	return "PROBLEMS FOUND"
+}
+
+func planStorage(s models.ReportStorage) string {
+	if s.Bucket != "" {
+		return fmt.Sprintf("%s, bucket %s, path %s", s.Type, s.Bucket, s.Path)
+	}
+
+	return fmt.Sprintf("%s, path %s", s.Type, s.Path)
+}
+
+func planList(list []string) string {
+	if len(list) == 0 {
+		return "all"
+	}
+
+	return strings.Join(list, ",")
+}
+
+func planValue(v string) string {
+	if v == "" {
+		return "-"
+	}
+
+	return v
+}
+
+func planTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Format(time.RFC1123)
+}
+
+func planPartitions(filters []models.ManifestPartitionFilter) string {
+	if len(filters) == 0 {
+		return "all"
+	}
+
+	parts := make([]string, 0, len(filters))
+
+	for _, f := range filters {
+		switch {
+		case f.Digest != "":
+			parts = append(parts, fmt.Sprintf("%d after digest %s", f.Begin, f.Digest))
+		case f.Count == 1:
+			parts = append(parts, fmt.Sprintf("%d", f.Begin))
+		default:
+			parts = append(parts, fmt.Sprintf("%d-%d", f.Begin, f.Begin+f.Count-1))
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func planContent(c *models.ManifestConfig) string {
+	var content []string
+
+	if !c.NoRecords {
+		content = append(content, "records")
+	}
+
+	if !c.NoIndexes {
+		content = append(content, "secondary indexes")
+	}
+
+	if !c.NoUDFs {
+		content = append(content, "UDFs")
+	}
+
+	if len(content) == 0 {
+		return "-"
+	}
+
+	return strings.Join(content, ", ")
+}
+
+func planFileLimit(limit uint64) string {
+	if limit == 0 {
+		return "none"
+	}
+
+	return formatBytes(float64(limit))
+}
+
+func planRemoveFiles(p *models.NamespacePlan) string {
+	switch {
+	case p.RemoveArtifacts:
+		return "yes, without running the backup"
+	case p.RemoveFiles:
+		return "yes"
+	default:
+		return "no"
+	}
+}
+
+func planNodes(nodes []models.PlanNode) []string {
+	result := make([]string, 0, len(nodes))
+
+	for _, n := range nodes {
+		line := n.Name + " " + n.Address
+		if n.Rack != nil {
+			line += fmt.Sprintf(" rack %d", *n.Rack)
+		}
+
+		result = append(result, line)
+	}
+
+	return result
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBackupPlan() *models.BackupPlan {
+	rack := 1
+	after := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	return &models.BackupPlan{
+		Storage: models.ReportStorage{Type: models.ReportStorageAwsS3, Bucket: "backups", Path: "daily"},
+		Namespaces: []models.NamespacePlan{{
+			Config: models.ManifestConfig{
+				Namespace: "test",
+				SetList:   []string{"set1", "set2"},
+				PartitionFilters: []models.ManifestPartitionFilter{
+					{Begin: 0, Count: 100},
+					{Begin: 200, Count: 1},
+				},
+				ModifiedAfter: &after,
+				NoUDFs:        true,
+				Compression:   "ZSTD",
+				Encryption:    "NONE",
+			},
+			Nodes:         []models.PlanNode{{Name: "BB9", Address: "10.0.0.1:3000", Rack: &rack}},
+			Directory:     "daily",
+			FileLimit:     1024 * 1024,
+			ParallelRead:  4,
+			ParallelWrite: 4,
+			RemoveFiles:   true,
+			ExistingFiles: []string{"daily/test_0.asb"},
+			Problems:      []string{"set set2 is not found in namespace test"},
+		}},
+	}
+}
+
+func TestPrintBackupPlan(t *testing.T) {
+	output := captureOutput(t, func() {
+		printBackupPlan(newTestBackupPlan())
+	})
+
+	metric := func(key, value string) string {
+		return key + ":" + strings.Repeat(" ", 21-len(key)) + value
+	}
+
+	assert.Contains(t, output, headerBackupPlan)
+	assert.Contains(t, output, metric("Result", "PROBLEMS FOUND"))
+	assert.Contains(t, output, metric("Storage", "aws-s3, bucket backups, path daily"))
+	assert.Contains(t, output, "::test::")
+	assert.Contains(t, output, metric("Sets", "set1,set2"))
+	assert.Contains(t, output, metric("Bins", "all"))
+	assert.Contains(t, output, metric("Partitions", "0-99,200"))
+	assert.Contains(t, output, metric("Modified After", "Fri, 02 Jan 2026 03:04:05 UTC"))
+	assert.Contains(t, output, metric("Modified Before", "-"))
+	assert.Contains(t, output, metric("Content", "records, secondary indexes"))
+	assert.Contains(t, output, metric("Directory", "daily"))
+	assert.Contains(t, output, metric("File Limit", "1.0 MiB"))
+	assert.Contains(t, output, metric("Remove Files", "yes"))
+	assert.Contains(t, output, "  BB9 10.0.0.1:3000 rack 1")
+	assert.Contains(t, output, metric("Existing Files", "1"))
+	assert.Contains(t, output, "  daily/test_0.asb")
+	assert.Contains(t, output, "  set set2 is not found in namespace test")
+	assert.NotContains(t, output, "Output File")
+}
+
+func TestLogBackupPlan(t *testing.T) {
+	plan := newTestBackupPlan()
+	plan.Namespaces[0].Problems = nil
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logBackupPlan(plan, logger)
+
+	logOutput := buf.String()
+	assert.Contains(t, logOutput, "backup plan")
+	assert.Contains(t, logOutput, "result=OK")
+	assert.Contains(t, logOutput, "namespace=test")
+	assert.Contains(t, logOutput, "partitions=0-99,200")
+	assert.Contains(t, logOutput, "remove-files=yes")
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const progressBarWidth = 20
+
+// ProgressCounters returns the number of records and bytes processed so far
+// and the expected total bytes, 0 if unknown.
+type ProgressCounters func() (records, bytes, total uint64)
+
+// Progress periodically reports records/s, bytes/s, percent done and ETA of a running backup or restore.
+// The percent and ETA are calculated only if the expected total bytes are known.
+type Progress struct {
+	operation   string
+	interval    time.Duration
+	counters    ProgressCounters
+	reportToLog bool
+	logger      *slog.Logger
+}
+
+// NewProgress returns a progress reporter for the operation.
+func NewProgress(
+	operation string,
+	interval time.Duration,
+	counters ProgressCounters,
+	reportToLog bool,
+	logger *slog.Logger,
+) *Progress {
+	return &Progress{
+		operation:   operation,
+		interval:    interval,
+		counters:    counters,
+		reportToLog: reportToLog,
+		logger:      logger,
+	}
+}
+
+// Start runs the reporter in the background until ctx is canceled or the returned stop function is called.
+// If the interval is not positive, nothing is reported.
+func (p *Progress) Start(ctx context.Context) (stop func()) {
+	if p == nil || p.interval <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	start := time.Now()
+	isTTY := !p.reportToLog && isTerminal(outWriter)
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				records, bytes, total := p.counters()
+				p.report(newProgressSnapshot(records, bytes, total, time.Since(start)), isTTY)
+			}
+		}
+	})
+
+	return func() {
+		cancel()
+		wg.Wait()
+		// Move the cursor from the progress bar line, so the report starts on a new line.
+		if isTTY {
+			printToOutWriter("")
+		}
+	}
+}
+
+func (p *Progress) report(s progressSnapshot, isTTY bool) {
+	switch {
+	case p.reportToLog:
+		logProgress(p.operation, s, p.logger)
+	case isTTY:
+		// Rewrite the same line on every tick.
+		_, _ = fmt.Fprintf(outWriter, "\r\033[K%s", formatProgress(p.operation, s))
+	default:
+		printToOutWriter(formatProgress(p.operation, s))
+	}
+}
+
+// progressSnapshot contains the progress values calculated on a single tick.
+type progressSnapshot struct {
+	records       uint64
+	bytes         uint64
+	elapsed       time.Duration
+	recordsPerSec float64
+	bytesPerSec   float64
+	// percent is negative if the total is unknown.
+	percent float64
+	// eta is negative if it can't be calculated yet.
+	eta time.Duration
+}
+
+func newProgressSnapshot(records, bytes, total uint64, elapsed time.Duration) progressSnapshot {
+	s := progressSnapshot{
+		records: records,
+		bytes:   bytes,
+		elapsed: elapsed,
+		percent: -1,
+		eta:     -1,
+	}
+
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		s.recordsPerSec = float64(records) / seconds
+		s.bytesPerSec = float64(bytes) / seconds
+	}
+
+	if total == 0 {
+		return s
+	}
+
+	// The total is an estimate, so the processed bytes can exceed it.
+	s.percent = min(float64(bytes)/float64(total)*100, 100)
+
+	if s.bytesPerSec > 0 {
+		remaining := float64(total) - float64(min(bytes, total))
+		s.eta = time.Duration(remaining / s.bytesPerSec * float64(time.Second)).Round(time.Second)
+	}
+
+	return s
+}
+
+// formatProgress renders the snapshot as a single line.
+func formatProgress(operation string, s progressSnapshot) string {
+	var sb strings.Builder
+
+	sb.WriteString(operation)
+	sb.WriteString(": ")
+
+	if s.percent >= 0 {
+		done := int(s.percent / 100 * progressBarWidth)
+		fmt.Fprintf(&sb, "[%s%s] %5.1f%% ",
+			strings.Repeat("=", done), strings.Repeat(" ", progressBarWidth-done), s.percent)
+	}
+
+	fmt.Fprintf(&sb, "%d records (%.0f rec/s), %s (%s/s)",
+		s.records, s.recordsPerSec, formatBytes(float64(s.bytes)), formatBytes(s.bytesPerSec))
+
+	if s.eta >= 0 {
+		fmt.Fprintf(&sb, ", ETA %s", s.eta)
+	}
+
+	return sb.String()
+}
+
+func logProgress(operation string, s progressSnapshot, logger *slog.Logger) {
+	attrs := []any{
+		slog.Duration("elapsed", s.elapsed.Round(time.Second)),
+		slog.Uint64("records", s.records),
+		slog.Uint64("bytes", s.bytes),
+		slog.Float64("records-per-second", s.recordsPerSec),
+		slog.Float64("bytes-per-second", s.bytesPerSec),
+	}
+
+	if s.percent >= 0 {
+		attrs = append(attrs, slog.Float64("percent", s.percent))
+	}
+
+	if s.eta >= 0 {
+		attrs = append(attrs, slog.Duration("eta", s.eta))
+	}
+
+	logger.Info(operation+" progress", attrs...)
+}
+
+// formatBytes formats the number of bytes with a binary unit suffix.
+func formatBytes(b float64) string {
+	const unit = 1024
+
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+
+	i := 0
+	for b >= unit && i < len(units)-1 {
+		b /= unit
+		i++
+	}
+
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", b, units[i])
+	}
+
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
+
+// isTerminal reports whether w is a character device, so the progress line can be rewritten in place.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProgressSnapshot(t *testing.T) {
+	t.Parallel()
+
+	s := newProgressSnapshot(1000, 4096, 16384, 4*time.Second)
+
+	assert.InDelta(t, 250, s.recordsPerSec, 0.001)
+	assert.InDelta(t, 1024, s.bytesPerSec, 0.001)
+	assert.InDelta(t, 25, s.percent, 0.001)
+	assert.Equal(t, 12*time.Second, s.eta)
+}
+
+func TestNewProgressSnapshotUnknownTotal(t *testing.T) {
+	t.Parallel()
+
+	s := newProgressSnapshot(1000, 4096, 0, 4*time.Second)
+
+	assert.InDelta(t, 250, s.recordsPerSec, 0.001)
+	assert.Negative(t, s.percent)
+	assert.Negative(t, s.eta)
+}
+
+func TestNewProgressSnapshotExceedsTotal(t *testing.T) {
+	t.Parallel()
+
+	// The backup total is an estimate, so it can be exceeded.
+	s := newProgressSnapshot(10, 2048, 1024, time.Second)
+
+	assert.InDelta(t, 100, s.percent, 0.001)
+	assert.Equal(t, time.Duration(0), s.eta)
+}
+
+func TestNewProgressSnapshotNoElapsed(t *testing.T) {
+	t.Parallel()
+
+	s := newProgressSnapshot(0, 0, 1024, 0)
+
+	assert.Zero(t, s.recordsPerSec)
+	assert.Zero(t, s.percent)
+	assert.Negative(t, s.eta)
+}
+
+func TestFormatProgress(t *testing.T) {
+	t.Parallel()
+
+	line := formatProgress("backup", newProgressSnapshot(1000, 4096, 16384, 4*time.Second))
+
+	assert.Equal(t,
+		"backup: [=====               ]  25.0% 1000 records (250 rec/s), 4.0 KiB (1.0 KiB/s), ETA 12s",
+		line)
+}
+
+func TestFormatProgressUnknownTotal(t *testing.T) {
+	t.Parallel()
+
+	line := formatProgress("restore", newProgressSnapshot(10, 100, 0, time.Second))
+
+	assert.Equal(t, "restore: 10 records (10 rec/s), 100 B (100 B/s)", line)
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0 B", formatBytes(0))
+	assert.Equal(t, "1023 B", formatBytes(1023))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "2.0 GiB", formatBytes(2*1024*1024*1024))
+}
+
+func TestProgressStartPrint(t *testing.T) {
+	output := captureOutput(t, func() {
+		p := NewProgress("restore", 5*time.Millisecond, func() (uint64, uint64, uint64) {
+			return 10, 500, 1000
+		}, false, slog.Default())
+
+		stop := p.Start(context.Background())
+		time.Sleep(30 * time.Millisecond)
+		stop()
+	})
+
+	require.NotEmpty(t, output)
+	// Output to a buffer is not a terminal, so every tick is printed on its own line.
+	assert.NotContains(t, output, "\r")
+	assert.Contains(t, strings.Split(output, "\n")[0], "restore: [==========          ]  50.0% 10 records")
+}
+
+func TestProgressStartLog(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+
+	p := NewProgress("backup", 5*time.Millisecond, func() (uint64, uint64, uint64) {
+		return 10, 500, 0
+	}, true, logger)
+
+	stop := p.Start(context.Background())
+	time.Sleep(30 * time.Millisecond)
+	stop()
+
+	out := buf.String()
+	assert.Contains(t, out, `"msg":"backup progress"`)
+	assert.Contains(t, out, `"records":10`)
+	assert.Contains(t, out, `"bytes":500`)
+	assert.NotContains(t, out, `"percent"`)
+}
+
+func TestProgressStartDisabled(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	p := NewProgress("backup", 0, func() (uint64, uint64, uint64) {
+		called = true
+		return 0, 0, 0
+	}, true, slog.Default())
+
+	stop := p.Start(context.Background())
+	time.Sleep(10 * time.Millisecond)
+	stop()
+
+	assert.False(t, called)
+}
@@ -15,10 +15,12 @@
 package logging
 
 import (
+	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
+	"github.com/aerospike/absctl/internal/models"
 	bModels "github.com/aerospike/backup-go/models"
 )
 
@@ -27,6 +29,7 @@ const (
 	headerRestoreReport    = "Restore report"
 	headerEstimateReport   = "Estimate report"
 	headerValidationReport = "Validation report"
+	headerVerifyReport     = "Verify report"
 )
 
 // ReportBackup prints the backup report.
@@ -83,18 +86,58 @@ func logBackupReport(stats *bModels.BackupStats, isXdr bool, logger *slog.Logger
 	)
 }
 
+// ReportDestinations prints the files and bytes written to each destination of a backup written
+// to several storages, and the error of each failed destination.
+// if toLog is true, it prints the report to log, but logger must be passed
+func ReportDestinations(destinations []models.DestinationStats, toLog bool, logger *slog.Logger) {
+	if len(destinations) == 0 {
+		return
+	}
+
+	if toLog {
+		for _, d := range destinations {
+			attrs := []any{
+				slog.String("destination", d.Name),
+				slog.Uint64("files-written", d.FilesWritten),
+				slog.Uint64("bytes-written", d.BytesWritten),
+			}
+
+			if d.Err != nil {
+				logger.Warn("backup destination failed", append(attrs, slog.Any("error", d.Err))...)
+				continue
+			}
+
+			logger.Info("backup destination", attrs...)
+		}
+
+		return
+	}
+
+	printToOutWriter("")
+
+	for _, d := range destinations {
+		value := fmt.Sprintf("%d files, %d bytes", d.FilesWritten, d.BytesWritten)
+		if d.Err != nil {
+			value += ", failed: " + d.Err.Error()
+		}
+
+		printMetric("Destination "+d.Name, value)
+	}
+}
+
 // ReportRestore prints the restore report.
+// filtered is the number of records that didn't match the filter expression.
 // if toLog is true, it prints the report to log, but logger must be passed
-func ReportRestore(stats *bModels.RestoreStats, isValidation, toLog bool, logger *slog.Logger) {
+func ReportRestore(stats *bModels.RestoreStats, filtered uint64, isValidation, toLog bool, logger *slog.Logger) {
 	if toLog {
-		logRestoreReport(stats, logger, isValidation)
+		logRestoreReport(stats, filtered, logger, isValidation)
 		return
 	}
 
-	printRestoreReport(stats, isValidation)
+	printRestoreReport(stats, filtered, isValidation)
 }
 
-func printRestoreReport(stats *bModels.RestoreStats, isValidation bool) {
+func printRestoreReport(stats *bModels.RestoreStats, filtered uint64, isValidation bool) {
 	header := headerRestoreReport
 	if isValidation {
 		header = headerValidationReport
@@ -112,6 +155,7 @@ func printRestoreReport(stats *bModels.RestoreStats, isValidation bool) {
 	printMetric("Records Read", stats.GetReadRecords())
 	printMetric("sIndex Read", stats.GetSIndexes())
 	printMetric("UDFs Read", stats.GetUDFs())
+	printMetric("Filtered Records", filtered)
 
 	printToOutWriter("")
 
@@ -135,7 +179,7 @@ func printRestoreReport(stats *bModels.RestoreStats, isValidation bool) {
 	}
 }
 
-func logRestoreReport(stats *bModels.RestoreStats, logger *slog.Logger, isValidation bool) {
+func logRestoreReport(stats *bModels.RestoreStats, filtered uint64, logger *slog.Logger, isValidation bool) {
 	header := strings.ToLower(headerRestoreReport)
 	if isValidation {
 		header = strings.ToLower(headerValidationReport)
@@ -148,6 +192,7 @@ func logRestoreReport(stats *bModels.RestoreStats, logger *slog.Logger, isValida
 		slog.Uint64("records-read", stats.GetReadRecords()),
 		slog.Uint64("s-index-read", uint64(stats.GetSIndexes())),
 		slog.Uint64("udf-read", uint64(stats.GetUDFs())),
+		slog.Uint64("filtered-records", filtered),
 	)
 
 	if !isValidation {
@@ -169,6 +214,47 @@ func logRestoreReport(stats *bModels.RestoreStats, logger *slog.Logger, isValida
 	logger.Info(header, logAttr...)
 }
 
+// ReportNotFound prints the records of the digest and key files that were not found in the backup.
+// if toLog is true, it prints them to log, but logger must be passed
+func ReportNotFound(notFound []string, toLog bool, logger *slog.Logger) {
+	if toLog {
+		if len(notFound) > 0 {
+			logger.Warn("records not found in backup",
+				slog.Int("count", len(notFound)),
+				slog.Any("records", notFound),
+			)
+		}
+
+		return
+	}
+
+	printToOutWriter("")
+	printMetric("Records Not Found", len(notFound))
+
+	for _, record := range notFound {
+		printToOutWriter("  " + record)
+	}
+}
+
+// ReportRejected prints the number of records that failed to write and where they were saved.
+// If toLog is true, it logs a warning when records were rejected.
+func ReportRejected(rejected uint64, location string, toLog bool, logger *slog.Logger) {
+	if toLog {
+		if rejected > 0 {
+			logger.Warn("records rejected",
+				slog.Uint64("count", rejected),
+				slog.String("reject-dir", location),
+			)
+		}
+
+		return
+	}
+
+	printToOutWriter("")
+	printMetric("Records Rejected", rejected)
+	printMetric("Reject Directory", location)
+}
+
 // ReportEstimate prints the estimate report.
 // if toLog is true, it prints the report to log, but logger must be passed
 // estimate is the size of the backup file in bytes.
@@ -193,3 +279,103 @@ func logEstimateReport(estimate uint64, logger *slog.Logger) {
 		slog.Uint64("file-size-bytes", estimate),
 	)
 }
+
+// ReportVerify prints the verify report.
+// if toLog is true, it prints the report to log, but logger must be passed
+func ReportVerify(report *models.VerifyReport, toLog bool, logger *slog.Logger) {
+	if toLog {
+		logVerifyReport(report, logger)
+		return
+	}
+
+	printVerifyReport(report)
+}
+
+func printVerifyReport(report *models.VerifyReport) {
+	printSection(headerVerifyReport)
+
+	printMetric("Result", verifyResult(report))
+
+	printToOutWriter("")
+
+	printMetric("Files Checked", report.FilesChecked)
+	printMetric("Bytes Checked", report.BytesChecked)
+	printMetric("Signature Checked", report.SignatureChecked)
+
+	if report.SignatureSkipped != "" {
+		printMetric("Signature Skipped", report.SignatureSkipped)
+	}
+
+	if report.DecodeChecked {
+		printMetric("Records Read", report.RecordsRead)
+	}
+
+	printList("Missing Files", report.Missing)
+	printList("Extra Files", report.Extra)
+	printList("Truncated Files", report.Truncated)
+	printList("Corrupted Files", report.Corrupted)
+	printList("Config Mismatch", report.ConfigMismatch)
+
+	if report.SignatureError != "" {
+		printList("Signature Error", []string{report.SignatureError})
+	}
+
+	if report.DecodeError != "" {
+		printList("Decode Error", []string{report.DecodeError})
+	}
+}
+
+// printList prints the number of items and one item per line, nothing if the list is empty.
+func printList(key string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+
+	printToOutWriter("")
+	printMetric(key, len(items))
+
+	for _, item := range items {
+		printToOutWriter("  " + item)
+	}
+}
+
+func logVerifyReport(report *models.VerifyReport, logger *slog.Logger) {
+	logAttr := []any{
+		slog.String("result", verifyResult(report)),
+		slog.Int("files-checked", report.FilesChecked),
+		slog.Int64("bytes-checked", report.BytesChecked),
+		slog.Bool("signature-checked", report.SignatureChecked),
+		slog.Bool("decode-checked", report.DecodeChecked),
+		slog.Uint64("records-read", report.RecordsRead),
+		slog.Any("missing-files", report.Missing),
+		slog.Any("extra-files", report.Extra),
+		slog.Any("truncated-files", report.Truncated),
+		slog.Any("corrupted-files", report.Corrupted),
+		slog.Any("config-mismatch", report.ConfigMismatch),
+	}
+
+	if report.SignatureSkipped != "" {
+		logAttr = append(logAttr, slog.String("signature-skipped", report.SignatureSkipped))
+	}
+
+	if report.SignatureError != "" {
+		logAttr = append(logAttr, slog.String("signature-error", report.SignatureError))
+	}
+
+	if report.DecodeError != "" {
+		logAttr = append(logAttr, slog.String("decode-error", report.DecodeError))
+	}
+
+	logger.Info(strings.ToLower(headerVerifyReport), logAttr...)
+}
+
+func verifyResult(report *models.VerifyReport) string {
+	switch {
+	case !report.Passed():
+		return "FAILED"
+	case !report.SignatureChecked:
+		return "PASSED, SIGNATURE NOT CHECKED"
+	default:
+		return "PASSED"
+	}
+}
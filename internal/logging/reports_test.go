@@ -16,11 +16,13 @@ package logging
 
 import (
 	"bytes"
+	"errors"
 	"log/slog"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aerospike/absctl/internal/models"
 	bModels "github.com/aerospike/backup-go/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -216,11 +218,47 @@ func TestReportBackup(t *testing.T) {
 	})
 }
 
+func TestReportDestinations(t *testing.T) {
+	destinations := []models.DestinationStats{
+		{Name: models.StorageLocal, FilesWritten: 10, BytesWritten: 5000},
+		{Name: models.StorageS3, FilesWritten: 4, BytesWritten: 2000, Err: errors.New("upload failed")},
+	}
+
+	t.Run("Console output", func(t *testing.T) {
+		output := captureOutput(t, func() {
+			ReportDestinations(destinations, false, nil)
+		})
+
+		assert.Contains(t, output, indent("Destination local")+"10 files, 5000 bytes\n")
+		assert.Contains(t, output, indent("Destination s3")+"4 files, 2000 bytes, failed: upload failed\n")
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		ReportDestinations(destinations, true, logger)
+
+		logOutput := buf.String()
+		assert.Contains(t, logOutput, "level=INFO msg=\"backup destination\" destination=local files-written=10")
+		assert.Contains(t, logOutput, "level=WARN msg=\"backup destination failed\" destination=s3")
+		assert.Contains(t, logOutput, "error=\"upload failed\"")
+	})
+
+	t.Run("No destinations", func(t *testing.T) {
+		output := captureOutput(t, func() {
+			ReportDestinations(nil, false, nil)
+		})
+
+		assert.Empty(t, output)
+	})
+}
+
 func TestPrintRestoreReport(t *testing.T) {
 	stats := newSampleRestoreStats()
 
 	output := captureOutput(t, func() {
-		printRestoreReport(stats, false)
+		printRestoreReport(stats, 70, false)
 	})
 
 	assert.Contains(t, output, headerRestoreReport)
@@ -233,6 +271,8 @@ func TestPrintRestoreReport(t *testing.T) {
 	assert.Contains(t, output, "5")
 	assert.Contains(t, output, "UDFs Read")
 	assert.Contains(t, output, "3")
+	assert.Contains(t, output, "Filtered Records")
+	assert.Contains(t, output, "70")
 	assert.Contains(t, output, "Expired Records")
 	assert.Contains(t, output, "10")
 	assert.Contains(t, output, "Skipped Records")
@@ -257,7 +297,7 @@ func TestLogRestoreReport(t *testing.T) {
 	var buf bytes.Buffer
 
 	logger := slog.New(slog.NewTextHandler(&buf, nil))
-	logRestoreReport(stats, logger, false)
+	logRestoreReport(stats, 70, logger, false)
 
 	logOutput := buf.String()
 	assert.Contains(t, logOutput, "restore report")
@@ -266,6 +306,7 @@ func TestLogRestoreReport(t *testing.T) {
 	assert.Contains(t, logOutput, "records-read=1000")
 	assert.Contains(t, logOutput, "s-index-read=5")
 	assert.Contains(t, logOutput, "udf-read=3")
+	assert.Contains(t, logOutput, "filtered-records=70")
 	assert.Contains(t, logOutput, "expired-records=10")
 	assert.Contains(t, logOutput, "skipped-records=20")
 	assert.Contains(t, logOutput, "ignored-records=30")
@@ -281,7 +322,7 @@ func TestReportRestore(t *testing.T) {
 
 	t.Run("Console output", func(t *testing.T) {
 		output := captureOutput(t, func() {
-			ReportRestore(stats, false, false, nil)
+			ReportRestore(stats, 0, false, false, nil)
 		})
 
 		assert.Contains(t, output, headerRestoreReport)
@@ -292,7 +333,7 @@ func TestReportRestore(t *testing.T) {
 		var buf bytes.Buffer
 
 		logger := slog.New(slog.NewTextHandler(&buf, nil))
-		ReportRestore(stats, false, true, logger)
+		ReportRestore(stats, 0, false, true, logger)
 
 		logOutput := buf.String()
 		assert.Contains(t, logOutput, "restore report")
@@ -300,6 +341,73 @@ func TestReportRestore(t *testing.T) {
 	})
 }
 
+func TestReportNotFound(t *testing.T) {
+	notFound := []string{"users:int:1", "0102030405060708090a0b0c0d0e0f1011121314"}
+
+	t.Run("Console output", func(t *testing.T) {
+		output := captureOutput(t, func() {
+			ReportNotFound(notFound, false, nil)
+		})
+
+		assert.Contains(t, output, "Records Not Found")
+		assert.Contains(t, output, "  users:int:1\n")
+		assert.Contains(t, output, "  0102030405060708090a0b0c0d0e0f1011121314\n")
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		ReportNotFound(notFound, true, logger)
+
+		logOutput := buf.String()
+		assert.Contains(t, logOutput, "records not found in backup")
+		assert.Contains(t, logOutput, "count=2")
+	})
+
+	t.Run("All found", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		ReportNotFound(nil, true, logger)
+
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestReportRejected(t *testing.T) {
+	t.Run("Console output", func(t *testing.T) {
+		output := captureOutput(t, func() {
+			ReportRejected(3, "aws-s3 bucket/rejects", false, nil)
+		})
+
+		assert.Contains(t, output, "Records Rejected")
+		assert.Contains(t, output, "3")
+		assert.Contains(t, output, "aws-s3 bucket/rejects")
+	})
+
+	t.Run("JSON output", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		ReportRejected(3, "rejects", true, logger)
+
+		logOutput := buf.String()
+		assert.Contains(t, logOutput, "records rejected")
+		assert.Contains(t, logOutput, "count=3")
+		assert.Contains(t, logOutput, "reject-dir=rejects")
+	})
+
+	t.Run("None rejected", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		ReportRejected(0, "rejects", true, logger)
+
+		assert.Empty(t, buf.String())
+	})
+}
+
 func TestPrintEstimateReport(t *testing.T) {
 	output := captureOutput(t, func() {
 		printEstimateReport(5000000)
@@ -343,3 +451,54 @@ func TestReportEstimate(t *testing.T) {
 		assert.Contains(t, logOutput, "file-size-bytes=5000000")
 	})
 }
+
+func TestPrintVerifyReport(t *testing.T) {
+	report := &models.VerifyReport{
+		FilesChecked:     2,
+		BytesChecked:     1024,
+		Missing:          []string{"missing.asb"},
+		Corrupted:        []string{"corrupted.asb"},
+		DecodeChecked:    true,
+		RecordsRead:      100,
+		SignatureChecked: true,
+		SignatureError:   "manifest signature does not match",
+	}
+
+	output := captureOutput(t, func() {
+		printVerifyReport(report)
+	})
+
+	assert.Contains(t, output, headerVerifyReport)
+	assert.Contains(t, output, "Result:"+strings.Repeat(" ", 21-len("Result"))+"FAILED")
+	assert.Contains(t, output, "Files Checked:"+strings.Repeat(" ", 21-len("Files Checked"))+"2")
+	assert.Contains(t, output, "Bytes Checked:"+strings.Repeat(" ", 21-len("Bytes Checked"))+"1024")
+	assert.Contains(t, output, "Records Read:"+strings.Repeat(" ", 21-len("Records Read"))+"100")
+	assert.Contains(t, output, "Missing Files:"+strings.Repeat(" ", 21-len("Missing Files"))+"1")
+	assert.Contains(t, output, "  missing.asb")
+	assert.Contains(t, output, "Corrupted Files:"+strings.Repeat(" ", 21-len("Corrupted Files"))+"1")
+	assert.Contains(t, output, "  corrupted.asb")
+	assert.Contains(t, output, "Signature Checked:"+strings.Repeat(" ", 21-len("Signature Checked"))+"true")
+	assert.Contains(t, output, "  manifest signature does not match")
+	assert.NotContains(t, output, "Extra Files")
+}
+
+func TestLogVerifyReport(t *testing.T) {
+	report := &models.VerifyReport{
+		FilesChecked:     2,
+		BytesChecked:     1024,
+		SignatureSkipped: "no encryption key given",
+	}
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logVerifyReport(report, logger)
+
+	logOutput := buf.String()
+	assert.Contains(t, logOutput, "verify report")
+	assert.Contains(t, logOutput, `result="PASSED, SIGNATURE NOT CHECKED"`)
+	assert.Contains(t, logOutput, `signature-skipped="no encryption key given"`)
+	assert.Contains(t, logOutput, "files-checked=2")
+	assert.Contains(t, logOutput, "bytes-checked=1024")
+	assert.NotContains(t, logOutput, "decode-error")
+}
@@ -0,0 +1,232 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aerospike/absctl/internal/models"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+const (
+	// metricsPath is the HTTP path the metrics are served on.
+	metricsPath = "/metrics"
+	// textfileInterval is how often the textfile is rewritten while the run is in progress.
+	textfileInterval = 10 * time.Second
+	// shutdownTimeout limits the time to wait for in-flight scrapes on stop.
+	shutdownTimeout = 5 * time.Second
+	// readHeaderTimeout protects the HTTP server from slow clients.
+	readHeaderTimeout = 10 * time.Second
+)
+
+const (
+	// TypeScan labels stats of a scan backup.
+	TypeScan = "scan"
+	// TypeXDR labels stats of an XDR backup.
+	TypeXDR = "xdr"
+)
+
+// Exporter publishes the stats of backups and restores registered during the run.
+// A nil Exporter is valid and does nothing, so services can call it unconditionally.
+type Exporter struct {
+	operation string
+	listen    string
+	textfile  string
+
+	mu       sync.Mutex
+	backups  []backupSource
+	restores []restoreSource
+	// result is nil while the run is in progress.
+	result *runResult
+
+	server *http.Server
+	// addr is the address the server listens on, with the resolved port.
+	addr   string
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *slog.Logger
+}
+
+type backupSource struct {
+	namespace string
+	kind      string
+	stats     *bModels.BackupStats
+}
+
+type restoreSource struct {
+	kind  string
+	stats *bModels.RestoreStats
+}
+
+type runResult struct {
+	success   bool
+	completed time.Time
+}
+
+// NewExporter returns an exporter for the operation ("backup" or "restore"),
+// or nil if neither metrics-listen nor metrics-textfile is set.
+func NewExporter(operation string, app *models.App, logger *slog.Logger) *Exporter {
+	if app == nil || (app.MetricsListen == "" && app.MetricsTextfile == "") {
+		return nil
+	}
+
+	return &Exporter{
+		operation: operation,
+		listen:    app.MetricsListen,
+		textfile:  app.MetricsTextfile,
+		logger:    logger,
+	}
+}
+
+// Start starts the HTTP endpoint and the periodic textfile writes.
+func (e *Exporter) Start(ctx context.Context) error {
+	if e == nil {
+		return nil
+	}
+
+	ctx, e.cancel = context.WithCancel(ctx)
+
+	if e.listen != "" {
+		// Listen synchronously, so a busy port fails the run before it starts.
+		lc := net.ListenConfig{}
+
+		ln, err := lc.Listen(ctx, "tcp", e.listen)
+		if err != nil {
+			e.cancel()
+			return fmt.Errorf("failed to listen on %s: %w", e.listen, err)
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc(metricsPath, e.handle)
+
+		e.addr = ln.Addr().String()
+		e.server = &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: readHeaderTimeout,
+		}
+
+		e.wg.Go(func() {
+			if err := e.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				e.logger.Error("metrics server failed", slog.Any("error", err))
+			}
+		})
+
+		e.logger.Info("serving metrics",
+			slog.String("address", e.addr),
+			slog.String("path", metricsPath),
+		)
+	}
+
+	if e.textfile != "" {
+		e.wg.Go(func() {
+			ticker := time.NewTicker(textfileInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					e.writeTextfile()
+				}
+			}
+		})
+	}
+
+	return nil
+}
+
+// AddBackup registers the stats of a running backup. The stats are read on every scrape.
+func (e *Exporter) AddBackup(namespace, kind string, stats *bModels.BackupStats) {
+	if e == nil || stats == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.backups = append(e.backups, backupSource{namespace: namespace, kind: kind, stats: stats})
+}
+
+// AddRestore registers the stats of a running restore. The stats are read on every scrape.
+func (e *Exporter) AddRestore(kind string, stats *bModels.RestoreStats) {
+	if e == nil || stats == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.restores = append(e.restores, restoreSource{kind: kind, stats: stats})
+}
+
+// Stop records the result of the run, writes the final textfile and stops the HTTP endpoint.
+func (e *Exporter) Stop(runErr error) {
+	if e == nil || e.cancel == nil {
+		return
+	}
+
+	e.mu.Lock()
+	e.result = &runResult{success: runErr == nil, completed: time.Now()}
+	e.mu.Unlock()
+
+	e.cancel()
+
+	if e.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := e.server.Shutdown(ctx); err != nil {
+			e.logger.Warn("failed to stop metrics server", slog.Any("error", err))
+		}
+	}
+
+	e.wg.Wait()
+
+	e.writeTextfile()
+}
+
+func (e *Exporter) handle(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(e.render())
+}
+
+// writeTextfile replaces the textfile atomically, so node exporter never reads a partial file.
+func (e *Exporter) writeTextfile() {
+	if e.textfile == "" {
+		return
+	}
+
+	tmp := e.textfile + ".tmp"
+
+	if err := os.WriteFile(tmp, e.render(), 0o644); err != nil {
+		e.logger.Warn("failed to write metrics textfile", slog.String("file", tmp), slog.Any("error", err))
+		return
+	}
+
+	if err := os.Rename(tmp, e.textfile); err != nil {
+		e.logger.Warn("failed to write metrics textfile", slog.String("file", e.textfile), slog.Any("error", err))
+	}
+}
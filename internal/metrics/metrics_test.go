@@ -0,0 +1,173 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/models"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExporter(listen, textfile string) *Exporter {
+	return NewExporter("backup", &models.App{MetricsListen: listen, MetricsTextfile: textfile},
+		slog.New(slog.DiscardHandler))
+}
+
+func newTestBackupStats() *bModels.BackupStats {
+	stats := bModels.NewBackupStats()
+	stats.ReadRecords.Add(1000)
+	stats.BytesWritten.Add(5000000)
+	stats.IncFiles()
+
+	return stats
+}
+
+func TestNewExporter_Disabled(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, NewExporter("backup", &models.App{}, slog.Default()))
+	assert.Nil(t, NewExporter("backup", nil, slog.Default()))
+}
+
+func TestExporter_Nil(t *testing.T) {
+	t.Parallel()
+
+	var e *Exporter
+
+	require.NoError(t, e.Start(t.Context()))
+	e.AddBackup("test", TypeScan, newTestBackupStats())
+	e.AddRestore("asb", bModels.NewRestoreStats())
+	e.Stop(nil)
+}
+
+func TestExporter_RenderBackup(t *testing.T) {
+	t.Parallel()
+
+	e := newTestExporter(":0", "")
+	e.AddBackup("test", TypeScan, newTestBackupStats())
+	e.AddBackup("bar", TypeScan, bModels.NewBackupStats())
+
+	out := string(e.render())
+
+	assert.Contains(t, out, "# HELP absctl_backup_records_read_total Records read from the database, or received from XDR.\n")
+	assert.Contains(t, out, "# TYPE absctl_backup_records_read_total counter\n")
+	assert.Contains(t, out, `absctl_backup_records_read_total{namespace="test",type="scan"} 1000`+"\n")
+	assert.Contains(t, out, `absctl_backup_records_read_total{namespace="bar",type="scan"} 0`+"\n")
+	assert.Contains(t, out, `absctl_backup_bytes_written_total{namespace="test",type="scan"} 5e+06`+"\n")
+	assert.Contains(t, out, `absctl_backup_files_written_total{namespace="test",type="scan"} 1`+"\n")
+	assert.NotContains(t, out, "absctl_restore_")
+	// The result is exported only after the run.
+	assert.NotContains(t, out, "absctl_backup_success")
+}
+
+func TestExporter_RenderRestore(t *testing.T) {
+	t.Parallel()
+
+	stats := bModels.NewRestoreStats()
+	stats.ReadRecords.Add(10)
+	stats.IncrRecordsInserted()
+	stats.IncrRecordsExisted()
+	stats.IncrErrorsInDoubt()
+
+	e := NewExporter("restore", &models.App{MetricsTextfile: "unused.prom"}, slog.Default())
+	e.AddRestore("asb", stats)
+
+	out := string(e.render())
+
+	assert.Contains(t, out, `absctl_restore_records_read_total{type="asb"} 10`+"\n")
+	assert.Contains(t, out, `absctl_restore_records_inserted_total{type="asb"} 1`+"\n")
+	assert.Contains(t, out, `absctl_restore_records_existed_total{type="asb"} 1`+"\n")
+	assert.Contains(t, out, `absctl_restore_errors_in_doubt_total{type="asb"} 1`+"\n")
+	assert.NotContains(t, out, "absctl_backup_")
+}
+
+func TestExporter_Handle(t *testing.T) {
+	t.Parallel()
+
+	e := newTestExporter(":0", "")
+	e.AddBackup("test", TypeScan, newTestBackupStats())
+
+	rec := httptest.NewRecorder()
+	e.handle(rec, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `absctl_backup_records_read_total{namespace="test",type="scan"} 1000`)
+}
+
+func TestExporter_ServeAndStop(t *testing.T) {
+	t.Parallel()
+
+	e := newTestExporter("127.0.0.1:0", "")
+	require.NoError(t, e.Start(t.Context()))
+
+	e.AddBackup("test", TypeScan, newTestBackupStats())
+
+	resp, err := http.Get("http://" + e.addr + metricsPath)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Contains(t, string(body), `absctl_backup_records_read_total{namespace="test",type="scan"} 1000`)
+
+	e.Stop(nil)
+
+	// The server is stopped.
+	_, err = http.Get("http://" + e.addr + metricsPath)
+	require.Error(t, err)
+}
+
+func TestExporter_StartListenError(t *testing.T) {
+	t.Parallel()
+
+	e := newTestExporter("invalid-address", "")
+	require.ErrorContains(t, e.Start(t.Context()), "failed to listen on invalid-address")
+}
+
+func TestExporter_Textfile(t *testing.T) {
+	t.Parallel()
+
+	textfile := filepath.Join(t.TempDir(), "absctl.prom")
+
+	e := newTestExporter("", textfile)
+	require.NoError(t, e.Start(t.Context()))
+
+	e.AddBackup("test", TypeScan, newTestBackupStats())
+	e.Stop(errors.New("backup failed"))
+
+	data, err := os.ReadFile(textfile)
+	require.NoError(t, err)
+
+	out := string(data)
+	assert.Contains(t, out, `absctl_backup_records_read_total{namespace="test",type="scan"} 1000`)
+	assert.Contains(t, out, "# TYPE absctl_backup_success gauge\nabsctl_backup_success 0\n")
+	assert.Contains(t, out, "absctl_backup_last_completion_timestamp_seconds ")
+
+	// The temporary file is renamed to the textfile.
+	_, err = os.Stat(textfile + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
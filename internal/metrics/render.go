@@ -0,0 +1,149 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// contentType is the content type of the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const (
+	typeCounter = "counter"
+	typeGauge   = "gauge"
+)
+
+// metric describes a single metric calculated from stats of type T.
+type metric[T any] struct {
+	name  string
+	help  string
+	kind  string
+	value func(T) float64
+}
+
+var backupMetrics = []metric[*bModels.BackupStats]{
+	{"absctl_backup_records_read_total", "Records read from the database, or received from XDR.", typeCounter,
+		func(s *bModels.BackupStats) float64 { return float64(s.GetReadRecords()) }},
+	{"absctl_backup_sindexes_read_total", "Secondary indexes read from the database.", typeCounter,
+		func(s *bModels.BackupStats) float64 { return float64(s.GetSIndexes()) }},
+	{"absctl_backup_udfs_read_total", "UDFs read from the database.", typeCounter,
+		func(s *bModels.BackupStats) float64 { return float64(s.GetUDFs()) }},
+	{"absctl_backup_bytes_written_total", "Bytes written to the backup files.", typeCounter,
+		func(s *bModels.BackupStats) float64 { return float64(s.GetBytesWritten()) }},
+	{"absctl_backup_files_written_total", "Backup files written.", typeCounter,
+		func(s *bModels.BackupStats) float64 { return float64(s.GetFileCount()) }},
+	{"absctl_backup_duration_seconds", "Duration of the backup.", typeGauge,
+		func(s *bModels.BackupStats) float64 { return s.GetDuration().Seconds() }},
+}
+
+var restoreMetrics = []metric[*bModels.RestoreStats]{
+	{"absctl_restore_records_read_total", "Records read from the backup files.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetReadRecords()) }},
+	{"absctl_restore_records_inserted_total", "Records written to the database.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetRecordsInserted()) }},
+	{"absctl_restore_records_skipped_total", "Records skipped by filters.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetRecordsSkipped()) }},
+	{"absctl_restore_records_ignored_total", "Records ignored by the restore.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetRecordsIgnored()) }},
+	{"absctl_restore_records_fresher_total", "Records not written, as the database has a fresher version.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetRecordsFresher()) }},
+	{"absctl_restore_records_existed_total", "Records not written, as they already exist in the database.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetRecordsExisted()) }},
+	{"absctl_restore_records_expired_total", "Records not written, as they expired.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetRecordsExpired()) }},
+	{"absctl_restore_sindexes_read_total", "Secondary indexes read from the backup files.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetSIndexes()) }},
+	{"absctl_restore_udfs_read_total", "UDFs read from the backup files.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetUDFs()) }},
+	{"absctl_restore_errors_in_doubt_total", "Writes that failed with an in doubt error.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetErrorsInDoubt()) }},
+	{"absctl_restore_bytes_read_total", "Bytes read from the backup files.", typeCounter,
+		func(s *bModels.RestoreStats) float64 { return float64(s.GetTotalBytesRead()) }},
+	{"absctl_restore_duration_seconds", "Duration of the restore.", typeGauge,
+		func(s *bModels.RestoreStats) float64 { return s.GetDuration().Seconds() }},
+}
+
+// render returns the current metrics in the Prometheus text exposition format.
+func (e *Exporter) render() []byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var buf bytes.Buffer
+
+	if len(e.backups) > 0 {
+		for _, m := range backupMetrics {
+			writeHeader(&buf, m.name, m.help, m.kind)
+
+			for _, src := range e.backups {
+				writeSample(&buf, m.name, m.value(src.stats), "namespace", src.namespace, "type", src.kind)
+			}
+		}
+	}
+
+	if len(e.restores) > 0 {
+		for _, m := range restoreMetrics {
+			writeHeader(&buf, m.name, m.help, m.kind)
+
+			for _, src := range e.restores {
+				writeSample(&buf, m.name, m.value(src.stats), "type", src.kind)
+			}
+		}
+	}
+
+	if e.result != nil {
+		name := "absctl_" + e.operation + "_success"
+		writeHeader(&buf, name, "Whether the last "+e.operation+" run succeeded (1) or failed (0).", typeGauge)
+
+		success := 0.0
+		if e.result.success {
+			success = 1
+		}
+
+		writeSample(&buf, name, success)
+
+		name = "absctl_" + e.operation + "_last_completion_timestamp_seconds"
+		writeHeader(&buf, name, "Unix time the last "+e.operation+" run completed.", typeGauge)
+		writeSample(&buf, name, float64(e.result.completed.Unix()))
+	}
+
+	return buf.Bytes()
+}
+
+func writeHeader(buf *bytes.Buffer, name, help, kind string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, kind)
+}
+
+// writeSample writes a metric line, labels are passed as name-value pairs.
+func writeSample(buf *bytes.Buffer, name string, value float64, labels ...string) {
+	buf.WriteString(name)
+
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, labels[i]+"="+strconv.Quote(labels[i+1]))
+		}
+
+		buf.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	buf.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
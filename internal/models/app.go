@@ -25,6 +25,12 @@ type App struct {
 	LogJSON bool
 	// LogFile Path to the log file, if logging to a file.
 	LogFile string
+	// MetricsListen Address to serve Prometheus metrics on, if set.
+	MetricsListen string
+	// MetricsTextfile Path to the node exporter textfile to write metrics to, if set.
+	MetricsTextfile string
+	// ReportFile Path to the JSON report file written when the run ends, if set.
+	ReportFile string
 
 	// ConfigFilePath is the path to the file used for tool configuration.
 	ConfigFilePath string
@@ -17,6 +17,7 @@ package models
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,8 +28,22 @@ import (
 	"github.com/aerospike/backup-go/models"
 )
 
-// MaxRack max number of racks that can exist.
-const MaxRack = 1000000
+const (
+	// MaxRack max number of racks that can exist.
+	MaxRack = 1000000
+	// NamespaceAll is the --namespace value to back up all namespaces of the cluster.
+	NamespaceAll = "all"
+	// ResumableStateFileName is the name of the state file kept in the backup directory by resumable backups
+	// and checkpoints.
+	ResumableStateFileName = "absctl-resumable.state"
+)
+
+// Backup output formats.
+const (
+	OutputFormatASB     = "asb"
+	OutputFormatNDJSON  = "ndjson"
+	OutputFormatParquet = "parquet"
+)
 
 var (
 	// Time parsing expressions.
@@ -43,9 +58,11 @@ type Backup struct {
 	Common
 	MaxRetries          int
 	OutputFile          string
+	OutputFormat        string
 	RemoveFiles         bool
 	ModifiedBefore      string
 	ModifiedAfter       string
+	IncrementalFrom     string
 	FileLimit           uint64
 	AfterDigest         string
 	MaxRecords          int64
@@ -60,11 +77,19 @@ type Backup struct {
 	PartitionList       string
 	Estimate            bool
 	EstimateSamples     int64
+	DryRun              bool
 	StateFileDst        string
 	Continue            string
+	Resumable           bool
+	NoCheckpoint        bool
 	ScanPageSize        int64
 	OutputFilePrefix    string
 	RackList            string
+	// Destinations are the storages the backup is written to at once, one of StorageLocal, StorageS3,
+	// StorageGCP, StorageAzure or StorageSftp, comma separated. If empty, the backup is written to the configured storage.
+	Destinations string
+	// MinDestinations is the number of destinations that must succeed, 0 means all of them.
+	MinDestinations int
 }
 
 // ShouldClearTarget check if we should clean target directory.
@@ -76,6 +101,45 @@ func (b *Backup) ShouldSaveState() bool {
 	return b.StateFileDst != "" || b.Continue != ""
 }
 
+// IsExport checks if records are exported to NDJSON or Parquet files instead of the asb format.
+func (b *Backup) IsExport() bool {
+	return b != nil && b.OutputFormat != "" && b.OutputFormat != OutputFormatASB
+}
+
+// DestinationList returns the list of storages the backup is written to at once.
+func (b *Backup) DestinationList() []string {
+	if b == nil {
+		return nil
+	}
+
+	return SplitByComma(b.Destinations)
+}
+
+// RequiredDestinations returns the number of destinations that must succeed for the backup to succeed.
+func (b *Backup) RequiredDestinations() int {
+	if b.MinDestinations > 0 {
+		return b.MinDestinations
+	}
+
+	return len(b.DestinationList())
+}
+
+// Namespaces returns the list of namespaces to back up.
+func (b *Backup) Namespaces() []string {
+	return SplitByComma(b.Namespace)
+}
+
+// IsAllNamespaces checks if all namespaces of the cluster must be backed up.
+func (b *Backup) IsAllNamespaces() bool {
+	return b.Namespace == NamespaceAll
+}
+
+// IsMultiNamespace checks if more than one namespace must be backed up.
+// Each namespace is backed up into its own subdirectory.
+func (b *Backup) IsMultiNamespace() bool {
+	return b.IsAllNamespaces() || len(b.Namespaces()) > 1
+}
+
 //nolint:gocyclo // Long validation function.
 func (b *Backup) Validate() error {
 	if b == nil {
@@ -111,19 +175,43 @@ func (b *Backup) Validate() error {
 		return fmt.Errorf("continue and remove-files are mutually exclusive, as remove-files will delete the backup files")
 	}
 
+	if err := b.validateResumable(); err != nil {
+		return err
+	}
+
+	if err := b.validateOutputFormat(); err != nil {
+		return err
+	}
+
+	if err := b.validateDestinations(); err != nil {
+		return err
+	}
+
+	if b.IncrementalFrom != "" {
+		if b.ModifiedAfter != "" {
+			return fmt.Errorf("incremental-from and modified-after are mutually exclusive")
+		}
+
+		if b.Directory == "" {
+			return fmt.Errorf("incremental-from requires directory, as the chain is saved to the backup manifest")
+		}
+	}
+
+	if err := b.validateMultiNamespace(); err != nil {
+		return err
+	}
+
+	if b.DryRun && b.Estimate {
+		return fmt.Errorf("dry-run and estimate are mutually exclusive")
+	}
+
 	if b.MaxRecords != 0 && b.Parallel != 1 {
 		return fmt.Errorf("max-records must be used with parallel = 1")
 	}
 
 	if b.Estimate {
 		// Estimate with filter not allowed.
-		if b.PartitionList != "" ||
-			b.NodeList != "" ||
-			b.AfterDigest != "" ||
-			b.FilterExpression != "" ||
-			b.ModifiedAfter != "" ||
-			b.ModifiedBefore != "" ||
-			b.NoTTLOnly {
+		if b.HasFilter() {
 			return fmt.Errorf("estimate with any filter is not allowed")
 		}
 		// For estimate directory or file must not be set.
@@ -140,6 +228,122 @@ func (b *Backup) Validate() error {
 	return b.Common.Validate()
 }
 
+// HasFilter returns true if records are filtered by anything but namespace, sets or bins.
+// Backup size estimate doesn't take these filters into account.
+func (b *Backup) HasFilter() bool {
+	return b.PartitionList != "" ||
+		b.NodeList != "" ||
+		b.AfterDigest != "" ||
+		b.FilterExpression != "" ||
+		b.ModifiedAfter != "" ||
+		b.ModifiedBefore != "" ||
+		b.IncrementalFrom != "" ||
+		b.NoTTLOnly
+}
+
+// validateMultiNamespace checks options that can't be used when several namespaces are backed up.
+func (b *Backup) validateMultiNamespace() error {
+	if !b.IsMultiNamespace() {
+		return nil
+	}
+
+	if slices.Contains(b.Namespaces(), "") {
+		return fmt.Errorf("namespace list must not contain empty values")
+	}
+
+	switch {
+	case b.Estimate:
+		return fmt.Errorf("multiple namespaces backup is not allowed with estimate")
+	case b.Directory == "":
+		return fmt.Errorf("multiple namespaces backup requires directory")
+	case b.StateFileDst != "" || b.Continue != "":
+		return fmt.Errorf("multiple namespaces backup is not allowed with state-file-dst or continue")
+	case b.Resumable:
+		return fmt.Errorf("multiple namespaces backup is not allowed with resumable")
+	case b.RemoveArtifacts:
+		return fmt.Errorf("multiple namespaces backup is not allowed with remove-artifacts")
+	default:
+		return nil
+	}
+}
+
+// validateResumable checks options that can't be used with resumable backups.
+func (b *Backup) validateResumable() error {
+	if !b.Resumable {
+		return nil
+	}
+
+	switch {
+	case b.StateFileDst != "" || b.Continue != "":
+		return fmt.Errorf("resumable is not allowed with state-file-dst or continue, as it manages the state file itself")
+	case b.Directory == "":
+		return fmt.Errorf("resumable requires directory, as the state file is kept in the backup directory")
+	case b.FileLimit == 0:
+		return fmt.Errorf("resumable requires file-limit, as the state is saved when a file is closed")
+	case b.NodeList != "" || b.RackList != "":
+		return fmt.Errorf("resumable is not allowed with node-list or rack-list")
+	default:
+		return nil
+	}
+}
+
+// validateOutputFormat checks the output format and options that can't be used with exports.
+func (b *Backup) validateOutputFormat() error {
+	switch b.OutputFormat {
+	case "", OutputFormatASB:
+		return nil
+	case OutputFormatNDJSON, OutputFormatParquet:
+	default:
+		return fmt.Errorf("invalid output-format %q, must be one of %s, %s or %s",
+			b.OutputFormat, OutputFormatASB, OutputFormatNDJSON, OutputFormatParquet)
+	}
+
+	if b.Directory == "" {
+		return fmt.Errorf("output-format %s requires directory", b.OutputFormat)
+	}
+
+	return nil
+}
+
+// validateDestinations checks the storages the backup is written to at once.
+func (b *Backup) validateDestinations() error {
+	destinations := b.DestinationList()
+	if len(destinations) == 0 {
+		if b.MinDestinations != 0 {
+			return fmt.Errorf("min-destinations requires destinations")
+		}
+
+		return nil
+	}
+
+	for i, name := range destinations {
+		switch name {
+		case StorageLocal, StorageS3, StorageGCP, StorageAzure, StorageSftp:
+		default:
+			return fmt.Errorf("invalid destination %q, must be one of %s, %s, %s, %s or %s",
+				name, StorageLocal, StorageS3, StorageGCP, StorageAzure, StorageSftp)
+		}
+
+		if slices.Contains(destinations[:i], name) {
+			return fmt.Errorf("destination %s is set more than once", name)
+		}
+	}
+
+	switch {
+	case b.MinDestinations < 0:
+		return fmt.Errorf("min-destinations must not be negative")
+	case b.MinDestinations > len(destinations):
+		return fmt.Errorf("min-destinations %d is greater than the number of destinations %d",
+			b.MinDestinations, len(destinations))
+	case b.Estimate:
+		return fmt.Errorf("destinations are not allowed with estimate")
+	case b.OutputFile == "-":
+		return fmt.Errorf("destinations are not allowed with output to stdout")
+	default:
+		return nil
+	}
+}
+
 // ScanPolicy map backup config to scan policy.
 func (b *Backup) ScanPolicy() (*aerospike.ScanPolicy, error) {
 	p := aerospike.NewScanPolicy()
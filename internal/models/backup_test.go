@@ -301,6 +301,15 @@ func TestValidateBackup(t *testing.T) {
 			wantErr:     true,
 			expectedErr: "continue and remove-files are mutually exclusive, as remove-files will delete the backup files",
 		},
+		{
+			name: "Dry run with estimate",
+			backup: &Backup{
+				DryRun:   true,
+				Estimate: true,
+			},
+			wantErr:     true,
+			expectedErr: "dry-run and estimate are mutually exclusive",
+		},
 		{
 			name: "Max-records set with parallel > 1",
 			backup: &Backup{
@@ -319,6 +328,222 @@ func TestValidateBackup(t *testing.T) {
 				Common:     Common{Namespace: testNamespace, Parallel: 1},
 			},
 		},
+		{
+			name: "Incremental from with modified after",
+			backup: &Backup{
+				IncrementalFrom: testDir,
+				ModifiedAfter:   "2024-01-01",
+				Common:          Common{Namespace: testNamespace, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "incremental-from and modified-after are mutually exclusive",
+		},
+		{
+			name: "Incremental from to output file",
+			backup: &Backup{
+				IncrementalFrom: testDir,
+				OutputFile:      testFile,
+				Common:          Common{Namespace: testNamespace},
+			},
+			wantErr:     true,
+			expectedErr: "incremental-from requires directory, as the chain is saved to the backup manifest",
+		},
+		{
+			name: "Multiple namespaces to directory",
+			backup: &Backup{
+				Common: Common{Namespace: "ns1,ns2", Directory: testDir},
+			},
+		},
+		{
+			name: "All namespaces to directory",
+			backup: &Backup{
+				Common: Common{Namespace: NamespaceAll, Directory: testDir},
+			},
+		},
+		{
+			name: "Multiple namespaces to output file",
+			backup: &Backup{
+				OutputFile: testFile,
+				Common:     Common{Namespace: "ns1,ns2"},
+			},
+			wantErr:     true,
+			expectedErr: "multiple namespaces backup requires directory",
+		},
+		{
+			name: "Multiple namespaces with empty value",
+			backup: &Backup{
+				Common: Common{Namespace: "ns1,", Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "namespace list must not contain empty values",
+		},
+		{
+			name: "All namespaces with state file",
+			backup: &Backup{
+				StateFileDst: "state",
+				Common:       Common{Namespace: NamespaceAll, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "multiple namespaces backup is not allowed with state-file-dst or continue",
+		},
+		{
+			name: "All namespaces with estimate",
+			backup: &Backup{
+				Estimate: true,
+				Common:   Common{Namespace: NamespaceAll},
+			},
+			wantErr:     true,
+			expectedErr: "multiple namespaces backup is not allowed with estimate",
+		},
+		{
+			name: "Resumable to directory",
+			backup: &Backup{
+				Resumable: true,
+				FileLimit: 250,
+				Common:    Common{Namespace: testNamespace, Directory: testDir},
+			},
+		},
+		{
+			name: "Resumable with continue",
+			backup: &Backup{
+				Resumable: true,
+				Continue:  "state",
+				FileLimit: 250,
+				Common:    Common{Namespace: testNamespace, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "resumable is not allowed with state-file-dst or continue, as it manages the state file itself",
+		},
+		{
+			name: "Resumable to output file",
+			backup: &Backup{
+				Resumable:  true,
+				OutputFile: testFile,
+				FileLimit:  250,
+				Common:     Common{Namespace: testNamespace},
+			},
+			wantErr:     true,
+			expectedErr: "resumable requires directory, as the state file is kept in the backup directory",
+		},
+		{
+			name: "Resumable without file limit",
+			backup: &Backup{
+				Resumable: true,
+				Common:    Common{Namespace: testNamespace, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "resumable requires file-limit, as the state is saved when a file is closed",
+		},
+		{
+			name: "Resumable with node list",
+			backup: &Backup{
+				Resumable: true,
+				FileLimit: 250,
+				NodeList:  "node1",
+				Common:    Common{Namespace: testNamespace, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "resumable is not allowed with node-list or rack-list",
+		},
+		{
+			name: "All namespaces resumable",
+			backup: &Backup{
+				Resumable: true,
+				FileLimit: 250,
+				Common:    Common{Namespace: NamespaceAll, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "multiple namespaces backup is not allowed with resumable",
+		},
+		{
+			name: "No checkpoint",
+			backup: &Backup{
+				NoCheckpoint: true,
+				FileLimit:    250,
+				Common:       Common{Namespace: testNamespace, Directory: testDir},
+			},
+		},
+		{
+			name: "Parquet output format",
+			backup: &Backup{
+				OutputFormat: OutputFormatParquet,
+				Common:       Common{Namespace: testNamespace, Directory: testDir},
+			},
+		},
+		{
+			name: "Invalid output format",
+			backup: &Backup{
+				OutputFormat: "csv",
+				Common:       Common{Namespace: testNamespace, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: `invalid output-format "csv", must be one of asb, ndjson or parquet`,
+		},
+		{
+			name: "NDJSON output format to output file",
+			backup: &Backup{
+				OutputFormat: OutputFormatNDJSON,
+				OutputFile:   testFile,
+				Common:       Common{Namespace: testNamespace},
+			},
+			wantErr:     true,
+			expectedErr: "output-format ndjson requires directory",
+		},
+		{
+			name: "Destinations",
+			backup: &Backup{
+				Destinations:    "local,s3,azure",
+				MinDestinations: 2,
+				Common:          Common{Namespace: testNamespace, Directory: testDir},
+			},
+		},
+		{
+			name: "Invalid destination",
+			backup: &Backup{
+				Destinations: "local,ftp",
+				Common:       Common{Namespace: testNamespace, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: `invalid destination "ftp", must be one of local, s3, gcp, azure or sftp`,
+		},
+		{
+			name: "Duplicate destination",
+			backup: &Backup{
+				Destinations: "s3,local,s3",
+				Common:       Common{Namespace: testNamespace, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "destination s3 is set more than once",
+		},
+		{
+			name: "Min destinations without destinations",
+			backup: &Backup{
+				MinDestinations: 1,
+				Common:          Common{Namespace: testNamespace, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "min-destinations requires destinations",
+		},
+		{
+			name: "Min destinations greater than destinations",
+			backup: &Backup{
+				Destinations:    "local,gcp",
+				MinDestinations: 3,
+				Common:          Common{Namespace: testNamespace, Directory: testDir},
+			},
+			wantErr:     true,
+			expectedErr: "min-destinations 3 is greater than the number of destinations 2",
+		},
+		{
+			name: "Destinations to stdout",
+			backup: &Backup{
+				Destinations: "local,s3",
+				OutputFile:   "-",
+				Common:       Common{Namespace: testNamespace},
+			},
+			wantErr:     true,
+			expectedErr: "destinations are not allowed with output to stdout",
+		},
 	}
 
 	for _, tt := range tests {
@@ -336,6 +561,73 @@ func TestValidateBackup(t *testing.T) {
 	}
 }
 
+func TestBackup_IsMultiNamespace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		namespace string
+		want      bool
+	}{
+		{namespace: testNamespace, want: false},
+		{namespace: "ns1,ns2", want: true},
+		{namespace: NamespaceAll, want: true},
+		{namespace: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			t.Parallel()
+
+			b := &Backup{Common: Common{Namespace: tt.namespace}}
+			assert.Equal(t, tt.want, b.IsMultiNamespace())
+		})
+	}
+}
+
+func TestBackup_IsExport(t *testing.T) {
+	t.Parallel()
+
+	var nilBackup *Backup
+
+	assert.False(t, nilBackup.IsExport())
+	assert.False(t, (&Backup{}).IsExport())
+	assert.False(t, (&Backup{OutputFormat: OutputFormatASB}).IsExport())
+	assert.True(t, (&Backup{OutputFormat: OutputFormatNDJSON}).IsExport())
+	assert.True(t, (&Backup{OutputFormat: OutputFormatParquet}).IsExport())
+}
+
+func TestBackup_RequiredDestinations(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, (&Backup{}).RequiredDestinations())
+	assert.Equal(t, 3, (&Backup{Destinations: "local,s3,gcp"}).RequiredDestinations())
+	assert.Equal(t, 1, (&Backup{Destinations: "local,s3,gcp", MinDestinations: 1}).RequiredDestinations())
+}
+
+func TestBackup_HasFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		backup *Backup
+		want   bool
+	}{
+		{name: "No filters", backup: &Backup{Common: Common{SetList: "set1", BinList: "bin1"}}, want: false},
+		{name: "Partition list", backup: &Backup{PartitionList: "0-1000"}, want: true},
+		{name: "Modified after", backup: &Backup{ModifiedAfter: "2024-01-01_00:00:00"}, want: true},
+		{name: "Incremental from", backup: &Backup{IncrementalFrom: "/backups/full"}, want: true},
+		{name: "No TTL only", backup: &Backup{NoTTLOnly: true}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.backup.HasFilter())
+		})
+	}
+}
+
 func TestBackup_validateSingleFilter(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -40,6 +40,13 @@ type Common struct {
 	Bandwidth int64
 	// Buffer size for stdin/stdout operations.
 	StdBufferSize int
+	// ProgressInterval is the interval in seconds between progress reports, 0 disables them.
+	ProgressInterval int64
+
+	// Shell commands run before the run, after it succeeded and after it failed.
+	PreHook       string
+	PostHook      string
+	OnFailureHook string
 }
 
 func (c *Common) Validate() error {
@@ -67,5 +74,9 @@ func (c *Common) Validate() error {
 		return fmt.Errorf("std buffer size must be non-negative")
 	}
 
+	if c.ProgressInterval < 0 {
+		return fmt.Errorf("progress-interval must be non-negative")
+	}
+
 	return nil
 }
@@ -90,6 +90,15 @@ func TestValidateCommon(t *testing.T) {
 			wantErr:     true,
 			expectedErr: "namespace is required",
 		},
+		{
+			name: "Invalid negative progress interval",
+			common: &Common{
+				Namespace:        testNamespace,
+				ProgressInterval: -1,
+			},
+			wantErr:     true,
+			expectedErr: "progress-interval must be non-negative",
+		},
 		{
 			name: "Valid namespace with all timeouts",
 			common: &Common{
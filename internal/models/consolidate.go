@@ -0,0 +1,70 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"path"
+	"slices"
+)
+
+// Consolidate contains flags that will be mapped to the consolidate command.
+type Consolidate struct {
+	// DirectoryList is a comma-separated list of the backups to consolidate:
+	// the full backup first, then its incremental backups in order.
+	DirectoryList string
+	// ParentDirectory is prepended to the paths of DirectoryList.
+	ParentDirectory string
+	// Directory is the directory the consolidated backup is written to.
+	Directory string
+	// FileLimit is the size in MiB at which the files of the consolidated backup are rotated, 0 means no limit.
+	FileLimit uint64
+	// MaxDigests is the number of record digests of the incremental backups kept in memory
+	// to skip older versions of the records, 0 means no limit.
+	MaxDigests uint64
+}
+
+// Validate checks if the consolidate settings are valid.
+func (c *Consolidate) Validate() error {
+	if c == nil {
+		return errors.New("consolidate config is required")
+	}
+
+	if c.DirectoryList == "" {
+		return errors.New("directory-list is required")
+	}
+
+	if c.Directory == "" {
+		return errors.New("directory is required")
+	}
+
+	if slices.Contains(c.Directories(), c.Directory) {
+		return errors.New("directory must not be one of the consolidated backups")
+	}
+
+	return nil
+}
+
+// Directories returns the directories of the backups to consolidate, in order.
+func (c *Consolidate) Directories() []string {
+	dirs := SplitByComma(c.DirectoryList)
+	if c.ParentDirectory != "" {
+		for i := range dirs {
+			dirs[i] = path.Join(c.ParentDirectory, dirs[i])
+		}
+	}
+
+	return dirs
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsolidate_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		consolidate *Consolidate
+		errMsg      string
+	}{
+		{
+			name:        "valid",
+			consolidate: &Consolidate{DirectoryList: "full,inc1,inc2", Directory: "consolidated", FileLimit: 250},
+		},
+		{
+			name:   "nil config",
+			errMsg: "consolidate config is required",
+		},
+		{
+			name:        "no directory list",
+			consolidate: &Consolidate{Directory: "consolidated"},
+			errMsg:      "directory-list is required",
+		},
+		{
+			name:        "no directory",
+			consolidate: &Consolidate{DirectoryList: "full,inc1"},
+			errMsg:      "directory is required",
+		},
+		{
+			name:        "directory in list",
+			consolidate: &Consolidate{DirectoryList: "inc1,inc2", ParentDirectory: "/backups", Directory: "/backups/inc2"},
+			errMsg:      "directory must not be one of the consolidated backups",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.consolidate.Validate()
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestConsolidate_Directories(t *testing.T) {
+	t.Parallel()
+
+	c := &Consolidate{DirectoryList: "full,inc1"}
+	assert.Equal(t, []string{"full", "inc1"}, c.Directories())
+
+	c.ParentDirectory = "/backups"
+	assert.Equal(t, []string{"/backups/full", "/backups/inc1"}, c.Directories())
+}
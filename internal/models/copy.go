@@ -0,0 +1,138 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Storage kinds of a storage path, StorageSftp is a backup destination only.
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+	StorageGCP   = "gcp"
+	StorageAzure = "azure"
+	StorageSftp  = "sftp"
+)
+
+// storageSchemes maps the URL schemes of storage paths to storage kinds.
+var storageSchemes = map[string]string{
+	"s3":    StorageS3,
+	"gs":    StorageGCP,
+	"az":    StorageAzure,
+	"azure": StorageAzure,
+}
+
+// Copy contains flags that will be mapped to the copy command.
+type Copy struct {
+	// From and To are the storage paths of the backup and of its copy, parsed with ParseStoragePath.
+	From string
+	To   string
+	// Parallel is the number of files copied at once.
+	Parallel int
+	// Bandwidth limits the copy, in MiB/s, 0 means no limit.
+	Bandwidth int64
+}
+
+// Validate checks if the copy settings are valid.
+func (c *Copy) Validate() error {
+	if c == nil {
+		return errors.New("copy config is required")
+	}
+
+	if c.From == "" {
+		return errors.New("from is required")
+	}
+
+	if c.To == "" {
+		return errors.New("to is required")
+	}
+
+	from, err := ParseStoragePath(c.From)
+	if err != nil {
+		return fmt.Errorf("invalid from: %w", err)
+	}
+
+	to, err := ParseStoragePath(c.To)
+	if err != nil {
+		return fmt.Errorf("invalid to: %w", err)
+	}
+
+	if *from == *to {
+		return errors.New("from and to must differ")
+	}
+
+	if c.Parallel < 1 {
+		return errors.New("parallel must be positive")
+	}
+
+	if c.Bandwidth < 0 {
+		return errors.New("bandwidth must be non-negative")
+	}
+
+	return nil
+}
+
+// StoragePath is the location of a backup on one of the storages.
+type StoragePath struct {
+	// Kind is one of StorageLocal, StorageS3, StorageGCP and StorageAzure.
+	Kind string
+	// Bucket is the bucket of S3 and GCP, or the container of Azure. Empty for local paths.
+	Bucket string
+	// Path is the directory of the backup, in the bucket for cloud storages.
+	Path string
+}
+
+// ParseStoragePath parses s3://bucket/path, gs://bucket/path, az://container/path or a local path.
+func ParseStoragePath(s string) (*StoragePath, error) {
+	scheme, rest, found := strings.Cut(s, "://")
+	if !found {
+		return &StoragePath{Kind: StorageLocal, Path: filepath.Clean(s)}, nil
+	}
+
+	kind, ok := storageSchemes[strings.ToLower(scheme)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported storage scheme %s, supported are s3, gs and az", scheme)
+	}
+
+	bucket, p, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket is required in %s", s)
+	}
+
+	if p = strings.Trim(p, "/"); p != "" {
+		p = path.Clean(p)
+	}
+
+	return &StoragePath{Kind: kind, Bucket: bucket, Path: p}, nil
+}
+
+// String returns the storage path in the form it is parsed from.
+func (p *StoragePath) String() string {
+	switch p.Kind {
+	case StorageS3:
+		return "s3://" + path.Join(p.Bucket, p.Path)
+	case StorageGCP:
+		return "gs://" + path.Join(p.Bucket, p.Path)
+	case StorageAzure:
+		return "az://" + path.Join(p.Bucket, p.Path)
+	default:
+		return p.Path
+	}
+}
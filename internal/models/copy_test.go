@@ -0,0 +1,124 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		copy   *Copy
+		errMsg string
+	}{
+		{
+			name: "valid",
+			copy: &Copy{From: "/backups/full", To: "s3://bucket/backups/full", Parallel: 4, Bandwidth: 100},
+		},
+		{
+			name:   "nil config",
+			errMsg: "copy config is required",
+		},
+		{
+			name:   "no from",
+			copy:   &Copy{To: "s3://bucket/full", Parallel: 1},
+			errMsg: "from is required",
+		},
+		{
+			name:   "no to",
+			copy:   &Copy{From: "/backups/full", Parallel: 1},
+			errMsg: "to is required",
+		},
+		{
+			name:   "invalid from",
+			copy:   &Copy{From: "ftp://host/full", To: "/backups/full", Parallel: 1},
+			errMsg: "invalid from: unsupported storage scheme ftp, supported are s3, gs and az",
+		},
+		{
+			name:   "invalid to",
+			copy:   &Copy{From: "/backups/full", To: "gs:///full", Parallel: 1},
+			errMsg: "invalid to: bucket is required in gs:///full",
+		},
+		{
+			name:   "same paths",
+			copy:   &Copy{From: "s3://bucket/full/", To: "S3://bucket/full", Parallel: 1},
+			errMsg: "from and to must differ",
+		},
+		{
+			name:   "no parallel",
+			copy:   &Copy{From: "/backups/full", To: "/copies/full"},
+			errMsg: "parallel must be positive",
+		},
+		{
+			name:   "negative bandwidth",
+			copy:   &Copy{From: "/backups/full", To: "/copies/full", Parallel: 1, Bandwidth: -1},
+			errMsg: "bandwidth must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.copy.Validate()
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseStoragePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  *StoragePath
+	}{
+		{input: "/backups/full/", want: &StoragePath{Kind: StorageLocal, Path: "/backups/full"}},
+		{input: "s3://bucket/backups/full", want: &StoragePath{Kind: StorageS3, Bucket: "bucket", Path: "backups/full"}},
+		{input: "gs://bucket", want: &StoragePath{Kind: StorageGCP, Bucket: "bucket"}},
+		{input: "az://container/full", want: &StoragePath{Kind: StorageAzure, Bucket: "container", Path: "full"}},
+		{input: "azure://container/full", want: &StoragePath{Kind: StorageAzure, Bucket: "container", Path: "full"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseStoragePath(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStoragePath_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/backups/full", (&StoragePath{Kind: StorageLocal, Path: "/backups/full"}).String())
+	assert.Equal(t, "s3://bucket/full", (&StoragePath{Kind: StorageS3, Bucket: "bucket", Path: "full"}).String())
+	assert.Equal(t, "gs://bucket", (&StoragePath{Kind: StorageGCP, Bucket: "bucket"}).String())
+	assert.Equal(t, "az://container/full", (&StoragePath{Kind: StorageAzure, Bucket: "container", Path: "full"}).String())
+}
@@ -16,13 +16,16 @@ package models
 
 // App.
 const (
-	DefaultAppHelp           = false
-	DefaultAppVersion        = false
-	DefaultAppVerbose        = false
-	DefaultAppLogLevel       = "debug"
-	DefaultAppLogFile        = ""
-	DefaultAppLogJSON        = false
-	DefaultAppConfigFilePath = ""
+	DefaultAppHelp            = false
+	DefaultAppVersion         = false
+	DefaultAppVerbose         = false
+	DefaultAppLogLevel        = "debug"
+	DefaultAppLogFile         = ""
+	DefaultAppLogJSON         = false
+	DefaultAppMetricsListen   = ""
+	DefaultAppMetricsTextfile = ""
+	DefaultAppReportFile      = ""
+	DefaultAppConfigFilePath  = ""
 )
 
 // Aws S3 Storage.
@@ -78,6 +81,21 @@ const (
 	DefaultLocalBufferSize = 4
 )
 
+// SFTP Storage.
+const (
+	DefaultSftpHost           = ""
+	DefaultSftpPort           = 22
+	DefaultSftpUser           = ""
+	DefaultSftpPassword       = ""
+	DefaultSftpKeyFile        = ""
+	DefaultSftpKey            = ""
+	DefaultSftpKeyPassphrase  = ""
+	DefaultSftpKnownHosts     = ""
+	DefaultSftpPath           = ""
+	DefaultSftpConnectTimeout = int64(30000)
+	DefaultSftpBufferSize     = 4
+)
+
 // Cloud common.
 const (
 	DefaultCloudMaxConnsPerHost      = 0
@@ -139,14 +157,20 @@ const (
 	DefaultCommonInfoRetryInterval     = int64(1000)
 	DefaultCommonBandwidth             = int64(0)
 	DefaultCommonStdBufferSize         = 4
+	DefaultCommonProgressInterval      = int64(0)
+	DefaultCommonPreHook               = ""
+	DefaultCommonPostHook              = ""
+	DefaultCommonOnFailureHook         = ""
 )
 
 // Backup.
 const (
 	DefaultBackupOutputFile          = ""
+	DefaultBackupOutputFormat        = "asb"
 	DefaultBackupRemoveFiles         = false
 	DefaultBackupModifiedBefore      = ""
 	DefaultBackupModifiedAfter       = ""
+	DefaultBackupIncrementalFrom     = ""
 	DefaultBackupFileLimit           = uint64(250)
 	DefaultBackupAfterDigest         = ""
 	DefaultBackupMaxRecords          = int64(0)
@@ -161,14 +185,19 @@ const (
 	DefaultBackupPartitionList       = ""
 	DefaultBackupEstimate            = false
 	DefaultBackupEstimateSamples     = int64(10000)
+	DefaultBackupDryRun              = false
 	DefaultBackupStateFileDst        = ""
 	DefaultBackupContinue            = ""
+	DefaultBackupResumable           = false
+	DefaultBackupNoCheckpoint        = false
 	DefaultBackupScanPageSize        = int64(10000)
 	DefaultBackupOutputFilePrefix    = ""
 	DefaultBackupRackList            = ""
 	DefaultBackupTotalTimeout        = int64(0)
 	DefaultBackupParallel            = 1
 	DefaultBackupMaxRetries          = 5
+	DefaultBackupDestinations        = ""
+	DefaultBackupMinDestinations     = 0
 )
 
 // Restore.
@@ -193,6 +222,33 @@ const (
 
 	DefaultRestoreValidateOnly      = false
 	DefaultRestoreApplyMetadataLast = false
+	DefaultRestoreFilterExpression  = ""
+	DefaultRestoreSetMap            = ""
+	DefaultRestoreBinMap            = ""
+	DefaultRestoreDigestFile        = ""
+	DefaultRestoreKeyFile           = ""
+	DefaultRestoreRejectDir         = ""
+	DefaultRestoreStateFileDst      = ""
+	DefaultRestoreContinue          = ""
+	DefaultRestorePointInTime       = ""
+	DefaultRestoreBackupRoot        = ""
+
+	DefaultRestoreInputFormat = "asb"
+	DefaultRestoreKeyField    = ""
+	DefaultRestoreSetField    = ""
+	DefaultRestoreSetName     = ""
+	DefaultRestoreBinFields   = ""
+)
+
+// Consolidate.
+const (
+	DefaultConsolidateMaxDigests = uint64(50_000_000)
+)
+
+// Copy.
+const (
+	DefaultCopyParallel  = 4
+	DefaultCopyBandwidth = int64(0)
 )
 
 // Service connection.
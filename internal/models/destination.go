@@ -0,0 +1,59 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// DestinationStats contains the stats of one of the destinations a backup is written to at once.
+type DestinationStats struct {
+	// Name is the storage of the destination, one of StorageLocal, StorageS3, StorageGCP, StorageAzure or StorageSftp.
+	Name         string
+	FilesWritten uint64
+	BytesWritten uint64
+	// Err is the error the destination was dropped for, nil if it succeeded.
+	Err error
+}
+
+// SumDestinationStats adds up the stats of the same destinations, e.g. of several namespaces backups.
+// The first error of a destination is kept.
+func SumDestinationStats(a, b []DestinationStats) []DestinationStats {
+	result := make([]DestinationStats, len(a))
+	copy(result, a)
+
+	for _, stats := range b {
+		i := indexOfDestination(result, stats.Name)
+		if i < 0 {
+			result = append(result, stats)
+			continue
+		}
+
+		result[i].FilesWritten += stats.FilesWritten
+		result[i].BytesWritten += stats.BytesWritten
+
+		if result[i].Err == nil {
+			result[i].Err = stats.Err
+		}
+	}
+
+	return result
+}
+
+func indexOfDestination(stats []DestinationStats, name string) int {
+	for i := range stats {
+		if stats[i].Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
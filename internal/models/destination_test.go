@@ -0,0 +1,47 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSumDestinationStats(t *testing.T) {
+	t.Parallel()
+
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	a := []DestinationStats{
+		{Name: StorageLocal, FilesWritten: 2, BytesWritten: 100},
+		{Name: StorageS3, FilesWritten: 1, BytesWritten: 50, Err: errFirst},
+	}
+	b := []DestinationStats{
+		{Name: StorageLocal, FilesWritten: 3, BytesWritten: 200},
+		{Name: StorageS3, Err: errSecond},
+		{Name: StorageAzure, FilesWritten: 1, BytesWritten: 10},
+	}
+
+	assert.Equal(t, []DestinationStats{
+		{Name: StorageLocal, FilesWritten: 5, BytesWritten: 300},
+		{Name: StorageS3, FilesWritten: 1, BytesWritten: 50, Err: errFirst},
+		{Name: StorageAzure, FilesWritten: 1, BytesWritten: 10},
+	}, SumDestinationStats(a, b))
+	assert.Len(t, a, 2, "the stats must not be changed")
+	assert.Empty(t, SumDestinationStats(nil, nil))
+}
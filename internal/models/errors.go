@@ -22,4 +22,15 @@ const (
 
 var (
 	ErrNodeNotFound = errors.New(ErrNodeNotFoundText)
+	// ErrVerifyFailed is returned when a backup doesn't match its manifest.
+	ErrVerifyFailed = errors.New("backup verification failed")
+	// ErrVerifySignatureNotChecked is returned when a backup matches its manifest,
+	// but the signature of the manifest was not checked.
+	ErrVerifySignatureNotChecked = errors.New("backup verified, manifest signature not checked")
+	// ErrDryRunFailed is returned when a backup dry run finds problems in the plan.
+	ErrDryRunFailed = errors.New("backup dry run found problems")
+	// ErrInterrupted is the cause of the root context cancellation on SIGINT or SIGTERM.
+	ErrInterrupted = errors.New("interrupted by signal")
+	// ErrBackupInterrupted is returned when a backup was stopped by a signal and its state was saved.
+	ErrBackupInterrupted = errors.New("backup interrupted, state saved")
 )
@@ -0,0 +1,89 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// digestSize is the size of a record digest in bytes.
+const digestSize = 20
+
+// Inspect contains flags that will be mapped to the inspect command.
+type Inspect struct {
+	// Directory is the backup directory to inspect.
+	Directory string
+	// InputFile is a single backup file to inspect.
+	InputFile string
+	// Limit is the maximum number of records to print, 0 means no limit.
+	Limit int64
+	// SetList is a comma-separated list of sets to print records and secondary indexes of.
+	SetList string
+	// DigestList is a comma-separated list of Base64 encoded digests of records to print.
+	DigestList string
+}
+
+// Validate checks if the inspect settings are valid.
+func (i *Inspect) Validate() error {
+	if i == nil {
+		return errors.New("inspect config is required")
+	}
+
+	if i.Directory == "" && i.InputFile == "" {
+		return errors.New("must specify either directory or input-file")
+	}
+
+	if i.Directory != "" && i.InputFile != "" {
+		return errors.New("only one of directory and input-file may be configured at the same time")
+	}
+
+	if i.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+
+	if _, err := i.Digests(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Sets returns the list of sets to print.
+func (i *Inspect) Sets() []string {
+	return SplitByComma(i.SetList)
+}
+
+// Digests returns the decoded list of digests to print.
+func (i *Inspect) Digests() ([][]byte, error) {
+	list := SplitByComma(i.DigestList)
+	digests := make([][]byte, 0, len(list))
+
+	for _, d := range list {
+		digest, err := base64.StdEncoding.DecodeString(d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid digest %s: %w", d, err)
+		}
+
+		if len(digest) != digestSize {
+			return nil, fmt.Errorf("invalid digest %s: must be %d bytes, got %d", d, digestSize, len(digest))
+		}
+
+		digests = append(digests, digest)
+	}
+
+	return digests, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDigest = "EjRWeJq83vEjRRI0VniavN7xI0U="
+
+func TestInspect_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		inspect *Inspect
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid directory",
+			inspect: &Inspect{Directory: "/backup", Limit: 10, SetList: "set1", DigestList: testDigest},
+			wantErr: false,
+		},
+		{
+			name:    "valid input file",
+			inspect: &Inspect{InputFile: "/backup/test_0.asb"},
+			wantErr: false,
+		},
+		{
+			name:    "no directory or input file",
+			inspect: &Inspect{},
+			wantErr: true,
+			errMsg:  "must specify either directory or input-file",
+		},
+		{
+			name:    "directory and input file",
+			inspect: &Inspect{Directory: "/backup", InputFile: "/backup/test_0.asb"},
+			wantErr: true,
+			errMsg:  "only one of directory and input-file may be configured at the same time",
+		},
+		{
+			name:    "negative limit",
+			inspect: &Inspect{Directory: "/backup", Limit: -1},
+			wantErr: true,
+			errMsg:  "limit must not be negative",
+		},
+		{
+			name:    "invalid digest",
+			inspect: &Inspect{Directory: "/backup", DigestList: "not-base64!"},
+			wantErr: true,
+			errMsg:  "invalid digest not-base64!",
+		},
+		{
+			name:    "short digest",
+			inspect: &Inspect{Directory: "/backup", DigestList: "AQID"},
+			wantErr: true,
+			errMsg:  "must be 20 bytes, got 3",
+		},
+		{
+			name:    "nil config",
+			inspect: nil,
+			wantErr: true,
+			errMsg:  "inspect config is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.inspect.Validate()
+			if tt.wantErr {
+				require.ErrorContains(t, err, tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInspect_Digests(t *testing.T) {
+	inspect := &Inspect{DigestList: testDigest + "," + testDigest}
+
+	digests, err := inspect.Digests()
+	require.NoError(t, err)
+	require.Len(t, digests, 2)
+	assert.Len(t, digests[0], digestSize)
+
+	digests, err = (&Inspect{}).Digests()
+	require.NoError(t, err)
+	assert.Empty(t, digests)
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// ManifestFileName is the name of the manifest file saved in the backup directory.
+	ManifestFileName = "manifest.json"
+	// ManifestFormatVersion is the current version of the manifest format.
+	ManifestFormatVersion = 1
+	// manifestSignatureInfo derives the signature key from the encryption key, so the encryption
+	// key is not used by two primitives.
+	manifestSignatureInfo = "absctl manifest signature v1"
+)
+
+var (
+	// ErrManifestUnsigned is returned if the manifest of an encrypted backup has no signature.
+	ErrManifestUnsigned = errors.New("manifest is not signed")
+	// ErrManifestSignature is returned if the signature of the manifest doesn't match its content.
+	ErrManifestSignature = errors.New("manifest signature does not match")
+)
+
+// Manifest describes a completed backup: the files it produced, the stats
+// of the run and the configuration that was used.
+type Manifest struct {
+	FormatVersion int            `json:"format_version"`
+	AbsctlVersion string         `json:"absctl_version"`
+	Commit        string         `json:"commit"`
+	Created       time.Time      `json:"created"`
+	StartTime     time.Time      `json:"start_time"`
+	Duration      string         `json:"duration"`
+	Config        ManifestConfig `json:"config"`
+	Stats         ManifestStats  `json:"stats"`
+	Files         []ManifestFile `json:"files"`
+	// Incremental is set only for backups made with --incremental-from.
+	Incremental *ManifestIncremental `json:"incremental,omitempty"`
+	// Signature is the hex encoded HMAC-SHA256 of the manifest without the signature, keyed with
+	// a key derived by HKDF-SHA256 from the encryption key of the backup.
+	// Manifests of unencrypted backups are not signed.
+	Signature string `json:"signature,omitempty"`
+}
+
+// Sign sets the signature of the manifest made with the encryption key of the backup.
+func (m *Manifest) Sign(key []byte) error {
+	signature, err := m.signature(key)
+	if err != nil {
+		return err
+	}
+
+	m.Signature = signature
+
+	return nil
+}
+
+// CheckSignature checks that the manifest was signed with the encryption key of the backup,
+// so its checksums were not changed along with the files.
+func (m *Manifest) CheckSignature(key []byte) error {
+	if m.Signature == "" {
+		return ErrManifestUnsigned
+	}
+
+	expected, err := m.signature(key)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal([]byte(m.Signature), []byte(expected)) {
+		return ErrManifestSignature
+	}
+
+	return nil
+}
+
+// signature returns the HMAC of the JSON encoded manifest without its signature.
+func (m *Manifest) signature(key []byte) (string, error) {
+	unsigned := *m
+	unsigned.Signature = ""
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	signingKey, err := hkdf.Key(sha256.New, key, nil, manifestSignatureInfo, sha256.Size)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive signature key: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write(data)
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// IsFull checks if the backup holds all records, not only those modified after a time.
+func (m *Manifest) IsFull() bool {
+	return m.Incremental == nil && m.Config.ModifiedAfter == nil
+}
+
+// ModifiedSince returns the time since which the records of an incremental backup were modified,
+// zero for full backups.
+func (m *Manifest) ModifiedSince() time.Time {
+	switch {
+	case m.Incremental != nil:
+		return m.Incremental.ParentStartTime
+	case m.Config.ModifiedAfter != nil:
+		return *m.Config.ModifiedAfter
+	default:
+		return time.Time{}
+	}
+}
+
+// ModifiedUntil returns the time until which the records of the backup were modified.
+func (m *Manifest) ModifiedUntil() time.Time {
+	if m.Config.ModifiedBefore != nil && m.Config.ModifiedBefore.Before(m.StartTime) {
+		return *m.Config.ModifiedBefore
+	}
+
+	return m.StartTime
+}
+
+// Continues checks if the backup holds all records modified since the previous backup of its chain,
+// so that no modified record is missing from the chain.
+func (m *Manifest) Continues(prev *Manifest) bool {
+	return !m.ModifiedSince().After(prev.ModifiedUntil())
+}
+
+// ManifestIncremental links an incremental backup to the previous backups of its chain.
+type ManifestIncremental struct {
+	// Chain lists the paths of the previous backups, ordered from the full backup to the direct parent.
+	Chain []string `json:"chain"`
+	// ParentStartTime is the start time of the direct parent, used as modified-after.
+	ParentStartTime time.Time `json:"parent_start_time"`
+}
+
+// Base returns the path of the full backup that starts the chain.
+func (m *ManifestIncremental) Base() string {
+	if m == nil || len(m.Chain) == 0 {
+		return ""
+	}
+
+	return m.Chain[0]
+}
+
+// ManifestConfig is the resolved backup configuration saved to the manifest.
+type ManifestConfig struct {
+	Namespace        string                    `json:"namespace"`
+	SetList          []string                  `json:"set_list,omitempty"`
+	BinList          []string                  `json:"bin_list,omitempty"`
+	NodeList         []string                  `json:"node_list,omitempty"`
+	RackList         []int                     `json:"rack_list,omitempty"`
+	ModifiedAfter    *time.Time                `json:"modified_after,omitempty"`
+	ModifiedBefore   *time.Time                `json:"modified_before,omitempty"`
+	PartitionFilters []ManifestPartitionFilter `json:"partition_filters,omitempty"`
+	NoRecords        bool                      `json:"no_records"`
+	NoIndexes        bool                      `json:"no_indexes"`
+	NoUDFs           bool                      `json:"no_udfs"`
+	Compression      string                    `json:"compression"`
+	Encryption       string                    `json:"encryption"`
+}
+
+// ManifestPartitionFilter is a partition filter saved to the manifest.
+// Digest is Base64 encoded and set only for after-digest filters.
+type ManifestPartitionFilter struct {
+	Begin  int    `json:"begin"`
+	Count  int    `json:"count"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// ManifestStats contains the backup counters saved to the manifest.
+type ManifestStats struct {
+	RecordsRead  uint64 `json:"records_read"`
+	SIndexes     uint32 `json:"sindexes"`
+	UDFs         uint32 `json:"udfs"`
+	BytesWritten uint64 `json:"bytes_written"`
+	FilesWritten uint64 `json:"files_written"`
+}
+
+// ManifestFile describes a single file produced by the backup.
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest_Chain(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+	after := start.Add(time.Hour)
+
+	full := &Manifest{StartTime: start}
+	assert.True(t, full.IsFull())
+	assert.True(t, full.ModifiedSince().IsZero())
+	assert.Equal(t, start, full.ModifiedUntil())
+
+	limited := &Manifest{StartTime: start, Config: ManifestConfig{ModifiedBefore: &before}}
+	assert.True(t, limited.IsFull())
+	assert.Equal(t, before, limited.ModifiedUntil())
+
+	incremental := &Manifest{
+		StartTime:   start.Add(2 * time.Hour),
+		Incremental: &ManifestIncremental{Chain: []string{"full"}, ParentStartTime: start},
+	}
+	assert.False(t, incremental.IsFull())
+	assert.Equal(t, start, incremental.ModifiedSince())
+	assert.True(t, incremental.Continues(full))
+	assert.False(t, incremental.Continues(limited))
+
+	modifiedAfter := &Manifest{StartTime: start.Add(2 * time.Hour), Config: ManifestConfig{ModifiedAfter: &after}}
+	assert.False(t, modifiedAfter.IsFull())
+	assert.Equal(t, after, modifiedAfter.ModifiedSince())
+	assert.False(t, modifiedAfter.Continues(full))
+}
+
+func TestManifest_Signature(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("0123456789abcdef")
+
+	manifest := &Manifest{
+		FormatVersion: ManifestFormatVersion,
+		StartTime:     time.Date(2024, 1, 1, 0, 0, 0, 123, time.UTC),
+		Config:        ManifestConfig{Namespace: "test", Encryption: "AES128"},
+		Files:         []ManifestFile{{Name: "test_0.asb", Size: 4, SHA256: "abcd"}},
+	}
+
+	require.ErrorIs(t, manifest.CheckSignature(key), ErrManifestUnsigned)
+
+	require.NoError(t, manifest.Sign(key))
+	require.NotEmpty(t, manifest.Signature)
+
+	// The manifest is not signed with the encryption key itself.
+	unsigned := *manifest
+	unsigned.Signature = ""
+	content, err := json.Marshal(&unsigned)
+	require.NoError(t, err)
+
+	rawMAC := hmac.New(sha256.New, key)
+	rawMAC.Write(content)
+	assert.NotEqual(t, hex.EncodeToString(rawMAC.Sum(nil)), manifest.Signature)
+
+	// The signature holds after the manifest is saved and read back.
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	require.NoError(t, err)
+
+	var saved Manifest
+	require.NoError(t, json.Unmarshal(data, &saved))
+	require.NoError(t, saved.CheckSignature(key))
+
+	require.ErrorIs(t, saved.CheckSignature([]byte("fedcba9876543210")), ErrManifestSignature)
+
+	saved.Files[0].SHA256 = "dcba"
+	require.ErrorIs(t, saved.CheckSignature(key), ErrManifestSignature)
+}
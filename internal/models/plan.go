@@ -0,0 +1,71 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// BackupPlan describes what a backup would do, as resolved by --dry-run
+// without scanning or writing any data.
+type BackupPlan struct {
+	Storage    ReportStorage
+	Namespaces []NamespacePlan
+}
+
+// Passed returns true if no problems were found in the plan.
+func (p *BackupPlan) Passed() bool {
+	for i := range p.Namespaces {
+		if len(p.Namespaces[i].Problems) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NamespacePlan is the resolved backup of a single namespace.
+type NamespacePlan struct {
+	// Config contains the resolved sets, bins, partition filters, node and rack lists,
+	// time window, compression and encryption, the same as saved to the manifest.
+	Config ManifestConfig
+	// Nodes are the cluster nodes that records would be read from.
+	Nodes []PlanNode
+	// Incremental is set for backups made with --incremental-from.
+	Incremental *ManifestIncremental
+
+	Directory  string
+	OutputFile string
+	FilePrefix string
+	// FileLimit is the file size limit in bytes, 0 if files are not split.
+	FileLimit     uint64
+	ParallelRead  int
+	ParallelWrite int
+	StateFile     string
+
+	// RemoveFiles is true if existing files would be removed before the backup.
+	RemoveFiles bool
+	// RemoveArtifacts is true if existing files would be removed without running the backup.
+	RemoveArtifacts bool
+	// ExistingFiles are the files already present in the backup directory.
+	ExistingFiles []string
+
+	// Problems would make the backup fail or produce an unexpected result.
+	Problems []string
+}
+
+// PlanNode is a cluster node that records would be read from.
+type PlanNode struct {
+	Name    string
+	Address string
+	// Rack is the rack of the node for the backed up namespace, nil if it is unknown.
+	Rack *int
+}
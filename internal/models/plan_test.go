@@ -0,0 +1,57 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupPlan_Passed(t *testing.T) {
+	tests := []struct {
+		name string
+		plan BackupPlan
+		want bool
+	}{
+		{
+			name: "no namespaces",
+			plan: BackupPlan{},
+			want: true,
+		},
+		{
+			name: "no problems",
+			plan: BackupPlan{Namespaces: []NamespacePlan{
+				{Config: ManifestConfig{Namespace: "test"}},
+				{Config: ManifestConfig{Namespace: "bar"}},
+			}},
+			want: true,
+		},
+		{
+			name: "problem in one namespace",
+			plan: BackupPlan{Namespaces: []NamespacePlan{
+				{Config: ManifestConfig{Namespace: "test"}},
+				{Config: ManifestConfig{Namespace: "bar"}, Problems: []string{"namespace bar is not configured"}},
+			}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.plan.Passed())
+		})
+	}
+}
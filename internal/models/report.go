@@ -0,0 +1,123 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ReportFormatVersion is the current version of the run report format.
+// It is increased on every incompatible change of the report schema.
+const ReportFormatVersion = 1
+
+const (
+	// ReportStatusSuccess is set when the run finished without errors.
+	ReportStatusSuccess = "success"
+	// ReportStatusFailure is set when the run failed.
+	ReportStatusFailure = "failure"
+)
+
+// Storage types saved to the run report.
+const (
+	ReportStorageLocal = "local"
+	ReportStorageStd   = "std"
+	ReportStorageAwsS3 = "aws-s3"
+	ReportStorageGcp   = "gcp-storage"
+	ReportStorageAzure = "azure-blob"
+	ReportStorageSftp  = "sftp"
+)
+
+// RunReport is the machine-readable report of a backup or restore run, written with --report-file.
+type RunReport struct {
+	FormatVersion   int       `json:"format_version"`
+	Operation       string    `json:"operation"`
+	AbsctlVersion   string    `json:"absctl_version"`
+	Commit          string    `json:"commit"`
+	Status          string    `json:"status"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	Duration        string    `json:"duration"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	// Errors is the error chain of a failed run, from the outermost error to the root cause.
+	Errors  []string      `json:"errors,omitempty"`
+	Storage ReportStorage `json:"storage"`
+	// Destinations are the storages the backup was written to at once, if destinations are set.
+	Destinations []ReportDestination `json:"destinations,omitempty"`
+	// Config is the resolved configuration of the run with secrets redacted.
+	Config  json.RawMessage     `json:"config"`
+	Backup  *ReportBackupStats  `json:"backup,omitempty"`
+	Restore *ReportRestoreStats `json:"restore,omitempty"`
+}
+
+// ReportStorage describes where the backup files were written to or read from.
+type ReportStorage struct {
+	Type string `json:"type"`
+	// Bucket is the bucket or container name for cloud storages, or the host of the SFTP server.
+	Bucket string `json:"bucket,omitempty"`
+	// Path is the directory, file, or comma-separated directory list.
+	Path string `json:"path"`
+}
+
+// ReportDestination describes one of the storages a backup was written to at once.
+type ReportDestination struct {
+	ReportStorage
+	FilesWritten uint64 `json:"files_written"`
+	BytesWritten uint64 `json:"bytes_written"`
+	// Error is the error the destination was dropped for, empty if it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// ReportBackupStats contains the backup counters saved to the run report.
+type ReportBackupStats struct {
+	RecordsRead  uint64 `json:"records_read"`
+	SIndexes     uint32 `json:"sindexes"`
+	UDFs         uint32 `json:"udfs"`
+	BytesWritten uint64 `json:"bytes_written"`
+	FilesWritten uint64 `json:"files_written"`
+}
+
+// ReportRestoreStats contains the restore counters saved to the run report.
+type ReportRestoreStats struct {
+	RecordsRead     uint64 `json:"records_read"`
+	SIndexes        uint32 `json:"sindexes"`
+	UDFs            uint32 `json:"udfs"`
+	RecordsInserted uint64 `json:"records_inserted"`
+	RecordsSkipped  uint64 `json:"records_skipped"`
+	RecordsIgnored  uint64 `json:"records_ignored"`
+	RecordsFresher  uint64 `json:"records_fresher"`
+	RecordsExisted  uint64 `json:"records_existed"`
+	RecordsExpired  uint64 `json:"records_expired"`
+	RecordsFiltered uint64 `json:"records_filtered"`
+	ErrorsInDoubt   uint64 `json:"errors_in_doubt"`
+	BytesRead       uint64 `json:"bytes_read"`
+	// RecordsNotFound are the records of the digest and key files that were not found in the backup.
+	RecordsNotFound []string `json:"records_not_found,omitempty"`
+	// RecordsRejected is the number of records that failed to write and were saved to the reject directory.
+	RecordsRejected uint64 `json:"records_rejected,omitempty"`
+	// RejectLocation is where the rejected records were saved, if a reject directory is configured.
+	RejectLocation *ReportStorage `json:"reject_location,omitempty"`
+}
+
+// RestoreResults contains the restore results that are not part of the backup library stats.
+type RestoreResults struct {
+	// Filtered is the number of records that were not in the digest and key files
+	// or didn't match the filter expression.
+	Filtered uint64
+	// NotFound are the records of the digest and key files that were not found in the backup.
+	NotFound []string
+	// Rejected is the number of records saved to the reject directory.
+	Rejected uint64
+}
@@ -16,6 +16,8 @@ package models
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aerospike/aerospike-client-go/v8"
@@ -29,6 +31,31 @@ const (
 	RestoreModeASBX = "asbx"
 )
 
+// Restore input formats.
+const (
+	InputFormatASB    = "asb"
+	InputFormatNDJSON = "ndjson"
+	InputFormatCSV    = "csv"
+)
+
+// Types the fields of imported rows are converted to.
+const (
+	// ImportTypeAuto keeps the JSON type of NDJSON values, CSV values are imported as strings.
+	ImportTypeAuto   = ""
+	ImportTypeString = "string"
+	ImportTypeInt    = "int"
+	ImportTypeFloat  = "float"
+	ImportTypeBool   = "bool"
+	// ImportTypeBlob is a Base64 encoded blob.
+	ImportTypeBlob = "blob"
+	// ImportTypeJSON is a JSON encoded string, imported as a list or map.
+	ImportTypeJSON = "json"
+)
+
+var importTypes = []string{
+	ImportTypeString, ImportTypeInt, ImportTypeFloat, ImportTypeBool, ImportTypeBlob, ImportTypeJSON,
+}
+
 // Restore contains flags that will be mapped to restore config.
 type Restore struct {
 	Common
@@ -56,6 +83,82 @@ type Restore struct {
 
 	ValidateOnly      bool
 	ApplyMetadataLast bool
+	// Base64 encoded expression, records that don't match it are not restored.
+	FilterExpression string
+	// Renaming of sets and bins, in the old:new,... format.
+	SetMap string
+	BinMap string
+	// Files of digests and keys, only the listed records are restored.
+	DigestFile string
+	KeyFile    string
+	// Directory where records that fail to write are saved, on the storage of the backup.
+	RejectDir string
+	// State files of resumable restores, on the storage of the backup. Continue resumes the restore of its state file.
+	StateFileDst string
+	Continue     string
+	// Point-in-time restore of the full and incremental backups found under BackupRoot.
+	BackupRoot  string
+	PointInTime string
+
+	// Import of NDJSON and CSV files.
+	InputFormat string
+	KeyField    string
+	SetField    string
+	SetName     string
+	BinFields   string
+}
+
+// ImportField is a field of the imported rows, with the type its values are converted to.
+type ImportField struct {
+	Name string
+	Type string
+}
+
+// ParseImportField parses a field in the <name>[:<type>] format.
+func ParseImportField(s string) (ImportField, error) {
+	name, typ, found := strings.Cut(s, ":")
+	if found && !slices.Contains(importTypes, typ) {
+		return ImportField{}, fmt.Errorf("invalid type %q of field %s, must be one of %s",
+			typ, name, strings.Join(importTypes, ", "))
+	}
+
+	if name == "" {
+		return ImportField{}, fmt.Errorf("empty field name in %q", s)
+	}
+
+	return ImportField{Name: name, Type: typ}, nil
+}
+
+// IsImport checks if NDJSON or CSV files are imported instead of restoring a backup.
+func (r *Restore) IsImport() bool {
+	return r != nil && r.InputFormat != "" && r.InputFormat != InputFormatASB
+}
+
+// ImportKey parses the key field of imported rows.
+func (r *Restore) ImportKey() (ImportField, error) {
+	return ParseImportField(r.KeyField)
+}
+
+// ImportBins parses the bin fields of imported rows.
+// Returns nil if empty, then all fields except the key and set fields are imported.
+func (r *Restore) ImportBins() ([]ImportField, error) {
+	names := SplitByComma(r.BinFields)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	fields := make([]ImportField, 0, len(names))
+
+	for _, name := range names {
+		field, err := ParseImportField(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bin-fields: %w", err)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
 }
 
 func (r *Restore) IsDirectoryRestore() bool {
@@ -76,7 +179,8 @@ func (r *Restore) Validate() error {
 
 	if r.InputFile == "" &&
 		r.Directory == "" &&
-		r.DirectoryList == "" {
+		r.DirectoryList == "" &&
+		r.BackupRoot == "" {
 		return fmt.Errorf("input file or directory required")
 	}
 
@@ -107,9 +211,238 @@ func (r *Restore) Validate() error {
 		return fmt.Errorf("replace and unique are mutually exclusive")
 	}
 
+	if err := r.validateFilter(); err != nil {
+		return err
+	}
+
+	if err := r.validateRenames(); err != nil {
+		return err
+	}
+
+	if err := r.validateRejectDir(); err != nil {
+		return err
+	}
+
+	if err := r.validateStateFile(); err != nil {
+		return err
+	}
+
+	if err := r.validatePointInTime(); err != nil {
+		return err
+	}
+
+	return r.validateImport()
+}
+
+// IsResumable checks if the progress of the restore is saved to a state file.
+func (r *Restore) IsResumable() bool {
+	return r.StateFileDst != "" || r.Continue != ""
+}
+
+// StateFile returns the path of the state file of a resumable restore.
+func (r *Restore) StateFile() string {
+	if r.Continue != "" {
+		return r.Continue
+	}
+
+	return r.StateFileDst
+}
+
+// validateStateFile checks the state file options, the restored files must be identified by their paths.
+func (r *Restore) validateStateFile() error {
+	if !r.IsResumable() {
+		return nil
+	}
+
+	switch {
+	case r.Continue != "" && r.StateFileDst != "":
+		return fmt.Errorf("continue and state-file-dst are mutually exclusive")
+	case r.ValidateOnly:
+		return fmt.Errorf("state-file-dst and continue are not allowed with validate")
+	case r.InputFile == "-":
+		return fmt.Errorf("state-file-dst and continue are not allowed with stdin")
+	case r.IsImport():
+		// Imported files are read in nested directories, the tracked paths only include the restored directory.
+		return fmt.Errorf("state-file-dst and continue are not allowed with input-format %s", r.InputFormat)
+	}
+
 	return nil
 }
 
+// IsPointInTime checks if the restore is a point-in-time restore from a backup root.
+func (r *Restore) IsPointInTime() bool {
+	return r != nil && r.BackupRoot != ""
+}
+
+// PointInTimeTime maps the PointInTime string into a UTC time.
+func (r *Restore) PointInTimeTime() (time.Time, error) {
+	return ParseLocalTimeToUTC(r.PointInTime)
+}
+
+// validatePointInTime checks the point-in-time options, the backups of the root are restored one by one.
+func (r *Restore) validatePointInTime() error {
+	if r.BackupRoot == "" && r.PointInTime == "" {
+		return nil
+	}
+
+	switch {
+	case r.BackupRoot == "":
+		return fmt.Errorf("point-in-time requires backup-root")
+	case r.PointInTime == "":
+		return fmt.Errorf("backup-root requires point-in-time")
+	case r.Directory != "" || r.InputFile != "" || r.DirectoryList != "":
+		return fmt.Errorf("backup-root is not allowed with directory, input-file and directory-list")
+	case r.Mode == RestoreModeASBX:
+		return fmt.Errorf("backup-root is not allowed with mode %s", RestoreModeASBX)
+	case r.IsImport():
+		return fmt.Errorf("backup-root is not allowed with input-format %s", r.InputFormat)
+	case r.IsResumable():
+		return fmt.Errorf("backup-root is not allowed with state-file-dst and continue")
+	}
+
+	if _, err := r.PointInTimeTime(); err != nil {
+		return fmt.Errorf("invalid point-in-time: %w", err)
+	}
+
+	return nil
+}
+
+// validateFilter checks the filter expression.
+func (r *Restore) validateFilter() error {
+	if r.FilterExpression == "" {
+		return nil
+	}
+
+	if _, err := aerospike.ExpFromBase64(r.FilterExpression); err != nil {
+		return fmt.Errorf("invalid filter-exp: %w", err)
+	}
+
+	return nil
+}
+
+// validateRejectDir checks the reject directory, records of asb files are rejected when they fail to write.
+func (r *Restore) validateRejectDir() error {
+	if r.RejectDir == "" {
+		return nil
+	}
+
+	if r.ValidateOnly {
+		return fmt.Errorf("reject-dir is not allowed with validate")
+	}
+
+	if r.Mode == RestoreModeASBX {
+		return fmt.Errorf("reject-dir is not allowed with mode %s", RestoreModeASBX)
+	}
+
+	return nil
+}
+
+// validateImport checks the input format and the mapping of imported rows to records.
+func (r *Restore) validateImport() error {
+	switch r.InputFormat {
+	case "", InputFormatASB:
+		return nil
+	case InputFormatNDJSON, InputFormatCSV:
+	default:
+		return fmt.Errorf("invalid input-format %q, must be one of %s, %s or %s",
+			r.InputFormat, InputFormatASB, InputFormatNDJSON, InputFormatCSV)
+	}
+
+	switch {
+	case r.KeyField == "":
+		return fmt.Errorf("input-format %s requires key-field", r.InputFormat)
+	case r.SetField != "" && r.SetName != "":
+		return fmt.Errorf("set-field and set-name are mutually exclusive")
+	case r.Mode == RestoreModeASBX:
+		return fmt.Errorf("input-format %s is not allowed with mode %s", r.InputFormat, RestoreModeASBX)
+	case r.InputFile == "-":
+		return fmt.Errorf("input-format %s is not allowed with stdin", r.InputFormat)
+	}
+
+	key, err := r.ImportKey()
+	if err != nil {
+		return fmt.Errorf("invalid key-field: %w", err)
+	}
+
+	if key.Type != ImportTypeAuto && key.Type != ImportTypeString && key.Type != ImportTypeInt &&
+		key.Type != ImportTypeBlob {
+		return fmt.Errorf("invalid key-field type %s, must be string, int or blob", key.Type)
+	}
+
+	if _, err = r.ImportBins(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateRenames checks the set and bin maps.
+func (r *Restore) validateRenames() error {
+	if r.SetMap == "" && r.BinMap == "" {
+		return nil
+	}
+
+	if _, err := ParseRenames(r.SetMap); err != nil {
+		return fmt.Errorf("invalid set-map: %w", err)
+	}
+
+	if _, err := ParseRenames(r.BinMap); err != nil {
+		return fmt.Errorf("invalid bin-map: %w", err)
+	}
+
+	return nil
+}
+
+// ParseRenames parses a comma-separated list of renames in the old:new format into a map of old to new names.
+// Returns nil if empty.
+func ParseRenames(s string) (map[string]string, error) {
+	pairs := SplitByComma(s)
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	renames := make(map[string]string, len(pairs))
+	targets := make(map[string]string, len(pairs))
+
+	for _, pair := range pairs {
+		from, to, found := strings.Cut(pair, ":")
+
+		switch {
+		case !found || from == "" || to == "":
+			return nil, fmt.Errorf("invalid rename %q, must be in the old:new format", pair)
+		case renames[from] != "":
+			return nil, fmt.Errorf("%s is renamed more than once", from)
+		case targets[to] != "":
+			return nil, fmt.Errorf("%s and %s are both renamed to %s", targets[to], from, to)
+		}
+
+		renames[from] = to
+		targets[to] = from
+	}
+
+	return renames, nil
+}
+
+// SetRenames returns the map of old to new set names. Returns nil if empty or invalid.
+func (r *Restore) SetRenames() map[string]string {
+	renames, err := ParseRenames(r.SetMap)
+	if err != nil {
+		return nil
+	}
+
+	return renames
+}
+
+// BinRenames returns the map of old to new bin names. Returns nil if empty or invalid.
+func (r *Restore) BinRenames() map[string]string {
+	renames, err := ParseRenames(r.BinMap)
+	if err != nil {
+		return nil
+	}
+
+	return renames
+}
+
 // NamespaceConfig creates and returns a RestoreNamespaceConfig with source and destination namespaces
 // derived from input. Took value from r.Namespace. If one namespace is provided,
 // it sets both source and destination to the same value.
@@ -144,7 +477,8 @@ func (r *Restore) WritePolicy() *aerospike.WritePolicy {
 	p.RecordExistsAction = recordExistsAction(r.Replace, r.Uniq)
 	p.GenerationPolicy = aerospike.EXPECT_GEN_GT
 
-	if r.NoGeneration {
+	// Imported rows have no generation to check.
+	if r.NoGeneration || r.IsImport() {
 		p.GenerationPolicy = aerospike.NONE
 	}
 
@@ -125,6 +125,284 @@ func TestValidateRestore(t *testing.T) {
 			wantErr: true,
 			errMsg:  "replace and unique are mutually exclusive",
 		},
+		{
+			name: "Valid NDJSON import",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Directory: "import-dir", Namespace: "test"},
+				InputFormat: InputFormatNDJSON,
+				KeyField:    "id:int",
+				SetName:     "users",
+				BinFields:   "name,age:int,tags:json",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Import without key field",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Directory: "import-dir", Namespace: "test"},
+				InputFormat: InputFormatCSV,
+			},
+			wantErr: true,
+			errMsg:  "input-format csv requires key-field",
+		},
+		{
+			name: "Invalid input format",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Directory: "import-dir", Namespace: "test"},
+				InputFormat: "parquet",
+				KeyField:    "id",
+			},
+			wantErr: true,
+			errMsg:  `invalid input-format "parquet", must be one of asb, ndjson or csv`,
+		},
+		{
+			name: "Import with set field and set name",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Directory: "import-dir", Namespace: "test"},
+				InputFormat: InputFormatCSV,
+				KeyField:    "id",
+				SetField:    "type",
+				SetName:     "users",
+			},
+			wantErr: true,
+			errMsg:  "set-field and set-name are mutually exclusive",
+		},
+		{
+			name: "Import from stdin",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				InputFile:   "-",
+				Common:      Common{Namespace: "test"},
+				InputFormat: InputFormatNDJSON,
+				KeyField:    "id",
+			},
+			wantErr: true,
+			errMsg:  "input-format ndjson is not allowed with stdin",
+		},
+		{
+			name: "Import with float key",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Directory: "import-dir", Namespace: "test"},
+				InputFormat: InputFormatCSV,
+				KeyField:    "id:float",
+			},
+			wantErr: true,
+			errMsg:  "invalid key-field type float, must be string, int or blob",
+		},
+		{
+			name: "Import with invalid bin type",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Directory: "import-dir", Namespace: "test"},
+				InputFormat: InputFormatCSV,
+				KeyField:    "id",
+				BinFields:   "age:number",
+			},
+			wantErr: true,
+			errMsg: `invalid bin-fields: invalid type "number" of field age, ` +
+				"must be one of string, int, float, bool, blob, json",
+		},
+		{
+			name: "Valid filter expression",
+			restore: &Restore{
+				Mode:             RestoreModeASB,
+				Common:           Common{Directory: "restore-dir", Namespace: "test"},
+				FilterExpression: "kwGTUQKhYQE=",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid filter expression",
+			restore: &Restore{
+				Mode:             RestoreModeASB,
+				Common:           Common{Directory: "restore-dir", Namespace: "test"},
+				FilterExpression: "not base64!",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Valid set and bin maps",
+			restore: &Restore{
+				Mode:   RestoreModeASB,
+				Common: Common{Directory: "restore-dir", Namespace: "test"},
+				SetMap: "prod:qa",
+				BinMap: "a:b,b:c",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid set map",
+			restore: &Restore{
+				Mode:   RestoreModeASB,
+				Common: Common{Directory: "restore-dir", Namespace: "test"},
+				SetMap: "prod",
+			},
+			wantErr: true,
+			errMsg:  `invalid set-map: invalid rename "prod", must be in the old:new format`,
+		},
+		{
+			name: "Bin map with the same target",
+			restore: &Restore{
+				Mode:   RestoreModeASB,
+				Common: Common{Directory: "restore-dir", Namespace: "test"},
+				BinMap: "a:c,b:c",
+			},
+			wantErr: true,
+			errMsg:  "invalid bin-map: a and b are both renamed to c",
+		},
+		{
+			name: "Reject dir",
+			restore: &Restore{
+				Mode:      RestoreModeASB,
+				Common:    Common{Directory: "restore-dir", Namespace: "test"},
+				RejectDir: "rejects",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Reject dir with validate",
+			restore: &Restore{
+				Mode:         RestoreModeASB,
+				Common:       Common{Directory: "restore-dir", Namespace: "test"},
+				RejectDir:    "rejects",
+				ValidateOnly: true,
+			},
+			wantErr: true,
+			errMsg:  "reject-dir is not allowed with validate",
+		},
+		{
+			name: "Reject dir with asbx mode",
+			restore: &Restore{
+				Mode:      RestoreModeASBX,
+				Common:    Common{Directory: "restore-dir", Namespace: "test"},
+				RejectDir: "rejects",
+			},
+			wantErr: true,
+			errMsg:  "reject-dir is not allowed with mode asbx",
+		},
+		{
+			name: "State file",
+			restore: &Restore{
+				Mode:         RestoreModeAuto,
+				Common:       Common{Directory: "restore-dir", Namespace: "test"},
+				StateFileDst: "restore.state",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Continue and state file",
+			restore: &Restore{
+				Mode:         RestoreModeASB,
+				Common:       Common{Directory: "restore-dir", Namespace: "test"},
+				StateFileDst: "restore.state",
+				Continue:     "restore.state",
+			},
+			wantErr: true,
+			errMsg:  "continue and state-file-dst are mutually exclusive",
+		},
+		{
+			name: "Continue with validate",
+			restore: &Restore{
+				Mode:         RestoreModeASB,
+				Common:       Common{Directory: "restore-dir", Namespace: "test"},
+				Continue:     "restore.state",
+				ValidateOnly: true,
+			},
+			wantErr: true,
+			errMsg:  "state-file-dst and continue are not allowed with validate",
+		},
+		{
+			name: "State file with stdin",
+			restore: &Restore{
+				Mode:         RestoreModeASB,
+				Common:       Common{Namespace: "test"},
+				InputFile:    "-",
+				StateFileDst: "restore.state",
+			},
+			wantErr: true,
+			errMsg:  "state-file-dst and continue are not allowed with stdin",
+		},
+		{
+			name: "State file with import",
+			restore: &Restore{
+				Mode:         RestoreModeASB,
+				Common:       Common{Directory: "import-dir", Namespace: "test"},
+				InputFormat:  InputFormatCSV,
+				KeyField:     "id:int",
+				StateFileDst: "restore.state",
+			},
+			wantErr: true,
+			errMsg:  "state-file-dst and continue are not allowed with input-format csv",
+		},
+		{
+			name: "Point in time",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Namespace: "test"},
+				BackupRoot:  "backups",
+				PointInTime: "2024-01-02_15:04:05",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Point in time without backup root",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Directory: "restore-dir", Namespace: "test"},
+				PointInTime: "2024-01-02_15:04:05",
+			},
+			wantErr: true,
+			errMsg:  "point-in-time requires backup-root",
+		},
+		{
+			name: "Backup root without point in time",
+			restore: &Restore{
+				Mode:       RestoreModeASB,
+				Common:     Common{Namespace: "test"},
+				BackupRoot: "backups",
+			},
+			wantErr: true,
+			errMsg:  "backup-root requires point-in-time",
+		},
+		{
+			name: "Backup root with directory",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Directory: "restore-dir", Namespace: "test"},
+				BackupRoot:  "backups",
+				PointInTime: "2024-01-02_15:04:05",
+			},
+			wantErr: true,
+			errMsg:  "backup-root is not allowed with directory, input-file and directory-list",
+		},
+		{
+			name: "Backup root with state file",
+			restore: &Restore{
+				Mode:         RestoreModeASB,
+				Common:       Common{Namespace: "test"},
+				BackupRoot:   "backups",
+				PointInTime:  "2024-01-02_15:04:05",
+				StateFileDst: "restore.state",
+			},
+			wantErr: true,
+			errMsg:  "backup-root is not allowed with state-file-dst and continue",
+		},
+		{
+			name: "Invalid point in time",
+			restore: &Restore{
+				Mode:        RestoreModeASB,
+				Common:      Common{Namespace: "test"},
+				BackupRoot:  "backups",
+				PointInTime: "yesterday",
+			},
+			wantErr: true,
+			errMsg:  "invalid point-in-time: unknown time format: yesterday",
+		},
 	}
 
 	for _, tt := range tests {
@@ -233,6 +511,13 @@ func TestMapWritePolicy_ConfigurationCombinations(t *testing.T) {
 			wantAction:    aerospike.UPDATE,
 			wantGenPolicy: aerospike.EXPECT_GEN_GT,
 		},
+		{
+			name:          "import without generation",
+			restoreModel:  &Restore{InputFormat: InputFormatNDJSON},
+			commonModel:   &Common{},
+			wantAction:    aerospike.UPDATE,
+			wantGenPolicy: aerospike.NONE,
+		},
 	}
 
 	for _, tt := range tests {
@@ -246,3 +531,46 @@ func TestMapWritePolicy_ConfigurationCombinations(t *testing.T) {
 		})
 	}
 }
+
+func TestParseImportField(t *testing.T) {
+	t.Parallel()
+
+	field, err := ParseImportField("age:int")
+	require.NoError(t, err)
+	assert.Equal(t, ImportField{Name: "age", Type: ImportTypeInt}, field)
+
+	field, err = ParseImportField("name")
+	require.NoError(t, err)
+	assert.Equal(t, ImportField{Name: "name", Type: ImportTypeAuto}, field)
+
+	_, err = ParseImportField(":int")
+	require.ErrorContains(t, err, "empty field name")
+}
+
+func TestRestore_IsImport(t *testing.T) {
+	t.Parallel()
+
+	var nilRestore *Restore
+
+	assert.False(t, nilRestore.IsImport())
+	assert.False(t, (&Restore{InputFormat: InputFormatASB}).IsImport())
+	assert.True(t, (&Restore{InputFormat: InputFormatCSV}).IsImport())
+}
+
+func TestParseRenames(t *testing.T) {
+	t.Parallel()
+
+	renames, err := ParseRenames("a:b,b:c")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "b", "b": "c"}, renames)
+
+	renames, err = ParseRenames("")
+	require.NoError(t, err)
+	assert.Nil(t, renames)
+
+	_, err = ParseRenames("a:b,a:c")
+	require.EqualError(t, err, "a is renamed more than once")
+
+	_, err = ParseRenames("a:")
+	require.EqualError(t, err, `invalid rename "a:", must be in the old:new format`)
+}
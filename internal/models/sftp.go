@@ -0,0 +1,83 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"fmt"
+)
+
+// Sftp represents the configuration for SFTP storage integration.
+type Sftp struct {
+	Host string
+	Port int
+	User string
+
+	// Password auth.
+	Password string
+	// Private key auth, with the path of the key file or the PEM content of the key.
+	KeyFile       string
+	Key           string
+	KeyPassphrase string
+
+	// KnownHosts is the known_hosts file the host key of the server is verified with.
+	KnownHosts string
+	// Path is the remote directory that the directories and files of the backup are relative to.
+	Path string
+
+	ConnectTimeout int64
+	BufferSize     int
+}
+
+// Validate internal validation for struct params.
+func (s *Sftp) Validate(isBackup bool) error {
+	if s.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+
+	if s.Port < 1 || s.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+
+	if s.User == "" {
+		return fmt.Errorf("user is required")
+	}
+
+	if s.Password == "" && s.KeyFile == "" && s.Key == "" {
+		return fmt.Errorf("password, key file or key is required")
+	}
+
+	if s.KeyFile != "" && s.Key != "" {
+		return fmt.Errorf("only one of key file and key can be set")
+	}
+
+	if s.ConnectTimeout < 0 {
+		return fmt.Errorf("connect timeout must be non-negative")
+	}
+
+	if isBackup && s.BufferSize < 1 {
+		return fmt.Errorf("buffer size can't be less than 1")
+	}
+
+	return nil
+}
+
+func (s *Sftp) IsConfigured() bool {
+	if s == nil {
+		return false
+	}
+
+	return s.Host != "" || s.User != "" || s.Password != "" ||
+		s.KeyFile != "" || s.Key != "" || s.Path != ""
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Transcode contains flags that will be mapped to the transcode command.
+type Transcode struct {
+	// Directory is the directory of the backup to transcode.
+	Directory string
+	// OutputDirectory is the directory the transcoded backup is written to.
+	OutputDirectory string
+	// SourceCompression and SourceEncryption are the modes the backup was made with.
+	// The compression and encryption flags of the command are applied to the transcoded backup.
+	SourceCompression Compression
+	SourceEncryption  Encryption
+}
+
+// Validate checks if the transcode settings are valid.
+func (t *Transcode) Validate() error {
+	if t == nil {
+		return errors.New("transcode config is required")
+	}
+
+	if t.Directory == "" {
+		return errors.New("directory is required")
+	}
+
+	if t.OutputDirectory == "" {
+		return errors.New("output-directory is required")
+	}
+
+	if t.OutputDirectory == t.Directory {
+		return errors.New("output-directory must differ from directory")
+	}
+
+	if err := t.SourceCompression.Validate(); err != nil {
+		return fmt.Errorf("invalid source compression: %w", err)
+	}
+
+	if err := t.SourceEncryption.Validate(); err != nil {
+		return fmt.Errorf("invalid source encryption: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscode_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		transcode *Transcode
+		errMsg    string
+	}{
+		{
+			name: "valid",
+			transcode: &Transcode{
+				Directory:         "backup",
+				OutputDirectory:   "rotated",
+				SourceCompression: Compression{Mode: "zstd"},
+				SourceEncryption:  Encryption{Mode: "aes256", KeyFile: "old.pem"},
+			},
+		},
+		{
+			name:   "nil config",
+			errMsg: "transcode config is required",
+		},
+		{
+			name:      "no directory",
+			transcode: &Transcode{OutputDirectory: "rotated"},
+			errMsg:    "directory is required",
+		},
+		{
+			name:      "no output directory",
+			transcode: &Transcode{Directory: "backup"},
+			errMsg:    "output-directory is required",
+		},
+		{
+			name:      "same directories",
+			transcode: &Transcode{Directory: "backup", OutputDirectory: "backup"},
+			errMsg:    "output-directory must differ from directory",
+		},
+		{
+			name: "invalid source compression",
+			transcode: &Transcode{
+				Directory:         "backup",
+				OutputDirectory:   "rotated",
+				SourceCompression: Compression{Mode: "gzip"},
+			},
+			errMsg: "invalid source compression: invalid compression mode: gzip",
+		},
+		{
+			name: "invalid source encryption",
+			transcode: &Transcode{
+				Directory:        "backup",
+				OutputDirectory:  "rotated",
+				SourceEncryption: Encryption{Mode: "aes512"},
+			},
+			errMsg: "invalid source encryption: invalid encryption mode: aes512",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.transcode.Validate()
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "errors"
+
+// Verify contains flags that will be mapped to the verify command.
+type Verify struct {
+	// Directory is the backup directory to verify.
+	Directory string
+	// SkipDecode disables the decompression and decryption check of backup files.
+	SkipDecode bool
+}
+
+// Validate checks if the verify settings are valid.
+func (v *Verify) Validate() error {
+	if v == nil {
+		return errors.New("verify config is required")
+	}
+
+	if v.Directory == "" {
+		return errors.New("directory must be specified")
+	}
+
+	return nil
+}
+
+// VerifyReport contains the result of a backup verification.
+type VerifyReport struct {
+	// FilesChecked is the number of files hashed.
+	FilesChecked int
+	// BytesChecked is the total size of the hashed files.
+	BytesChecked int64
+	// Missing files are listed in the manifest but absent from the storage.
+	Missing []string
+	// Extra files are present in the storage but not listed in the manifest.
+	Extra []string
+	// Truncated files are shorter than recorded in the manifest.
+	Truncated []string
+	// Corrupted files have a size or checksum different from the manifest.
+	Corrupted []string
+	// SignatureChecked is true if the signature of the manifest was checked with the encryption key.
+	SignatureChecked bool
+	// SignatureSkipped is the reason the signature was not checked, empty if it was checked.
+	SignatureSkipped string
+	// SignatureError is set if the manifest is not signed or its signature doesn't match.
+	SignatureError string
+	// ConfigMismatch describes differences between the given compression or
+	// encryption settings and the ones recorded in the manifest.
+	ConfigMismatch []string
+	// DecodeChecked is true if the files were decrypted and decompressed.
+	DecodeChecked bool
+	// DecodeError is set if files could not be decrypted or decompressed.
+	DecodeError string
+	// RecordsRead is the number of records read during the decode check.
+	RecordsRead uint64
+}
+
+// Passed returns true if no problems were found.
+func (r *VerifyReport) Passed() bool {
+	return len(r.Missing) == 0 &&
+		len(r.Extra) == 0 &&
+		len(r.Truncated) == 0 &&
+		len(r.Corrupted) == 0 &&
+		len(r.ConfigMismatch) == 0 &&
+		r.SignatureError == "" &&
+		r.DecodeError == ""
+}
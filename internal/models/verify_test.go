@@ -0,0 +1,115 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		verify  *Verify
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid directory",
+			verify:  &Verify{Directory: "/backup"},
+			wantErr: false,
+		},
+		{
+			name:    "empty directory",
+			verify:  &Verify{},
+			wantErr: true,
+			errMsg:  "directory must be specified",
+		},
+		{
+			name:    "nil config",
+			verify:  nil,
+			wantErr: true,
+			errMsg:  "verify config is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.verify.Validate()
+			if tt.wantErr {
+				require.ErrorContains(t, err, tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyReport_Passed(t *testing.T) {
+	tests := []struct {
+		name   string
+		report VerifyReport
+		want   bool
+	}{
+		{
+			name:   "no problems",
+			report: VerifyReport{FilesChecked: 3, DecodeChecked: true},
+			want:   true,
+		},
+		{
+			name:   "missing file",
+			report: VerifyReport{Missing: []string{"a.asb"}},
+			want:   false,
+		},
+		{
+			name:   "extra file",
+			report: VerifyReport{Extra: []string{"a.asb"}},
+			want:   false,
+		},
+		{
+			name:   "truncated file",
+			report: VerifyReport{Truncated: []string{"a.asb"}},
+			want:   false,
+		},
+		{
+			name:   "corrupted file",
+			report: VerifyReport{Corrupted: []string{"a.asb"}},
+			want:   false,
+		},
+		{
+			name:   "config mismatch",
+			report: VerifyReport{ConfigMismatch: []string{"compression"}},
+			want:   false,
+		},
+		{
+			name:   "signature error",
+			report: VerifyReport{SignatureChecked: true, SignatureError: "manifest is not signed"},
+			want:   false,
+		},
+		{
+			name:   "decode error",
+			report: VerifyReport{DecodeError: "failed"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.report.Passed())
+		})
+	}
+}
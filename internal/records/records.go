@@ -0,0 +1,265 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package records
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	particleType "github.com/aerospike/aerospike-client-go/v8/types/particle_type"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// Line types.
+const (
+	LineTypeRecord = "record"
+	LineTypeSIndex = "sindex"
+	LineTypeUDF    = "udf"
+	LineTypeXDR    = "xdr"
+)
+
+// Value types.
+const (
+	ValueTypeNil     = "nil"
+	ValueTypeBool    = "bool"
+	ValueTypeInt     = "int"
+	ValueTypeFloat   = "float"
+	ValueTypeString  = "string"
+	ValueTypeBlob    = "blob"
+	ValueTypeList    = "list"
+	ValueTypeMap     = "map"
+	ValueTypeGeoJSON = "geojson"
+	ValueTypeHLL     = "hll"
+)
+
+// TTLNeverExpire is the TTL of records that never expire.
+const TTLNeverExpire = -1
+
+// Value is a typed key or bin value.
+// Blobs, HLLs and lists or maps that are not decoded are printed as Base64 strings.
+type Value struct {
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// MapEntry is a map entry, as map keys can be of any type.
+type MapEntry struct {
+	Key   Value `json:"key"`
+	Value Value `json:"value"`
+}
+
+// RecordLine is a record decoded from an .asb file.
+type RecordLine struct {
+	Type      string `json:"type"`
+	File      string `json:"file"`
+	Namespace string `json:"namespace"`
+	Set       string `json:"set,omitempty"`
+	Key       *Value `json:"key,omitempty"`
+	Digest    []byte `json:"digest"`
+	// Generation of the record.
+	Generation uint32 `json:"generation"`
+	// VoidTime is the expiration time in seconds since 2010-01-01 UTC, as saved in the backup, 0 if never expires.
+	VoidTime int64 `json:"void_time"`
+	// TTL is the time to live in seconds when the record is printed, -1 if never expires, 0 if expired.
+	TTL  int64            `json:"ttl"`
+	Bins map[string]Value `json:"bins"`
+}
+
+// SIndexLine is a secondary index definition decoded from an .asb file.
+type SIndexLine struct {
+	Type      string `json:"type"`
+	File      string `json:"file"`
+	Namespace string `json:"namespace"`
+	Set       string `json:"set,omitempty"`
+	Name      string `json:"name"`
+	IndexType string `json:"index_type"`
+	Bin       string `json:"bin"`
+	BinType   string `json:"bin_type"`
+	Context   string `json:"context,omitempty"`
+}
+
+// UDFLine is a UDF decoded from an .asb file.
+type UDFLine struct {
+	Type    string `json:"type"`
+	File    string `json:"file"`
+	Name    string `json:"name"`
+	UDFType string `json:"udf_type"`
+	Content string `json:"content"`
+}
+
+// XDRLine is a record decoded from an .asbx file.
+// The payload is the raw record message of the XDR protocol.
+type XDRLine struct {
+	Type      string `json:"type"`
+	File      string `json:"file"`
+	Namespace string `json:"namespace"`
+	Set       string `json:"set,omitempty"`
+	Key       *Value `json:"key,omitempty"`
+	Digest    []byte `json:"digest"`
+	Payload   []byte `json:"payload"`
+}
+
+// NewRecordLine converts a record decoded from an .asb file, with the TTL at the given time.
+func NewRecordLine(file string, record *bModels.Record, now time.Time) *RecordLine {
+	line := &RecordLine{
+		Type:       LineTypeRecord,
+		File:       file,
+		Namespace:  record.Key.Namespace(),
+		Set:        record.Key.SetName(),
+		Key:        newKeyValue(record.Key),
+		Digest:     record.Key.Digest(),
+		Generation: record.Generation,
+		VoidTime:   record.VoidTime,
+		TTL:        TTL(record.VoidTime, now),
+		Bins:       make(map[string]Value, len(record.Bins)),
+	}
+
+	for name, v := range record.Bins {
+		line.Bins[name] = NewValue(v)
+	}
+
+	return line
+}
+
+// NewSIndexLine converts a secondary index decoded from an .asb file.
+func NewSIndexLine(file string, sindex *bModels.SIndex) *SIndexLine {
+	return &SIndexLine{
+		Type:      LineTypeSIndex,
+		File:      file,
+		Namespace: sindex.Namespace,
+		Set:       sindex.Set,
+		Name:      sindex.Name,
+		IndexType: fmt.Sprintf("%c", sindex.IndexType),
+		Bin:       sindex.Path.BinName,
+		BinType:   fmt.Sprintf("%c", sindex.Path.BinType),
+		Context:   sindex.Path.B64Context,
+	}
+}
+
+// NewUDFLine converts a UDF decoded from an .asb file.
+func NewUDFLine(file string, udf *bModels.UDF) *UDFLine {
+	return &UDFLine{
+		Type:    LineTypeUDF,
+		File:    file,
+		Name:    udf.Name,
+		UDFType: fmt.Sprintf("%c", udf.UDFType),
+		Content: string(udf.Content),
+	}
+}
+
+// NewXDRLine converts a record decoded from an .asbx file.
+func NewXDRLine(file string, key *aerospike.Key, payload []byte) *XDRLine {
+	return &XDRLine{
+		Type:      LineTypeXDR,
+		File:      file,
+		Namespace: key.Namespace(),
+		Set:       key.SetName(),
+		Key:       newKeyValue(key),
+		Digest:    key.Digest(),
+		Payload:   payload,
+	}
+}
+
+// newKeyValue returns the user key, or nil if only the digest was saved.
+func newKeyValue(key *aerospike.Key) *Value {
+	if key.Value() == nil {
+		return nil
+	}
+
+	v := NewValue(key.Value())
+
+	return &v
+}
+
+// TTL converts the void time to the time to live in seconds at the given time.
+func TTL(voidTime int64, now time.Time) int64 {
+	if voidTime == 0 {
+		return TTLNeverExpire
+	}
+
+	return max(voidTime+types.CITRUSLEAF_EPOCH-now.Unix(), 0)
+}
+
+// NewValue converts a decoded key or bin value to a typed value.
+//
+//nolint:gocyclo // Long type switch.
+func NewValue(v any) Value {
+	switch v := v.(type) {
+	case nil:
+		return Value{Type: ValueTypeNil}
+	case bool:
+		return Value{Type: ValueTypeBool, Value: v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return Value{Type: ValueTypeInt, Value: v}
+	case float32, float64:
+		return Value{Type: ValueTypeFloat, Value: v}
+	case string:
+		return Value{Type: ValueTypeString, Value: v}
+	case []byte:
+		return Value{Type: ValueTypeBlob, Value: v}
+	case aerospike.BytesValue:
+		return Value{Type: ValueTypeBlob, Value: []byte(v)}
+	case aerospike.GeoJSONValue:
+		return Value{Type: ValueTypeGeoJSON, Value: string(v)}
+	case aerospike.HLLValue:
+		return Value{Type: ValueTypeHLL, Value: []byte(v)}
+	case *aerospike.RawBlobValue:
+		return newRawBlobValue(v)
+	case aerospike.ListValue:
+		return newListValue(v)
+	case []any:
+		return newListValue(v)
+	case aerospike.MapValue:
+		return newMapValue(v)
+	case map[any]any:
+		return newMapValue(v)
+	case aerospike.Value:
+		return NewValue(v.GetObject())
+	default:
+		return Value{Type: fmt.Sprintf("%T", v), Value: fmt.Sprint(v)}
+	}
+}
+
+// newRawBlobValue returns the type of lists and maps that are saved as MessagePack blobs.
+func newRawBlobValue(v *aerospike.RawBlobValue) Value {
+	switch v.ParticleType {
+	case particleType.LIST:
+		return Value{Type: ValueTypeList, Value: v.Data}
+	case particleType.MAP:
+		return Value{Type: ValueTypeMap, Value: v.Data}
+	default:
+		return Value{Type: ValueTypeBlob, Value: v.Data}
+	}
+}
+
+func newListValue(list []any) Value {
+	values := make([]Value, 0, len(list))
+	for _, v := range list {
+		values = append(values, NewValue(v))
+	}
+
+	return Value{Type: ValueTypeList, Value: values}
+}
+
+func newMapValue(m map[any]any) Value {
+	entries := make([]MapEntry, 0, len(m))
+	for k, v := range m {
+		entries = append(entries, MapEntry{Key: NewValue(k), Value: NewValue(v)})
+	}
+
+	return Value{Type: ValueTypeMap, Value: entries}
+}
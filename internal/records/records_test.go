@@ -0,0 +1,92 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package records
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	particleType "github.com/aerospike/aerospike-client-go/v8/types/particle_type"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   any
+		want Value
+	}{
+		{name: "nil", in: nil, want: Value{Type: ValueTypeNil}},
+		{name: "bool", in: true, want: Value{Type: ValueTypeBool, Value: true}},
+		{name: "int", in: int64(42), want: Value{Type: ValueTypeInt, Value: int64(42)}},
+		{name: "float", in: 1.5, want: Value{Type: ValueTypeFloat, Value: 1.5}},
+		{name: "string", in: "abc", want: Value{Type: ValueTypeString, Value: "abc"}},
+		{name: "blob", in: []byte{1, 2}, want: Value{Type: ValueTypeBlob, Value: []byte{1, 2}}},
+		{
+			name: "geojson",
+			in:   aerospike.GeoJSONValue(`{"type":"Point"}`),
+			want: Value{Type: ValueTypeGeoJSON, Value: `{"type":"Point"}`},
+		},
+		{name: "hll", in: aerospike.HLLValue{1}, want: Value{Type: ValueTypeHLL, Value: []byte{1}}},
+		{
+			name: "raw list",
+			in:   &aerospike.RawBlobValue{ParticleType: particleType.LIST, Data: []byte{0x90}},
+			want: Value{Type: ValueTypeList, Value: []byte{0x90}},
+		},
+		{
+			name: "raw map",
+			in:   &aerospike.RawBlobValue{ParticleType: particleType.MAP, Data: []byte{0x80}},
+			want: Value{Type: ValueTypeMap, Value: []byte{0x80}},
+		},
+		{
+			name: "list",
+			in:   []any{int64(1), "a"},
+			want: Value{Type: ValueTypeList, Value: []Value{
+				{Type: ValueTypeInt, Value: int64(1)},
+				{Type: ValueTypeString, Value: "a"},
+			}},
+		},
+		{
+			name: "map",
+			in:   map[any]any{"a": int64(1)},
+			want: Value{Type: ValueTypeMap, Value: []MapEntry{
+				{Key: Value{Type: ValueTypeString, Value: "a"}, Value: Value{Type: ValueTypeInt, Value: int64(1)}},
+			}},
+		},
+		{name: "client value", in: aerospike.NewStringValue("key"), want: Value{Type: ValueTypeString, Value: "key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, NewValue(tt.in))
+		})
+	}
+}
+
+func TestTTL(t *testing.T) {
+	t.Parallel()
+
+	// 2010-01-01 00:01:40 UTC.
+	now := time.Unix(1262304100, 0)
+
+	assert.Equal(t, int64(TTLNeverExpire), TTL(0, now))
+	assert.Equal(t, int64(50), TTL(150, now))
+	// Expired.
+	assert.Equal(t, int64(0), TTL(50, now))
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reject
+
+import (
+	"encoding/hex"
+	"errors"
+	"log/slog"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	"github.com/aerospike/backup-go"
+)
+
+// Client wraps backup.AerospikeClient and saves the records that fail to write to the reject Writer.
+// Only single record writes are intercepted, so batch writes must be disabled.
+type Client struct {
+	backup.AerospikeClient
+
+	rejects *Writer
+	logger  *slog.Logger
+}
+
+// NewClient returns a Client that writes records with c and saves the rejected ones to rejects.
+func NewClient(c backup.AerospikeClient, rejects *Writer, logger *slog.Logger) *Client {
+	return &Client{
+		AerospikeClient: c,
+		rejects:         rejects,
+		logger:          logger,
+	}
+}
+
+// Put writes the record and saves it to the reject directory if it failed with a record error.
+// The error is returned unchanged, so the restore counts or fails on it as without a reject directory.
+func (c *Client) Put(policy *aerospike.WritePolicy, key *aerospike.Key, bins aerospike.BinMap) aerospike.Error {
+	aErr := c.AerospikeClient.Put(policy, key, bins)
+	if aErr == nil {
+		return nil
+	}
+
+	code, ok := rejectedCode(aErr)
+	if !ok {
+		return aErr
+	}
+
+	if err := c.rejects.Reject(policy, key, bins, code, aErr); err != nil {
+		// The error is returned by Writer.Close, so the restore fails when it ends.
+		c.logger.Error("failed to save rejected record",
+			slog.String("digest", hex.EncodeToString(key.Digest())),
+			slog.Any("error", err),
+		)
+	}
+
+	return aErr
+}
+
+// rejectedCode returns the result code of a record that failed to write. Client errors, like network
+// errors, don't depend on the record. Timeouts and overloads are retried, existing and fresher records
+// are counted by the restore, so they are not rejected either.
+func rejectedCode(err error) (types.ResultCode, bool) {
+	var ae *aerospike.AerospikeError
+	if !errors.As(err, &ae) || ae.ResultCode <= 0 {
+		return 0, false
+	}
+
+	switch ae.ResultCode {
+	case types.TIMEOUT, types.DEVICE_OVERLOAD, types.KEY_EXISTS_ERROR, types.GENERATION_ERROR:
+		return 0, false
+	default:
+		return ae.ResultCode, true
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reject
+
+import (
+	"log/slog"
+	"testing"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	"github.com/aerospike/backup-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient is a backup.AerospikeClient that fails writes with an error.
+type testClient struct {
+	backup.AerospikeClient
+
+	err a.Error
+}
+
+func (c *testClient) Put(_ *a.WritePolicy, _ *a.Key, _ a.BinMap) a.Error {
+	return c.err
+}
+
+func TestClient_Put(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      a.Error
+		rejected bool
+	}{
+		{name: "Success"},
+		{name: "Record error", err: &a.AerospikeError{ResultCode: types.RECORD_TOO_BIG}, rejected: true},
+		{name: "Bin type error", err: &a.AerospikeError{ResultCode: types.BIN_TYPE_ERROR}, rejected: true},
+		{name: "Existing record", err: &a.AerospikeError{ResultCode: types.KEY_EXISTS_ERROR}},
+		{name: "Fresher record", err: &a.AerospikeError{ResultCode: types.GENERATION_ERROR}},
+		{name: "Timeout", err: &a.AerospikeError{ResultCode: types.TIMEOUT}},
+		{name: "Network error", err: a.ErrNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage := newTestStorage()
+			rejects := newTestWriter(t, storage)
+			client := NewClient(&testClient{err: tt.err}, rejects, slog.Default())
+
+			key, keyErr := a.NewKey("test", "users", 1)
+			require.NoError(t, keyErr)
+
+			// The error is returned unchanged, so the restore handles it as without rejects.
+			aErr := client.Put(a.NewWritePolicy(1, 0), key, a.BinMap{"age": 1})
+			assert.Equal(t, tt.err, aErr)
+
+			require.NoError(t, rejects.Close())
+
+			if tt.rejected {
+				assert.Equal(t, uint64(1), rejects.Count())
+				assert.Contains(t, storage.files, RecordsFile)
+			} else {
+				assert.Zero(t, rejects.Count())
+				assert.Empty(t, storage.files)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reject
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+const (
+	// RecordsFile holds the rejected records in the asb format, so they can be restored again.
+	RecordsFile = "rejected.asb"
+	// ErrorsFile holds the error of each rejected record, one JSON object per line.
+	ErrorsFile = "rejected.ndjson"
+)
+
+// rejection is a line of the errors file.
+type rejection struct {
+	Namespace string `json:"namespace"`
+	Set       string `json:"set,omitempty"`
+	// Digest is encoded in base64, as in asb files.
+	Digest  []byte           `json:"digest"`
+	Code    types.ResultCode `json:"code"`
+	Message string           `json:"error"`
+}
+
+// Writer saves records that failed to write to the reject directory. The files are created with the first
+// rejected record, so nothing is written if all records are restored.
+type Writer struct {
+	// ctx is used to create the files, on the first rejected record.
+	ctx    context.Context
+	writer backup.Writer
+	// now returns the current time, to convert the TTL of rejected records to a void time.
+	now func() time.Time
+
+	mu sync.Mutex
+	// encoder writes the rejected records like backup records, it is created with the files.
+	encoder *asb.Encoder[*bModels.Token]
+	buf     bytes.Buffer
+	records io.WriteCloser
+	errors  io.WriteCloser
+	count   uint64
+	// err is the first error, records are not saved after it and it is returned by Close.
+	err error
+}
+
+// NewWriter returns a Writer that saves the rejected records to w.
+func NewWriter(ctx context.Context, w backup.Writer) *Writer {
+	return &Writer{
+		ctx:    ctx,
+		writer: w,
+		now:    time.Now,
+	}
+}
+
+// Reject saves the record, written with the policy, and the error it failed with.
+func (w *Writer) Reject(policy *aerospike.WritePolicy, key *aerospike.Key, bins aerospike.BinMap,
+	code types.ResultCode, writeErr error,
+) error {
+	record := &bModels.Record{
+		Record: &aerospike.Record{
+			Key:        key,
+			Bins:       bins,
+			Generation: policy.Generation,
+		},
+		VoidTime: w.voidTime(policy.Expiration),
+	}
+
+	line, err := json.Marshal(rejection{
+		Namespace: key.Namespace(),
+		Set:       key.SetName(),
+		Digest:    key.Digest(),
+		Code:      code,
+		Message:   writeErr.Error(),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.records == nil {
+		w.err = w.open(key.Namespace())
+	}
+
+	if w.err == nil {
+		w.err = w.write(record)
+	}
+
+	if w.err == nil {
+		_, w.err = w.errors.Write(append(line, '\n'))
+	}
+
+	if w.err != nil {
+		w.err = fmt.Errorf("failed to save rejected record: %w", w.err)
+		return w.err
+	}
+
+	w.count++
+
+	return nil
+}
+
+// write encodes the record to the records file.
+func (w *Writer) write(record *bModels.Record) error {
+	w.buf.Reset()
+
+	if err := w.encoder.EncodeToken(bModels.NewRecordToken(record, 0, nil), &w.buf); err != nil {
+		return err
+	}
+
+	_, err := w.records.Write(w.buf.Bytes())
+
+	return err
+}
+
+// open creates the files of the reject directory.
+func (w *Writer) open(namespace string) error {
+	recordsFile, err := w.writer.NewWriter(w.ctx, RecordsFile)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", RecordsFile, err)
+	}
+
+	encoder := asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig(namespace, false, false))
+
+	if _, err = recordsFile.Write(encoder.GetHeader(0, true)); err != nil {
+		_ = recordsFile.Close()
+		return err
+	}
+
+	errorsFile, err := w.writer.NewWriter(w.ctx, ErrorsFile)
+	if err != nil {
+		_ = recordsFile.Close()
+		return fmt.Errorf("failed to create %s: %w", ErrorsFile, err)
+	}
+
+	w.encoder, w.records, w.errors = encoder, recordsFile, errorsFile
+
+	return nil
+}
+
+// voidTime converts the expiration of a write policy, relative to now, to a void time.
+func (w *Writer) voidTime(expiration uint32) int64 {
+	if expiration == aerospike.TTLDontExpire || expiration == aerospike.TTLServerDefault {
+		return 0
+	}
+
+	return w.now().Unix() - types.CITRUSLEAF_EPOCH + int64(expiration)
+}
+
+// Count returns the number of rejected records.
+func (w *Writer) Count() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.count
+}
+
+// Close closes the files, so they are uploaded to cloud storages. Returns the first error of the Writer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.records == nil {
+		return w.err
+	}
+
+	err := errors.Join(w.err, w.records.Close(), w.errors.Close())
+	w.records, w.errors = nil, nil
+
+	return err
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reject
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/aerospike-client-go/v8/types"
+	"github.com/aerospike/backup-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFile is a file written to memory.
+type testFile struct {
+	bytes.Buffer
+
+	closed bool
+}
+
+func (f *testFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+// testStorage is a backup.Writer that writes files to memory.
+type testStorage struct {
+	backup.Writer
+
+	mu    sync.Mutex
+	files map[string]*testFile
+	err   error
+}
+
+func newTestStorage() *testStorage {
+	return &testStorage{files: make(map[string]*testFile)}
+}
+
+func (s *testStorage) NewWriter(_ context.Context, filename string) (io.WriteCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	f := &testFile{}
+	s.files[filename] = f
+
+	return f, nil
+}
+
+func newTestWriter(t *testing.T, storage *testStorage) *Writer {
+	t.Helper()
+
+	w := NewWriter(t.Context(), storage)
+	w.now = func() time.Time { return time.Unix(types.CITRUSLEAF_EPOCH+1000, 0) }
+
+	return w
+}
+
+func TestWriter_Reject(t *testing.T) {
+	t.Parallel()
+
+	storage := newTestStorage()
+	w := newTestWriter(t, storage)
+
+	key, keyErr := a.NewKey("test", "users", 1)
+	require.NoError(t, keyErr)
+
+	policy := a.NewWritePolicy(2, 500)
+	writeErr := &a.AerospikeError{ResultCode: types.RECORD_TOO_BIG}
+
+	require.NoError(t, w.Reject(policy, key, a.BinMap{"age": 42}, types.RECORD_TOO_BIG, writeErr))
+	require.NoError(t, w.Reject(a.NewWritePolicy(1, a.TTLDontExpire), key, a.BinMap{"age": 7},
+		types.BIN_TYPE_ERROR, writeErr))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, uint64(2), w.Count())
+
+	recordsFile := storage.files[RecordsFile]
+	require.NotNil(t, recordsFile)
+	assert.True(t, recordsFile.closed)
+
+	keyLines := "+ k I 1\n+ n test\n+ d " + base64.StdEncoding.EncodeToString(key.Digest()) + "\n+ s users\n"
+
+	assert.Equal(t, "Version 3.1\n# namespace test\n# first-file\n"+
+		keyLines+"+ g 2\n+ t 1500\n+ b 1\n- I age 42\n"+
+		keyLines+"+ g 1\n+ t 0\n+ b 1\n- I age 7\n", recordsFile.String())
+
+	errorsFile := storage.files[ErrorsFile]
+	require.NotNil(t, errorsFile)
+	assert.True(t, errorsFile.closed)
+
+	lines := bytes.Split(bytes.TrimSpace(errorsFile.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"namespace":"test","set":"users","digest":"`+
+		base64.StdEncoding.EncodeToString(key.Digest())+`","code":13,"error":"`+writeErr.Error()+`"}`, string(lines[0]))
+}
+
+func TestWriter_NoRejects(t *testing.T) {
+	t.Parallel()
+
+	storage := newTestStorage()
+	w := newTestWriter(t, storage)
+
+	require.NoError(t, w.Close())
+	assert.Empty(t, storage.files)
+	assert.Zero(t, w.Count())
+}
+
+func TestWriter_StorageError(t *testing.T) {
+	t.Parallel()
+
+	storage := newTestStorage()
+	storage.err = errors.New("access denied")
+	w := newTestWriter(t, storage)
+
+	key, keyErr := a.NewKey("test", "users", 1)
+	require.NoError(t, keyErr)
+
+	writeErr := &a.AerospikeError{ResultCode: types.RECORD_TOO_BIG}
+
+	err := w.Reject(a.NewWritePolicy(1, 0), key, a.BinMap{"age": 42}, types.RECORD_TOO_BIG, writeErr)
+	require.ErrorContains(t, err, "failed to save rejected record: failed to create rejected.asb: access denied")
+
+	// The first error is kept, so the restore fails when it ends.
+	require.ErrorContains(t, w.Close(), "access denied")
+	assert.Zero(t, w.Count())
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+
+	"github.com/aerospike/absctl/internal/secrets"
+)
+
+// redactedValue replaces the values of secret fields in the report config.
+const redactedValue = "REDACTED"
+
+// secretFields are the config fields that contain credentials or keys.
+var secretFields = map[string]bool{
+	// Aerospike password, TLS private key and SFTP private key.
+	"Password": true,
+	"Key":      true,
+	"KeyPass":  true,
+	// Encryption key secret.
+	"KeySecret": true,
+	// Cloud storage credentials.
+	"SecretAccessKey": true,
+	"AccountKey":      true,
+	"ClientSecret":    true,
+	// SFTP private key passphrase.
+	"KeyPassphrase": true,
+}
+
+// redactConfig marshals the config to JSON, replacing non-empty secret fields and the values resolved
+// from the secret agent with redactedValue.
+func redactConfig(cfg any) (json.RawMessage, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(redact(value))
+}
+
+func redact(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if secretFields[key] && !isEmpty(field) {
+				v[key] = redactedValue
+				continue
+			}
+
+			v[key] = redact(field)
+		}
+	case []any:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	case string:
+		// Any field may hold a value from the secret agent, like the GCP key file with the JSON credentials.
+		if secrets.IsResolved(v) {
+			return redactedValue
+		}
+	}
+
+	return value
+}
+
+func isEmpty(value any) bool {
+	return value == nil || value == ""
+}
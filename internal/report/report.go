@@ -0,0 +1,247 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+const (
+	operationBackup  = "backup"
+	operationRestore = "restore"
+)
+
+// NewBackup builds the report of a backup run. stats may be nil if the backup didn't start.
+// destinations are the stats of each destination, if the backup is written to several storages.
+func NewBackup(
+	cfg *config.BackupServiceConfig,
+	stats *bModels.BackupStats,
+	destinations []models.DestinationStats,
+	runErr error,
+	start time.Time,
+	appVersion, commitHash string,
+) (*models.RunReport, error) {
+	report, err := newReport(operationBackup, cfg, runErr, start, appVersion, commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var path string
+
+	switch {
+	case cfg.Backup != nil && cfg.Backup.OutputFile != "":
+		path = cfg.Backup.OutputFile
+	case cfg.Backup != nil:
+		path = cfg.Backup.Directory
+	case cfg.BackupXDR != nil:
+		path = cfg.BackupXDR.Directory
+	}
+
+	report.Storage = NewStorage(cfg.PrimaryStorage(), path)
+
+	for _, d := range destinations {
+		destination := models.ReportDestination{
+			ReportStorage: NewStorage(cfg.WithStorage(d.Name), path),
+			FilesWritten:  d.FilesWritten,
+			BytesWritten:  d.BytesWritten,
+		}
+
+		if d.Err != nil {
+			destination.Error = d.Err.Error()
+		}
+
+		report.Destinations = append(report.Destinations, destination)
+	}
+
+	if stats != nil {
+		report.Backup = &models.ReportBackupStats{
+			RecordsRead:  stats.GetReadRecords(),
+			SIndexes:     stats.GetSIndexes(),
+			UDFs:         stats.GetUDFs(),
+			BytesWritten: stats.GetBytesWritten(),
+			FilesWritten: stats.GetFileCount(),
+		}
+	}
+
+	return report, nil
+}
+
+// NewRestore builds the report of a restore run. stats may be nil if the restore didn't start.
+// filtered is the number of records that were not restored because of filters, notFound are the requested
+// records that were not found in the backup.
+func NewRestore(
+	cfg *config.RestoreServiceConfig,
+	stats *bModels.RestoreStats,
+	results models.RestoreResults,
+	runErr error,
+	start time.Time,
+	appVersion, commitHash string,
+) (*models.RunReport, error) {
+	report, err := newReport(operationRestore, cfg, runErr, start, appVersion, commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var path string
+
+	if cfg.Restore != nil {
+		switch {
+		case cfg.Restore.InputFile != "":
+			path = cfg.Restore.InputFile
+		case cfg.Restore.DirectoryList != "":
+			path = cfg.Restore.DirectoryList
+		case cfg.Restore.BackupRoot != "":
+			path = cfg.Restore.BackupRoot
+		default:
+			path = cfg.Restore.Directory
+		}
+	}
+
+	report.Storage = NewStorage(&cfg.ServiceConfigCommon, path)
+
+	if stats != nil {
+		report.Restore = &models.ReportRestoreStats{
+			RecordsRead:     stats.GetReadRecords(),
+			SIndexes:        stats.GetSIndexes(),
+			UDFs:            stats.GetUDFs(),
+			RecordsInserted: stats.GetRecordsInserted(),
+			RecordsSkipped:  stats.GetRecordsSkipped(),
+			RecordsIgnored:  stats.GetRecordsIgnored(),
+			RecordsFresher:  stats.GetRecordsFresher(),
+			RecordsExisted:  stats.GetRecordsExisted(),
+			RecordsExpired:  stats.GetRecordsExpired(),
+			RecordsFiltered: results.Filtered,
+			RecordsNotFound: results.NotFound,
+			RecordsRejected: results.Rejected,
+			ErrorsInDoubt:   stats.GetErrorsInDoubt(),
+			BytesRead:       stats.GetTotalBytesRead(),
+		}
+
+		if cfg.Restore != nil && cfg.Restore.RejectDir != "" {
+			rejectLocation := NewStorage(&cfg.ServiceConfigCommon, cfg.Restore.RejectDir)
+			report.Restore.RejectLocation = &rejectLocation
+		}
+	}
+
+	return report, nil
+}
+
+func newReport(
+	operation string,
+	cfg any,
+	runErr error,
+	start time.Time,
+	appVersion, commitHash string,
+) (*models.RunReport, error) {
+	cfgJSON, err := redactConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	end := time.Now()
+
+	report := &models.RunReport{
+		FormatVersion:   models.ReportFormatVersion,
+		Operation:       operation,
+		AbsctlVersion:   appVersion,
+		Commit:          commitHash,
+		Status:          models.ReportStatusSuccess,
+		StartTime:       start.UTC(),
+		EndTime:         end.UTC(),
+		Duration:        end.Sub(start).String(),
+		DurationSeconds: end.Sub(start).Seconds(),
+		Config:          cfgJSON,
+	}
+
+	if runErr != nil {
+		report.Status = models.ReportStatusFailure
+		report.Errors = errorChain(runErr)
+	}
+
+	return report, nil
+}
+
+// NewStorage returns the storage the backup files are written to or read from.
+// Only one cloud or SFTP storage can be configured at the same time.
+func NewStorage(cfg *config.ServiceConfigCommon, path string) models.ReportStorage {
+	storage := models.ReportStorage{
+		Type: models.ReportStorageLocal,
+		Path: path,
+	}
+
+	switch {
+	case cfg.AwsS3.IsConfigured():
+		storage.Type = models.ReportStorageAwsS3
+		storage.Bucket = cfg.AwsS3.BucketName
+	case cfg.GcpStorage.IsConfigured():
+		storage.Type = models.ReportStorageGcp
+		storage.Bucket = cfg.GcpStorage.BucketName
+	case cfg.AzureBlob.IsConfigured():
+		storage.Type = models.ReportStorageAzure
+		storage.Bucket = cfg.AzureBlob.ContainerName
+	case cfg.Sftp.IsConfigured():
+		storage.Type = models.ReportStorageSftp
+		storage.Bucket = cfg.Sftp.Host
+	case path == config.StdPlaceholder:
+		storage.Type = models.ReportStorageStd
+	}
+
+	return storage
+}
+
+// errorChain returns the messages of the error and all errors it wraps, from the outermost to the root cause.
+func errorChain(err error) []string {
+	var chain []string
+
+	for err != nil {
+		chain = append(chain, err.Error())
+
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, joined := range e.Unwrap() {
+				chain = append(chain, errorChain(joined)...)
+			}
+
+			return chain
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			err = nil
+		}
+	}
+
+	return chain
+}
+
+// Write saves the report to the file as indented JSON.
+func Write(path string, report *models.RunReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write report file %s: %w", path, err)
+	}
+
+	return nil
+}
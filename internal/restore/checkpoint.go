@@ -0,0 +1,226 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aerospike/absctl/internal/checkpoint"
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/storage/common"
+)
+
+// checkpointInterval is how often the progress of a resumable restore is saved to its state file.
+const checkpointInterval = 10 * time.Second
+
+// newCheckpoints returns the tracker of a resumable restore, resumed from the state file with continue.
+// The state file is kept on the storage the backup is read from.
+func newCheckpoints(
+	ctx context.Context, cfg *config.RestoreServiceConfig, restoreConfig *backup.ConfigRestore, logger *slog.Logger,
+) (*checkpoint.Tracker, error) {
+	source := storageLocation(&cfg.ServiceConfigCommon, restorePath(cfg.Restore))
+
+	var previous *checkpoint.State
+
+	if cfg.Restore.Continue != "" {
+		state, err := readCheckpoint(ctx, cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load restore state: %w", err)
+		}
+
+		previous = state
+	}
+
+	writer, err := storage.NewRestoreStateWriter(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := checkpoint.NewTracker(writer, cfg.Restore.StateFile(), source, previous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load restore state: %w", err)
+	}
+
+	checkpoints.Drops = checkpoint.Drops{
+		NoRecords: restoreConfig.NoRecords,
+		Sets:      restoreConfig.SetList,
+		Bins:      restoreConfig.BinList,
+		ExtraTTL:  restoreConfig.ExtraTTL,
+	}
+
+	if cfg.Restore.Continue != "" {
+		files, records := checkpoints.Resumed()
+		logger.Info("resuming restore",
+			slog.String("state-file", checkpoints.Path()),
+			slog.Int("restored-files", files),
+			slog.Uint64("restored-records", records),
+		)
+	}
+
+	return checkpoints, nil
+}
+
+// readCheckpoint reads the state file a restore is resumed from.
+func readCheckpoint(
+	ctx context.Context, cfg *config.RestoreServiceConfig, logger *slog.Logger,
+) (*checkpoint.State, error) {
+	stateFile := cfg.Restore.StateFile()
+	directory := path.Dir(stateFile)
+
+	// Skip the file checks, as the state file is not a backup file.
+	reader, err := storage.NewReader(ctx, &cfg.ServiceConfigCommon, directory, "", "", "", 0, false, true, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader for %s: %w", directory, err)
+	}
+
+	file, err := storage.OpenFile(ctx, reader, stateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state file %s: %w", stateFile, err)
+	}
+	defer file.Reader.Close()
+
+	return checkpoint.ReadState(file.Reader, stateFile)
+}
+
+// restorePath returns the file, directory or comma-separated directory list that is restored.
+func restorePath(cfg *models.Restore) string {
+	switch {
+	case cfg.InputFile != "":
+		return cfg.InputFile
+	case cfg.DirectoryList != "":
+		return strings.Join(restoreDirs(cfg), ",")
+	default:
+		return cfg.Directory
+	}
+}
+
+// restoreDirs returns the directories of the directory list, in the parent directory.
+func restoreDirs(cfg *models.Restore) []string {
+	dirs := models.SplitByComma(cfg.DirectoryList)
+	for i := range dirs {
+		dirs[i] = path.Join(cfg.ParentDirectory, dirs[i])
+	}
+
+	return dirs
+}
+
+// newCheckpointReader wraps the reader of a resumable restore, so the files restored by earlier runs are skipped.
+// The directories of a directory list are read one by one, so the files are tracked by their paths.
+func newCheckpointReader(
+	ctx context.Context,
+	cfg *config.RestoreServiceConfig,
+	reader backup.StreamingReader,
+	isXDR bool,
+	checkpoints *checkpoint.Tracker,
+	logger *slog.Logger,
+) (*checkpoint.Reader, error) {
+	switch {
+	case cfg.Restore.InputFile != "":
+		dirs := []checkpoint.Dir{{Path: path.Dir(cfg.Restore.InputFile), Reader: reader}}
+		return checkpoint.NewReader(reader, dirs, checkpoints, logger), nil
+	case cfg.Restore.DirectoryList == "":
+		dirs := []checkpoint.Dir{{Path: cfg.Restore.Directory, Reader: reader}}
+		return checkpoint.NewReader(reader, dirs, checkpoints, logger), nil
+	}
+
+	var dirs []checkpoint.Dir
+
+	for _, dir := range restoreDirs(cfg.Restore) {
+		dirReader, err := storage.NewReader(ctx, &cfg.ServiceConfigCommon, dir, "", "", "",
+			cfg.Restore.StdBufferSize, isXDR, false, logger)
+
+		switch {
+		case errors.Is(err, common.ErrEmptyStorage):
+			// In auto mode a directory may only have files of the other reader.
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("failed to create reader for directory %s: %w", dir, err)
+		}
+
+		dirs = append(dirs, checkpoint.Dir{Path: dir, Reader: dirReader})
+	}
+
+	return checkpoint.NewReader(reader, dirs, checkpoints, logger), nil
+}
+
+// startCheckpoints saves the progress of a resumable restore periodically until the returned function is called.
+func (r *Service) startCheckpoints(ctx context.Context) func() {
+	if r.checkpoints == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(checkpointInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.checkpoints.Save(ctx); err != nil {
+					r.logger.Warn("failed to save restore state", slog.Any("error", err))
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// saveCheckpoint saves the progress of a resumable restore when it ends.
+// If the restore failed, it can be resumed from the state file.
+func (r *Service) saveCheckpoint(ctx context.Context, runErr error) error {
+	// The state is saved even if the restore was canceled.
+	if err := r.checkpoints.Save(context.WithoutCancel(ctx)); err != nil {
+		return fmt.Errorf("failed to save restore state: %w", err)
+	}
+
+	if runErr != nil {
+		r.logger.Info("restore state saved, the restore can be resumed with --continue",
+			slog.String("state-file", r.checkpoints.Path()))
+	}
+
+	return nil
+}
+
+// restored marks the files of a resumable restore as restored, once their restore succeeded.
+func (r *Service) restored(encoderType backup.EncoderType) {
+	reader := r.checkpointReader
+	if encoderType == backup.EncoderTypeASBX {
+		reader = r.checkpointReaderXdr
+	}
+
+	if reader != nil {
+		reader.Done()
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// chainBackup is a backup of a point-in-time chain, with the reader of its files.
+type chainBackup struct {
+	path   string
+	reader backup.StreamingReader
+}
+
+// chainReader streams the files of the backup of the chain that is being restored.
+// Backups are restored one by one, so that the records of later backups overwrite those of earlier ones.
+type chainReader struct {
+	// StreamingReader is the reader of the full backup, it serves everything but the streaming of files.
+	backup.StreamingReader
+
+	backups []chainBackup
+
+	mu      sync.Mutex
+	current backup.StreamingReader
+}
+
+// newChainReader finds the backups under the backup root and returns the reader of the backups
+// that restore the namespace as it was at the point in time.
+func newChainReader(
+	ctx context.Context,
+	cfg *config.RestoreServiceConfig,
+	logger *slog.Logger,
+) (*chainReader, error) {
+	at, err := cfg.Restore.PointInTimeTime()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse point-in-time: %w", err)
+	}
+
+	backups, err := storage.FindBackups(ctx, &cfg.ServiceConfigCommon, cfg.Restore.BackupRoot, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find backups under %s: %w", cfg.Restore.BackupRoot, err)
+	}
+
+	var namespace string
+	if ns := cfg.Restore.NamespaceConfig(); ns != nil {
+		namespace = *ns.Source
+	}
+
+	chain, err := selectChain(backups, namespace, at)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &chainReader{backups: make([]chainBackup, 0, len(chain))}
+
+	for i, b := range chain {
+		logger.Info("selected backup for point-in-time restore",
+			slog.Int("order", i+1),
+			slog.String("directory", b.Path),
+			slog.Time("start-time", b.Manifest.StartTime),
+			slog.Bool("incremental", !b.Manifest.IsFull()),
+		)
+
+		reader, err := storage.NewReader(ctx, &cfg.ServiceConfigCommon, b.Path, "", "", "",
+			cfg.Restore.StdBufferSize, false, false, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reader for backup %s: %w", b.Path, err)
+		}
+
+		r.backups = append(r.backups, chainBackup{path: b.Path, reader: reader})
+	}
+
+	r.StreamingReader = r.backups[0].reader
+	r.current = r.backups[0].reader
+
+	return r, nil
+}
+
+// use sets the backup whose files are streamed by the next restore.
+func (r *chainReader) use(b chainBackup) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.current = b.reader
+}
+
+// StreamFiles streams the files of the backup that is being restored.
+func (r *chainReader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) {
+	r.mu.Lock()
+	current := r.current
+	r.mu.Unlock()
+
+	current.StreamFiles(ctx, readersCh, errorsCh, skipPrefixes)
+}
+
+// selectChain returns the backups that restore the namespace as it was at the point in time, in restore order:
+// the latest full backup started before it, then the incremental backups started after the full backup.
+// Each backup must hold the records modified since the previous backup of the chain,
+// otherwise records would be missing and the chain is rejected. An empty namespace matches any namespace.
+func selectChain(backups []storage.Backup, namespace string, at time.Time) ([]storage.Backup, error) {
+	candidates := make([]storage.Backup, 0, len(backups))
+
+	for _, b := range backups {
+		m := b.Manifest
+		// Backups without records don't hold the state of the namespace.
+		if m.StartTime.IsZero() || m.StartTime.After(at) || m.Config.NoRecords ||
+			(namespace != "" && m.Config.Namespace != namespace) {
+			continue
+		}
+
+		if len(candidates) > 0 && m.Config.Namespace != candidates[0].Manifest.Config.Namespace {
+			return nil, fmt.Errorf("backups of namespaces %s and %s found, namespace is required",
+				candidates[0].Manifest.Config.Namespace, m.Config.Namespace)
+		}
+
+		candidates = append(candidates, b)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b storage.Backup) int {
+		if c := a.Manifest.StartTime.Compare(b.Manifest.StartTime); c != 0 {
+			return c
+		}
+
+		// Full backups are restored before the incremental backups started at the same time.
+		switch fullA, fullB := a.Manifest.IsFull(), b.Manifest.IsFull(); {
+		case fullA && !fullB:
+			return -1
+		case !fullA && fullB:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	full := -1
+
+	for i, b := range candidates {
+		if b.Manifest.IsFull() {
+			full = i
+		}
+	}
+
+	if full < 0 {
+		return nil, fmt.Errorf("no full backup started before %s found", at.Format(time.RFC3339))
+	}
+
+	chain := candidates[full:]
+
+	for i := 1; i < len(chain); i++ {
+		prev, next := chain[i-1], chain[i]
+
+		if !next.Manifest.Continues(prev.Manifest) {
+			return nil, fmt.Errorf("backup %s holds the records modified since %s, but backup %s ends at %s: "+
+				"the records modified in between are in no backup", next.Path,
+				next.Manifest.ModifiedSince().Format(time.RFC3339), prev.Path,
+				prev.Manifest.ModifiedUntil().Format(time.RFC3339))
+		}
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+var pitStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// pitDay returns the time of the given day after pitStart.
+func pitDay(day int) time.Time {
+	return pitStart.AddDate(0, 0, day)
+}
+
+func fullBackup(path, namespace string, day int) storage.Backup {
+	return storage.Backup{Path: path, Manifest: &models.Manifest{
+		StartTime: pitDay(day),
+		Config:    models.ManifestConfig{Namespace: namespace},
+	}}
+}
+
+func incrementalBackup(path, namespace string, day, parentDay int) storage.Backup {
+	return storage.Backup{Path: path, Manifest: &models.Manifest{
+		StartTime: pitDay(day),
+		Config:    models.ManifestConfig{Namespace: namespace},
+		Incremental: &models.ManifestIncremental{
+			Chain:           []string{"full"},
+			ParentStartTime: pitDay(parentDay),
+		},
+	}}
+}
+
+func TestSelectChain(t *testing.T) {
+	t.Parallel()
+
+	modifiedAfter := pitDay(3)
+	manual := storage.Backup{Path: "manual", Manifest: &models.Manifest{
+		StartTime: pitDay(4),
+		Config:    models.ManifestConfig{Namespace: testNamespace, ModifiedAfter: &modifiedAfter},
+	}}
+
+	noRecords := fullBackup("no-records", testNamespace, 3)
+	noRecords.Manifest.Config.NoRecords = true
+
+	backups := []storage.Backup{
+		incrementalBackup("inc-3", testNamespace, 3, 2),
+		fullBackup("full-0", testNamespace, 0),
+		incrementalBackup("inc-1", testNamespace, 1, 0),
+		fullBackup("full-2", testNamespace, 2),
+		fullBackup("other", "other", 3),
+		noRecords,
+		manual,
+		incrementalBackup("inc-5", testNamespace, 5, 4),
+	}
+
+	tests := []struct {
+		name      string
+		backups   []storage.Backup
+		namespace string
+		at        time.Time
+		want      []string
+		errMsg    string
+	}{
+		{
+			name:      "Full backup only",
+			backups:   backups,
+			namespace: testNamespace,
+			at:        pitDay(0),
+			want:      []string{"full-0"},
+		},
+		{
+			name:      "Incremental of first full backup",
+			backups:   backups,
+			namespace: testNamespace,
+			at:        pitDay(1).Add(time.Hour),
+			want:      []string{"full-0", "inc-1"},
+		},
+		{
+			name:      "Latest full backup",
+			backups:   backups,
+			namespace: testNamespace,
+			at:        pitDay(2),
+			want:      []string{"full-2"},
+		},
+		{
+			name:      "Incremental backups in order",
+			backups:   backups,
+			namespace: testNamespace,
+			at:        pitDay(10),
+			want:      []string{"full-2", "inc-3", "manual", "inc-5"},
+		},
+		{
+			name:    "Backups of several namespaces",
+			backups: backups,
+			at:      pitDay(10),
+			errMsg:  "backups of namespaces test and other found, namespace is required",
+		},
+		{
+			name:      "No full backup",
+			backups:   backups,
+			namespace: testNamespace,
+			at:        pitStart.Add(-time.Second),
+			errMsg:    "no full backup started before 2023-12-31T23:59:59Z found",
+		},
+		{
+			name: "Gap in chain",
+			backups: []storage.Backup{
+				fullBackup("full-0", testNamespace, 0),
+				incrementalBackup("inc-2", testNamespace, 2, 1),
+			},
+			namespace: testNamespace,
+			at:        pitDay(10),
+			errMsg: "backup inc-2 holds the records modified since 2024-01-02T00:00:00Z, " +
+				"but backup full-0 ends at 2024-01-01T00:00:00Z: the records modified in between are in no backup",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chain, err := selectChain(tt.backups, tt.namespace, tt.at)
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+
+			paths := make([]string, 0, len(chain))
+			for _, b := range chain {
+				paths = append(paths, b.Path)
+			}
+
+			require.Equal(t, tt.want, paths)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/aerospike/absctl/internal/logging"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// sizeReader is implemented by readers that calculate the total size of the backup files.
+type sizeReader interface {
+	GetSize() int64
+}
+
+// startProgress reports the restore progress until the returned function is called.
+// Stats are loaded on every report, as restores in auto mode are started concurrently.
+// The percent done is based on the total size of the input files, calculated by the readers.
+func (r *Service) startProgress(
+	ctx context.Context,
+	stats []*atomic.Pointer[bModels.RestoreStats],
+	readers ...backup.StreamingReader,
+) func() {
+	operation := "restore"
+	if r.config.ValidateOnly {
+		operation = "validation"
+	}
+
+	return logging.NewProgress(operation, r.progressInterval, func() (records, bytes, total uint64) {
+		for _, s := range stats {
+			if st := s.Load(); st != nil {
+				records += st.GetReadRecords()
+				bytes += st.GetTotalBytesRead()
+			}
+		}
+
+		for _, reader := range readers {
+			// The size is calculated in the background, so it is read on every report.
+			if sr, ok := reader.(sizeReader); ok && sr.GetSize() > 0 {
+				total += uint64(sr.GetSize())
+			}
+		}
+
+		return records, bytes, total
+	}, r.reportToLog, r.logger).Start(ctx)
+}
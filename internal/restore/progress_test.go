@@ -0,0 +1,82 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"bytes"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// testSizeReader reports a fixed total size of the backup files.
+type testSizeReader struct {
+	backup.StreamingReader
+
+	size int64
+}
+
+func (r *testSizeReader) GetSize() int64 {
+	return r.size
+}
+
+func Test_StartProgress(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+
+	s := &Service{
+		config:           &backup.ConfigRestore{ValidateOnly: true},
+		logger:           slog.New(slog.NewJSONHandler(buf, nil)),
+		reportToLog:      true,
+		progressInterval: 5 * time.Millisecond,
+	}
+
+	var stats, xdrStats atomic.Pointer[bModels.RestoreStats]
+	stats.Store(bModels.NewRestoreStats())
+
+	stop := s.startProgress(t.Context(), []*atomic.Pointer[bModels.RestoreStats]{&stats, &xdrStats},
+		&testSizeReader{size: 1024}, nil)
+	time.Sleep(30 * time.Millisecond)
+	stop()
+
+	out := buf.String()
+	assert.Contains(t, out, `"msg":"validation progress"`)
+	assert.Contains(t, out, `"records":0`)
+	assert.Contains(t, out, `"percent":0`)
+}
+
+func Test_StartProgress_Disabled(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+
+	s := &Service{
+		config:      &backup.ConfigRestore{},
+		logger:      slog.New(slog.NewJSONHandler(buf, nil)),
+		reportToLog: true,
+	}
+
+	stop := s.startProgress(t.Context(), nil, &testSizeReader{size: 1024})
+	time.Sleep(10 * time.Millisecond)
+	stop()
+
+	assert.Empty(t, buf.String())
+}
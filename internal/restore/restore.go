@@ -16,18 +16,33 @@ package restore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"path"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/aerospike/absctl/internal/aeskey"
+	"github.com/aerospike/absctl/internal/checkpoint"
+	"github.com/aerospike/absctl/internal/codec"
 	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/importer"
 	"github.com/aerospike/absctl/internal/logging"
+	"github.com/aerospike/absctl/internal/metrics"
 	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/reject"
+	"github.com/aerospike/absctl/internal/report"
 	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/absctl/internal/transform"
 	"github.com/aerospike/backup-go"
 	bModels "github.com/aerospike/backup-go/models"
 )
 
+// operationName is used in metric names.
+const operationName = "restore"
+
 // Service represents a type used to handle Aerospike data recovery operations with configurable restore settings.
 type Service struct {
 	backupClient *backup.Client
@@ -35,10 +50,31 @@ type Service struct {
 
 	reader    backup.StreamingReader
 	readerXdr backup.StreamingReader
+	// transform filters and renames records, nil if neither is configured.
+	transform *transform.Reader
+	// rejects saves the records that fail to write, nil if there is no reject directory.
+	rejects *reject.Writer
+	// rejectLocation is the reject directory, as printed in the restore report.
+	rejectLocation string
+	// checkpoints saves the progress of a resumable restore, nil if the restore is not resumable.
+	checkpoints *checkpoint.Tracker
+	// checkpointReader and checkpointReaderXdr are the readers of a resumable restore,
+	// their files are restored once their restore succeeds.
+	checkpointReader, checkpointReaderXdr *checkpoint.Reader
+	// chain streams the backups of a point-in-time restore, nil if the restore is not point-in-time.
+	chain *chainReader
 	// Restore Mode: auto, asb, asbx
 	mode string
 
 	reportToLog bool
+	// progressInterval is the interval between progress reports, 0 if progress is not reported.
+	progressInterval time.Duration
+	// metrics exports live stats, nil if metrics are not enabled.
+	metrics *metrics.Exporter
+	// stats of all started restores, summed up for the run report.
+	// Restores in auto mode are started concurrently, so access is guarded by statsMu.
+	statsMu sync.Mutex
+	stats   []*bModels.RestoreStats
 
 	logger *slog.Logger
 }
@@ -54,12 +90,20 @@ func NewService(
 		// Important! To describe variable as interface not exact *a.Client.
 		// So we can run backup files validation with the 'nil' aerospike client.
 		aerospikeClient backup.AerospikeClient
+		rejects         *reject.Writer
+		checkpoints     *checkpoint.Tracker
 		err             error
 	)
 
 	// Initializations.
 	restoreConfig := config.NewRestoreConfig(cfg, logger)
 
+	if cfg.Restore.IsResumable() {
+		if checkpoints, err = newCheckpoints(ctx, cfg, restoreConfig, logger); err != nil {
+			return nil, err
+		}
+	}
+
 	// Skip this part on validation.
 	if !restoreConfig.ValidateOnly {
 		warmUp := GetWarmUp(cfg.Restore.WarmUp, cfg.Restore.MaxAsyncBatches)
@@ -75,11 +119,87 @@ func NewService(
 		if err != nil {
 			return nil, fmt.Errorf("failed to create aerospike client: %w", err)
 		}
+
+		if cfg.Restore.RejectDir != "" {
+			rejectWriter, err := storage.NewRejectWriter(ctx, cfg, logger)
+			if err != nil {
+				return nil, err
+			}
+
+			rejects = reject.NewWriter(ctx, rejectWriter)
+			aerospikeClient = reject.NewClient(aerospikeClient, rejects, logger)
+		}
+
+		if checkpoints != nil {
+			aerospikeClient = checkpoint.NewClient(aerospikeClient, checkpoints, cfg.Restore.IgnoreRecordError)
+		}
+	}
+
+	var (
+		reader, xdrReader backup.StreamingReader
+		chain             *chainReader
+	)
+
+	if cfg.Restore.IsPointInTime() {
+		if chain, err = newChainReader(ctx, cfg, logger); err != nil {
+			return nil, err
+		}
+
+		reader = chain
+	} else {
+		reader, xdrReader, err = storage.NewRestoreReader(ctx, cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create restore reader: %w", err)
+		}
+	}
+
+	// Files restored by earlier runs are skipped before they are read.
+	var checkpointReader, checkpointReaderXdr *checkpoint.Reader
+
+	if checkpoints != nil {
+		if reader != nil {
+			if checkpointReader, err = newCheckpointReader(ctx, cfg, reader, false, checkpoints, logger); err != nil {
+				return nil, err
+			}
+
+			reader = checkpointReader
+		}
+
+		if xdrReader != nil {
+			if checkpointReaderXdr, err = newCheckpointReader(ctx, cfg, xdrReader, true, checkpoints, logger); err != nil {
+				return nil, err
+			}
+
+			xdrReader = checkpointReaderXdr
+		}
+	}
+
+	if cfg.Restore.IsImport() {
+		mapping, err := importer.NewMapping(cfg.Restore)
+		if err != nil {
+			return nil, err
+		}
+
+		reader = importer.NewReader(reader, cfg.Restore.InputFormat, mapping, logger)
 	}
 
-	reader, xdrReader, err := storage.NewRestoreReader(ctx, cfg, logger)
+	transformReader, err := newTransformReader(ctx, reader, xdrReader, cfg, checkpoints, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create restore reader: %w", err)
+		return nil, err
+	}
+
+	if transformReader != nil {
+		if reader != nil {
+			reader = transformReader
+		}
+
+		if xdrReader != nil {
+			if xdrReader, err = newDecodingReader(ctx, cfg, xdrReader); err != nil {
+				return nil, err
+			}
+
+			xdrReader = transformReader.Wrap(xdrReader)
+		}
 	}
 
 	logger.Info("initializing restore client")
@@ -97,13 +217,22 @@ func NewService(
 	}
 
 	return &Service{
-		backupClient: backupClient,
-		config:       restoreConfig,
-		reader:       reader,
-		readerXdr:    xdrReader,
-		mode:         cfg.Restore.Mode,
-		logger:       logger,
-		reportToLog:  cfg.App.LogJSON || cfg.App.LogFile != "",
+		backupClient:        backupClient,
+		config:              restoreConfig,
+		reader:              reader,
+		readerXdr:           xdrReader,
+		transform:           transformReader,
+		rejects:             rejects,
+		rejectLocation:      rejectLocation(cfg),
+		checkpoints:         checkpoints,
+		checkpointReader:    checkpointReader,
+		checkpointReaderXdr: checkpointReaderXdr,
+		chain:               chain,
+		mode:                cfg.Restore.Mode,
+		logger:              logger,
+		reportToLog:         cfg.App.LogJSON || cfg.App.LogFile != "",
+		progressInterval:    time.Duration(cfg.Restore.ProgressInterval) * time.Second,
+		metrics:             metrics.NewExporter(operationName, cfg.App, logger),
 	}, nil
 }
 
@@ -113,12 +242,42 @@ func (r *Service) Run(ctx context.Context) error {
 		return nil
 	}
 
+	if err := r.metrics.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start metrics exporter: %w", err)
+	}
+
+	stopCheckpoints := r.startCheckpoints(ctx)
+
+	err := r.runMode(ctx)
+
+	stopCheckpoints()
+
+	if r.checkpoints != nil {
+		err = errors.Join(err, r.saveCheckpoint(ctx, err))
+	}
+
+	if r.rejects != nil {
+		if closeErr := r.rejects.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to save rejected records: %w", closeErr))
+		}
+	}
+
+	r.metrics.Stop(err)
+
+	return err
+}
+
+func (r *Service) runMode(ctx context.Context) error {
 	// For restore and validation we init different header for log messages.
 	logMessage := "restore"
 	if r.config.ValidateOnly {
 		logMessage = "validation"
 	}
 
+	if r.chain != nil {
+		return r.runChain(ctx, logMessage)
+	}
+
 	switch r.mode {
 	case models.RestoreModeASB, models.RestoreModeAuto:
 		return r.run(ctx, backup.EncoderTypeASB, logMessage)
@@ -130,6 +289,50 @@ func (r *Service) Run(ctx context.Context) error {
 }
 
 func (r *Service) run(ctx context.Context, encoderType backup.EncoderType, logMessage string) error {
+	stats, err := r.restore(ctx, encoderType, logMessage, r.reader)
+	if err != nil {
+		return err
+	}
+
+	r.restored(encoderType)
+
+	// Print report.
+	r.report(stats)
+
+	return nil
+}
+
+// runChain restores the backups of a point-in-time restore one by one, in order.
+func (r *Service) runChain(ctx context.Context, logMessage string) error {
+	var total *bModels.RestoreStats
+
+	for i, b := range r.chain.backups {
+		r.logger.Info("restoring backup of point-in-time restore",
+			slog.Int("order", i+1),
+			slog.Int("backups", len(r.chain.backups)),
+			slog.String("directory", b.path),
+		)
+
+		r.chain.use(b)
+
+		stats, err := r.restore(ctx, backup.EncoderTypeASB, logMessage, b.reader)
+		if err != nil {
+			return fmt.Errorf("failed to restore backup %s: %w", b.path, err)
+		}
+
+		total = bModels.SumRestoreStats(total, stats)
+	}
+
+	r.report(total)
+
+	return nil
+}
+
+// restore runs a restore of r.reader and waits for it to finish.
+// The progress is based on the size of the files of the given reader.
+func (r *Service) restore(
+	ctx context.Context, encoderType backup.EncoderType, logMessage string, progressReader backup.StreamingReader,
+) (*bModels.RestoreStats, error) {
 	restoreType := "asb"
 	if encoderType == backup.EncoderTypeASBX {
 		restoreType = "asbx"
@@ -137,22 +340,29 @@ func (r *Service) run(ctx context.Context, encoderType backup.EncoderType, logMe
 
 	r.logger.Info(fmt.Sprintf("starting %s %s", restoreType, logMessage))
 
-	r.config.EncoderType = encoderType
 	// Run restore / validation.
-	h, err := r.backupClient.Restore(ctx, r.config, r.reader)
+	h, err := r.backupClient.Restore(ctx, r.restoreConfig(encoderType), r.reader)
 	if err != nil {
-		return fmt.Errorf("failed to start %s %s: %w", restoreType, logMessage, err)
+		return nil, fmt.Errorf("failed to start %s %s: %w", restoreType, logMessage, err)
 	}
 
+	r.trackStats(restoreType, h.GetStats())
+
+	var stats atomic.Pointer[bModels.RestoreStats]
+	stats.Store(h.GetStats())
+
+	stopProgress := r.startProgress(ctx, []*atomic.Pointer[bModels.RestoreStats]{&stats}, progressReader)
+
 	// Wait for restore / validation to finish.
-	if err = h.Wait(ctx); err != nil {
-		return fmt.Errorf("failed to perform %s %s: %w", restoreType, logMessage, err)
-	}
+	err = h.Wait(ctx)
 
-	// Print report.
-	logging.ReportRestore(h.GetStats(), r.config.ValidateOnly, r.reportToLog, r.logger)
+	stopProgress()
 
-	return nil
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform %s %s: %w", restoreType, logMessage, err)
+	}
+
+	return h.GetStats(), nil
 }
 
 func (r *Service) runAuto(ctx context.Context) error {
@@ -163,16 +373,18 @@ func (r *Service) runAuto(ctx context.Context) error {
 	var (
 		wg              sync.WaitGroup
 		xdrStats, stats *bModels.RestoreStats
+		// Running stats are published for the progress reporter.
+		xdrProgress, progress atomic.Pointer[bModels.RestoreStats]
 	)
 
 	errChan := make(chan error, 2)
 
+	stopProgress := r.startProgress(ctx,
+		[]*atomic.Pointer[bModels.RestoreStats]{&progress, &xdrProgress}, r.reader, r.readerXdr)
+
 	if r.reader != nil {
 		wg.Go(func() {
-			restoreCfg := *r.config
-			restoreCfg.EncoderType = backup.EncoderTypeASB
-
-			h, err := r.backupClient.Restore(ctx, &restoreCfg, r.reader)
+			h, err := r.backupClient.Restore(ctx, r.restoreConfig(backup.EncoderTypeASB), r.reader)
 			if err != nil {
 				errChan <- fmt.Errorf("failed to start asb restore: %w", err)
 
@@ -181,6 +393,9 @@ func (r *Service) runAuto(ctx context.Context) error {
 				return
 			}
 
+			progress.Store(h.GetStats())
+			r.trackStats("asb", h.GetStats())
+
 			if err = h.Wait(ctx); err != nil {
 				errChan <- fmt.Errorf("failed to perform asb restore: %w", err)
 
@@ -189,16 +404,15 @@ func (r *Service) runAuto(ctx context.Context) error {
 				return
 			}
 
+			r.restored(backup.EncoderTypeASB)
+
 			stats = h.GetStats()
 		})
 	}
 
 	if r.readerXdr != nil {
 		wg.Go(func() {
-			restoreXdrCfg := *r.config
-			restoreXdrCfg.EncoderType = backup.EncoderTypeASBX
-
-			hXdr, err := r.backupClient.Restore(ctx, &restoreXdrCfg, r.readerXdr)
+			hXdr, err := r.backupClient.Restore(ctx, r.restoreConfig(backup.EncoderTypeASBX), r.readerXdr)
 			if err != nil {
 				errChan <- fmt.Errorf("failed to start asbx restore: %w", err)
 
@@ -207,6 +421,9 @@ func (r *Service) runAuto(ctx context.Context) error {
 				return
 			}
 
+			xdrProgress.Store(hXdr.GetStats())
+			r.trackStats("asbx", hXdr.GetStats())
+
 			if err = hXdr.Wait(ctx); err != nil {
 				errChan <- fmt.Errorf("failed to perform asbx restore: %w", err)
 
@@ -215,11 +432,14 @@ func (r *Service) runAuto(ctx context.Context) error {
 				return
 			}
 
+			r.restored(backup.EncoderTypeASBX)
+
 			xdrStats = hXdr.GetStats()
 		})
 	}
 
 	wg.Wait()
+	stopProgress()
 	close(errChan)
 
 	// Return the first error encountered
@@ -231,7 +451,7 @@ func (r *Service) runAuto(ctx context.Context) error {
 	}
 
 	restStats := bModels.SumRestoreStats(xdrStats, stats)
-	logging.ReportRestore(restStats, r.config.ValidateOnly, r.reportToLog, r.logger)
+	r.report(restStats)
 
 	// To prevent context leaking.
 	cancel()
@@ -239,6 +459,192 @@ func (r *Service) runAuto(ctx context.Context) error {
 	return nil
 }
 
+// Stats returns the total stats of all restores started by the service, or nil if none was started.
+// Stats are available even if the restore failed.
+func (r *Service) Stats() *bModels.RestoreStats {
+	if r == nil {
+		return nil
+	}
+
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+
+	var total *bModels.RestoreStats
+
+	for _, stats := range r.stats {
+		if total == nil {
+			total = stats
+			continue
+		}
+
+		total = bModels.SumRestoreStats(total, stats)
+	}
+
+	return total
+}
+
+// Results returns the results of the restore that are not part of the stats.
+func (r *Service) Results() models.RestoreResults {
+	var results models.RestoreResults
+
+	if r == nil {
+		return results
+	}
+
+	if r.transform != nil {
+		// Records that were not in the digest and key files or didn't match the filter expression.
+		results.Filtered = r.transform.Filtered()
+	}
+
+	if targets := r.targets(); targets != nil {
+		results.NotFound = targets.NotFound()
+	}
+
+	if r.rejects != nil {
+		results.Rejected = r.rejects.Count()
+	}
+
+	return results
+}
+
+func (r *Service) targets() *transform.Targets {
+	if r == nil || r.transform == nil {
+		return nil
+	}
+
+	return r.transform.Targets()
+}
+
+// report prints the restore report.
+func (r *Service) report(stats *bModels.RestoreStats) {
+	results := r.Results()
+
+	logging.ReportRestore(stats, results.Filtered, r.config.ValidateOnly, r.reportToLog, r.logger)
+
+	if r.targets() != nil {
+		logging.ReportNotFound(results.NotFound, r.reportToLog, r.logger)
+	}
+
+	if r.rejects != nil {
+		logging.ReportRejected(results.Rejected, r.rejectLocation, r.reportToLog, r.logger)
+	}
+}
+
+// rejectLocation returns the reject directory, with the storage type and bucket for cloud storages.
+func rejectLocation(cfg *config.RestoreServiceConfig) string {
+	return storageLocation(&cfg.ServiceConfigCommon, cfg.Restore.RejectDir)
+}
+
+// storageLocation returns the path, with the storage type and bucket for cloud storages.
+func storageLocation(cfg *config.ServiceConfigCommon, p string) string {
+	location := report.NewStorage(cfg, p)
+	if location.Bucket == "" {
+		return location.Path
+	}
+
+	return location.Type + " " + path.Join(location.Bucket, location.Path)
+}
+
+// newTransformReader wraps the reader to restore only the records of the digest and key files,
+// and to filter and rename records. The asbx files of xdrReader are transformed by the Wrap of the
+// returned reader. Returns nil if none is configured.
+// Compressed and encrypted files are decoded before they are transformed, they are restored as plain files.
+func newTransformReader(
+	ctx context.Context,
+	reader, xdrReader backup.StreamingReader,
+	serviceConfig *config.RestoreServiceConfig,
+	checkpoints *checkpoint.Tracker,
+	logger *slog.Logger,
+) (*transform.Reader, error) {
+	cfg := serviceConfig.Restore
+	renames := &transform.Renames{Sets: cfg.SetRenames(), Bins: cfg.BinRenames()}
+	hasTargets := cfg.DigestFile != "" || cfg.KeyFile != ""
+
+	if reader == nil && xdrReader == nil ||
+		cfg.FilterExpression == "" && renames.Empty() && !hasTargets && checkpoints == nil {
+		return nil, nil
+	}
+
+	var (
+		exp     *transform.Expression
+		targets *transform.Targets
+		err     error
+	)
+
+	if cfg.FilterExpression != "" {
+		if exp, err = transform.NewExpression(cfg.FilterExpression); err != nil {
+			return nil, fmt.Errorf("failed to compile filter expression: %w", err)
+		}
+	}
+
+	if hasTargets {
+		if targets, err = transform.LoadTargets(cfg.DigestFile, cfg.KeyFile); err != nil {
+			return nil, fmt.Errorf("failed to load records to restore: %w", err)
+		}
+
+		// Records may be in more than one backup of a directory list or point-in-time chain,
+		// or in both asb and asbx files, so all files are read.
+		targets.StopWhenFound = cfg.DirectoryList == "" && !cfg.IsPointInTime() && xdrReader == nil
+	}
+
+	if reader != nil {
+		if reader, err = newDecodingReader(ctx, serviceConfig, reader); err != nil {
+			return nil, err
+		}
+	}
+
+	return transform.NewReader(reader, exp, targets, renames, checkpoints, logger), nil
+}
+
+// newDecodingReader wraps the reader to decrypt and decompress its files, if they are encrypted or compressed.
+func newDecodingReader(
+	ctx context.Context, cfg *config.RestoreServiceConfig, reader backup.StreamingReader,
+) (backup.StreamingReader, error) {
+	compressed := cfg.Compression.Policy() != nil
+	policy := cfg.Encryption.Policy()
+
+	if !compressed && policy == nil {
+		return reader, nil
+	}
+
+	var (
+		key []byte
+		err error
+	)
+
+	if policy != nil {
+		if key, err = aeskey.Read(ctx, policy, cfg.SecretAgent.Config()); err != nil {
+			return nil, fmt.Errorf("failed to read encryption key: %w", err)
+		}
+	}
+
+	return codec.NewStreamingReader(reader, key, compressed), nil
+}
+
+// restoreConfig returns the config of a restore of the encoder type. The files of the transform reader
+// are decoded by it, so they are restored without compression and encryption.
+func (r *Service) restoreConfig(encoderType backup.EncoderType) *backup.ConfigRestore {
+	cfg := *r.config
+	cfg.EncoderType = encoderType
+
+	if r.transform != nil {
+		cfg.CompressionPolicy = nil
+		cfg.EncryptionPolicy = nil
+	}
+
+	return &cfg
+}
+
+// trackStats registers the stats of a started restore for metrics and the run report.
+func (r *Service) trackStats(kind string, stats *bModels.RestoreStats) {
+	r.metrics.AddRestore(kind, stats)
+
+	r.statsMu.Lock()
+	defer r.statsMu.Unlock()
+
+	r.stats = append(r.stats, stats)
+}
+
 // GetWarmUp calculates and returns the warm-up value based on the provided warmUp and maxAsyncBatches parameters.
 // If warmUp is 0, it returns one greater than maxAsyncBatches. Otherwise, it returns the warmUp value.
 func GetWarmUp(warmUp, maxAsyncBatches int) int {
@@ -18,10 +18,13 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	appBackup "github.com/aerospike/absctl/internal/backup"
+	"github.com/aerospike/absctl/internal/checkpoint"
+	"github.com/aerospike/absctl/internal/codec"
 	"github.com/aerospike/absctl/internal/config"
 	"github.com/aerospike/absctl/internal/models"
 	"github.com/aerospike/absctl/internal/storage"
@@ -300,6 +303,127 @@ func Test_NewService_ValidateOnly_AutoMode_EmptyDir(t *testing.T) {
 	require.Nil(t, svc)
 }
 
+func Test_NewTransformReader(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	reader := &struct{ backup.StreamingReader }{}
+	serviceConfig := func(restore *models.Restore) *config.RestoreServiceConfig {
+		return &config.RestoreServiceConfig{Restore: restore}
+	}
+
+	tr, err := newTransformReader(ctx, reader, nil, serviceConfig(&models.Restore{}), nil, quietLogger())
+	require.NoError(t, err)
+	require.Nil(t, tr)
+
+	tr, err = newTransformReader(ctx, nil, nil, serviceConfig(&models.Restore{SetMap: "prod:qa"}), nil, quietLogger())
+	require.NoError(t, err)
+	require.Nil(t, tr)
+
+	tr, err = newTransformReader(ctx, reader, nil, serviceConfig(&models.Restore{SetMap: "prod:qa"}), nil, quietLogger())
+	require.NoError(t, err)
+	require.NotNil(t, tr)
+
+	exp, aErr := aerospike.ExpGreater(aerospike.ExpLastUpdate(), aerospike.ExpIntVal(0)).Base64()
+	require.NoError(t, aErr)
+
+	filtered := serviceConfig(&models.Restore{FilterExpression: exp})
+
+	_, err = newTransformReader(ctx, reader, nil, filtered, nil, quietLogger())
+	require.ErrorContains(t, err, "failed to compile filter expression")
+
+	keyFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(keyFile, []byte("users:int:1\n"), 0o600))
+
+	tr, err = newTransformReader(ctx, reader, nil, serviceConfig(&models.Restore{KeyFile: keyFile}), nil, quietLogger())
+	require.NoError(t, err)
+	require.NotNil(t, tr.Targets())
+	require.True(t, tr.Targets().StopWhenFound)
+
+	tr, err = newTransformReader(
+		ctx,
+		reader,
+		nil,
+		serviceConfig(&models.Restore{KeyFile: keyFile, DirectoryList: "a,b"}),
+		nil,
+		quietLogger(),
+	)
+	require.NoError(t, err)
+	require.False(t, tr.Targets().StopWhenFound)
+
+	// Records may be in both asb and asbx files.
+	tr, err = newTransformReader(ctx, reader, reader, serviceConfig(&models.Restore{KeyFile: keyFile}), nil, quietLogger())
+	require.NoError(t, err)
+	require.False(t, tr.Targets().StopWhenFound)
+
+	_, err = newTransformReader(ctx, reader, nil, serviceConfig(&models.Restore{DigestFile: keyFile}), nil, quietLogger())
+	require.ErrorContains(t, err, "failed to load records to restore")
+
+	// Records are read to track them for a resumable restore.
+	checkpoints, err := checkpoint.NewTracker(nil, "restore.state", "backup", nil)
+	require.NoError(t, err)
+
+	tr, err = newTransformReader(ctx, reader, nil, serviceConfig(&models.Restore{}), checkpoints, quietLogger())
+	require.NoError(t, err)
+	require.NotNil(t, tr)
+
+	// Compressed files are decompressed before their records are read.
+	compressed := serviceConfig(&models.Restore{SetMap: "prod:qa"})
+	compressed.Compression = &models.Compression{Mode: "ZSTD", Level: 3}
+
+	tr, err = newTransformReader(ctx, reader, nil, compressed, nil, quietLogger())
+	require.NoError(t, err)
+	require.IsType(t, &codec.StreamingReader{}, tr.StreamingReader)
+}
+
+func TestRestorePath(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "backup", restorePath(&models.Restore{Common: models.Common{Directory: "backup"}}))
+	require.Equal(t, "backup.asb", restorePath(&models.Restore{InputFile: "backup.asb"}))
+	require.Equal(t, "root/a,root/b", restorePath(&models.Restore{DirectoryList: "a,b", ParentDirectory: "root"}))
+}
+
+func TestNewCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "state", "restore.state")
+
+	cfg := &config.RestoreServiceConfig{
+		Restore: &models.Restore{
+			Common:       models.Common{Directory: "backup"},
+			StateFileDst: stateFile,
+		},
+		ServiceConfigCommon: config.ServiceConfigCommon{Local: &models.Local{}},
+	}
+
+	checkpoints, err := newCheckpoints(ctx, cfg, &backup.ConfigRestore{}, quietLogger())
+	require.NoError(t, err)
+
+	checkpoints.Open("backup/test_0.asb")
+	checkpoints.Done("backup/test_0.asb")
+	require.NoError(t, checkpoints.Save(ctx))
+
+	// The state file is written through the storage writer of its directory.
+	require.FileExists(t, stateFile)
+
+	cfg.Restore.StateFileDst = ""
+	cfg.Restore.Continue = stateFile
+
+	resumed, err := newCheckpoints(ctx, cfg, &backup.ConfigRestore{}, quietLogger())
+	require.NoError(t, err)
+
+	_, done := resumed.Restored("backup/test_0.asb")
+	require.True(t, done)
+
+	// The state file of another source is not continued.
+	cfg.Restore.Directory = "other"
+	_, err = newCheckpoints(ctx, cfg, &backup.ConfigRestore{}, quietLogger())
+	require.ErrorContains(t, err, "is the state of the restore of")
+}
+
 func TestGetWarmUp(t *testing.T) {
 	tests := []struct {
 		name            string
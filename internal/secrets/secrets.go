@@ -0,0 +1,63 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets resolves values from the secret agent and remembers them,
+// so that they can be redacted wherever the configuration is printed.
+package secrets
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aerospike/backup-go"
+)
+
+var (
+	mu       sync.RWMutex
+	resolved = make(map[string]struct{})
+)
+
+// Resolve returns the value of the secret agent reference and remembers it.
+func Resolve(ctx context.Context, cfg *backup.SecretAgentConfig, ref string) (string, error) {
+	value, err := backup.ParseSecret(ctx, cfg, ref)
+	if err != nil {
+		return "", err
+	}
+
+	Remember(value)
+
+	return value, nil
+}
+
+// Remember remembers a value resolved from the secret agent. Empty values are ignored.
+func Remember(value string) {
+	if value == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	resolved[value] = struct{}{}
+}
+
+// IsResolved checks if the value was resolved from the secret agent.
+func IsResolved(value string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	_, ok := resolved[value]
+
+	return ok
+}
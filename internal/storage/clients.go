@@ -21,6 +21,8 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 
 	gcpStorage "cloud.google.com/go/storage"
@@ -37,6 +39,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/googleapis/gax-go/v2"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
@@ -278,6 +283,112 @@ func newAzureClient(a *models.AzureBlob) (*azblob.Client, error) {
 	return azClient, nil
 }
 
+// newSftpClient connects to the SFTP server and returns a client.
+// The host key of the server must be listed in the known hosts file.
+func newSftpClient(ctx context.Context, s *models.Sftp) (*sftp.Client, error) {
+	auth, err := getSftpAuth(s)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := getSftpHostKeyCallback(s.KnownHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User:            s.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         time.Duration(s.ConnectTimeout) * time.Millisecond,
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	dialer := &net.Dialer{Timeout: sshConfig.Timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SFTP server %s: %w", addr, err)
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, sshConfig)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to establish SSH connection to %s: %w", addr, err)
+	}
+
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+
+	sftpClient, err := sftp.NewClient(sshClient)
+	if err != nil {
+		_ = sshClient.Close()
+		return nil, fmt.Errorf("failed to start SFTP session on %s: %w", addr, err)
+	}
+
+	return sftpClient, nil
+}
+
+// getSftpAuth returns the auth methods for the SFTP server: the private key, if set, then the password.
+// The key is read from the key file, or set as content, e.g. when it is loaded from the secret agent.
+func getSftpAuth(s *models.Sftp) ([]ssh.AuthMethod, error) {
+	var auth []ssh.AuthMethod
+
+	if s.KeyFile != "" || s.Key != "" {
+		key := []byte(s.Key)
+		if s.KeyFile != "" {
+			var err error
+
+			key, err = os.ReadFile(s.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read SFTP key file %s: %w", s.KeyFile, err)
+			}
+		}
+
+		var (
+			signer ssh.Signer
+			err    error
+		)
+
+		if s.KeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(s.KeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SFTP private key: %w", err)
+		}
+
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if s.Password != "" {
+		auth = append(auth, ssh.Password(s.Password))
+	}
+
+	return auth, nil
+}
+
+// getSftpHostKeyCallback returns the host key callback for the known hosts file,
+// ~/.ssh/known_hosts is used if the file is not set.
+func getSftpHostKeyCallback(knownHostsFile string) (ssh.HostKeyCallback, error) {
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+
+		knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known hosts file %s: %w", knownHostsFile, err)
+	}
+
+	return callback, nil
+}
+
 func toHosts(htpSlice client.HostTLSPortSlice) []*aerospike.Host {
 	hosts := make([]*aerospike.Host, len(htpSlice))
 	for i, htp := range htpSlice {
@@ -15,6 +15,9 @@
 package storage
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"log/slog"
 	"net/http"
 	"os"
@@ -22,10 +25,12 @@ import (
 	"testing"
 
 	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage/sftp/sftptest"
 	"github.com/aerospike/aerospike-client-go/v8"
 	"github.com/aerospike/tools-common-go/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 	"golang.org/x/oauth2"
 )
 
@@ -299,3 +304,110 @@ func TestToHosts(t *testing.T) {
 		})
 	}
 }
+
+func newTestSftpServer(t *testing.T, authorizedKey ssh.PublicKey) (*sftptest.Server, *models.Sftp) {
+	t.Helper()
+
+	server, err := sftptest.NewServer(t.TempDir(), "backup", "secret", authorizedKey)
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	require.NoError(t, os.WriteFile(knownHosts, []byte(server.KnownHosts()), 0o600))
+
+	return server, &models.Sftp{
+		Host:           server.Host,
+		Port:           server.Port,
+		User:           server.User,
+		KnownHosts:     knownHosts,
+		ConnectTimeout: models.DefaultSftpConnectTimeout,
+	}
+}
+
+func TestNewSftpClient_Password(t *testing.T) {
+	t.Parallel()
+
+	server, cfg := newTestSftpServer(t, nil)
+	cfg.Password = server.Password
+
+	c, err := newSftpClient(t.Context(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	wd, err := c.Getwd()
+	require.NoError(t, err)
+	assert.NotEmpty(t, wd)
+}
+
+func TestNewSftpClient_Key(t *testing.T) {
+	t.Parallel()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	authorizedKey, err := ssh.NewPublicKey(public)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(private, "", []byte("passphrase"))
+	require.NoError(t, err)
+
+	_, cfg := newTestSftpServer(t, authorizedKey)
+	cfg.KeyFile = filepath.Join(t.TempDir(), "id_ed25519")
+	cfg.KeyPassphrase = "passphrase"
+	require.NoError(t, os.WriteFile(cfg.KeyFile, pem.EncodeToMemory(block), 0o600))
+
+	c, err := newSftpClient(t.Context(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	// The key may also be passed as PEM content, as the secret agent does.
+	cfg.KeyFile = ""
+	cfg.Key = string(pem.EncodeToMemory(block))
+
+	c, err = newSftpClient(t.Context(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+}
+
+func TestNewSftpClient_UnknownHostKey(t *testing.T) {
+	t.Parallel()
+
+	server, cfg := newTestSftpServer(t, nil)
+	cfg.Password = server.Password
+
+	// The known hosts file of another server.
+	other, _ := newTestSftpServer(t, nil)
+	require.NoError(t, os.WriteFile(cfg.KnownHosts, []byte(other.KnownHosts()), 0o600))
+
+	c, err := newSftpClient(t.Context(), cfg)
+	require.Error(t, err)
+	assert.Nil(t, c)
+	assert.Contains(t, err.Error(), "failed to establish SSH connection")
+}
+
+func TestNewSftpClient_WrongPassword(t *testing.T) {
+	t.Parallel()
+
+	_, cfg := newTestSftpServer(t, nil)
+	cfg.Password = "wrong"
+
+	c, err := newSftpClient(t.Context(), cfg)
+	require.Error(t, err)
+	assert.Nil(t, c)
+}
+
+func TestNewSftpClient_KeyFileMissing(t *testing.T) {
+	t.Parallel()
+
+	cfg := &models.Sftp{
+		Host:    testHost,
+		Port:    models.DefaultSftpPort,
+		User:    "backup",
+		KeyFile: filepath.Join(t.TempDir(), "missing-key"),
+	}
+
+	c, err := newSftpClient(t.Context(), cfg)
+	require.Error(t, err)
+	assert.Nil(t, c)
+	assert.Contains(t, err.Error(), "failed to read SFTP key file")
+}
@@ -0,0 +1,352 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/storage/options"
+)
+
+// errTooFewDestinations is returned when fewer destinations than required are left.
+var errTooFewDestinations = errors.New("too few destinations left")
+
+// destination is one of the storages a FanOutWriter writes to.
+type destination struct {
+	name   string
+	writer backup.Writer
+
+	files atomic.Uint64
+	bytes atomic.Uint64
+
+	mu sync.Mutex
+	// err is the error the destination was dropped for.
+	err error
+}
+
+// fail drops the destination, only the first error is kept.
+func (d *destination) fail(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *destination) failure() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.err
+}
+
+// FanOutWriter writes every file of the backup to several destinations at once, so the cluster is scanned
+// once for all of them. The destinations are written to concurrently. A destination that fails is dropped,
+// and the backup goes on as long as the required number of destinations is left.
+type FanOutWriter struct {
+	destinations []*destination
+	// required is the number of destinations that must succeed.
+	required int
+
+	logger *slog.Logger
+}
+
+// newFanOutWriter returns a FanOutWriter for the writers, by the names of their destinations.
+// If required is 0, all destinations must succeed.
+func newFanOutWriter(names []string, writers []backup.Writer, required int, logger *slog.Logger) *FanOutWriter {
+	destinations := make([]*destination, len(writers))
+	for i := range writers {
+		destinations[i] = &destination{name: names[i], writer: writers[i]}
+	}
+
+	if required <= 0 || required > len(destinations) {
+		required = len(destinations)
+	}
+
+	return &FanOutWriter{
+		destinations: destinations,
+		required:     required,
+		logger:       logger,
+	}
+}
+
+// newDestinationsWriter initializes a writer for each destination of the backup and returns a FanOutWriter
+// for all of them. A destination that can't be initialized fails the backup.
+func newDestinationsWriter(
+	ctx context.Context,
+	params *config.BackupServiceConfig,
+	opts []options.Opt,
+	logger *slog.Logger,
+) (backup.Writer, error) {
+	names := params.Backup.DestinationList()
+	writers := make([]backup.Writer, 0, len(names))
+
+	for _, name := range names {
+		// The options are appended to by each storage, so each one gets its own copy.
+		writer, err := newStorageWriter(ctx, params.WithStorage(name), slices.Clone(opts), logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize destination %s: %w", name, err)
+		}
+
+		writers = append(writers, writer)
+	}
+
+	logger.Info("initialized fan-out writer",
+		slog.Any("destinations", names),
+		slog.Int("required", params.Backup.RequiredDestinations()),
+	)
+
+	return newFanOutWriter(names, writers, params.Backup.MinDestinations, logger), nil
+}
+
+// NewWriter opens the file on all destinations left.
+func (w *FanOutWriter) NewWriter(ctx context.Context, filename string) (io.WriteCloser, error) {
+	var (
+		mu      sync.Mutex
+		targets = make([]*fanOutTarget, 0, len(w.destinations))
+	)
+
+	err := w.forEach(func(d *destination) error {
+		wc, err := d.writer.NewWriter(ctx, filename)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", filename, err)
+		}
+
+		mu.Lock()
+		targets = append(targets, &fanOutTarget{WriteCloser: wc, destination: d})
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		closeTargets(targets)
+		return nil, err
+	}
+
+	return &fanOutFile{writer: w, name: filename, targets: targets}, nil
+}
+
+// Remove removes the path from all destinations left.
+func (w *FanOutWriter) Remove(ctx context.Context, path string) error {
+	return w.forEach(func(d *destination) error {
+		if err := d.writer.Remove(ctx, path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+// RemoveFiles removes the backup files from all destinations left.
+func (w *FanOutWriter) RemoveFiles(ctx context.Context) error {
+	return w.forEach(func(d *destination) error {
+		if err := d.writer.RemoveFiles(ctx); err != nil {
+			return fmt.Errorf("failed to remove files: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetOptions returns the options of the first destination left, or of the first destination if none is left.
+func (w *FanOutWriter) GetOptions() options.Options {
+	for _, d := range w.destinations {
+		if d.failure() == nil {
+			return d.writer.GetOptions()
+		}
+	}
+
+	return w.destinations[0].writer.GetOptions()
+}
+
+// GetType returns the types of all destinations.
+func (w *FanOutWriter) GetType() string {
+	types := make([]string, len(w.destinations))
+	for i, d := range w.destinations {
+		types[i] = d.writer.GetType()
+	}
+
+	return strings.Join(types, ",")
+}
+
+// Stats returns the stats of each destination, in the order of the destinations.
+func (w *FanOutWriter) Stats() []models.DestinationStats {
+	stats := make([]models.DestinationStats, len(w.destinations))
+	for i, d := range w.destinations {
+		stats[i] = models.DestinationStats{
+			Name:         d.name,
+			FilesWritten: d.files.Load(),
+			BytesWritten: d.bytes.Load(),
+			Err:          d.failure(),
+		}
+	}
+
+	return stats
+}
+
+// forEach runs fn concurrently on each destination left. A destination fn fails on is dropped.
+// It returns an error if fewer destinations than required are left.
+func (w *FanOutWriter) forEach(fn func(d *destination) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for _, d := range w.destinations {
+		if d.failure() != nil {
+			continue
+		}
+
+		wg.Go(func() {
+			if err := fn(d); err != nil {
+				if dErr := w.drop(d, err); dErr != nil {
+					mu.Lock()
+					errs = append(errs, dErr)
+					mu.Unlock()
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// drop drops the failed destination. It returns an error if fewer destinations than required are left.
+func (w *FanOutWriter) drop(d *destination, err error) error {
+	d.fail(err)
+
+	left := 0
+
+	for _, dest := range w.destinations {
+		if dest.failure() == nil {
+			left++
+		}
+	}
+
+	w.logger.Warn("backup destination failed",
+		slog.String("destination", d.name),
+		slog.Int("left", left),
+		slog.Int("required", w.required),
+		slog.Any("error", err),
+	)
+
+	if left < w.required {
+		return fmt.Errorf("%w, %d of %d required: destination %s: %w", errTooFewDestinations, left, w.required,
+			d.name, err)
+	}
+
+	return nil
+}
+
+// fanOutTarget is a file opened on one destination.
+type fanOutTarget struct {
+	io.WriteCloser
+	destination *destination
+	// failed is set if writing the file failed.
+	failed bool
+}
+
+// fanOutFile writes to the file on all destinations it was opened on.
+type fanOutFile struct {
+	writer  *FanOutWriter
+	name    string
+	targets []*fanOutTarget
+}
+
+// Write writes p to all destinations left concurrently. A destination dropped by another file is skipped.
+func (f *fanOutFile) Write(p []byte) (int, error) {
+	err := f.forEach(func(t *fanOutTarget) error {
+		if t.failed || t.destination.failure() != nil {
+			return nil
+		}
+
+		n, err := t.Write(p)
+		if err == nil && n < len(p) {
+			err = io.ErrShortWrite
+		}
+
+		if err != nil {
+			t.failed = true
+			return f.writer.drop(t.destination, fmt.Errorf("failed to write %s: %w", f.name, err))
+		}
+
+		t.destination.bytes.Add(uint64(n))
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the file on all destinations concurrently, the file is counted for the destinations left.
+func (f *fanOutFile) Close() error {
+	return f.forEach(func(t *fanOutTarget) error {
+		err := t.Close()
+		if t.failed || t.destination.failure() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return f.writer.drop(t.destination, fmt.Errorf("failed to close %s: %w", f.name, err))
+		}
+
+		t.destination.files.Add(1)
+
+		return nil
+	})
+}
+
+// forEach runs fn concurrently on each target and returns the errors it returns.
+func (f *fanOutFile) forEach(fn func(t *fanOutTarget) error) error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(f.targets))
+	)
+
+	for i, t := range f.targets {
+		wg.Go(func() {
+			errs[i] = fn(t)
+		})
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// closeTargets closes the files opened before a failure.
+func closeTargets(targets []*fanOutTarget) {
+	for _, t := range targets {
+		_ = t.Close()
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestDestination = errors.New("destination failed")
+
+// memFile is a file written to memory that fails on write if failWrite is set.
+type memFile struct {
+	bytes.Buffer
+	failWrite bool
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	if f.failWrite {
+		return 0, errTestDestination
+	}
+
+	return f.Buffer.Write(p)
+}
+
+func (f *memFile) Close() error {
+	return nil
+}
+
+// memStorage is a backup.Writer that writes files to memory.
+type memStorage struct {
+	backup.Writer
+
+	mu         sync.Mutex
+	files      map[string]*memFile
+	failOpen   bool
+	failWrite  bool
+	failRemove bool
+	// started is closed on the first write, and writes wait for release if it is set.
+	started chan struct{}
+	release chan struct{}
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{files: make(map[string]*memFile)}
+}
+
+func (s *memStorage) NewWriter(_ context.Context, filename string) (io.WriteCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failOpen {
+		return nil, errTestDestination
+	}
+
+	f := &memFile{failWrite: s.failWrite}
+	s.files[filename] = f
+
+	if s.release != nil {
+		return &blockingFile{memFile: f, started: s.started, release: s.release}, nil
+	}
+
+	return f, nil
+}
+
+func (s *memStorage) Remove(_ context.Context, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failRemove {
+		return errTestDestination
+	}
+
+	delete(s.files, path)
+
+	return nil
+}
+
+func (s *memStorage) RemoveFiles(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failRemove {
+		return errTestDestination
+	}
+
+	clear(s.files)
+
+	return nil
+}
+
+func (s *memStorage) GetOptions() options.Options {
+	return options.Options{PathList: []string{fmt.Sprintf("%p", s)}}
+}
+
+// blockingFile is a memFile whose writes wait until they are released.
+type blockingFile struct {
+	*memFile
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *blockingFile) Write(p []byte) (int, error) {
+	close(f.started)
+	<-f.release
+
+	return f.memFile.Write(p)
+}
+
+func writeFanOutFile(t *testing.T, w *FanOutWriter, name, data string) error {
+	t.Helper()
+
+	wc, err := w.NewWriter(t.Context(), name)
+	if err != nil {
+		return err
+	}
+
+	if _, err = wc.Write([]byte(data)); err != nil {
+		_ = wc.Close()
+		return err
+	}
+
+	return wc.Close()
+}
+
+func TestFanOutWriter_WritesAllDestinations(t *testing.T) {
+	t.Parallel()
+
+	local, s3 := newMemStorage(), newMemStorage()
+	w := newFanOutWriter([]string{models.StorageLocal, models.StorageS3},
+		[]backup.Writer{local, s3}, 0, slog.Default())
+
+	require.NoError(t, writeFanOutFile(t, w, "test_1.asb", "records"))
+	require.NoError(t, writeFanOutFile(t, w, "test_2.asb", "more"))
+
+	for _, s := range []*memStorage{local, s3} {
+		assert.Equal(t, "records", s.files["test_1.asb"].String())
+		assert.Equal(t, "more", s.files["test_2.asb"].String())
+	}
+
+	assert.Equal(t, []models.DestinationStats{
+		{Name: models.StorageLocal, FilesWritten: 2, BytesWritten: 11},
+		{Name: models.StorageS3, FilesWritten: 2, BytesWritten: 11},
+	}, w.Stats())
+}
+
+func TestFanOutWriter_FailFast(t *testing.T) {
+	t.Parallel()
+
+	local, s3 := newMemStorage(), newMemStorage()
+	s3.failWrite = true
+
+	w := newFanOutWriter([]string{models.StorageLocal, models.StorageS3},
+		[]backup.Writer{local, s3}, 0, slog.Default())
+
+	err := writeFanOutFile(t, w, "test_1.asb", "records")
+	require.ErrorIs(t, err, errTooFewDestinations)
+	require.ErrorIs(t, err, errTestDestination)
+}
+
+func TestFanOutWriter_MinDestinations(t *testing.T) {
+	t.Parallel()
+
+	local, s3, azure := newMemStorage(), newMemStorage(), newMemStorage()
+	s3.failWrite = true
+
+	w := newFanOutWriter([]string{models.StorageLocal, models.StorageS3, models.StorageAzure},
+		[]backup.Writer{local, s3, azure}, 2, slog.Default())
+
+	require.NoError(t, writeFanOutFile(t, w, "test_1.asb", "records"))
+	// The failed destination is not written to anymore.
+	require.NoError(t, writeFanOutFile(t, w, "test_2.asb", "more"))
+	assert.NotContains(t, s3.files, "test_2.asb")
+
+	stats := w.Stats()
+	assert.Equal(t, uint64(2), stats[0].FilesWritten)
+	assert.Equal(t, uint64(0), stats[1].FilesWritten)
+	require.ErrorIs(t, stats[1].Err, errTestDestination)
+	assert.Equal(t, uint64(2), stats[2].FilesWritten)
+
+	// Another failure leaves fewer destinations than required.
+	azure.failOpen = true
+
+	err := writeFanOutFile(t, w, "test_3.asb", "last")
+	require.ErrorIs(t, err, errTooFewDestinations)
+}
+
+func TestFanOutWriter_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	local, s3 := newMemStorage(), newMemStorage()
+	local.started, local.release = make(chan struct{}), make(chan struct{})
+	s3.started, s3.release = make(chan struct{}), make(chan struct{})
+
+	w := newFanOutWriter([]string{models.StorageLocal, models.StorageS3},
+		[]backup.Writer{local, s3}, 0, slog.Default())
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- writeFanOutFile(t, w, "test_1.asb", "records")
+	}()
+
+	// Both destinations are written to before either write returns.
+	<-local.started
+	<-s3.started
+	close(local.release)
+	close(s3.release)
+
+	require.NoError(t, <-done)
+	assert.Equal(t, "records", local.files["test_1.asb"].String())
+	assert.Equal(t, "records", s3.files["test_1.asb"].String())
+}
+
+func TestFanOutWriter_LiveDestinations(t *testing.T) {
+	t.Parallel()
+
+	local, s3, azure := newMemStorage(), newMemStorage(), newMemStorage()
+	w := newFanOutWriter([]string{models.StorageLocal, models.StorageS3, models.StorageAzure},
+		[]backup.Writer{local, s3, azure}, 2, slog.Default())
+
+	require.NoError(t, writeFanOutFile(t, w, "test_1.asb", "records"))
+	require.NoError(t, writeFanOutFile(t, w, "test_2.asb", "more"))
+	assert.Equal(t, local.GetOptions(), w.GetOptions())
+
+	// A destination that fails to remove a file is dropped, and is not used anymore.
+	local.failRemove = true
+
+	require.NoError(t, w.Remove(t.Context(), "test_1.asb"))
+	require.ErrorIs(t, w.Stats()[0].Err, errTestDestination)
+	assert.NotContains(t, s3.files, "test_1.asb")
+	assert.NotContains(t, azure.files, "test_1.asb")
+	assert.Equal(t, s3.GetOptions(), w.GetOptions())
+
+	local.failRemove = false
+
+	require.NoError(t, w.RemoveFiles(t.Context()))
+	assert.Contains(t, local.files, "test_2.asb")
+	assert.Empty(t, s3.files)
+	assert.Empty(t, azure.files)
+
+	// Another failure leaves fewer destinations than required.
+	azure.failRemove = true
+
+	err := w.RemoveFiles(t.Context())
+	require.ErrorIs(t, err, errTooFewDestinations)
+	require.ErrorIs(t, err, errTestDestination)
+}
+
+func TestNewWriter_Destinations(t *testing.T) {
+	t.Parallel()
+
+	params := &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Destinations: models.StorageLocal,
+			Common: models.Common{
+				Directory: t.TempDir(),
+			},
+		},
+		ServiceConfigCommon: config.ServiceConfigCommon{
+			Local: &models.Local{},
+		},
+	}
+
+	writer, err := newWriter(t.Context(), params, slog.Default())
+	require.NoError(t, err)
+
+	fanOut, ok := writer.(*FanOutWriter)
+	require.True(t, ok)
+	assert.Equal(t, testLocalType, fanOut.GetType())
+	assert.Len(t, fanOut.Stats(), 1)
+}
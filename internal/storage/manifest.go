@@ -0,0 +1,135 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/backup-go"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// Backup is a backup found under a backup root.
+type Backup struct {
+	// Path is the directory of the backup.
+	Path     string
+	Manifest *models.Manifest
+}
+
+// ReadManifest reads and parses the manifest of the backup in the directory.
+// The directory is a path of the reader storage, as the paths of its objects.
+func ReadManifest(ctx context.Context, reader backup.StreamingReader, directory string) (*models.Manifest, error) {
+	return readManifest(ctx, reader, path.Join(directory, models.ManifestFileName))
+}
+
+// FindBackups reads the manifests of all backups under the root directory, on any storage.
+// Directories without a manifest are not backups made by absctl, so they are skipped.
+// Backups with a manifest that can't be read are skipped with a warning, so the other backups can still be used.
+func FindBackups(
+	ctx context.Context,
+	cfg *config.ServiceConfigCommon,
+	root string,
+	logger *slog.Logger,
+) ([]Backup, error) {
+	reader, err := NewReader(ctx, cfg, root, "", "", "", 0, false, true, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader for backup root %s: %w", root, err)
+	}
+
+	objects, err := reader.ListObjects(ctx, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	var backups []Backup
+
+	for _, object := range objects {
+		// Objects are keys of the storage, their separator is always a slash.
+		if path.Base(object) != models.ManifestFileName {
+			continue
+		}
+
+		manifest, err := readManifest(ctx, reader, object)
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			logger.Warn("skipping backup with unreadable manifest",
+				slog.String("path", path.Dir(object)),
+				slog.Any("error", err),
+			)
+
+			continue
+		}
+
+		backups = append(backups, Backup{Path: path.Dir(object), Manifest: manifest})
+	}
+
+	return backups, nil
+}
+
+// readManifest reads and parses the manifest object.
+func readManifest(ctx context.Context, reader backup.StreamingReader, object string) (*models.Manifest, error) {
+	file, err := OpenFile(ctx, reader, object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest %s: %w", object, err)
+	}
+	defer file.Reader.Close()
+
+	content, err := io.ReadAll(file.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", object, err)
+	}
+
+	var manifest models.Manifest
+	if err = json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", object, err)
+	}
+
+	if manifest.FormatVersion > models.ManifestFormatVersion {
+		return nil, fmt.Errorf("unsupported manifest format version %d, maximum supported %d",
+			manifest.FormatVersion, models.ManifestFormatVersion)
+	}
+
+	return &manifest, nil
+}
+
+// OpenFile opens the object for reading with the reader. The object is a full path of the reader storage,
+// as returned by ListObjects, not a name in the directory of the reader.
+func OpenFile(ctx context.Context, reader backup.StreamingReader, object string) (bModels.File, error) {
+	readersCh := make(chan bModels.File, 1)
+	errorsCh := make(chan error, 1)
+
+	defer close(readersCh)
+	defer close(errorsCh)
+
+	go reader.StreamFile(ctx, object, readersCh, errorsCh)
+
+	select {
+	case <-ctx.Done():
+		return bModels.File{}, ctx.Err()
+	case err := <-errorsCh:
+		return bModels.File{}, err
+	case file := <-readersCh:
+		return file, nil
+	}
+}
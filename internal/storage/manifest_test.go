@@ -0,0 +1,96 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestManifest(t *testing.T, dir, absctlVersion string) {
+	t.Helper()
+
+	content := `{"format_version":1,"absctl_version":"` + absctlVersion + `"}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, models.ManifestFileName), []byte(content), 0o600))
+}
+
+// The manifest is read in the backup directory, not in the working directory.
+func TestReadManifest_OtherWorkingDirectory(t *testing.T) {
+	workDir := t.TempDir()
+	writeTestManifest(t, workDir, "working-directory")
+	t.Chdir(workDir)
+
+	dir := filepath.Join(t.TempDir(), "backup")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	writeTestManifest(t, dir, "backup")
+
+	params := &config.ServiceConfigCommon{
+		AwsS3:      &models.AwsS3{},
+		GcpStorage: &models.GcpStorage{},
+		AzureBlob:  &models.AzureBlob{},
+	}
+
+	reader, err := NewReader(t.Context(), params, dir, "", "", "", 0, false, true, slog.Default())
+	require.NoError(t, err)
+
+	manifest, err := ReadManifest(t.Context(), reader, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "backup", manifest.AbsctlVersion)
+
+	_, err = ReadManifest(t.Context(), reader, filepath.Join(dir, "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFindBackups(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	for _, name := range []string{"full", "incremental", "broken", "other"} {
+		require.NoError(t, os.Mkdir(filepath.Join(root, name), 0o755))
+	}
+
+	writeTestManifest(t, filepath.Join(root, "full"), "full")
+	writeTestManifest(t, filepath.Join(root, "incremental"), "incremental")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "broken", models.ManifestFileName), []byte("{"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "other", "test_0.asb"), []byte("data"), 0o600))
+
+	params := &config.ServiceConfigCommon{
+		AwsS3:      &models.AwsS3{},
+		GcpStorage: &models.GcpStorage{},
+		AzureBlob:  &models.AzureBlob{},
+	}
+
+	backups, err := FindBackups(t.Context(), params, root, slog.Default())
+	require.NoError(t, err)
+
+	// The backup with an unreadable manifest and the directory without one are skipped.
+	versions := make(map[string]string, len(backups))
+	for _, b := range backups {
+		versions[b.Path] = b.Manifest.AbsctlVersion
+	}
+
+	assert.Equal(t, map[string]string{
+		filepath.Join(root, "full"):        "full",
+		filepath.Join(root, "incremental"): "incremental",
+	}, versions)
+}
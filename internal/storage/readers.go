@@ -24,6 +24,7 @@ import (
 
 	"github.com/aerospike/absctl/internal/config"
 	"github.com/aerospike/absctl/internal/models"
+	sftpStorage "github.com/aerospike/absctl/internal/storage/sftp"
 	"github.com/aerospike/backup-go"
 	"github.com/aerospike/backup-go/io/encoding/asb"
 	"github.com/aerospike/backup-go/io/encoding/asbx"
@@ -48,6 +49,7 @@ func NewRestoreReader(
 
 	switch cfg.Restore.Mode {
 	case models.RestoreModeASB, models.RestoreModeAuto:
+		// Imported files are not .asb files, so they are not validated.
 		reader, err = NewReader(
 			ctx,
 			&cfg.ServiceConfigCommon,
@@ -57,7 +59,7 @@ func NewRestoreReader(
 			directoryList,
 			cfg.Restore.StdBufferSize,
 			false,
-			false,
+			cfg.Restore.IsImport(),
 			logger,
 		)
 		if err != nil {
@@ -154,9 +156,10 @@ func NewStateReader(
 
 	logger.Info("initializing state file", slog.String("path", stateFile))
 
+	// With several destinations, the state file is read from the first one.
 	return NewReader(
 		ctx,
-		&cfg.ServiceConfigCommon,
+		cfg.PrimaryStorage(),
 		cfg.Backup.Directory,
 		stateFile,
 		"",
@@ -214,6 +217,14 @@ func NewReader(
 		)
 
 		return newAzureReader(ctx, cfg.AzureBlob, opts, logger)
+	case cfg.Sftp != nil && cfg.Sftp.Host != "":
+		defer logger.Info("initialized SFTP storage reader",
+			slog.String("host", cfg.Sftp.Host),
+			slog.Int("port", cfg.Sftp.Port),
+			slog.String("path", cfg.Sftp.Path),
+		)
+
+		return newSftpReader(ctx, cfg.Sftp, opts)
 	case inputFile == config.StdPlaceholder:
 		defer logger.Info("initialized standard input reader")
 		return newStdReader(ctx, stdBufferSize)
@@ -352,6 +363,19 @@ func newAzureReader(
 	return blob.NewReader(ctx, client, a.ContainerName, opts...)
 }
 
+func newSftpReader(
+	ctx context.Context,
+	s *models.Sftp,
+	opts []options.Opt,
+) (backup.StreamingReader, error) {
+	client, err := newSftpClient(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return sftpStorage.NewReader(ctx, client, s.Path, opts...)
+}
+
 // prepareDirectoryList parses command line parameters and return slice of strings.
 func prepareDirectoryList(parentDir, dirList string) []string {
 	result := models.SplitByComma(dirList)
@@ -0,0 +1,262 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/aerospike/backup-go/io/storage/common"
+	"github.com/aerospike/backup-go/io/storage/options"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/pkg/sftp"
+)
+
+// readBufferSize is the buffer of a remote file being read, large reads are sent to the server concurrently.
+const readBufferSize = 1024 * 1024
+
+// Reader reads backup files from an SFTP server.
+type Reader struct {
+	client *sftp.Client
+	root   string
+
+	options.Options
+
+	// files are the remote paths of the files to stream, listed on creation.
+	files []remoteFile
+	size  int64
+
+	// skipped are the files skipped by the prefixes set on StreamFiles.
+	skipped *common.SkippedFiles
+}
+
+// remoteFile is a regular file on the server.
+type remoteFile struct {
+	path string
+	size int64
+}
+
+// NewReader returns a reader for the directories or the file set in opts, relative to root.
+// The files to read are listed on creation, common.ErrEmptyStorage is returned if there are none,
+// unless the directory check is skipped.
+func NewReader(ctx context.Context, client *sftp.Client, root string, opts ...options.Opt) (*Reader, error) {
+	r := &Reader{
+		client: client,
+		root:   root,
+	}
+
+	for _, opt := range opts {
+		opt(&r.Options)
+	}
+
+	if len(r.PathList) == 0 {
+		return nil, fmt.Errorf("at least one path is required, use WithDir(path string), " +
+			"WithDirList(paths []string) or WithFile(path string) to set")
+	}
+
+	files, err := r.listFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 && !r.SkipDirCheck {
+		return nil, fmt.Errorf("%w: no backup files found in %s",
+			common.ErrEmptyStorage, strings.Join(r.PathList, ", "))
+	}
+
+	r.files = files
+	for _, file := range files {
+		r.size += file.size
+	}
+
+	return r, nil
+}
+
+// listFiles lists the files to read, sorted by path. Only backup files are listed
+// unless the directory check is skipped, then nested directories are listed too.
+func (r *Reader) listFiles(ctx context.Context) ([]remoteFile, error) {
+	if !r.IsDir {
+		target := r.remotePath(r.PathList[0])
+
+		info, err := r.client.Stat(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat file %s: %w", target, err)
+		}
+
+		return []remoteFile{{path: target, size: info.Size()}}, nil
+	}
+
+	var result []remoteFile
+
+	for _, dir := range r.PathList {
+		files, err := listFiles(ctx, r.client, r.remotePath(dir), r.WithNestedDir)
+
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && r.SkipDirCheck:
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		for _, file := range files {
+			if r.SkipDirCheck || r.Validator == nil || r.Validator.Run(file.path) == nil {
+				result = append(result, file)
+			}
+		}
+	}
+
+	slices.SortFunc(result, func(a, b remoteFile) int {
+		return strings.Compare(a.path, b.path)
+	})
+
+	return result, nil
+}
+
+// StreamFiles sends the files to readersCh, skipping the files whose names start with any of skipPrefixes.
+// readersCh is closed once all files are sent.
+func (r *Reader) StreamFiles(ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error,
+	skipPrefixes []string,
+) {
+	defer close(readersCh)
+
+	r.skipped = common.NewSkippedFiles(skipPrefixes)
+
+	for _, file := range r.files {
+		if r.skipped.Skip(file.path) {
+			continue
+		}
+
+		if !r.sendFile(ctx, file.path, readersCh, errorsCh) {
+			return
+		}
+	}
+}
+
+// StreamFile sends the file at the path, relative to root, to readersCh.
+func (r *Reader) StreamFile(ctx context.Context, filename string, readersCh chan<- bModels.File,
+	errorsCh chan<- error,
+) {
+	r.sendFile(ctx, r.remotePath(filename), readersCh, errorsCh)
+}
+
+// sendFile opens the remote file and sends it to readersCh, or the error to errorsCh.
+// It returns false if the file was not sent.
+func (r *Reader) sendFile(ctx context.Context, target string, readersCh chan<- bModels.File,
+	errorsCh chan<- error,
+) bool {
+	if err := ctx.Err(); err != nil {
+		errorsCh <- err
+		return false
+	}
+
+	file, err := r.client.Open(target)
+	if err != nil {
+		errorsCh <- fmt.Errorf("failed to open file %s: %w", target, err)
+		return false
+	}
+
+	reader := &bufferedReader{Reader: bufio.NewReaderSize(file, readBufferSize), Closer: file}
+
+	select {
+	case <-ctx.Done():
+		_ = file.Close()
+		errorsCh <- ctx.Err()
+
+		return false
+	case readersCh <- bModels.File{Reader: reader, Name: path.Base(target)}:
+		return true
+	}
+}
+
+// GetType returns the type of the storage.
+func (r *Reader) GetType() string {
+	return sftpType
+}
+
+// ListObjects returns the paths of all files under the path, in nested directories too.
+// The paths are relative to root, as the path is.
+func (r *Reader) ListObjects(ctx context.Context, p string) ([]string, error) {
+	files, err := listFiles(ctx, r.client, r.remotePath(p), true)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]string, 0, len(files))
+	for _, file := range files {
+		objects = append(objects, path.Join(p, strings.TrimPrefix(file.path, r.remotePath(p))))
+	}
+
+	return objects, nil
+}
+
+// GetSkipped returns the files skipped by the prefixes set on StreamFiles.
+func (r *Reader) GetSkipped() []string {
+	return r.skipped.GetSkipped()
+}
+
+// GetSize returns the total size of the files to read.
+func (r *Reader) GetSize() int64 {
+	return r.size
+}
+
+// GetNumber returns the number of the files to read.
+func (r *Reader) GetNumber() int64 {
+	return int64(len(r.files))
+}
+
+func (r *Reader) remotePath(p string) string {
+	return remotePath(r.root, p)
+}
+
+// bufferedReader buffers the reads from a remote file, as every read is a round trip to the server.
+type bufferedReader struct {
+	*bufio.Reader
+	io.Closer
+}
+
+// listFiles returns the regular files of the directory, and of its nested directories if nested is set.
+func listFiles(ctx context.Context, client *sftp.Client, dir string, nested bool) ([]remoteFile, error) {
+	entries, err := client.ReadDirContext(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory %s: %w", dir, err)
+	}
+
+	var files []remoteFile
+
+	for _, entry := range entries {
+		p := path.Join(dir, entry.Name())
+
+		switch {
+		case entry.Mode().IsRegular():
+			files = append(files, remoteFile{path: p, size: entry.Size()})
+		case entry.IsDir() && nested:
+			nestedFiles, err := listFiles(ctx, client, p, nested)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, nestedFiles...)
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,292 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftp
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/storage/sftp/sftptest"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	"github.com/aerospike/backup-go/io/storage/common"
+	"github.com/aerospike/backup-go/io/storage/options"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	testUser     = "backup"
+	testPassword = "secret"
+	testDir      = "backup"
+)
+
+// newTestClient starts a server serving a temporary directory and returns a client connected to it.
+func newTestClient(t *testing.T) (client *sftp.Client, dir string) {
+	t.Helper()
+
+	dir = t.TempDir()
+
+	server, err := sftptest.NewServer(dir, testUser, testPassword, nil)
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	conn, err := ssh.Dial("tcp", server.Addr(), &ssh.ClientConfig{
+		User:            testUser,
+		Auth:            []ssh.AuthMethod{ssh.Password(testPassword)},
+		HostKeyCallback: ssh.FixedHostKey(server.HostKey),
+	})
+	require.NoError(t, err)
+
+	client, err = sftp.NewClient(conn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client, dir
+}
+
+func writeLocalFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+}
+
+func writeRemoteFile(t *testing.T, w *Writer, name, content string) {
+	t.Helper()
+
+	wc, err := w.NewWriter(t.Context(), name)
+	require.NoError(t, err)
+
+	_, err = io.WriteString(wc, content)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+}
+
+func readAll(t *testing.T, file bModels.File) string {
+	t.Helper()
+
+	defer file.Reader.Close()
+
+	content, err := io.ReadAll(file.Reader)
+	require.NoError(t, err)
+
+	return string(content)
+}
+
+func TestWriter_Directory(t *testing.T) {
+	t.Parallel()
+
+	client, dir := newTestClient(t)
+
+	w, err := NewWriter(t.Context(), client, "", options.WithDir(testDir), options.WithValidator(asb.NewValidator()))
+	require.NoError(t, err)
+	assert.Equal(t, sftpType, w.GetType())
+
+	writeRemoteFile(t, w, "ns_0.asb", "records")
+	writeRemoteFile(t, w, "sub/ns_1.asb", "nested")
+
+	content, err := os.ReadFile(filepath.Join(dir, testDir, "ns_0.asb"))
+	require.NoError(t, err)
+	assert.Equal(t, "records", string(content))
+
+	content, err = os.ReadFile(filepath.Join(dir, testDir, "sub", "ns_1.asb"))
+	require.NoError(t, err)
+	assert.Equal(t, "nested", string(content))
+}
+
+func TestWriter_Root(t *testing.T) {
+	t.Parallel()
+
+	client, dir := newTestClient(t)
+
+	w, err := NewWriter(t.Context(), client, "remote", options.WithDir(testDir))
+	require.NoError(t, err)
+
+	writeRemoteFile(t, w, "ns_0.asb", "records")
+
+	assert.FileExists(t, filepath.Join(dir, "remote", testDir, "ns_0.asb"))
+}
+
+func TestWriter_File(t *testing.T) {
+	t.Parallel()
+
+	client, dir := newTestClient(t)
+
+	w, err := NewWriter(t.Context(), client, "", options.WithFile("out/backup.asb"))
+	require.NoError(t, err)
+
+	writeRemoteFile(t, w, "ignored.asb", "records")
+
+	content, err := os.ReadFile(filepath.Join(dir, "out", "backup.asb"))
+	require.NoError(t, err)
+	assert.Equal(t, "records", string(content))
+	assert.NoFileExists(t, filepath.Join(dir, "out", "ignored.asb"))
+}
+
+func TestWriter_NotEmpty(t *testing.T) {
+	t.Parallel()
+
+	client, dir := newTestClient(t)
+	writeLocalFile(t, filepath.Join(dir, testDir, "old.asb"), "old")
+	writeLocalFile(t, filepath.Join(dir, testDir, "notes.txt"), "notes")
+
+	_, err := NewWriter(t.Context(), client, "", options.WithDir(testDir), options.WithValidator(asb.NewValidator()))
+	require.ErrorContains(t, err, "backup folder must be empty")
+
+	_, err = NewWriter(t.Context(), client, "", options.WithDir(testDir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, testDir, "old.asb"))
+
+	_, err = NewWriter(t.Context(), client, "", options.WithDir(testDir),
+		options.WithValidator(asb.NewValidator()), options.WithRemoveFiles())
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, testDir, "old.asb"))
+	assert.FileExists(t, filepath.Join(dir, testDir, "notes.txt"))
+}
+
+func TestWriter_Remove(t *testing.T) {
+	t.Parallel()
+
+	client, dir := newTestClient(t)
+	writeLocalFile(t, filepath.Join(dir, testDir, "state.asb.state"), "state")
+	writeLocalFile(t, filepath.Join(dir, testDir, "sub", "ns_0.asb"), "records")
+
+	w, err := NewWriter(t.Context(), client, "", options.WithDir(testDir), options.WithSkipDirCheck())
+	require.NoError(t, err)
+
+	require.NoError(t, w.Remove(t.Context(), testDir+"/state.asb.state"))
+	assert.NoFileExists(t, filepath.Join(dir, testDir, "state.asb.state"))
+
+	require.NoError(t, w.Remove(t.Context(), testDir+"/sub"))
+	assert.NoDirExists(t, filepath.Join(dir, testDir, "sub"))
+
+	require.NoError(t, w.Remove(t.Context(), testDir+"/missing"))
+}
+
+func TestReader_StreamFiles(t *testing.T) {
+	t.Parallel()
+
+	client, dir := newTestClient(t)
+	writeLocalFile(t, filepath.Join(dir, testDir, "ns_1.asb"), "second")
+	writeLocalFile(t, filepath.Join(dir, testDir, "ns_0.asb"), "first")
+	writeLocalFile(t, filepath.Join(dir, testDir, "manifest.json"), "{}")
+
+	r, err := NewReader(t.Context(), client, "", options.WithDir(testDir), options.WithValidator(asb.NewValidator()))
+	require.NoError(t, err)
+	assert.Equal(t, sftpType, r.GetType())
+	assert.Equal(t, int64(2), r.GetNumber())
+	assert.Equal(t, int64(len("first")+len("second")), r.GetSize())
+
+	readersCh := make(chan bModels.File, 2)
+	errorsCh := make(chan error, 1)
+
+	r.StreamFiles(t.Context(), readersCh, errorsCh, nil)
+
+	var names, contents []string
+	for file := range readersCh {
+		names = append(names, file.Name)
+		contents = append(contents, readAll(t, file))
+	}
+
+	require.Empty(t, errorsCh)
+	assert.Equal(t, []string{"ns_0.asb", "ns_1.asb"}, names)
+	assert.Equal(t, []string{"first", "second"}, contents)
+
+	readersCh = make(chan bModels.File, 2)
+	r.StreamFiles(t.Context(), readersCh, errorsCh, []string{"ns_0"})
+
+	names = nil
+	for file := range readersCh {
+		names = append(names, file.Name)
+		_ = file.Reader.Close()
+	}
+
+	assert.Equal(t, []string{"ns_1.asb"}, names)
+	assert.Equal(t, []string{testDir + "/ns_0.asb"}, r.GetSkipped())
+}
+
+func TestReader_Empty(t *testing.T) {
+	t.Parallel()
+
+	client, dir := newTestClient(t)
+	writeLocalFile(t, filepath.Join(dir, testDir, "manifest.json"), "{}")
+
+	_, err := NewReader(t.Context(), client, "", options.WithDir(testDir), options.WithValidator(asb.NewValidator()))
+	require.ErrorIs(t, err, common.ErrEmptyStorage)
+
+	_, err = NewReader(t.Context(), client, "", options.WithDir("missing"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	r, err := NewReader(t.Context(), client, "", options.WithDir("missing"), options.WithSkipDirCheck())
+	require.NoError(t, err)
+	assert.Zero(t, r.GetNumber())
+}
+
+func TestReader_StreamFile(t *testing.T) {
+	t.Parallel()
+
+	client, dir := newTestClient(t)
+	writeLocalFile(t, filepath.Join(dir, "remote", testDir, "ns_0.asb"), "records")
+	writeLocalFile(t, filepath.Join(dir, "remote", testDir, "manifest.json"), "{}")
+
+	r, err := NewReader(t.Context(), client, "remote", options.WithDir(testDir),
+		options.WithValidator(asb.NewValidator()))
+	require.NoError(t, err)
+
+	readersCh := make(chan bModels.File, 1)
+	errorsCh := make(chan error, 1)
+
+	r.StreamFile(t.Context(), testDir+"/manifest.json", readersCh, errorsCh)
+
+	require.Empty(t, errorsCh)
+	file := <-readersCh
+	assert.Equal(t, "manifest.json", file.Name)
+	assert.Equal(t, "{}", readAll(t, file))
+
+	// The path is relative to root, not to the directory.
+	r.StreamFile(t.Context(), "manifest.json", readersCh, errorsCh)
+	require.ErrorIs(t, <-errorsCh, fs.ErrNotExist)
+}
+
+func TestReader_ListObjects(t *testing.T) {
+	t.Parallel()
+
+	client, dir := newTestClient(t)
+	writeLocalFile(t, filepath.Join(dir, "root", "full", "manifest.json"), "{}")
+	writeLocalFile(t, filepath.Join(dir, "root", "full", "ns_0.asb"), "records")
+	writeLocalFile(t, filepath.Join(dir, "root", "incr", "1", "manifest.json"), "{}")
+
+	r, err := NewReader(t.Context(), client, "", options.WithDir("root"), options.WithSkipDirCheck(),
+		options.WithNestedDir())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), r.GetNumber())
+
+	objects, err := r.ListObjects(t.Context(), "root")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"root/full/manifest.json",
+		"root/full/ns_0.asb",
+		"root/incr/1/manifest.json",
+	}, objects)
+
+	_, err = r.ListObjects(t.Context(), "missing")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
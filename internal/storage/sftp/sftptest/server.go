@@ -0,0 +1,217 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sftptest provides an in-process SFTP server for tests.
+package sftptest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Server is an SFTP server on the loopback interface, serving a local directory
+// as the login directory of a single user, who logs in with a password or an authorized key.
+type Server struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	// Dir is the local directory relative paths are served from.
+	Dir string
+	// HostKey is the public host key of the server.
+	HostKey ssh.PublicKey
+
+	authorizedKey ssh.PublicKey
+	listener      net.Listener
+	config        *ssh.ServerConfig
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// NewServer starts a server for the user with the password, serving dir.
+// authorizedKey, if not nil, may be used to log in as well.
+func NewServer(dir, user, password string, authorizedKey ssh.PublicKey) (*Server, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate host key: %w", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create host key signer: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+
+	addr := listener.Addr().(*net.TCPAddr)
+
+	s := &Server{
+		Host:          addr.IP.String(),
+		Port:          addr.Port,
+		User:          user,
+		Password:      password,
+		Dir:           dir,
+		HostKey:       signer.PublicKey(),
+		authorizedKey: authorizedKey,
+		listener:      listener,
+		conns:         make(map[net.Conn]struct{}),
+	}
+
+	s.config = &ssh.ServerConfig{
+		PasswordCallback:  s.checkPassword,
+		PublicKeyCallback: s.checkPublicKey,
+	}
+	s.config.AddHostKey(signer)
+
+	s.wg.Add(1)
+
+	go s.serve()
+
+	return s, nil
+}
+
+// Addr returns the host:port address of the server.
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// KnownHosts returns the known_hosts line of the server.
+func (s *Server) KnownHosts() string {
+	return knownhosts.Line([]string{knownhosts.Normalize(s.Addr())}, s.HostKey) + "\n"
+}
+
+// Close stops the server and closes all connections.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Server) checkPassword(meta ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+	if meta.User() == s.User && string(password) == s.Password {
+		return nil, nil
+	}
+
+	return nil, errors.New("invalid user or password")
+}
+
+func (s *Server) checkPublicKey(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	if s.authorizedKey != nil && meta.User() == s.User &&
+		bytes.Equal(key.Marshal(), s.authorizedKey.Marshal()) {
+		return nil, nil
+	}
+
+	return nil, errors.New("unauthorized key")
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// handleConn serves the sftp subsystem on the session channels of the connection.
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		return
+	}
+
+	go ssh.DiscardRequests(reqs)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer channel.Close()
+
+			for req := range requests {
+				// The payload of a subsystem request is the length prefixed name of the subsystem.
+				ok := req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "sftp"
+				_ = req.Reply(ok, nil)
+
+				if !ok {
+					continue
+				}
+
+				server, err := sftp.NewServer(channel, sftp.WithServerWorkingDirectory(s.Dir))
+				if err != nil {
+					return
+				}
+
+				_ = server.Serve()
+				_ = server.Close()
+
+				return
+			}
+		}()
+	}
+}
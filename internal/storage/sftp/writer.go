@@ -0,0 +1,243 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sftp implements the backup storage on an SFTP server.
+// Paths are relative to a root directory on the server, as object keys are relative to a bucket.
+package sftp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aerospike/backup-go/io/storage/options"
+	"github.com/pkg/sftp"
+)
+
+const (
+	sftpType = "sftp"
+
+	defaultBufferSize = 4 * 1024 * 1024
+)
+
+// Writer writes backup files to an SFTP server.
+type Writer struct {
+	client *sftp.Client
+	root   string
+
+	options.Options
+}
+
+// NewWriter returns a writer for the directory or the file set in opts, relative to root.
+// The directory is created if it does not exist. It must be empty unless
+// its files are removed or the directory check is skipped.
+func NewWriter(ctx context.Context, client *sftp.Client, root string, opts ...options.Opt) (*Writer, error) {
+	w := &Writer{
+		client: client,
+		root:   root,
+	}
+
+	for _, opt := range opts {
+		opt(&w.Options)
+	}
+
+	if len(w.PathList) != 1 {
+		return nil, fmt.Errorf("one path is required, use WithDir(path string) or WithFile(path string) to set")
+	}
+
+	if w.ChunkSize < 1 {
+		w.ChunkSize = defaultBufferSize
+	}
+
+	if w.IsRemovingFiles {
+		if err := w.RemoveFiles(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if !w.IsDir {
+		return w, nil
+	}
+
+	dir := w.remotePath(w.PathList[0])
+	if err := w.client.MkdirAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	if w.SkipDirCheck {
+		return w, nil
+	}
+
+	files, err := listFiles(ctx, w.client, dir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if w.isValid(file.path) {
+			return nil, fmt.Errorf("backup folder must be empty or set RemoveFiles = true")
+		}
+	}
+
+	return w, nil
+}
+
+// NewWriter returns a writer for the file. In a directory the file is created in the directory,
+// along with its parent directories, otherwise the file set in the options is written.
+func (w *Writer) NewWriter(ctx context.Context, filename string) (io.WriteCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	target := w.remotePath(w.PathList[0])
+	if w.IsDir {
+		target = w.remotePath(inDir(w.PathList[0], filename))
+	}
+
+	if err := w.client.MkdirAll(path.Dir(target)); err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", target, err)
+	}
+
+	file, err := w.client.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file %s: %w", target, err)
+	}
+
+	return &bufferedFile{Writer: bufio.NewWriterSize(file, w.ChunkSize), file: file}, nil
+}
+
+// GetType returns the type of the storage.
+func (w *Writer) GetType() string {
+	return sftpType
+}
+
+// GetOptions returns the options of the writer.
+func (w *Writer) GetOptions() options.Options {
+	return w.Options
+}
+
+// RemoveFiles removes the backup files of the directory, or the file set in the options.
+func (w *Writer) RemoveFiles(ctx context.Context) error {
+	target := w.remotePath(w.PathList[0])
+
+	if !w.IsDir {
+		return removeFile(w.client, target)
+	}
+
+	files, err := listFiles(ctx, w.client, target, false)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for _, file := range files {
+		if !w.isValid(file.path) {
+			continue
+		}
+
+		if err = removeFile(w.client, file.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove removes the file or the directory at the path with all its content.
+// A path that does not exist is not an error.
+func (w *Writer) Remove(ctx context.Context, p string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	target := w.remotePath(p)
+
+	info, err := w.client.Stat(target)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to stat %s: %w", target, err)
+	case info.IsDir():
+		if err = w.client.RemoveAll(target); err != nil {
+			return fmt.Errorf("failed to remove directory %s: %w", target, err)
+		}
+
+		return nil
+	default:
+		return removeFile(w.client, target)
+	}
+}
+
+// isValid checks if the file is a backup file, all files are if no validator is set.
+func (w *Writer) isValid(file string) bool {
+	return w.Validator == nil || w.Validator.Run(file) == nil
+}
+
+func (w *Writer) remotePath(p string) string {
+	return remotePath(w.root, p)
+}
+
+// bufferedFile buffers the writes to a remote file, as every write is a round trip to the server.
+type bufferedFile struct {
+	*bufio.Writer
+	file *sftp.File
+}
+
+// Close flushes the buffer and closes the file.
+func (f *bufferedFile) Close() error {
+	if err := f.Flush(); err != nil {
+		_ = f.file.Close()
+		return fmt.Errorf("failed to write file %s: %w", f.file.Name(), err)
+	}
+
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close file %s: %w", f.file.Name(), err)
+	}
+
+	return nil
+}
+
+func removeFile(client *sftp.Client, target string) error {
+	err := client.Remove(target)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove file %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// remotePath returns the path on the server of the path relative to root.
+func remotePath(root, p string) string {
+	return path.Join(root, p)
+}
+
+// inDir returns the path of the file in the directory, the file may already be a path in the directory.
+func inDir(dir, file string) string {
+	if file == dir || strings.HasPrefix(file, strings.TrimSuffix(dir, "/")+"/") {
+		return file
+	}
+
+	return path.Join(dir, file)
+}
@@ -18,9 +18,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"path"
 
 	"github.com/aerospike/absctl/internal/config"
 	"github.com/aerospike/absctl/internal/models"
+	sftpStorage "github.com/aerospike/absctl/internal/storage/sftp"
 	"github.com/aerospike/backup-go"
 	"github.com/aerospike/backup-go/io/encoding/asb"
 	"github.com/aerospike/backup-go/io/encoding/asbx"
@@ -54,6 +56,51 @@ func NewBackupWriter(
 	return writer, nil
 }
 
+// NewRejectWriter initializes and returns a backup.Writer for the reject directory of a restore,
+// on the storage the backup is read from.
+func NewRejectWriter(
+	ctx context.Context,
+	params *config.RestoreServiceConfig,
+	logger *slog.Logger,
+) (backup.Writer, error) {
+	writer, err := newWriter(ctx, &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: params.Restore.RejectDir,
+			},
+		},
+		ServiceConfigCommon: params.ServiceConfigCommon,
+	}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reject writer: %w", err)
+	}
+
+	return writer, nil
+}
+
+// NewRestoreStateWriter initializes and returns a backup.Writer for the directory of the state file of a restore,
+// on the storage the backup is read from. The directory may hold the backup or other files, so it is not checked
+// to be empty.
+func NewRestoreStateWriter(
+	ctx context.Context,
+	params *config.RestoreServiceConfig,
+	logger *slog.Logger,
+) (backup.Writer, error) {
+	directory := path.Dir(params.Restore.StateFile())
+	opts := newWriterOpts(directory, "", false, true, false, logger)
+
+	logger.Info("initializing storage for restore state writer",
+		slog.String("directory", directory),
+	)
+
+	writer, err := newStorageWriter(ctx, &params.ServiceConfigCommon, opts, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create restore state writer: %w", err)
+	}
+
+	return writer, nil
+}
+
 func newWriter(
 	ctx context.Context,
 	params *config.BackupServiceConfig,
@@ -78,39 +125,86 @@ func newWriter(
 		slog.Bool("continue-backup", continueBackup),
 	)
 
+	if params.IsStdout() {
+		defer logger.Info("initialized standard output writer")
+		return newStdWriter(ctx, params.Backup.StdBufferSize)
+	}
+
+	if len(params.Backup.DestinationList()) > 0 {
+		return newDestinationsWriter(ctx, params, opts, logger)
+	}
+
+	return newStorageWriter(ctx, &params.ServiceConfigCommon, opts, logger)
+}
+
+// NewCopyWriter initializes and returns a backup.Writer for the copy of a backup to the directory.
+// The directory may hold the files of an interrupted copy, so it is not checked to be empty.
+func NewCopyWriter(
+	ctx context.Context,
+	cfg *config.ServiceConfigCommon,
+	directory string,
+	logger *slog.Logger,
+) (backup.Writer, error) {
+	opts := newWriterOpts(directory, "", false, true, false, logger)
+
+	logger.Info("initializing storage for copy writer",
+		slog.String("directory", directory),
+	)
+
+	writer, err := newStorageWriter(ctx, cfg, opts, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create copy writer: %w", err)
+	}
+
+	return writer, nil
+}
+
+// newStorageWriter initializes the writer of the configured cloud or SFTP storage, or of the local storage.
+func newStorageWriter(
+	ctx context.Context,
+	cfg *config.ServiceConfigCommon,
+	opts []options.Opt,
+	logger *slog.Logger,
+) (backup.Writer, error) {
 	switch {
-	case params.AwsS3 != nil && params.AwsS3.BucketName != "":
+	case cfg.AwsS3 != nil && cfg.AwsS3.BucketName != "":
 		defer logger.Info("initialized AWS storage writer",
-			slog.String("bucket", params.AwsS3.BucketName),
-			slog.String("storage-class", params.AwsS3.StorageClass),
-			slog.Int("chunk-size", params.AwsS3.ChunkSize),
-			slog.String("endpoint", params.AwsS3.Endpoint),
+			slog.String("bucket", cfg.AwsS3.BucketName),
+			slog.String("storage-class", cfg.AwsS3.StorageClass),
+			slog.Int("chunk-size", cfg.AwsS3.ChunkSize),
+			slog.String("endpoint", cfg.AwsS3.Endpoint),
 		)
 
-		return newS3Writer(ctx, params.AwsS3, opts)
-	case params.GcpStorage != nil && params.GcpStorage.BucketName != "":
+		return newS3Writer(ctx, cfg.AwsS3, opts)
+	case cfg.GcpStorage != nil && cfg.GcpStorage.BucketName != "":
 		defer logger.Info("initialized GCP storage writer",
-			slog.String("bucket", params.GcpStorage.BucketName),
-			slog.Int("chunk-size", params.GcpStorage.ChunkSize),
-			slog.String("endpoint", params.GcpStorage.Endpoint),
+			slog.String("bucket", cfg.GcpStorage.BucketName),
+			slog.Int("chunk-size", cfg.GcpStorage.ChunkSize),
+			slog.String("endpoint", cfg.GcpStorage.Endpoint),
 		)
 
-		return newGcpWriter(ctx, params.GcpStorage, opts)
-	case params.AzureBlob != nil && params.AzureBlob.ContainerName != "":
+		return newGcpWriter(ctx, cfg.GcpStorage, opts)
+	case cfg.AzureBlob != nil && cfg.AzureBlob.ContainerName != "":
 		defer logger.Info("initialized Azure storage writer",
-			slog.String("container", params.AzureBlob.ContainerName),
-			slog.String("access-tier", params.AzureBlob.AccessTier),
-			slog.Int("block-size", params.AzureBlob.BlockSize),
-			slog.String("endpoint", params.AzureBlob.Endpoint),
+			slog.String("container", cfg.AzureBlob.ContainerName),
+			slog.String("access-tier", cfg.AzureBlob.AccessTier),
+			slog.Int("block-size", cfg.AzureBlob.BlockSize),
+			slog.String("endpoint", cfg.AzureBlob.Endpoint),
 		)
 
-		return newAzureWriter(ctx, params.AzureBlob, opts)
-	case params.IsStdout():
-		defer logger.Info("initialized standard output writer")
-		return newStdWriter(ctx, params.Backup.StdBufferSize)
+		return newAzureWriter(ctx, cfg.AzureBlob, opts)
+	case cfg.Sftp != nil && cfg.Sftp.Host != "":
+		defer logger.Info("initialized SFTP storage writer",
+			slog.String("host", cfg.Sftp.Host),
+			slog.Int("port", cfg.Sftp.Port),
+			slog.String("path", cfg.Sftp.Path),
+			slog.Int("buffer-size", cfg.Sftp.BufferSize),
+		)
+
+		return newSftpWriter(ctx, cfg.Sftp, opts)
 	default:
 		defer logger.Info("initialized local storage writer")
-		return newLocalWriter(ctx, params.Local, opts)
+		return newLocalWriter(ctx, cfg.Local, opts)
 	}
 }
 
@@ -248,6 +342,21 @@ func newAzureWriter(
 	return blob.NewWriter(ctx, client, a.ContainerName, opts...)
 }
 
+func newSftpWriter(
+	ctx context.Context,
+	s *models.Sftp,
+	opts []options.Opt,
+) (backup.Writer, error) {
+	client, err := newSftpClient(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, options.WithChunkSize(toBytes(s.BufferSize)))
+
+	return sftpStorage.NewWriter(ctx, client, s.Path, opts...)
+}
+
 func toBytes(m int) int {
 	return m * 1024 * 1024
 }
@@ -397,6 +397,32 @@ func TestNewBackupWriter_WrapsInnerError(t *testing.T) {
 	assert.Contains(t, err.Error(), "params cannot be nil")
 }
 
+func TestNewRejectWriter_Local(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "rejects")
+	params := &config.RestoreServiceConfig{
+		Restore: &models.Restore{
+			RejectDir: dir,
+		},
+		ServiceConfigCommon: config.ServiceConfigCommon{
+			AwsS3:      &models.AwsS3{},
+			GcpStorage: &models.GcpStorage{},
+			AzureBlob:  &models.AzureBlob{},
+			Local:      &models.Local{},
+		},
+	}
+
+	writer, err := NewRejectWriter(t.Context(), params, slog.Default())
+	require.NoError(t, err)
+
+	w, err := writer.NewWriter(t.Context(), "rejected.asb")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.FileExists(t, filepath.Join(dir, "rejected.asb"))
+}
+
 func TestNewWriter_NilParams(t *testing.T) {
 	t.Parallel()
 
@@ -20,6 +20,7 @@ import (
 	"log/slog"
 
 	"github.com/aerospike/absctl/internal/flags"
+	"github.com/aerospike/absctl/internal/hooks"
 	"github.com/aerospike/absctl/internal/logging"
 	"github.com/aerospike/absctl/internal/models"
 	asFlags "github.com/aerospike/tools-common-go/flags"
@@ -34,6 +35,11 @@ type ServiceConfig interface {
 	Validate() error
 }
 
+// HookedConfig is implemented by service configs that support pre/post execution hooks.
+type HookedConfig interface {
+	GetHooks() *hooks.Config
+}
+
 // SharedFlags holds all flag objects shared across subcommands.
 type SharedFlags struct {
 	Root         *flags.Root
@@ -205,5 +211,16 @@ func RunCommand(
 		}
 	}()
 
-	return runner.RunService(cmd.Context(), cfg, logger)
+	var hooksCfg *hooks.Config
+	if hooked, ok := cfg.(HookedConfig); ok {
+		hooksCfg = hooked.GetHooks()
+	}
+
+	if err = hooksCfg.RunPre(cmd.Context(), logger); err != nil {
+		return hooksCfg.RunPost(cmd.Context(), err, logger)
+	}
+
+	err = runner.RunService(cmd.Context(), cfg, logger)
+
+	return hooksCfg.RunPost(cmd.Context(), err, logger)
 }
@@ -19,9 +19,13 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/aerospike/absctl/internal/flags"
+	"github.com/aerospike/absctl/internal/hooks"
 	"github.com/aerospike/absctl/internal/models"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -48,6 +52,14 @@ type fakeServiceConfig struct {
 func (f *fakeServiceConfig) GetApp() *models.App { return f.app }
 func (f *fakeServiceConfig) Validate() error     { return f.validateErr }
 
+// fakeHookedConfig is a ServiceConfig with execution hooks.
+type fakeHookedConfig struct {
+	fakeServiceConfig
+	hooks *hooks.Config
+}
+
+func (f *fakeHookedConfig) GetHooks() *hooks.Config { return f.hooks }
+
 // fakeRunner is a configurable Runner implementation used to drive tests.
 type fakeRunner struct {
 	flagSets        []*pflag.FlagSet
@@ -349,6 +361,79 @@ func TestRunCommand_RunServiceSuccess(t *testing.T) {
 	require.True(t, r.runCalled, "RunService must be called on the happy path")
 }
 
+func TestRunCommand_PreHookErrorSkipsRunner(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use sh commands")
+	}
+
+	out := filepath.Join(t.TempDir(), "status")
+
+	r := newFakeRunner()
+	r.cfg = &fakeHookedConfig{
+		fakeServiceConfig: *validAppCfg(),
+		hooks: &hooks.Config{
+			PreHook:       "exit 1",
+			OnFailureHook: "echo $ABSCTL_STATUS > " + out,
+		},
+	}
+
+	cmd, shared := BuildCommand(
+		testCmdName, testCmdShort, testCmdLong,
+		flags.NewRoot(), testAppVersion, testCommitHash, testBuildTime,
+		flags.OperationBackup, r,
+	)
+	cmd.SetContext(t.Context())
+
+	require.NoError(t, cmd.Flags().Set(testRunnerFlag, testRunnerFlagVal))
+
+	err := RunCommand(cmd, r, shared, testAppVersion, testCommitHash, testBuildTime)
+
+	require.ErrorContains(t, err, "pre-hook failed")
+	require.False(t, r.runCalled, "RunService must not be called if the pre-hook failed")
+
+	status, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Equal(t, hooks.StatusFailure+"\n", string(status))
+}
+
+func TestRunCommand_PostHookRunsAfterService(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use sh commands")
+	}
+
+	out := filepath.Join(t.TempDir(), "status")
+
+	r := newFakeRunner()
+	r.cfg = &fakeHookedConfig{
+		fakeServiceConfig: *validAppCfg(),
+		hooks: &hooks.Config{
+			PostHook: "echo $ABSCTL_STATUS > " + out,
+		},
+	}
+
+	cmd, shared := BuildCommand(
+		testCmdName, testCmdShort, testCmdLong,
+		flags.NewRoot(), testAppVersion, testCommitHash, testBuildTime,
+		flags.OperationBackup, r,
+	)
+	cmd.SetContext(t.Context())
+
+	require.NoError(t, cmd.Flags().Set(testRunnerFlag, testRunnerFlagVal))
+
+	err := RunCommand(cmd, r, shared, testAppVersion, testCommitHash, testBuildTime)
+
+	require.NoError(t, err)
+	require.True(t, r.runCalled)
+
+	status, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Equal(t, hooks.StatusSuccess+"\n", string(status))
+}
+
 func TestBuildCommand_PersistentPreRunE_NoSecrets(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,472 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aerospike/absctl/internal/aeskey"
+	appBackup "github.com/aerospike/absctl/internal/backup"
+	"github.com/aerospike/absctl/internal/codec"
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// errCountMismatch is returned if the transcoded backup does not hold the same data as the backup.
+var errCountMismatch = errors.New("transcoded backup does not match the backup")
+
+// fileCount contains the counters of a decoded backup file.
+type fileCount struct {
+	records uint64
+	// bytes is the size of the file after decryption and decompression.
+	bytes uint64
+}
+
+// Service rewrites the files of a backup with another compression or encryption key, without connecting
+// to a cluster, and checks that the rewritten files hold the same records.
+type Service struct {
+	cfg    *config.TranscodeServiceConfig
+	reader backup.StreamingReader
+	output *appBackup.ManifestWriter
+
+	// manifest is the manifest of the backup, nil if it has none.
+	manifest *models.Manifest
+
+	// sourceKey and key are nil if the files are not encrypted.
+	sourceKey        []byte
+	sourceCompressed bool
+	key              []byte
+	compression      *backup.CompressionPolicy
+
+	// counts are the counters of the transcoded files by name.
+	counts map[string]fileCount
+
+	appVersion string
+	commitHash string
+
+	logger *slog.Logger
+}
+
+// NewService reads the manifest of the backup, if it has one, and initializes the writer of the transcoded backup.
+func NewService(
+	ctx context.Context,
+	cfg *config.TranscodeServiceConfig,
+	logger *slog.Logger,
+) (*Service, error) {
+	// Skip the file checks, so both .asb and .asbx files are streamed.
+	reader, err := storage.NewReader(ctx, &cfg.ServiceConfigCommon, cfg.Transcode.Directory,
+		"", "", "", 0, false, true, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader: %w", err)
+	}
+
+	s := &Service{
+		cfg:              cfg,
+		reader:           reader,
+		sourceCompressed: cfg.Transcode.SourceCompression.Policy() != nil,
+		compression:      cfg.Compression.Policy(),
+		counts:           make(map[string]fileCount),
+		logger:           logger,
+	}
+
+	s.manifest, err = readManifest(ctx, reader, cfg.Transcode.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkManifest(s.manifest, &cfg.Transcode.SourceCompression, &cfg.Transcode.SourceEncryption); err != nil {
+		return nil, err
+	}
+
+	saConfig := cfg.SecretAgent.Config()
+
+	if policy := cfg.Transcode.SourceEncryption.Policy(); policy != nil {
+		s.sourceKey, err = aeskey.Read(ctx, policy, saConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read source encryption key: %w", err)
+		}
+	}
+
+	if policy := cfg.Encryption.Policy(); policy != nil {
+		s.key, err = aeskey.Read(ctx, policy, saConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key: %w", err)
+		}
+	}
+
+	writer, err := storage.NewBackupWriter(ctx, &config.BackupServiceConfig{
+		Backup: &models.Backup{
+			Common: models.Common{
+				Directory: cfg.Transcode.OutputDirectory,
+			},
+		},
+		ServiceConfigCommon: cfg.ServiceConfigCommon,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	s.output = appBackup.NewManifestWriter(writer)
+	s.output.SetSigningKey(s.key)
+
+	return s, nil
+}
+
+// SetBuildInfo sets the absctl version and commit that are saved to the manifest of the transcoded backup.
+func (s *Service) SetBuildInfo(appVersion, commitHash string) {
+	s.appVersion = appVersion
+	s.commitHash = commitHash
+}
+
+// Run transcodes the backup files, checks that the transcoded files hold the same records
+// and then saves the manifest of the transcoded backup.
+func (s *Service) Run(ctx context.Context) error {
+	s.logger.Info("starting backup transcoding",
+		slog.String("directory", s.cfg.Transcode.Directory),
+		slog.String("output-directory", s.cfg.Transcode.OutputDirectory),
+	)
+
+	start := time.Now()
+
+	err := codec.StreamFiles(ctx, s.reader, func(file bModels.File) error {
+		return s.transcodeFile(ctx, file)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = s.verify(ctx); err != nil {
+		return err
+	}
+
+	if s.manifest != nil {
+		if err = s.output.Save(ctx, s.newManifest()); err != nil {
+			return err
+		}
+	}
+
+	var records uint64
+	for _, count := range s.counts {
+		records += count.records
+	}
+
+	s.logger.Info("backup transcoding finished",
+		slog.Int("files", len(s.counts)),
+		slog.Uint64("records", records),
+		slog.Bool("manifest", s.manifest != nil),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	return nil
+}
+
+// transcodeFile decodes a file with the source settings and writes it with the new ones, counting its records.
+// Files other than .asb and .asbx, like the manifest, are skipped.
+func (s *Service) transcodeFile(ctx context.Context, file bModels.File) error {
+	defer file.Reader.Close()
+
+	name := path.Base(file.Name)
+	if !codec.IsBackupFile(name) {
+		s.logger.Debug("skipping file", slog.String("file", file.Name))
+		return nil
+	}
+
+	plain, err := codec.NewReader(file.Reader, s.sourceKey, s.sourceCompressed)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	defer plain.Close()
+
+	out, err := s.output.NewWriter(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+
+	encoded, err := codec.NewWriter(out, s.key, s.compression)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	// Records are counted while the decoded data is copied to the new file.
+	count, err := countRecords(name, io.TeeReader(plain, encoded))
+	if err != nil {
+		_ = encoded.Close()
+		return fmt.Errorf("failed to transcode %s: %w", file.Name, err)
+	}
+
+	if err = encoded.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", name, err)
+	}
+
+	s.counts[name] = count
+
+	s.logger.Debug("file transcoded",
+		slog.String("file", file.Name),
+		slog.Uint64("records", count.records),
+	)
+
+	return nil
+}
+
+// verify reads the transcoded backup with the new settings and compares the counters of its files
+// with the counters of the backup files.
+func (s *Service) verify(ctx context.Context) error {
+	reader, err := storage.NewReader(ctx, &s.cfg.ServiceConfigCommon, s.cfg.Transcode.OutputDirectory,
+		"", "", "", 0, false, true, s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to create reader for transcoded backup: %w", err)
+	}
+
+	counts := make(map[string]fileCount, len(s.counts))
+
+	err = codec.StreamFiles(ctx, reader, func(file bModels.File) error {
+		defer file.Reader.Close()
+
+		name := path.Base(file.Name)
+		if !codec.IsBackupFile(name) {
+			return nil
+		}
+
+		plain, err := codec.NewReader(file.Reader, s.key, s.compression != nil)
+		if err != nil {
+			return fmt.Errorf("failed to read transcoded %s: %w", file.Name, err)
+		}
+		defer plain.Close()
+
+		counts[name], err = countRecords(name, plain)
+		if err != nil {
+			return fmt.Errorf("failed to decode transcoded %s: %w", file.Name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return compareCounts(s.counts, counts)
+}
+
+// newManifest returns the manifest of the backup with the files, compression and encryption
+// of the transcoded backup. The start time and the incremental chain are kept, so the backup
+// can still be used as the base of incremental backups.
+func (s *Service) newManifest() *models.Manifest {
+	manifest := *s.manifest
+	manifest.AbsctlVersion = s.appVersion
+	manifest.Commit = s.commitHash
+	manifest.Created = time.Now().UTC()
+	manifest.Files = s.output.Files()
+	// The manifest is signed again with the new encryption key on save.
+	manifest.Signature = ""
+
+	manifest.Config.Compression = backup.CompressNone
+	if s.compression != nil {
+		manifest.Config.Compression = s.compression.Mode
+	}
+
+	manifest.Config.Encryption = backup.EncryptNone
+	if policy := s.cfg.Encryption.Policy(); policy != nil {
+		manifest.Config.Encryption = policy.Mode
+	}
+
+	manifest.Stats.BytesWritten = 0
+	for _, file := range manifest.Files {
+		manifest.Stats.BytesWritten += uint64(file.Size)
+	}
+
+	manifest.Stats.FilesWritten = uint64(len(manifest.Files))
+
+	return &manifest
+}
+
+// readManifest reads the manifest of the backup in the directory, nil if the backup has none.
+func readManifest(ctx context.Context, reader backup.StreamingReader, directory string) (*models.Manifest, error) {
+	objects, err := reader.ListObjects(ctx, directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	for _, object := range objects {
+		if path.Base(object) == models.ManifestFileName {
+			return storage.ReadManifest(ctx, reader, directory)
+		}
+	}
+
+	return nil, nil
+}
+
+// checkManifest checks that the source flags match the compression and encryption saved in the manifest.
+func checkManifest(manifest *models.Manifest, compression *models.Compression, encryption *models.Encryption) error {
+	if manifest == nil {
+		return nil
+	}
+
+	compressionMode := backup.CompressNone
+	if policy := compression.Policy(); policy != nil {
+		compressionMode = policy.Mode
+	}
+
+	if !strings.EqualFold(manifest.Config.Compression, compressionMode) {
+		return fmt.Errorf("backup is compressed with %s, but source-compress is %s",
+			manifest.Config.Compression, compressionMode)
+	}
+
+	encryptionMode := backup.EncryptNone
+	if policy := encryption.Policy(); policy != nil {
+		encryptionMode = policy.Mode
+	}
+
+	if !strings.EqualFold(manifest.Config.Encryption, encryptionMode) {
+		return fmt.Errorf("backup is encrypted with %s, but source-encrypt is %s",
+			manifest.Config.Encryption, encryptionMode)
+	}
+
+	return nil
+}
+
+// compareCounts checks that the transcoded backup has the same files as the backup,
+// with the same number of records and the same decoded size.
+func compareCounts(before, after map[string]fileCount) error {
+	names := make([]string, 0, len(before))
+	for name := range before {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var errs []error
+
+	for _, name := range names {
+		got, ok := after[name]
+
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("file %s is missing", name))
+		case got != before[name]:
+			errs = append(errs, fmt.Errorf("file %s has %d records of %d bytes, expected %d records of %d bytes",
+				name, got.records, got.bytes, before[name].records, before[name].bytes))
+		}
+	}
+
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			errs = append(errs, fmt.Errorf("file %s is unexpected", name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", errCountMismatch, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// countRecords decodes the file and counts its records and bytes.
+func countRecords(name string, r io.Reader) (fileCount, error) {
+	counter := &countingReader{Reader: r}
+
+	var (
+		count fileCount
+		err   error
+	)
+
+	if codec.IsASBX(name) {
+		count.records, err = countXDRRecords(name, counter)
+	} else {
+		count.records, err = countASBRecords(name, counter)
+	}
+
+	if err != nil {
+		return fileCount{}, err
+	}
+
+	// Read what the decoder left, so all data is counted and copied.
+	if _, err = io.Copy(io.Discard, counter); err != nil {
+		return fileCount{}, err
+	}
+
+	count.bytes = counter.n
+
+	return count, nil
+}
+
+// countASBRecords counts the records of an .asb file.
+func countASBRecords(name string, r io.Reader) (uint64, error) {
+	decoder, err := asb.NewDecoder[*bModels.Token](r, name, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create decoder: %w", err)
+	}
+
+	return countTokens(decoder, func(token *bModels.Token) bool {
+		return token.Type == bModels.TokenTypeRecord
+	})
+}
+
+// countXDRRecords counts the records of an .asbx file, every token is a record.
+func countXDRRecords(name string, r io.Reader) (uint64, error) {
+	decoder, err := codec.NewASBXDecoder(r, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create decoder: %w", err)
+	}
+
+	return countTokens(decoder, func(*bModels.ASBXToken) bool {
+		return true
+	})
+}
+
+// countTokens decodes the file to the end and counts the tokens isRecord reports as records.
+func countTokens[T any](decoder codec.TokenDecoder[T], isRecord func(T) bool) (uint64, error) {
+	var records uint64
+
+	err := codec.DecodeTokens(decoder, func(token T) error {
+		if isRecord(token) {
+			records++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return records, nil
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	io.Reader
+
+	n uint64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.n += uint64(n)
+
+	return n, err
+}
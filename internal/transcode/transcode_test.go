@@ -0,0 +1,101 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transcode
+
+import (
+	"testing"
+
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckManifest(t *testing.T) {
+	t.Parallel()
+
+	manifest := &models.Manifest{
+		Config: models.ManifestConfig{Compression: "ZSTD", Encryption: "AES256"},
+	}
+
+	tests := []struct {
+		name        string
+		manifest    *models.Manifest
+		compression *models.Compression
+		encryption  *models.Encryption
+		errMsg      string
+	}{
+		{
+			name:        "No manifest",
+			compression: &models.Compression{},
+			encryption:  &models.Encryption{},
+		},
+		{
+			name:        "Matching modes",
+			manifest:    manifest,
+			compression: &models.Compression{Mode: "zstd"},
+			encryption:  &models.Encryption{Mode: "aes256"},
+		},
+		{
+			name:        "Compression mismatch",
+			manifest:    manifest,
+			compression: &models.Compression{},
+			encryption:  &models.Encryption{Mode: "aes256"},
+			errMsg:      "backup is compressed with ZSTD, but source-compress is NONE",
+		},
+		{
+			name:        "Encryption mismatch",
+			manifest:    manifest,
+			compression: &models.Compression{Mode: "zstd"},
+			encryption:  &models.Encryption{Mode: "aes128"},
+			errMsg:      "backup is encrypted with AES256, but source-encrypt is AES128",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkManifest(tt.manifest, tt.compression, tt.encryption)
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCompareCounts(t *testing.T) {
+	t.Parallel()
+
+	before := map[string]fileCount{
+		"test_0.asb": {records: 0, bytes: 40},
+		"test_1.asb": {records: 10, bytes: 1000},
+	}
+
+	require.NoError(t, compareCounts(before, map[string]fileCount{
+		"test_0.asb": {records: 0, bytes: 40},
+		"test_1.asb": {records: 10, bytes: 1000},
+	}))
+
+	err := compareCounts(before, map[string]fileCount{
+		"test_1.asb": {records: 9, bytes: 900},
+		"test_2.asb": {records: 1, bytes: 100},
+	})
+	require.ErrorIs(t, err, errCountMismatch)
+	require.EqualError(t, err, "transcoded backup does not match the backup: file test_0.asb is missing\n"+
+		"file test_1.asb has 9 records of 900 bytes, expected 10 records of 1000 bytes\n"+
+		"file test_2.asb is unexpected")
+}
@@ -0,0 +1,525 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	particleType "github.com/aerospike/aerospike-client-go/v8/types/particle_type"
+)
+
+// Operation codes of the expression wire format, as sent by the Aerospike clients.
+// Only the operations below are evaluated.
+const (
+	opEQ           = 1
+	opNE           = 2
+	opGT           = 3
+	opGE           = 4
+	opLT           = 5
+	opLE           = 6
+	opRegex        = 7
+	opAnd          = 16
+	opOr           = 17
+	opNot          = 18
+	opDigestModulo = 64
+	opVoidTime     = 68
+	opTTL          = 69
+	opSetName      = 70
+	opKeyExists    = 71
+	opKey          = 80
+	opBin          = 81
+	opBinType      = 82
+)
+
+// citrusleafEpoch is the start of the Aerospike epoch, in which record void times are stored, in Unix seconds.
+const citrusleafEpoch = 1262304000
+
+// errUnsupported is returned for the parts of expressions that can't be evaluated against backup records.
+var errUnsupported = errors.New("unsupported")
+
+// rawString is a msgpack string, values are prefixed with their particle type, names are not.
+type rawString []byte
+
+// Expression is a filter expression, evaluated against the records read from backup files
+// instead of by the server.
+type Expression struct {
+	root node
+	// now returns the current time, for TTL expressions.
+	now func() time.Time
+}
+
+// node evaluates a part of the expression. ok is false if the value is unknown,
+// for example if a bin doesn't exist or has another type.
+type node func(e *env) (value any, ok bool)
+
+// env is the evaluation environment of an expression.
+type env struct {
+	rec *record
+	now time.Time
+}
+
+// NewExpression compiles the Base64 encoded expression, as produced by the Aerospike clients.
+// Comparisons, regular expressions, and, or and not of the bins, key, set, digest modulo
+// and expiration of records are supported.
+func NewExpression(encoded string) (*Expression, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode expression: %w", err)
+	}
+
+	d := &msgpackDecoder{data: data}
+
+	value, err := d.decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode expression: %w", err)
+	}
+
+	if d.off != len(data) {
+		return nil, fmt.Errorf("failed to decode expression: %d trailing bytes", len(data)-d.off)
+	}
+
+	root, err := compile(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{root: root, now: time.Now}, nil
+}
+
+// Match reports whether the record matches the expression.
+// As on the server, records for which the expression is unknown or not a boolean don't match.
+func (x *Expression) Match(rec *record) bool {
+	value, ok := x.root(&env{rec: rec, now: x.now()})
+	matched, isBool := value.(bool)
+
+	return ok && isBool && matched
+}
+
+func compile(value any) (node, error) {
+	if exp, ok := value.([]any); ok {
+		return compileOp(exp)
+	}
+
+	literal, err := literalValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(*env) (any, bool) { return literal, true }, nil
+}
+
+// literalValue converts a decoded value to the value it is evaluated to.
+func literalValue(value any) (any, error) {
+	switch v := value.(type) {
+	case nil, bool, int64, float64:
+		return v, nil
+	case rawString:
+		if len(v) == 0 {
+			return nil, fmt.Errorf("invalid value: missing particle type")
+		}
+
+		switch v[0] {
+		case particleType.STRING:
+			return string(v[1:]), nil
+		case particleType.BLOB:
+			return []byte(v[1:]), nil
+		default:
+			return nil, fmt.Errorf("values of particle type %d are %w", v[0], errUnsupported)
+		}
+	default:
+		return nil, fmt.Errorf("values of type %T are %w", v, errUnsupported)
+	}
+}
+
+func compileOp(exp []any) (node, error) {
+	if len(exp) == 0 {
+		return nil, fmt.Errorf("invalid expression: empty operation")
+	}
+
+	op, ok := exp[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("invalid expression: operation code is %T", exp[0])
+	}
+
+	args := exp[1:]
+
+	switch op {
+	case opEQ, opNE, opGT, opGE, opLT, opLE:
+		return compileCompare(op, args)
+	case opRegex:
+		return compileRegex(args)
+	case opAnd, opOr, opNot:
+		return compileLogical(op, args)
+	case opDigestModulo:
+		return compileDigestModulo(args)
+	case opVoidTime:
+		return compileMeta(args, func(e *env) (any, bool) {
+			switch {
+			case !e.rec.hasVoidTime:
+				return nil, false
+			case e.rec.voidTime == 0:
+				return int64(-1), true
+			default:
+				return (e.rec.voidTime + citrusleafEpoch) * int64(time.Second), true
+			}
+		})
+	case opTTL:
+		return compileMeta(args, func(e *env) (any, bool) {
+			switch {
+			case !e.rec.hasVoidTime:
+				return nil, false
+			case e.rec.voidTime == 0:
+				return int64(-1), true
+			default:
+				return e.rec.voidTime + citrusleafEpoch - e.now.Unix(), true
+			}
+		})
+	case opSetName:
+		return compileMeta(args, func(e *env) (any, bool) { return e.rec.set, true })
+	case opKeyExists:
+		return compileMeta(args, func(e *env) (any, bool) { return e.rec.key != nil, true })
+	case opKey:
+		return compileKey(args)
+	case opBin:
+		return compileBin(args)
+	case opBinType:
+		return compileBinType(args)
+	default:
+		return nil, fmt.Errorf("operation %d is %w", op, errUnsupported)
+	}
+}
+
+func compileAll(args []any) ([]node, error) {
+	nodes := make([]node, len(args))
+
+	for i, arg := range args {
+		var err error
+		if nodes[i], err = compile(arg); err != nil {
+			return nil, err
+		}
+	}
+
+	return nodes, nil
+}
+
+func checkArgs(op int64, args []any, minArgs, maxArgs int) error {
+	if len(args) < minArgs || (maxArgs >= 0 && len(args) > maxArgs) {
+		return fmt.Errorf("invalid expression: operation %d with %d arguments", op, len(args))
+	}
+
+	return nil
+}
+
+func compileMeta(args []any, n node) (node, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("invalid expression: record metadata with %d arguments", len(args))
+	}
+
+	return n, nil
+}
+
+func compileCompare(op int64, args []any) (node, error) {
+	if err := checkArgs(op, args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	nodes, err := compileAll(args)
+	if err != nil {
+		return nil, err
+	}
+
+	left, right := nodes[0], nodes[1]
+
+	return func(e *env) (any, bool) {
+		a, ok := left(e)
+		if !ok {
+			return nil, false
+		}
+
+		b, ok := right(e)
+		if !ok {
+			return nil, false
+		}
+
+		c, ok := compareValues(a, b)
+		if !ok {
+			return nil, false
+		}
+
+		switch op {
+		case opEQ:
+			return c == 0, true
+		case opNE:
+			return c != 0, true
+		case opGT:
+			return c > 0, true
+		case opGE:
+			return c >= 0, true
+		case opLT:
+			return c < 0, true
+		default:
+			return c <= 0, true
+		}
+	}, nil
+}
+
+// compareValues compares values of the same type, ok is false for values of different types.
+func compareValues(a, b any) (c int, ok bool) {
+	switch x := a.(type) {
+	case nil:
+		return 0, b == nil
+	case bool:
+		y, ok := b.(bool)
+
+		switch {
+		case !ok:
+			return 0, false
+		case x == y:
+			return 0, true
+		case y:
+			return -1, true
+		default:
+			return 1, true
+		}
+	case int64:
+		y, ok := b.(int64)
+		return cmp.Compare(x, y), ok
+	case float64:
+		y, ok := b.(float64)
+		return cmp.Compare(x, y), ok
+	case string:
+		y, ok := b.(string)
+		return strings.Compare(x, y), ok
+	case []byte:
+		y, ok := b.([]byte)
+		return bytes.Compare(x, y), ok
+	default:
+		return 0, false
+	}
+}
+
+// Regex flags of the expression wire format.
+const (
+	regexICase   = 1 << 1
+	regexNewline = 1 << 3
+)
+
+func compileRegex(args []any) (node, error) {
+	if err := checkArgs(opRegex, args, 3, 3); err != nil {
+		return nil, err
+	}
+
+	flags, ok := args[0].(int64)
+	pattern, isString := args[1].(rawString)
+
+	if !ok || !isString {
+		return nil, fmt.Errorf("invalid expression: regex arguments are %T and %T", args[0], args[1])
+	}
+
+	// The server uses POSIX regular expressions, where '.' matches new lines,
+	// unless the newline flag is set.
+	prefix := "(?s)"
+	if flags&regexNewline != 0 {
+		prefix = "(?m)"
+	}
+
+	if flags&regexICase != 0 {
+		prefix += "(?i)"
+	}
+
+	re, err := regexp.Compile(prefix + string(pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression regex: %w", err)
+	}
+
+	bin, err := compile(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return func(e *env) (any, bool) {
+		value, ok := bin(e)
+		s, isString := value.(string)
+
+		if !ok || !isString {
+			return nil, false
+		}
+
+		return re.MatchString(s), true
+	}, nil
+}
+
+func compileLogical(op int64, args []any) (node, error) {
+	maxArgs := -1
+	if op == opNot {
+		maxArgs = 1
+	}
+
+	if err := checkArgs(op, args, 1, maxArgs); err != nil {
+		return nil, err
+	}
+
+	nodes, err := compileAll(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(e *env) (any, bool) {
+		for _, n := range nodes {
+			value, ok := n(e)
+			b, isBool := value.(bool)
+
+			if !ok || !isBool {
+				return nil, false
+			}
+
+			switch {
+			case op == opNot:
+				return !b, true
+			case op == opAnd && !b:
+				return false, true
+			case op == opOr && b:
+				return true, true
+			}
+		}
+
+		return op == opAnd, true
+	}, nil
+}
+
+func compileDigestModulo(args []any) (node, error) {
+	if err := checkArgs(opDigestModulo, args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	modulo, ok := args[0].(int64)
+	if !ok || modulo <= 0 {
+		return nil, fmt.Errorf("invalid expression: digest modulo %v", args[0])
+	}
+
+	return func(e *env) (any, bool) {
+		if len(e.rec.digest) != digestLength {
+			return nil, false
+		}
+
+		// As on the server, the modulo of the last 4 bytes of the digest.
+		return int64(binary.LittleEndian.Uint32(e.rec.digest[16:]) % uint32(modulo)), true
+	}, nil
+}
+
+// Value types of the expression wire format.
+const (
+	expTypeBool   = 1
+	expTypeInt    = 2
+	expTypeString = 3
+	expTypeBlob   = 6
+	expTypeFloat  = 7
+)
+
+// expTypeParticles are the particle types of the values of each supported expression type.
+var expTypeParticles = map[int64]int{
+	expTypeBool:   particleType.BOOL,
+	expTypeInt:    particleType.INTEGER,
+	expTypeString: particleType.STRING,
+	expTypeBlob:   particleType.BLOB,
+	expTypeFloat:  particleType.FLOAT,
+}
+
+func compileKey(args []any) (node, error) {
+	if err := checkArgs(opKey, args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	expType, _ := args[0].(int64)
+	if expType != expTypeInt && expType != expTypeString && expType != expTypeBlob {
+		return nil, fmt.Errorf("keys of type %v are %w", args[0], errUnsupported)
+	}
+
+	return func(e *env) (any, bool) {
+		var ok bool
+
+		switch e.rec.key.(type) {
+		case int64:
+			ok = expType == expTypeInt
+		case string:
+			ok = expType == expTypeString
+		case []byte:
+			ok = expType == expTypeBlob
+		}
+
+		return e.rec.key, ok
+	}, nil
+}
+
+func compileBin(args []any) (node, error) {
+	if err := checkArgs(opBin, args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	expType, _ := args[0].(int64)
+	name, ok := args[1].(rawString)
+
+	if !ok {
+		return nil, fmt.Errorf("invalid expression: bin name is %T", args[1])
+	}
+
+	want, ok := expTypeParticles[expType]
+	if !ok {
+		return nil, fmt.Errorf("bins of type %v are %w, only bool, int, float, string and blob bins are",
+			args[0], errUnsupported)
+	}
+
+	return func(e *env) (any, bool) {
+		value, ok := e.rec.bins[string(name)]
+		if !ok {
+			return nil, false
+		}
+
+		if p, known := particle(value); !known || p != want {
+			return nil, false
+		}
+
+		return value, true
+	}, nil
+}
+
+func compileBinType(args []any) (node, error) {
+	if err := checkArgs(opBinType, args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	name, ok := args[0].(rawString)
+	if !ok {
+		return nil, fmt.Errorf("invalid expression: bin name is %T", args[0])
+	}
+
+	return func(e *env) (any, bool) {
+		if e.rec.bins == nil {
+			return nil, false
+		}
+
+		p, ok := particle(e.rec.bins[string(name)])
+
+		return int64(p), ok
+	}, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+	"time"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	particleType "github.com/aerospike/aerospike-client-go/v8/types/particle_type"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestExpression(t *testing.T, exp *a.Expression) *Expression {
+	t.Helper()
+
+	encoded, aErr := exp.Base64()
+	require.NoError(t, aErr)
+
+	compiled, err := NewExpression(encoded)
+	require.NoError(t, err)
+
+	compiled.now = func() time.Time { return time.Unix(citrusleafEpoch+1000, 0) }
+
+	return compiled
+}
+
+func testRecord() *record {
+	digest := make([]byte, 20)
+	digest[16] = 7
+
+	return &record{
+		key:         "user-1",
+		digest:      digest,
+		set:         "users",
+		voidTime:    1500,
+		hasVoidTime: true,
+		bins: map[string]any{
+			"tenant": "acme",
+			"age":    int64(42),
+			"score":  1.5,
+			"active": true,
+			"data":   []byte{1, 2},
+			"tags":   a.NewRawBlobValue(particleType.LIST, []byte{0x90}),
+		},
+	}
+}
+
+func TestExpression_Match(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		exp  *a.Expression
+		want bool
+	}{
+		{"string bin", a.ExpEq(a.ExpStringBin("tenant"), a.ExpStringVal("acme")), true},
+		{"other string", a.ExpEq(a.ExpStringBin("tenant"), a.ExpStringVal("other")), false},
+		{"missing bin", a.ExpEq(a.ExpStringBin("missing"), a.ExpStringVal("acme")), false},
+		{"bin of other type", a.ExpEq(a.ExpIntBin("tenant"), a.ExpIntVal(1)), false},
+		{"not of unknown", a.ExpNot(a.ExpEq(a.ExpIntBin("missing"), a.ExpIntVal(1))), false},
+		{"int range", a.ExpAnd(
+			a.ExpGreaterEq(a.ExpIntBin("age"), a.ExpIntVal(18)),
+			a.ExpLess(a.ExpIntBin("age"), a.ExpIntVal(65)),
+		), true},
+		{"float", a.ExpGreater(a.ExpFloatBin("score"), a.ExpFloatVal(1.0)), true},
+		{"bool", a.ExpEq(a.ExpBoolBin("active"), a.ExpBoolVal(true)), true},
+		{"blob", a.ExpEq(a.ExpBlobBin("data"), a.ExpBlobVal([]byte{1, 2})), true},
+		{"or", a.ExpOr(a.ExpEq(a.ExpIntBin("age"), a.ExpIntVal(1)), a.ExpBinExists("data")), true},
+		{"bin exists", a.ExpBinExists("tags"), true},
+		{"bin type", a.ExpEq(a.ExpBinType("tags"), a.ExpIntVal(particleType.LIST)), true},
+		{"bin not exists", a.ExpBinExists("missing"), false},
+		{"set name", a.ExpEq(a.ExpSetName(), a.ExpStringVal("users")), true},
+		{"key", a.ExpEq(a.ExpKey(a.ExpTypeSTRING), a.ExpStringVal("user-1")), true},
+		{"key of other type", a.ExpEq(a.ExpKey(a.ExpTypeINT), a.ExpIntVal(1)), false},
+		{"key exists", a.ExpKeyExists(), true},
+		{"digest modulo", a.ExpEq(a.ExpDigestModulo(3), a.ExpIntVal(1)), true},
+		{"void time", a.ExpEq(a.ExpVoidTime(), a.ExpIntVal((citrusleafEpoch+1500)*int64(time.Second))), true},
+		{"ttl", a.ExpEq(a.ExpTTL(), a.ExpIntVal(500)), true},
+		{"regex", a.ExpRegexCompare("^AC", a.ExpRegexFlagICASE, a.ExpStringBin("tenant")), true},
+		{"regex case", a.ExpRegexCompare("^AC", a.ExpRegexFlagNONE, a.ExpStringBin("tenant")), false},
+		{"not", a.ExpNot(a.ExpEq(a.ExpIntBin("age"), a.ExpIntVal(1))), true},
+		{"not a boolean", a.ExpIntBin("age"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, newTestExpression(t, tt.exp).Match(testRecord()))
+		})
+	}
+}
+
+func TestExpression_NeverExpires(t *testing.T) {
+	t.Parallel()
+
+	rec := testRecord()
+	rec.voidTime = 0
+
+	assert.True(t, newTestExpression(t, a.ExpEq(a.ExpTTL(), a.ExpIntVal(-1))).Match(rec))
+	assert.True(t, newTestExpression(t, a.ExpEq(a.ExpVoidTime(), a.ExpIntVal(-1))).Match(rec))
+}
+
+func TestExpression_UnknownMetadata(t *testing.T) {
+	t.Parallel()
+
+	// The expiration of asbx records is not stored, and deletes have no bins.
+	rec := testRecord()
+	rec.hasVoidTime = false
+	rec.bins = nil
+
+	for _, exp := range []*a.Expression{
+		a.ExpEq(a.ExpTTL(), a.ExpIntVal(-1)),
+		a.ExpNot(a.ExpEq(a.ExpTTL(), a.ExpIntVal(-1))),
+		a.ExpBinExists("age"),
+		a.ExpNot(a.ExpBinExists("age")),
+	} {
+		assert.False(t, newTestExpression(t, exp).Match(rec))
+	}
+
+	assert.True(t, newTestExpression(t, a.ExpEq(a.ExpSetName(), a.ExpStringVal("users"))).Match(rec))
+}
+
+func TestNewExpression_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		exp    *a.Expression
+		errMsg string
+	}{
+		{
+			name:   "last update",
+			exp:    a.ExpGreater(a.ExpLastUpdate(), a.ExpIntVal(0)),
+			errMsg: "operation 66 is unsupported",
+		},
+		{
+			name:   "arithmetic",
+			exp:    a.ExpEq(a.ExpNumAdd(a.ExpIntBin("age"), a.ExpIntVal(8)), a.ExpIntVal(50)),
+			errMsg: "operation 20 is unsupported",
+		},
+		{
+			name:   "let",
+			exp:    a.ExpLet(a.ExpDef("x", a.ExpIntBin("age")), a.ExpGreater(a.ExpVar("x"), a.ExpIntVal(40))),
+			errMsg: "operation 125 is unsupported",
+		},
+		{
+			name:   "list bin",
+			exp:    a.ExpGreater(a.ExpListSize(a.ExpListBin("tags")), a.ExpIntVal(0)),
+			errMsg: "operation 127 is unsupported",
+		},
+		{
+			name:   "geo bin",
+			exp:    a.ExpGeoCompare(a.ExpGeoBin("location"), a.ExpGeoVal(`{"type":"Point","coordinates":[1,2]}`)),
+			errMsg: "operation 8 is unsupported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, aErr := tt.exp.Base64()
+			require.NoError(t, aErr)
+
+			_, err := NewExpression(encoded)
+			require.ErrorIs(t, err, errUnsupported)
+			assert.Equal(t, tt.errMsg, err.Error())
+		})
+	}
+}
+
+func TestNewExpression_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, encoded := range []string{"not base64!", "kwGTUQKhYQ==", "kwGTUQKhYQEB"} {
+		_, err := NewExpression(encoded)
+		assert.Error(t, err, encoded)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// msgpackDecoder decodes the msgpack encoded expressions.
+// Arrays are decoded to []any, strings to rawString, integers to int64, and floats to float64.
+type msgpackDecoder struct {
+	data []byte
+	off  int
+}
+
+// maxDepth limits the nesting of decoded arrays.
+const maxDepth = 256
+
+func (d *msgpackDecoder) decode() (any, error) {
+	return d.decodeDepth(0)
+}
+
+//nolint:gocyclo // One case per msgpack type.
+func (d *msgpackDecoder) decodeDepth(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("nesting deeper than %d", maxDepth)
+	}
+
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := b[0]; {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c >= 0xa0 && c <= 0xbf:
+		return d.readString(int(c & 0x1f))
+	case c >= 0x90 && c <= 0x9f:
+		return d.readArray(int(c&0x0f), depth)
+	case c >= 0x80 && c <= 0x8f:
+		return nil, fmt.Errorf("maps are %w", errUnsupported)
+	}
+
+	switch b[0] {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xca:
+		v, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.readUint(8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := d.readUint(1 << (b[0] - 0xcc))
+		return int64(v), err
+	case 0xd0:
+		v, err := d.readUint(1)
+		return int64(int8(v)), err
+	case 0xd1:
+		v, err := d.readUint(2)
+		return int64(int16(v)), err
+	case 0xd2:
+		v, err := d.readUint(4)
+		return int64(int32(v)), err
+	case 0xd3:
+		v, err := d.readUint(8)
+		return int64(v), err
+	case 0xc4, 0xd9:
+		n, err := d.readUint(1)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.readString(int(n))
+	case 0xc5, 0xda:
+		n, err := d.readUint(2)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.readString(int(n))
+	case 0xc6, 0xdb:
+		n, err := d.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.readString(int(n))
+	case 0xdc:
+		n, err := d.readUint(2)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.readArray(int(n), depth)
+	case 0xdd:
+		n, err := d.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.readArray(int(n), depth)
+	case 0xde, 0xdf:
+		return nil, fmt.Errorf("maps are %w", errUnsupported)
+	default:
+		return nil, fmt.Errorf("msgpack type 0x%x is %w", b[0], errUnsupported)
+	}
+}
+
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.off < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b := d.data[d.off : d.off+n]
+	d.off += n
+
+	return b, nil
+}
+
+func (d *msgpackDecoder) readUint(n int) (uint64, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func (d *msgpackDecoder) readString(n int) (any, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return rawString(b), nil
+}
+
+func (d *msgpackDecoder) readArray(n, depth int) (any, error) {
+	// Each element takes at least one byte.
+	if n > len(d.data)-d.off {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	items := make([]any, n)
+
+	for i := range items {
+		var err error
+		if items[i], err = d.decodeDepth(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,443 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/aerospike/absctl/internal/checkpoint"
+	"github.com/aerospike/absctl/internal/codec"
+	a "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+// asbxHeaderSize is the size of the header of asbx files.
+const asbxHeaderSize = 44
+
+// Reader wraps backup.StreamingReader and transforms the asb and asbx files it streams: records that are not
+// targets or don't match the filter expression are removed, so they are not restored, and sets and bins are
+// renamed. Records of asb files restored by an earlier run of a resumed restore are removed too.
+type Reader struct {
+	backup.StreamingReader
+
+	// exp is nil if records are not filtered.
+	exp *Expression
+	// targets is nil if all records are restored.
+	targets *Targets
+	// renames is nil if nothing is renamed.
+	renames *Renames
+	// tracker is nil if the records are not tracked for a resumable restore.
+	tracker *checkpoint.Tracker
+	logger  *slog.Logger
+	// filtered is the number of removed records, shared with the readers returned by Wrap.
+	filtered *atomic.Uint64
+}
+
+// NewReader returns a Reader that keeps the targets among the records of r, filters them with the expression
+// and renames their sets and bins. The records are registered with the tracker, and those it restored
+// already are removed. exp, targets, renames and tracker may be nil.
+func NewReader(
+	r backup.StreamingReader,
+	exp *Expression,
+	targets *Targets,
+	renames *Renames,
+	tracker *checkpoint.Tracker,
+	logger *slog.Logger,
+) *Reader {
+	if renames.Empty() {
+		renames = nil
+	}
+
+	return &Reader{
+		StreamingReader: r,
+		exp:             exp,
+		targets:         targets,
+		renames:         renames,
+		tracker:         tracker,
+		logger:          logger,
+		filtered:        new(atomic.Uint64),
+	}
+}
+
+// Wrap returns a Reader that transforms the files of another reader in the same way,
+// for example the asbx files of a restore of asb and asbx files. Its removed records are counted with those of r.
+func (r *Reader) Wrap(other backup.StreamingReader) *Reader {
+	wrapped := *r
+	wrapped.StreamingReader = other
+
+	return &wrapped
+}
+
+// Filtered returns the number of records that were not targets or didn't match the expression so far.
+func (r *Reader) Filtered() uint64 {
+	return r.filtered.Load()
+}
+
+// Targets returns the records to restore, nil if all records are restored.
+func (r *Reader) Targets() *Targets {
+	return r.targets
+}
+
+// StreamFiles streams the files of the wrapped reader, transformed in the background while they are read.
+func (r *Reader) StreamFiles(
+	ctx context.Context, readersCh chan<- bModels.File, errorsCh chan<- error, skipPrefixes []string,
+) {
+	defer close(readersCh)
+
+	filesCh := make(chan bModels.File)
+
+	go r.StreamingReader.StreamFiles(ctx, filesCh, errorsCh, skipPrefixes)
+
+	for file := range filesCh {
+		transformed := r.transformFile(file)
+
+		select {
+		case <-ctx.Done():
+			_ = transformed.Reader.Close()
+			return
+		case readersCh <- transformed:
+		}
+	}
+}
+
+// transformFile returns the file with a reader of the transformed records.
+// Read errors are returned by the reader, so they fail the restore.
+func (r *Reader) transformFile(file bModels.File) bModels.File {
+	pr, pw := io.Pipe()
+	source, name := file.Reader, file.Name
+	// All files other than asbx files are asb files.
+	isASBX := codec.IsASBX(name)
+
+	// Files are claimed in the order they are streamed, the tracker knows them by their paths.
+	// The records of asbx files are not confirmed by the client, so they are restored with their files.
+	if r.tracker != nil && !isASBX {
+		name = r.tracker.Claim(name)
+	}
+
+	go func() {
+		out := bufio.NewWriter(pw)
+
+		var err error
+		if isASBX {
+			err = r.transformASBX(out, source, name)
+		} else {
+			err = r.transformASB(out, source, name)
+		}
+
+		if err == nil {
+			err = out.Flush()
+		}
+
+		_ = source.Close()
+		pw.CloseWithError(err)
+	}()
+
+	file.Reader = pr
+
+	return file
+}
+
+// transformASB writes the transformed records of an asb file to w. The header is written unchanged,
+// the records are decoded and encoded again by backup-go.
+func (r *Reader) transformASB(w io.Writer, source io.Reader, name string) error {
+	in := bufio.NewReader(source)
+
+	header, err := readASBHeader(in)
+	if err != nil {
+		return fmt.Errorf("failed to read header of %s: %w", name, err)
+	}
+
+	if _, err = w.Write(header); err != nil {
+		return err
+	}
+
+	decoder, err := asb.NewDecoder[*bModels.Token](io.MultiReader(bytes.NewReader(header), in), name, false, r.logger)
+	if err != nil {
+		return err
+	}
+
+	encoder := asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig("", false, false))
+
+	// restored is the number of records restored by an earlier run, resumed is the number of them read so far.
+	var restored, resumed uint64
+	if r.tracker != nil {
+		restored, _ = r.tracker.Restored(name)
+	}
+
+	var (
+		stats fileStats
+		buf   bytes.Buffer
+	)
+
+	for i := 1; ; {
+		// Once all targets are found, the rest of the file is not read. Files start with their metadata,
+		// so they are read up to their first record.
+		if i > 1 && r.targetsDone() {
+			break
+		}
+
+		token, err := decoder.NextToken()
+
+		switch {
+		case errors.Is(err, io.EOF):
+			r.finishFile(name, stats)
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to read record %d of %s: %w", i, name, err)
+		case token.Type == bModels.TokenTypeRecord:
+			if r.targetsDone() {
+				r.finishFile(name, stats)
+				return nil
+			}
+
+			i++
+
+			// Targets restored by an earlier run are found too.
+			found := r.targets == nil || r.targets.find(token.Record.Key.Digest())
+
+			if resumed < restored {
+				resumed++
+				continue
+			}
+
+			if !found || r.exp != nil && !r.exp.Match(newRecord(token.Record)) {
+				stats.filtered++
+				r.filtered.Add(1)
+				r.removed(name)
+
+				continue
+			}
+
+			if r.renames != nil {
+				keptDigest, err := r.renames.renameRecord(token.Record)
+				if err != nil {
+					return fmt.Errorf("failed to rename record %d of %s: %w", i-1, name, err)
+				}
+
+				if keptDigest {
+					stats.keptDigests++
+				}
+			}
+
+			r.passed(name, token.Record)
+		case token.Type == bModels.TokenTypeSIndex && r.renames != nil:
+			r.renames.renameSIndex(token.SIndex)
+		}
+
+		buf.Reset()
+
+		if err = encoder.EncodeToken(token, &buf); err != nil {
+			return err
+		}
+
+		if _, err = w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	r.finishFile(name, stats)
+
+	return nil
+}
+
+// readASBHeader reads the version and metadata lines that start an asb file.
+func readASBHeader(r *bufio.Reader) ([]byte, error) {
+	var header []byte
+
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		header = append(header, line...)
+
+		next, err := r.Peek(1)
+		if err != nil || next[0] != '#' {
+			return header, nil
+		}
+	}
+}
+
+// transformASBX writes the transformed records of an asbx file to w. The header is written unchanged.
+// The payloads of the records are only decoded to filter or rename them.
+func (r *Reader) transformASBX(w io.Writer, source io.Reader, name string) error {
+	header := make([]byte, asbxHeaderSize)
+	if _, err := io.ReadFull(source, header); err != nil {
+		return fmt.Errorf("failed to read header of %s: %w", name, err)
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	fileNumber := binary.BigEndian.Uint64(header[1:9])
+
+	decoder, err := asbx.NewDecoder[*bModels.ASBXToken](io.MultiReader(bytes.NewReader(header), source), fileNumber, name)
+	if err != nil {
+		return err
+	}
+
+	encoder := asbx.NewEncoder[*bModels.ASBXToken]("")
+
+	var (
+		stats fileStats
+		buf   bytes.Buffer
+	)
+
+	for i := 1; !r.targetsDone(); i++ {
+		token, err := decoder.NextToken()
+
+		switch {
+		case errors.Is(err, io.EOF):
+			r.finishFile(name, stats)
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to read record %d of %s: %w", i, name, err)
+		}
+
+		token, err = r.transformXDRToken(token, &stats)
+
+		switch {
+		case err != nil:
+			return fmt.Errorf("failed to transform record %d of %s: %w", i, name, err)
+		case token == nil:
+			stats.filtered++
+			r.filtered.Add(1)
+
+			continue
+		}
+
+		buf.Reset()
+
+		if err = encoder.EncodeToken(token, &buf); err != nil {
+			return err
+		}
+
+		if _, err = w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	r.finishFile(name, stats)
+
+	return nil
+}
+
+// transformXDRToken returns the transformed asbx record, nil if it is removed.
+func (r *Reader) transformXDRToken(token *bModels.ASBXToken, stats *fileStats) (*bModels.ASBXToken, error) {
+	digest := token.Key.Digest()
+
+	if r.targets != nil && !r.targets.find(digest) {
+		return nil, nil
+	}
+
+	if r.exp == nil && r.renames == nil {
+		return token, nil
+	}
+
+	msg, err := parseXDRMessage(token.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.exp != nil && !r.exp.Match(msg.record(digest)) {
+		return nil, nil
+	}
+
+	if r.renames == nil {
+		return token, nil
+	}
+
+	newDigest, keptDigest, err := r.renames.renameXDRMessage(msg, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	if keptDigest {
+		stats.keptDigests++
+	}
+
+	payload, err := msg.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := a.NewKeyWithDigest(token.Key.Namespace(), "", "", newDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	return bModels.NewASBXToken(key, payload), nil
+}
+
+// targetsDone checks if reading records can stop, as all targets are found.
+func (r *Reader) targetsDone() bool {
+	return r.targets != nil && r.targets.done()
+}
+
+// removed registers a removed record with the tracker.
+func (r *Reader) removed(name string) {
+	if r.tracker != nil {
+		r.tracker.Removed(name)
+	}
+}
+
+// passed registers a record passed to the restore with the tracker, with its restored set, bins and digest.
+func (r *Reader) passed(name string, rec *bModels.Record) {
+	if r.tracker == nil {
+		return
+	}
+
+	r.tracker.Passed(name, &checkpoint.Record{
+		Digest:   rec.Key.Digest(),
+		Set:      rec.Key.SetName(),
+		Bins:     newRecord(rec).binNames(),
+		VoidTime: rec.VoidTime,
+	})
+}
+
+// fileStats are the numbers of transformed records of a file.
+type fileStats struct {
+	filtered    uint64
+	keptDigests uint64
+}
+
+// finishFile registers the end of the records of the file with the tracker, and logs what was transformed.
+func (r *Reader) finishFile(name string, stats fileStats) {
+	if r.tracker != nil {
+		r.tracker.EOF(name)
+	}
+
+	if r.exp != nil || r.targets != nil {
+		r.logger.Debug("filtered file", slog.String("file", name), slog.Uint64("filtered", stats.filtered))
+	}
+
+	if stats.keptDigests > 0 {
+		r.logger.Warn("records without a stored key keep the digest of their old set",
+			slog.String("file", name), slog.Uint64("records", stats.keptDigests))
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/checkpoint"
+	a "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFilesReader streams files from memory.
+type testFilesReader struct {
+	backup.StreamingReader
+
+	files map[string]string
+}
+
+func (r *testFilesReader) StreamFiles(
+	_ context.Context, readersCh chan<- bModels.File, _ chan<- error, _ []string,
+) {
+	defer close(readersCh)
+
+	for name, content := range r.files {
+		readersCh <- bModels.File{Name: name, Reader: io.NopCloser(strings.NewReader(content))}
+	}
+}
+
+// readFiles streams the files of the reader and returns their content by name.
+func readFiles(t *testing.T, r backup.StreamingReader) (map[string]string, error) {
+	t.Helper()
+
+	readersCh := make(chan bModels.File)
+	errorsCh := make(chan error, 1)
+
+	go r.StreamFiles(t.Context(), readersCh, errorsCh, nil)
+
+	files := make(map[string]string)
+
+	for file := range readersCh {
+		data, err := io.ReadAll(file.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		files[file.Name] = string(data)
+	}
+
+	return files, nil
+}
+
+// asbHeader is the header of the first asb file of the namespace test.
+const asbHeader = "Version 3.1\n# namespace test\n# first-file\n"
+
+// encodeASB returns the tokens encoded by the asb encoder of backup-go.
+func encodeASB(t *testing.T, tokens ...*bModels.Token) string {
+	t.Helper()
+
+	encoder := asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig("test", false, false))
+
+	var buf bytes.Buffer
+	for _, token := range tokens {
+		require.NoError(t, encoder.EncodeToken(token, &buf))
+	}
+
+	return buf.String()
+}
+
+// recordToken returns the token of a record of the namespace test, without a stored key if key is nil.
+func recordToken(t *testing.T, set string, key any, bins a.BinMap) *bModels.Token {
+	t.Helper()
+
+	return bModels.NewRecordToken(testASBRecord(t, set, key, bins), 0, nil)
+}
+
+// sindexToken returns the token of the numeric secondary index idx_age.
+func sindexToken(set, bin string) *bModels.Token {
+	return bModels.NewSIndexToken(&bModels.SIndex{
+		Namespace: "test",
+		Set:       set,
+		Name:      "idx_age",
+		Path:      bModels.SIndexPath{BinName: bin, BinType: bModels.NumericSIDataType},
+		IndexType: bModels.BinSIndex,
+	}, 0)
+}
+
+// userRecord returns a record of the users set with an int key and an age bin.
+func userRecord(t *testing.T, key, age int64) *bModels.Token {
+	t.Helper()
+
+	return recordToken(t, "users", key, a.BinMap{"age": age})
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	header := asbHeader + encodeASB(t, sindexToken("users", "age"))
+	acme := recordToken(t, "users", nil, a.BinMap{"tenant": "acme"})
+	other := recordToken(t, "users", nil, a.BinMap{"tenant": "other"})
+
+	source := &testFilesReader{files: map[string]string{
+		"test_0.asb": header + encodeASB(t, acme, other, acme),
+		"test_1.asb": header + encodeASB(t, other),
+	}}
+
+	reader := NewReader(source, newTestExpression(t, a.ExpEq(a.ExpStringBin("tenant"), a.ExpStringVal("acme"))),
+		nil, nil, nil, slog.Default())
+
+	files, err := readFiles(t, reader)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"test_0.asb": header + encodeASB(t, acme, acme),
+		"test_1.asb": header,
+	}, files)
+	assert.Equal(t, uint64(2), reader.Filtered())
+}
+
+func TestReader_InvalidFile(t *testing.T) {
+	t.Parallel()
+
+	source := &testFilesReader{files: map[string]string{
+		"test_0.asb": "Version 3.1\n+ n test\n+ b 1\n- I age x\n",
+	}}
+
+	_, err := readFiles(t, NewReader(source, newTestExpression(t, a.ExpKeyExists()), nil, nil, nil, slog.Default()))
+	require.ErrorContains(t, err, "failed to read record 1 of test_0.asb")
+}
+
+func TestReader_Renames(t *testing.T) {
+	t.Parallel()
+
+	source := &testFilesReader{files: map[string]string{
+		"test_0.asb": asbHeader + encodeASB(t,
+			sindexToken("prod", "age"),
+			recordToken(t, "prod", nil, a.BinMap{"age": int64(42)}),
+			recordToken(t, "other", nil, a.BinMap{"age": int64(7)}),
+			recordToken(t, "prod", "user-1", a.BinMap{"age": int64(30)}),
+		),
+	}}
+	renames := &Renames{Sets: map[string]string{"prod": "qa set"}, Bins: map[string]string{"age": "years"}}
+
+	// The filter expression uses the old names.
+	reader := NewReader(source, newTestExpression(t, a.ExpGreater(a.ExpIntBin("age"), a.ExpIntVal(10))),
+		nil, renames, nil, slog.Default())
+
+	files, err := readFiles(t, reader)
+	require.NoError(t, err)
+
+	// The record without a stored key keeps its digest.
+	withoutKey := recordToken(t, "prod", nil, a.BinMap{"years": int64(42)})
+	withoutKey.Record.Key, _ = a.NewKeyWithDigest("test", "qa set", nil, make([]byte, digestLength))
+
+	assert.Equal(t, map[string]string{
+		"test_0.asb": asbHeader + encodeASB(t,
+			sindexToken("qa set", "years"),
+			withoutKey,
+			recordToken(t, "qa set", "user-1", a.BinMap{"years": int64(30)}),
+		),
+	}, files)
+	assert.Equal(t, uint64(1), reader.Filtered())
+}
+
+func TestReader_Targets(t *testing.T) {
+	t.Parallel()
+
+	source := &testFilesReader{files: map[string]string{
+		"test_0.asb": asbHeader + encodeASB(t, userRecord(t, 1, 10), userRecord(t, 2, 20)),
+		"test_1.asb": asbHeader + encodeASB(t, userRecord(t, 3, 30), userRecord(t, 4, 40)),
+	}}
+
+	targets, err := LoadTargets("", writeFile(t, "users:int:1\nusers:int:4\nusers:int:5\n"))
+	require.NoError(t, err)
+
+	reader := NewReader(source, nil, targets, nil, nil, slog.Default())
+
+	files, err := readFiles(t, reader)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"test_0.asb": asbHeader + encodeASB(t, userRecord(t, 1, 10)),
+		"test_1.asb": asbHeader + encodeASB(t, userRecord(t, 4, 40)),
+	}, files)
+	assert.Equal(t, uint64(2), reader.Filtered())
+	assert.Equal(t, []string{"users:int:5"}, reader.Targets().NotFound())
+}
+
+func TestReader_TargetsStopWhenFound(t *testing.T) {
+	t.Parallel()
+
+	header := asbHeader + encodeASB(t, sindexToken("users", "age"))
+	// Records after the last target are not read.
+	source := &testFilesReader{files: map[string]string{
+		"test_0.asb": header + encodeASB(t, userRecord(t, 1, 10)) + "+ n test\n+ b 1\n- I age x\n",
+	}}
+
+	targets, err := LoadTargets("", writeFile(t, "users:int:1\n"))
+	require.NoError(t, err)
+
+	targets.StopWhenFound = true
+
+	files, err := readFiles(t, NewReader(source, nil, targets, nil, nil, slog.Default()))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"test_0.asb": header + encodeASB(t, userRecord(t, 1, 10))}, files)
+	assert.Empty(t, targets.NotFound())
+}
+
+func TestReader_Resume(t *testing.T) {
+	t.Parallel()
+
+	header := asbHeader + encodeASB(t, sindexToken("users", "age"))
+	source := &testFilesReader{files: map[string]string{
+		"test_0.asb": header + encodeASB(t,
+			userRecord(t, 1, 10), userRecord(t, 2, 20), userRecord(t, 3, 5), userRecord(t, 4, 40)),
+	}}
+
+	state := `{"version": 1, "source": "backup", "files": {"backup/test_0.asb": {"records": 1}}}`
+
+	previous, err := checkpoint.ReadState(strings.NewReader(state), "restore.state")
+	require.NoError(t, err)
+
+	tracker, err := checkpoint.NewTracker(nil, "restore.state", "backup", previous)
+	require.NoError(t, err)
+
+	dirs := []checkpoint.Dir{{Path: "backup", Reader: source}}
+	reader := NewReader(checkpoint.NewReader(source, dirs, tracker, slog.Default()),
+		newTestExpression(t, a.ExpGreater(a.ExpIntBin("age"), a.ExpIntVal(10))), nil, nil, tracker, slog.Default())
+
+	files, err := readFiles(t, reader)
+	require.NoError(t, err)
+
+	// The record restored by the earlier run is removed, the metadata is restored again.
+	assert.Equal(t, map[string]string{
+		"test_0.asb": header + encodeASB(t, userRecord(t, 2, 20), userRecord(t, 4, 40)),
+	}, files)
+	assert.Equal(t, uint64(1), reader.Filtered())
+
+	// The filtered record is restored once the record before it is.
+	assert.Equal(t, checkpoint.FileState{Records: 1}, tracker.State().Files["backup/test_0.asb"])
+
+	tracker.Applied(testDigest(t, "users", int64(2)))
+	assert.Equal(t, checkpoint.FileState{Records: 3}, tracker.State().Files["backup/test_0.asb"])
+
+	tracker.Applied(testDigest(t, "users", int64(4)))
+	assert.Equal(t, checkpoint.FileState{Done: true}, tracker.State().Files["backup/test_0.asb"])
+}
+
+// encodeASBX returns an asbx file with the header of file number 1 and records that write or delete the keys.
+func encodeASBX(t *testing.T, records ...*bModels.ASBXToken) string {
+	t.Helper()
+
+	encoder := asbx.NewEncoder[*bModels.ASBXToken]("test")
+	buf := bytes.NewBuffer(encoder.GetHeader(1, true))
+
+	for _, token := range records {
+		require.NoError(t, encoder.EncodeToken(token, buf))
+	}
+
+	return buf.String()
+}
+
+// xdrToken returns an asbx record of the namespace test with an int key.
+func xdrToken(t *testing.T, set string, key int64, isDelete bool, bins ...xdrBin) *bModels.ASBXToken {
+	t.Helper()
+
+	k, aErr := a.NewKey("test", set, key)
+	require.NoError(t, aErr)
+
+	return bModels.NewASBXToken(k, testXDRPayload(t, k, isDelete, bins...))
+}
+
+func TestReader_ASBX(t *testing.T) {
+	t.Parallel()
+
+	source := &testFilesReader{files: map[string]string{
+		"test_1.asbx": encodeASBX(t,
+			xdrToken(t, "users", 1, false, xdrBin{"age", int64(42)}),
+			xdrToken(t, "users", 2, false, xdrBin{"age", int64(5)}),
+			xdrToken(t, "users", 3, true),
+			xdrToken(t, "other", 4, false, xdrBin{"age", int64(30)}),
+		),
+	}}
+	renames := &Renames{Sets: map[string]string{"users": "members"}, Bins: map[string]string{"age": "years"}}
+
+	reader := NewReader(source, newTestExpression(t, a.ExpGreater(a.ExpIntBin("age"), a.ExpIntVal(10))),
+		nil, renames, nil, slog.Default()).Wrap(source)
+
+	files, err := readFiles(t, reader)
+	require.NoError(t, err)
+
+	// The delete is removed, as its bins are unknown.
+	assert.Equal(t, map[string]string{
+		"test_1.asbx": encodeASBX(t,
+			xdrToken(t, "members", 1, false, xdrBin{"years", int64(42)}),
+			xdrToken(t, "other", 4, false, xdrBin{"years", int64(30)}),
+		),
+	}, files)
+	assert.Equal(t, uint64(2), reader.Filtered())
+}
+
+func TestReader_ASBXTargets(t *testing.T) {
+	t.Parallel()
+
+	source := &testFilesReader{files: map[string]string{
+		"test_1.asbx": encodeASBX(t,
+			xdrToken(t, "users", 1, false, xdrBin{"age", int64(42)}),
+			xdrToken(t, "users", 2, true),
+			xdrToken(t, "users", 3, false, xdrBin{"age", int64(30)}),
+		),
+	}}
+
+	targets, err := LoadTargets("", writeFile(t, "users:int:2\nusers:int:3\n"))
+	require.NoError(t, err)
+
+	reader := NewReader(source, nil, targets, nil, nil, slog.Default())
+
+	files, err := readFiles(t, reader)
+	require.NoError(t, err)
+
+	// Payloads are restored unchanged.
+	assert.Equal(t, map[string]string{
+		"test_1.asbx": encodeASBX(t,
+			xdrToken(t, "users", 2, true),
+			xdrToken(t, "users", 3, false, xdrBin{"age", int64(30)}),
+		),
+	}, files)
+	assert.Equal(t, uint64(1), reader.Filtered())
+	assert.Empty(t, targets.NotFound())
+}
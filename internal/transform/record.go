@@ -0,0 +1,108 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"slices"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	particleType "github.com/aerospike/aerospike-client-go/v8/types/particle_type"
+	"github.com/aerospike/backup-go/models"
+)
+
+// unknownValue is the value of a bin that is changed by an operation other than a write,
+// in a record of an asbx file. Expressions on such bins are unknown.
+type unknownValue struct{}
+
+// record is a record of an asb or asbx file, as it is seen by expressions.
+type record struct {
+	// key is the stored user key, an int64, string or []byte. It is nil if the key is not stored.
+	key    any
+	digest []byte
+	set    string
+	// voidTime is the expiration in seconds since the Aerospike epoch, 0 if the record never expires.
+	voidTime int64
+	// hasVoidTime is false if the expiration is unknown, as for the records of asbx files.
+	hasVoidTime bool
+	// bins are the values of the bins, as decoded by backup-go. They are nil if unknown, as for deletes.
+	bins map[string]any
+}
+
+// newRecord returns the record of a decoded asb record.
+func newRecord(rec *models.Record) *record {
+	return &record{
+		key:         userKey(rec.Key),
+		digest:      rec.Key.Digest(),
+		set:         rec.Key.SetName(),
+		voidTime:    rec.VoidTime,
+		hasVoidTime: true,
+		bins:        rec.Bins,
+	}
+}
+
+// userKey returns the stored user key of the key, nil if it is not stored or of an unsupported type.
+func userKey(key *a.Key) any {
+	if key.Value() == nil {
+		return nil
+	}
+
+	switch v := key.Value().GetObject().(type) {
+	case int:
+		return int64(v)
+	case int64, string, []byte:
+		return v
+	default:
+		return nil
+	}
+}
+
+// binNames returns the sorted names of the bins of the record.
+func (r *record) binNames() []string {
+	names := make([]string, 0, len(r.bins))
+	for name := range r.bins {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// particle returns the particle type of a bin value, NULL if the bin doesn't exist.
+// ok is false if the type is unknown.
+func particle(value any) (particle int, ok bool) {
+	switch v := value.(type) {
+	case nil:
+		return particleType.NULL, true
+	case int64:
+		return particleType.INTEGER, true
+	case float64:
+		return particleType.FLOAT, true
+	case string:
+		return particleType.STRING, true
+	case []byte:
+		return particleType.BLOB, true
+	case bool:
+		return particleType.BOOL, true
+	case a.HLLValue:
+		return particleType.HLL, true
+	case a.GeoJSONValue:
+		return particleType.GEOJSON, true
+	case *a.RawBlobValue:
+		return v.ParticleType, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go/io/aerospike/xdr"
+	"github.com/aerospike/backup-go/models"
+)
+
+// Renames maps old to new set and bin names.
+type Renames struct {
+	Sets map[string]string
+	Bins map[string]string
+}
+
+// Empty checks if nothing is renamed.
+func (rn *Renames) Empty() bool {
+	return rn == nil || (len(rn.Sets) == 0 && len(rn.Bins) == 0)
+}
+
+// renameRecord renames the set and bins of an asb record. As the digest depends on the set,
+// it is recalculated for records with a stored key. keptDigest is true if the set of a record without
+// a stored key is renamed, so the record keeps the digest of the old set.
+func (rn *Renames) renameRecord(rec *models.Record) (keptDigest bool, err error) {
+	key := rec.Key
+
+	if to, ok := rn.Sets[key.SetName()]; ok {
+		var newKey *a.Key
+
+		if value := userKey(key); value != nil {
+			newKey, err = a.NewKey(key.Namespace(), to, value)
+		} else {
+			keptDigest = true
+			newKey, err = a.NewKeyWithDigest(key.Namespace(), to, nil, key.Digest())
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("failed to calculate digest: %w", err)
+		}
+
+		rec.Key = newKey
+	}
+
+	if len(rn.Bins) == 0 {
+		return keptDigest, nil
+	}
+
+	bins := make(a.BinMap, len(rec.Bins))
+	names := make(map[string]string, len(rec.Bins))
+
+	for name, value := range rec.Bins {
+		newName := rn.binName(name)
+		if from, ok := names[newName]; ok {
+			return false, duplicateBinError(from, name, newName)
+		}
+
+		names[newName] = name
+		bins[newName] = value
+	}
+
+	rec.Bins = bins
+
+	return keptDigest, nil
+}
+
+// renameXDRMessage renames the set and bins of the message of an asbx record, and returns the digest
+// of the renamed record. keptDigest is true if the set of a record without a stored key is renamed.
+func (rn *Renames) renameXDRMessage(msg *xdrMessage, digest []byte) (newDigest []byte, keptDigest bool, err error) {
+	newDigest = digest
+
+	if to, ok := rn.Sets[string(msg.field(xdr.FieldTypeSet))]; ok {
+		msg.setField(xdr.FieldTypeSet, []byte(to))
+
+		if value := msg.userKey(); value != nil {
+			key, err := a.NewKey(string(msg.field(xdr.FieldTypeNamespace)), to, value)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to calculate digest: %w", err)
+			}
+
+			newDigest = key.Digest()
+			msg.setField(xdr.FieldTypeDigest, newDigest)
+		} else {
+			keptDigest = true
+		}
+	}
+
+	names := make(map[string]string, len(msg.ops))
+
+	for i, op := range msg.ops {
+		newName := rn.binName(op.name)
+		if from, ok := names[newName]; ok && from != op.name {
+			return nil, false, duplicateBinError(from, op.name, newName)
+		}
+
+		names[newName] = op.name
+		msg.ops[i].name = newName
+	}
+
+	return newDigest, keptDigest, nil
+}
+
+// renameSIndex renames the set and bin of a secondary index.
+func (rn *Renames) renameSIndex(index *models.SIndex) {
+	if to, ok := rn.Sets[index.Set]; ok {
+		index.Set = to
+	}
+
+	index.Path.BinName = rn.binName(index.Path.BinName)
+}
+
+// binName returns the new name of the bin.
+func (rn *Renames) binName(name string) string {
+	if to, ok := rn.Bins[name]; ok {
+		return to
+	}
+
+	return name
+}
+
+func duplicateBinError(from, other, name string) error {
+	if other < from {
+		from, other = other, from
+	}
+
+	return fmt.Errorf("bins %s and %s both have the name %s after renaming", from, other, name)
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testASBRecord returns a record of the namespace test.
+func testASBRecord(t *testing.T, set string, key any, bins a.BinMap) *models.Record {
+	t.Helper()
+
+	var (
+		k    *a.Key
+		aErr a.Error
+	)
+
+	if key != nil {
+		k, aErr = a.NewKey("test", set, key)
+	} else {
+		k, aErr = a.NewKeyWithDigest("test", set, nil, make([]byte, digestLength))
+	}
+
+	require.NoError(t, aErr)
+
+	return &models.Record{Record: &a.Record{Key: k, Bins: bins, Generation: 1}}
+}
+
+func TestRenames_RenameRecord(t *testing.T) {
+	t.Parallel()
+
+	newKey, aErr := a.NewKey("test", "qa", "user-1")
+	require.NoError(t, aErr)
+
+	rec := testASBRecord(t, "prod", "user-1", a.BinMap{"a": int64(1), "b": "x"})
+	renames := &Renames{Sets: map[string]string{"prod": "qa"}, Bins: map[string]string{"a": "b", "b": "c"}}
+
+	keptDigest, err := renames.renameRecord(rec)
+	require.NoError(t, err)
+	assert.False(t, keptDigest)
+	assert.Equal(t, "qa", rec.Key.SetName())
+	assert.Equal(t, newKey.Digest(), rec.Key.Digest())
+	assert.Equal(t, "user-1", userKey(rec.Key))
+	assert.Equal(t, a.BinMap{"b": int64(1), "c": "x"}, rec.Bins)
+}
+
+func TestRenames_RenameRecordWithoutKey(t *testing.T) {
+	t.Parallel()
+
+	rec := testASBRecord(t, "prod", nil, a.BinMap{})
+
+	keptDigest, err := (&Renames{Sets: map[string]string{"prod": "qa"}}).renameRecord(rec)
+	require.NoError(t, err)
+	assert.True(t, keptDigest)
+	assert.Equal(t, "qa", rec.Key.SetName())
+	assert.Equal(t, make([]byte, digestLength), rec.Key.Digest())
+	assert.Nil(t, userKey(rec.Key))
+
+	// Records of other sets are not changed.
+	keptDigest, err = (&Renames{Sets: map[string]string{"other": "x"}}).renameRecord(rec)
+	require.NoError(t, err)
+	assert.False(t, keptDigest)
+	assert.Equal(t, "qa", rec.Key.SetName())
+}
+
+func TestRenames_RenameRecordDuplicateBin(t *testing.T) {
+	t.Parallel()
+
+	rec := testASBRecord(t, "", nil, a.BinMap{"a": int64(1), "b": int64(2)})
+
+	_, err := (&Renames{Bins: map[string]string{"a": "b"}}).renameRecord(rec)
+	require.EqualError(t, err, "bins a and b both have the name b after renaming")
+}
+
+func TestRenames_RenameXDRMessage(t *testing.T) {
+	t.Parallel()
+
+	key, aErr := a.NewKey("test", "prod", int64(7))
+	require.NoError(t, aErr)
+
+	newKey, aErr := a.NewKey("test", "qa", int64(7))
+	require.NoError(t, aErr)
+
+	msg, err := parseXDRMessage(testXDRPayload(t, key, false, xdrBin{"a", int64(1)}, xdrBin{"b", "x"}))
+	require.NoError(t, err)
+
+	renames := &Renames{Sets: map[string]string{"prod": "qa"}, Bins: map[string]string{"a": "b", "b": "c"}}
+
+	digest, keptDigest, err := renames.renameXDRMessage(msg, key.Digest())
+	require.NoError(t, err)
+	assert.False(t, keptDigest)
+	assert.Equal(t, newKey.Digest(), digest)
+
+	payload, err := msg.payload()
+	require.NoError(t, err)
+	assert.Equal(t, testXDRPayload(t, newKey, false, xdrBin{"b", int64(1)}, xdrBin{"c", "x"}), payload)
+
+	_, _, err = (&Renames{Bins: map[string]string{"b": "c"}}).renameXDRMessage(msg, digest)
+	require.EqualError(t, err, "bins b and c both have the name c after renaming")
+}
+
+func TestRenames_RenameSIndex(t *testing.T) {
+	t.Parallel()
+
+	index := &models.SIndex{
+		Namespace: "test",
+		Set:       "prod",
+		Name:      "idx_age",
+		Path:      models.SIndexPath{BinName: "age", BinType: models.NumericSIDataType},
+		IndexType: models.BinSIndex,
+	}
+
+	(&Renames{Sets: map[string]string{"prod": "qa"}, Bins: map[string]string{"age": "years"}}).renameSIndex(index)
+	assert.Equal(t, "qa", index.Set)
+	assert.Equal(t, "years", index.Path.BinName)
+}
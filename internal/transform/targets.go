@@ -0,0 +1,223 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aerospike/aerospike-client-go/v8"
+)
+
+// digestLength is the length of record digests.
+const digestLength = 20
+
+// Key types of key file lines.
+const (
+	keyTypeString = "string"
+	keyTypeInt    = "int"
+	keyTypeBlob   = "blob"
+)
+
+// Targets are the records to restore, read from digest and key files. Records that are not targets
+// are removed without decoding their bins.
+type Targets struct {
+	// StopWhenFound stops reading records once all targets are found. It must only be set if each record
+	// is in the backup once, so remaining files are only read up to their first record, for metadata.
+	StopWhenFound bool
+
+	// entries are the targets as written in the files, by digest, in the order of the files.
+	entries []string
+	index   map[[digestLength]byte]int
+
+	mu    sync.Mutex
+	found []bool
+	// remaining is the number of targets that were not found yet.
+	remaining atomic.Int64
+}
+
+// LoadTargets reads the targets from a digest file and a key file, either may be empty.
+// Digest file lines are digests in hex or base64. Key file lines are in the <set>:<type>:<key> format,
+// with type string, int or blob, and blob keys in base64. Empty lines and lines starting with # are skipped.
+func LoadTargets(digestFile, keyFile string) (*Targets, error) {
+	t := &Targets{index: make(map[[digestLength]byte]int)}
+
+	if digestFile != "" {
+		if err := t.load(digestFile, parseDigestLine); err != nil {
+			return nil, err
+		}
+	}
+
+	if keyFile != "" {
+		if err := t.load(keyFile, parseKeyLine); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(t.entries) == 0 {
+		return nil, errors.New("no records to restore in the digest and key files")
+	}
+
+	t.found = make([]bool, len(t.entries))
+	t.remaining.Store(int64(len(t.entries)))
+
+	return t, nil
+}
+
+func (t *Targets) load(path string, parse func(line string) ([]byte, error)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		digest, err := parse(line)
+		if err != nil {
+			return fmt.Errorf("invalid line %d of %s: %w", n, path, err)
+		}
+
+		// Records listed more than once, or in both files, are reported once.
+		if _, ok := t.index[[digestLength]byte(digest)]; ok {
+			continue
+		}
+
+		t.index[[digestLength]byte(digest)] = len(t.entries)
+		t.entries = append(t.entries, line)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func parseDigestLine(line string) ([]byte, error) {
+	line = strings.TrimSpace(line)
+
+	digest, err := hex.DecodeString(line)
+	if err != nil || len(digest) != digestLength {
+		digest, err = base64.StdEncoding.DecodeString(line)
+	}
+
+	if err != nil || len(digest) != digestLength {
+		return nil, fmt.Errorf("digest %q must be %d bytes in hex or base64", line, digestLength)
+	}
+
+	return digest, nil
+}
+
+func parseKeyLine(line string) ([]byte, error) {
+	parts := strings.SplitN(line, ":", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("key %q must be in the <set>:<type>:<key> format", line)
+	}
+
+	set, typ, value := parts[0], parts[1], parts[2]
+
+	var key any
+
+	switch typ {
+	case keyTypeString:
+		key = value
+	case keyTypeInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int key %q: %w", value, err)
+		}
+
+		key = i
+	case keyTypeBlob:
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blob key %q: %w", value, err)
+		}
+
+		key = b
+	default:
+		return nil, fmt.Errorf("invalid key type %q, must be %s, %s or %s", typ, keyTypeString, keyTypeInt, keyTypeBlob)
+	}
+
+	// The digest doesn't depend on the namespace.
+	k, err := aerospike.NewKey("", set, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate digest of key %q: %w", line, err)
+	}
+
+	return k.Digest(), nil
+}
+
+// Len returns the number of targets.
+func (t *Targets) Len() int {
+	return len(t.entries)
+}
+
+// find checks if the record with the digest is a target, and marks it as found.
+func (t *Targets) find(digest []byte) bool {
+	if len(digest) != digestLength {
+		return false
+	}
+
+	i, ok := t.index[[digestLength]byte(digest)]
+	if !ok {
+		return false
+	}
+
+	t.mu.Lock()
+	if !t.found[i] {
+		t.found[i] = true
+		t.remaining.Add(-1)
+	}
+	t.mu.Unlock()
+
+	return true
+}
+
+// done checks if reading records can stop, as all targets are found.
+func (t *Targets) done() bool {
+	return t.StopWhenFound && t.remaining.Load() == 0
+}
+
+// NotFound returns the targets that were not found in the backup, as written in the files.
+func (t *Targets) NotFound() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var notFound []string
+
+	for i, found := range t.found {
+		if !found {
+			notFound = append(notFound, t.entries[i])
+		}
+	}
+
+	return notFound
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDigest returns the digest of the key.
+func testDigest(t *testing.T, set string, key any) []byte {
+	t.Helper()
+
+	k, err := a.NewKey("test", set, key)
+	require.NoError(t, err)
+
+	return k.Digest()
+}
+
+// writeFile writes the content to a file in a temporary directory and returns its path.
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "targets.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoadTargets(t *testing.T) {
+	t.Parallel()
+
+	hexDigest := hex.EncodeToString(testDigest(t, "users", "alice"))
+	b64Digest := base64.StdEncoding.EncodeToString(testDigest(t, "orders", 7))
+
+	digestFile := writeFile(t, "# corrupted records\n"+hexDigest+"\n\n"+b64Digest+"\r\n")
+	keyFile := writeFile(t, "users:string:alice\nusers:int:42\n:blob:AQI=\nusers:string:a:b\n")
+
+	targets, err := LoadTargets(digestFile, keyFile)
+	require.NoError(t, err)
+
+	// users:string:alice is the same record as the first digest.
+	assert.Equal(t, 5, targets.Len())
+
+	assert.True(t, targets.find(testDigest(t, "users", 42)))
+	assert.True(t, targets.find(testDigest(t, "", []byte{1, 2})))
+	assert.True(t, targets.find(testDigest(t, "users", "alice")))
+	assert.False(t, targets.find(testDigest(t, "users", "bob")))
+	assert.False(t, targets.done(), "done is only reported if StopWhenFound is set")
+
+	assert.Equal(t, []string{b64Digest, "users:string:a:b"}, targets.NotFound())
+
+	targets.StopWhenFound = true
+
+	assert.True(t, targets.find(testDigest(t, "orders", 7)))
+	assert.False(t, targets.done())
+	assert.True(t, targets.find(testDigest(t, "users", "a:b")))
+	assert.True(t, targets.done())
+	assert.Empty(t, targets.NotFound())
+}
+
+func TestLoadTargets_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		digestFile string
+		keyFile    string
+		errMsg     string
+	}{
+		{
+			name:       "Short digest",
+			digestFile: "0102\n",
+			errMsg:     `invalid line 1 of %s: digest "0102" must be 20 bytes in hex or base64`,
+		},
+		{
+			name:    "Missing key type",
+			keyFile: "users:string:a\nusers:1\n",
+			errMsg:  `invalid line 2 of %s: key "users:1" must be in the <set>:<type>:<key> format`,
+		},
+		{
+			name:    "Invalid key type",
+			keyFile: "users:float:1.5\n",
+			errMsg:  `invalid line 1 of %s: invalid key type "float", must be string, int or blob`,
+		},
+		{
+			name:    "Invalid int key",
+			keyFile: "users:int:x\n",
+			errMsg:  `invalid line 1 of %s: invalid int key "x"`,
+		},
+		{
+			name:       "No records",
+			digestFile: "# nothing\n",
+			errMsg:     "no records to restore in the digest and key files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var digestFile, keyFile, path string
+
+			if tt.digestFile != "" {
+				digestFile = writeFile(t, tt.digestFile)
+				path = digestFile
+			}
+
+			if tt.keyFile != "" {
+				keyFile = writeFile(t, tt.keyFile)
+				path = keyFile
+			}
+
+			_, err := LoadTargets(digestFile, keyFile)
+			require.Error(t, err)
+
+			if path != "" {
+				tt.errMsg = strings.Replace(tt.errMsg, "%s", path, 1)
+			}
+
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadTargets_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := LoadTargets(filepath.Join(t.TempDir(), "missing.txt"), "")
+	require.ErrorContains(t, err, "failed to open")
+}
@@ -0,0 +1,245 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	particleType "github.com/aerospike/aerospike-client-go/v8/types/particle_type"
+	"github.com/aerospike/backup-go/io/aerospike/xdr"
+)
+
+const (
+	// opWrite is the wire protocol operation that writes a bin.
+	opWrite = 2
+	// info2 is the position of the second info byte in the message header.
+	info2 = 2
+)
+
+// errTruncated is returned for payloads that end within a field or an operation.
+var errTruncated = errors.New("truncated message")
+
+// xdrMessage is the payload of an asbx record, an Aerospike wire protocol message that writes
+// or deletes the record, decoded into its fields and operations.
+type xdrMessage struct {
+	// header is the message header, without the proto header.
+	header []byte
+	fields []xdrField
+	ops    []xdrOp
+}
+
+// xdrField is a field of a message, like the namespace, set, user key or digest.
+type xdrField struct {
+	typ  byte
+	data []byte
+}
+
+// xdrOp is an operation of a message, with the bin it changes.
+type xdrOp struct {
+	op       byte
+	particle byte
+	version  byte
+	name     string
+	value    []byte
+}
+
+// parseXDRMessage decodes the payload of an asbx record.
+func parseXDRMessage(payload []byte) (*xdrMessage, error) {
+	if len(payload) < xdr.LenProtoHeader+xdr.LenMessageHeader {
+		return nil, errTruncated
+	}
+
+	body := payload[xdr.LenProtoHeader:]
+	msg := &xdrMessage{header: body[:xdr.LenMessageHeader]}
+	numFields := int(binary.BigEndian.Uint16(msg.header[18:20]))
+	numOps := int(binary.BigEndian.Uint16(msg.header[20:22]))
+	off := xdr.LenMessageHeader
+
+	for range numFields {
+		if len(body)-off < 5 {
+			return nil, errTruncated
+		}
+
+		size := int(binary.BigEndian.Uint32(body[off:]))
+		if size < 1 || len(body)-off-4 < size {
+			return nil, errTruncated
+		}
+
+		msg.fields = append(msg.fields, xdrField{typ: body[off+4], data: body[off+5 : off+4+size]})
+		off += 4 + size
+	}
+
+	for range numOps {
+		if len(body)-off < 8 {
+			return nil, errTruncated
+		}
+
+		size := int(binary.BigEndian.Uint32(body[off:]))
+		nameLen := int(body[off+7])
+
+		if size < 4+nameLen || len(body)-off-4 < size {
+			return nil, errTruncated
+		}
+
+		msg.ops = append(msg.ops, xdrOp{
+			op:       body[off+4],
+			particle: body[off+5],
+			version:  body[off+6],
+			name:     string(body[off+8 : off+8+nameLen]),
+			value:    body[off+8+nameLen : off+4+size],
+		})
+		off += 4 + size
+	}
+
+	return msg, nil
+}
+
+// isDelete checks if the message deletes the record.
+func (m *xdrMessage) isDelete() bool {
+	return m.header[info2]&xdr.MsgInfo2Delete != 0
+}
+
+// field returns the data of the field of the type, nil if the message doesn't have it.
+func (m *xdrMessage) field(typ byte) []byte {
+	for _, f := range m.fields {
+		if f.typ == typ {
+			return f.data
+		}
+	}
+
+	return nil
+}
+
+// setField sets the data of the field of the type, adding the field if the message doesn't have it.
+func (m *xdrMessage) setField(typ byte, data []byte) {
+	for i, f := range m.fields {
+		if f.typ == typ {
+			m.fields[i].data = data
+			return
+		}
+	}
+
+	m.fields = append(m.fields, xdrField{typ: typ, data: data})
+}
+
+// record returns the record the message writes or deletes. The bins of deletes are unknown.
+func (m *xdrMessage) record(digest []byte) *record {
+	rec := &record{
+		key:    m.userKey(),
+		digest: digest,
+		set:    string(m.field(xdr.FieldTypeSet)),
+	}
+
+	if m.isDelete() {
+		return rec
+	}
+
+	rec.bins = make(map[string]any, len(m.ops))
+
+	for _, op := range m.ops {
+		if op.op != opWrite {
+			rec.bins[op.name] = unknownValue{}
+			continue
+		}
+
+		rec.bins[op.name] = op.decode()
+	}
+
+	return rec
+}
+
+// userKey returns the stored user key, nil if it is not stored or of an unsupported type.
+func (m *xdrMessage) userKey() any {
+	data := m.field(xdr.FieldTypeUserKey)
+	if len(data) < 1 {
+		return nil
+	}
+
+	switch value := data[1:]; data[0] {
+	case xdr.UserKeyTypeInt:
+		if len(value) != 8 {
+			return nil
+		}
+
+		return int64(binary.BigEndian.Uint64(value))
+	case xdr.UserKeyTypeString:
+		return string(value)
+	case xdr.UserKeyTypeBlob:
+		return value
+	default:
+		return nil
+	}
+}
+
+// decode returns the value written by the operation, decoded to the types of the asb decoder.
+func (op *xdrOp) decode() any {
+	switch op.particle {
+	case particleType.NULL:
+		return nil
+	case particleType.INTEGER:
+		if len(op.value) == 8 {
+			return int64(binary.BigEndian.Uint64(op.value))
+		}
+	case particleType.FLOAT:
+		if len(op.value) == 8 {
+			return math.Float64frombits(binary.BigEndian.Uint64(op.value))
+		}
+	case particleType.STRING:
+		return string(op.value)
+	case particleType.BLOB:
+		return op.value
+	case particleType.BOOL:
+		if len(op.value) == 1 {
+			return op.value[0] != 0
+		}
+	default:
+		return a.NewRawBlobValue(int(op.particle), op.value)
+	}
+
+	return unknownValue{}
+}
+
+// payload returns the message encoded as an asbx record payload.
+func (m *xdrMessage) payload() ([]byte, error) {
+	if len(m.fields) > math.MaxUint16 {
+		return nil, fmt.Errorf("too many fields: %d", len(m.fields))
+	}
+
+	body := append([]byte(nil), m.header...)
+	binary.BigEndian.PutUint16(body[18:20], uint16(len(m.fields)))
+
+	for _, f := range m.fields {
+		body = binary.BigEndian.AppendUint32(body, uint32(len(f.data)+1))
+		body = append(body, f.typ)
+		body = append(body, f.data...)
+	}
+
+	for _, op := range m.ops {
+		if len(op.name) > math.MaxUint8 {
+			return nil, fmt.Errorf("bin name %s is too long", op.name)
+		}
+
+		body = binary.BigEndian.AppendUint32(body, uint32(4+len(op.name)+len(op.value)))
+		body = append(body, op.op, op.particle, op.version, byte(len(op.name)))
+		body = append(body, op.name...)
+		body = append(body, op.value...)
+	}
+
+	return xdr.NewPayload(body), nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"encoding/binary"
+	"testing"
+
+	a "github.com/aerospike/aerospike-client-go/v8"
+	particleType "github.com/aerospike/aerospike-client-go/v8/types/particle_type"
+	"github.com/aerospike/backup-go/io/aerospike/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xdrBin is a bin written by a test payload, with an int64, string or bool value.
+type xdrBin struct {
+	name  string
+	value any
+}
+
+// testXDRPayload returns the payload of an asbx record that writes the bins of the record, or deletes it,
+// laid out as the messages shipped by XDR.
+func testXDRPayload(t *testing.T, key *a.Key, isDelete bool, bins ...xdrBin) []byte {
+	t.Helper()
+
+	header := make([]byte, xdr.LenMessageHeader)
+	header[0] = xdr.LenMessageHeader
+	header[1] = xdr.MsgInfo1Xdr
+
+	if isDelete {
+		header[2] = xdr.MsgInfo2Delete
+	} else {
+		header[2] = xdr.MsgInfo2Write
+	}
+
+	body := append([]byte(nil), header...)
+	fields := 0
+	appendField := func(typ byte, data []byte) {
+		body = binary.BigEndian.AppendUint32(body, uint32(len(data)+1))
+		body = append(body, typ)
+		body = append(body, data...)
+		fields++
+	}
+
+	appendField(xdr.FieldTypeNamespace, []byte(key.Namespace()))
+
+	if key.SetName() != "" {
+		appendField(xdr.FieldTypeSet, []byte(key.SetName()))
+	}
+
+	switch v := userKey(key).(type) {
+	case int64:
+		appendField(xdr.FieldTypeUserKey, binary.BigEndian.AppendUint64([]byte{xdr.UserKeyTypeInt}, uint64(v)))
+	case string:
+		appendField(xdr.FieldTypeUserKey, append([]byte{xdr.UserKeyTypeString}, v...))
+	}
+
+	appendField(xdr.FieldTypeDigest, key.Digest())
+
+	for _, bin := range bins {
+		var (
+			particle byte
+			value    []byte
+		)
+
+		switch v := bin.value.(type) {
+		case int64:
+			particle, value = particleType.INTEGER, binary.BigEndian.AppendUint64(nil, uint64(v))
+		case string:
+			particle, value = particleType.STRING, []byte(v)
+		case bool:
+			particle, value = particleType.BOOL, []byte{0}
+			if v {
+				value[0] = 1
+			}
+		default:
+			require.Failf(t, "unsupported bin value", "%T", v)
+		}
+
+		body = binary.BigEndian.AppendUint32(body, uint32(4+len(bin.name)+len(value)))
+		body = append(body, opWrite, particle, 0, byte(len(bin.name)))
+		body = append(body, bin.name...)
+		body = append(body, value...)
+	}
+
+	binary.BigEndian.PutUint16(body[18:20], uint16(fields))
+	binary.BigEndian.PutUint16(body[20:22], uint16(len(bins)))
+
+	return xdr.NewPayload(body)
+}
+
+func TestParseXDRMessage(t *testing.T) {
+	t.Parallel()
+
+	key, aErr := a.NewKey("test", "users", int64(42))
+	require.NoError(t, aErr)
+
+	payload := testXDRPayload(t, key, false, xdrBin{"age", int64(42)}, xdrBin{"name", "bob"}, xdrBin{"ok", true})
+
+	msg, err := parseXDRMessage(payload)
+	require.NoError(t, err)
+	assert.False(t, msg.isDelete())
+	assert.Equal(t, &record{
+		key:    int64(42),
+		digest: key.Digest(),
+		set:    "users",
+		bins:   map[string]any{"age": int64(42), "name": "bob", "ok": true},
+	}, msg.record(key.Digest()))
+
+	// Payloads are encoded unchanged.
+	encoded, err := msg.payload()
+	require.NoError(t, err)
+	assert.Equal(t, payload, encoded)
+
+	// The bins of deletes are unknown.
+	msg, err = parseXDRMessage(testXDRPayload(t, key, true))
+	require.NoError(t, err)
+	assert.True(t, msg.isDelete())
+	assert.Nil(t, msg.record(key.Digest()).bins)
+}
+
+func TestParseXDRMessage_Truncated(t *testing.T) {
+	t.Parallel()
+
+	key, aErr := a.NewKey("test", "users", "bob")
+	require.NoError(t, aErr)
+
+	payload := testXDRPayload(t, key, false, xdrBin{"name", "bob"})
+
+	for _, n := range []int{0, xdr.LenProtoHeader + xdr.LenMessageHeader - 1, xdr.LenProtoHeader + 30, len(payload) - 1} {
+		_, err := parseXDRMessage(payload[:n])
+		require.ErrorIs(t, err, errTruncated, n)
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/aerospike/absctl/internal/aeskey"
+	"github.com/aerospike/absctl/internal/codec"
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/logging"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	"github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go"
+	"github.com/aerospike/backup-go/io/storage/common"
+	bModels "github.com/aerospike/backup-go/models"
+)
+
+const modeNone = "NONE"
+
+// Service verifies backup files against the backup manifest without connecting to a cluster.
+type Service struct {
+	// directory is the backup directory.
+	directory string
+	// reader streams all files in the backup directory.
+	reader backup.StreamingReader
+	// asbReader and asbxReader stream only the .asb and .asbx files for the decode check,
+	// they are nil if the check is skipped or there are no such files.
+	asbReader  backup.StreamingReader
+	asbxReader backup.StreamingReader
+	// backupClient runs the decode check with a nil aerospike client.
+	backupClient *backup.Client
+	config       *backup.ConfigRestore
+
+	compressionMode string
+	encryptionMode  string
+	// key checks the signature of the manifest, nil if no encryption key is given.
+	key []byte
+
+	reportToLog bool
+
+	logger *slog.Logger
+}
+
+// NewService initializes and returns a new verify Service.
+func NewService(
+	ctx context.Context,
+	cfg *config.VerifyServiceConfig,
+	logger *slog.Logger,
+) (*Service, error) {
+	directory := cfg.Verify.Directory
+
+	// Skip the file checks, so extra files are also streamed.
+	reader, err := storage.NewReader(ctx, &cfg.ServiceConfigCommon, directory, "", "", "", 0, false, true, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader: %w", err)
+	}
+
+	s := &Service{
+		directory:       directory,
+		reader:          reader,
+		compressionMode: modeNone,
+		encryptionMode:  modeNone,
+		reportToLog:     cfg.App.LogJSON || cfg.App.LogFile != "",
+		logger:          logger,
+	}
+
+	// The signature is checked even if the decode check is skipped.
+	if policy := cfg.Encryption.Policy(); policy != nil {
+		s.key, err = aeskey.Read(ctx, policy, cfg.SecretAgent.Config())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key: %w", err)
+		}
+	}
+
+	if cfg.Verify.SkipDecode {
+		return s, nil
+	}
+
+	s.asbReader, err = newDecodeReader(ctx, cfg, false, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create asb reader: %w", err)
+	}
+
+	s.asbxReader, err = newDecodeReader(ctx, cfg, true, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create asbx reader: %w", err)
+	}
+
+	// Important! To describe variable as interface not exact *a.Client.
+	// So we can run backup files validation with the 'nil' aerospike client.
+	var aerospikeClient backup.AerospikeClient
+
+	s.backupClient, err = backup.NewClient(aerospikeClient, backup.WithLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup client: %w", err)
+	}
+
+	s.config = backup.NewDefaultRestoreConfig()
+	s.config.ValidateOnly = true
+	// The restore takes the default write policy from the client, which is nil.
+	s.config.WritePolicy = aerospike.NewWritePolicy(0, 0)
+	s.config.CompressionPolicy = cfg.Compression.Policy()
+	s.config.EncryptionPolicy = cfg.Encryption.Policy()
+	s.config.SecretAgentConfig = cfg.SecretAgent.Config()
+
+	if s.config.CompressionPolicy != nil {
+		s.compressionMode = s.config.CompressionPolicy.Mode
+	}
+
+	if s.config.EncryptionPolicy != nil {
+		s.encryptionMode = s.config.EncryptionPolicy.Mode
+	}
+
+	return s, nil
+}
+
+// newDecodeReader returns a reader of the .asbx files if isXdr is set, of the .asb files otherwise,
+// nil if the backup has no such files.
+func newDecodeReader(
+	ctx context.Context, cfg *config.VerifyServiceConfig, isXdr bool, logger *slog.Logger,
+) (backup.StreamingReader, error) {
+	reader, err := storage.NewReader(ctx, &cfg.ServiceConfigCommon, cfg.Verify.Directory, "", "", "", 0, isXdr, false,
+		logger)
+	if errors.Is(err, common.ErrEmptyStorage) {
+		// Nothing to decode, missing files are reported by the manifest check.
+		return nil, nil
+	}
+
+	return reader, err
+}
+
+// Run verifies the backup and prints the report.
+// Returns models.ErrVerifyFailed if any problem was found, models.ErrVerifySignatureNotChecked
+// if none was found but the signature of the manifest was not checked.
+func (s *Service) Run(ctx context.Context) error {
+	s.logger.Info("starting backup verification")
+
+	manifest, err := storage.ReadManifest(ctx, s.reader, s.directory)
+	if err != nil {
+		return err
+	}
+
+	report := &models.VerifyReport{}
+
+	s.checkSignature(manifest, report)
+
+	if err = s.checkFiles(ctx, manifest, report); err != nil {
+		return fmt.Errorf("failed to check backup files: %w", err)
+	}
+
+	if s.backupClient != nil {
+		s.checkConfig(manifest, report)
+
+		if err = s.checkDecode(ctx, report); err != nil {
+			return err
+		}
+	}
+
+	logging.ReportVerify(report, s.reportToLog, s.logger)
+
+	switch {
+	case !report.Passed():
+		return models.ErrVerifyFailed
+	case !report.SignatureChecked:
+		return models.ErrVerifySignatureNotChecked
+	default:
+		return nil
+	}
+}
+
+// checkSignature checks the signature of the manifest with the given encryption key. Without a key,
+// the manifest is not checked, and the report says why: only the manifests of encrypted backups are signed.
+func (s *Service) checkSignature(manifest *models.Manifest, report *models.VerifyReport) {
+	if s.key == nil {
+		report.SignatureSkipped = signatureSkipped(manifest)
+		s.logger.Warn("manifest signature not checked", slog.String("reason", report.SignatureSkipped))
+
+		return
+	}
+
+	report.SignatureChecked = true
+
+	if err := manifest.CheckSignature(s.key); err != nil {
+		report.SignatureError = err.Error()
+	}
+}
+
+// signatureSkipped returns the reason the signature of the manifest is not checked without a key.
+func signatureSkipped(manifest *models.Manifest) string {
+	if manifest.Signature == "" && (manifest.Config.Encryption == "" || manifest.Config.Encryption == modeNone) {
+		return "the backup is not encrypted, its manifest is not signed"
+	}
+
+	return "no encryption key given"
+}
+
+// checkFiles hashes every file in the backup directory and compares it with the manifest.
+func (s *Service) checkFiles(ctx context.Context, manifest *models.Manifest, report *models.VerifyReport) error {
+	expected := make(map[string]models.ManifestFile, len(manifest.Files))
+	for _, f := range manifest.Files {
+		expected[f.Name] = f
+	}
+
+	found := make(map[string]struct{}, len(manifest.Files))
+
+	err := codec.StreamFiles(ctx, s.reader, func(file bModels.File) error {
+		name := filepath.Base(file.Name)
+
+		size, checksum, err := hashFile(file.Reader)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", file.Name, err)
+		}
+
+		if name == models.ManifestFileName {
+			return nil
+		}
+
+		found[name] = struct{}{}
+		report.FilesChecked++
+		report.BytesChecked += size
+
+		compareFile(name, size, checksum, expected, report)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, f := range manifest.Files {
+		if _, ok := found[f.Name]; !ok {
+			report.Missing = append(report.Missing, f.Name)
+		}
+	}
+
+	s.logger.Debug("backup files checked",
+		slog.Int("files", report.FilesChecked),
+		slog.Int64("bytes", report.BytesChecked),
+	)
+
+	return nil
+}
+
+// compareFile adds the file to the report if it doesn't match the manifest.
+func compareFile(
+	name string,
+	size int64,
+	checksum string,
+	expected map[string]models.ManifestFile,
+	report *models.VerifyReport,
+) {
+	want, ok := expected[name]
+
+	switch {
+	case !ok:
+		report.Extra = append(report.Extra, name)
+	case size < want.Size:
+		report.Truncated = append(report.Truncated, name)
+	case size != want.Size || checksum != want.SHA256:
+		report.Corrupted = append(report.Corrupted, name)
+	}
+}
+
+// hashFile reads the file to the end and returns its size and SHA-256 checksum.
+func hashFile(r io.ReadCloser) (int64, string, error) {
+	defer r.Close()
+
+	h := sha256.New()
+
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checkConfig compares the given compression and encryption modes with the ones recorded in the manifest.
+func (s *Service) checkConfig(manifest *models.Manifest, report *models.VerifyReport) {
+	if !strings.EqualFold(manifest.Config.Compression, s.compressionMode) {
+		report.ConfigMismatch = append(report.ConfigMismatch,
+			fmt.Sprintf("compression mode %s, backup was made with %s",
+				s.compressionMode, manifest.Config.Compression))
+	}
+
+	if !strings.EqualFold(manifest.Config.Encryption, s.encryptionMode) {
+		report.ConfigMismatch = append(report.ConfigMismatch,
+			fmt.Sprintf("encryption mode %s, backup was made with %s",
+				s.encryptionMode, manifest.Config.Encryption))
+	}
+}
+
+// checkDecode decrypts, decompresses and decodes all backup files without restoring them.
+// The .asb and .asbx files are decoded with the decoder of their format.
+func (s *Service) checkDecode(ctx context.Context, report *models.VerifyReport) error {
+	if s.asbReader == nil && s.asbxReader == nil {
+		return nil
+	}
+
+	for _, check := range []struct {
+		reader      backup.StreamingReader
+		encoderType backup.EncoderType
+	}{
+		{reader: s.asbReader, encoderType: backup.EncoderTypeASB},
+		{reader: s.asbxReader, encoderType: backup.EncoderTypeASBX},
+	} {
+		if check.reader == nil {
+			continue
+		}
+
+		records, err := s.decode(ctx, check.reader, check.encoderType)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			report.DecodeError = err.Error()
+
+			return nil
+		}
+
+		report.RecordsRead += records
+	}
+
+	report.DecodeChecked = true
+
+	return nil
+}
+
+// decode runs a restore that only decodes the files of the reader, and returns the number of records read.
+func (s *Service) decode(
+	ctx context.Context, reader backup.StreamingReader, encoderType backup.EncoderType,
+) (uint64, error) {
+	cfg := *s.config
+	cfg.EncoderType = encoderType
+
+	h, err := s.backupClient.Restore(ctx, &cfg, reader)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = h.Wait(ctx); err != nil {
+		return 0, err
+	}
+
+	return h.GetStats().GetReadRecords(), nil
+}
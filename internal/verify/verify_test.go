@@ -0,0 +1,403 @@
+// Copyright 2024 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/absctl/internal/aeskey"
+	"github.com/aerospike/absctl/internal/config"
+	"github.com/aerospike/absctl/internal/models"
+	"github.com/aerospike/absctl/internal/storage"
+	a "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/backup-go/io/encoding/asb"
+	"github.com/aerospike/backup-go/io/encoding/asbx"
+	bModels "github.com/aerospike/backup-go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFiles = map[string][]byte{
+	"test_0_1.asb": []byte("first backup file"),
+	"test_1_1.asb": []byte("second backup file"),
+}
+
+// writeTestBackup creates backup files and a matching manifest in a temp directory.
+func writeTestBackup(t *testing.T) string {
+	t.Helper()
+
+	return writeTestBackupFiles(t, testFiles)
+}
+
+// writeTestBackupFiles creates the files and a matching manifest in a temp directory.
+func writeTestBackupFiles(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	manifest := models.Manifest{
+		FormatVersion: models.ManifestFormatVersion,
+		Config: models.ManifestConfig{
+			Compression: modeNone,
+			Encryption:  modeNone,
+		},
+	}
+
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, models.ManifestFile{
+			Name:   name,
+			Size:   int64(len(data)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	content, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, models.ManifestFileName), content, 0o600))
+
+	return dir
+}
+
+func newTestService(t *testing.T, dir string) *Service {
+	t.Helper()
+
+	return newTestServiceWithKey(t, dir, &models.Encryption{})
+}
+
+func newTestServiceWithKey(t *testing.T, dir string, encryption *models.Encryption) *Service {
+	t.Helper()
+
+	return newTestServiceWithConfig(t, &models.Verify{Directory: dir, SkipDecode: true}, encryption)
+}
+
+func newTestServiceWithConfig(t *testing.T, verify *models.Verify, encryption *models.Encryption) *Service {
+	t.Helper()
+
+	cfg := config.NewVerifyServiceConfig(
+		&models.App{},
+		verify,
+		&models.Compression{},
+		encryption,
+		&models.SecretAgent{},
+		&models.AwsS3{},
+		&models.GcpStorage{},
+		&models.AzureBlob{},
+	)
+
+	s, err := NewService(t.Context(), cfg, slog.Default())
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestService_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tamper  func(t *testing.T, dir string)
+		wantErr error
+	}{
+		{
+			name:   "intact backup",
+			tamper: func(*testing.T, string) {},
+			// The backup is not encrypted, so its manifest is not signed.
+			wantErr: models.ErrVerifySignatureNotChecked,
+		},
+		{
+			name: "corrupted file",
+			tamper: func(t *testing.T, dir string) {
+				t.Helper()
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "test_0_1.asb"), []byte("FIRST BACKUP FILE"), 0o600))
+			},
+			wantErr: models.ErrVerifyFailed,
+		},
+		{
+			name: "truncated file",
+			tamper: func(t *testing.T, dir string) {
+				t.Helper()
+				require.NoError(t, os.Truncate(filepath.Join(dir, "test_1_1.asb"), 3))
+			},
+			wantErr: models.ErrVerifyFailed,
+		},
+		{
+			name: "missing file",
+			tamper: func(t *testing.T, dir string) {
+				t.Helper()
+				require.NoError(t, os.Remove(filepath.Join(dir, "test_0_1.asb")))
+			},
+			wantErr: models.ErrVerifyFailed,
+		},
+		{
+			name: "extra file",
+			tamper: func(t *testing.T, dir string) {
+				t.Helper()
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "test_2_1.asb"), []byte("extra"), 0o600))
+			},
+			wantErr: models.ErrVerifyFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := writeTestBackup(t)
+			tt.tamper(t, dir)
+
+			err := newTestService(t, dir).Run(t.Context())
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// newTestKey writes a new encryption key file, and returns its encryption settings.
+func newTestKey(t *testing.T) *models.Encryption {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	require.NoError(t, os.WriteFile(keyFile, pemData, 0o600))
+
+	return &models.Encryption{Mode: "AES256", KeyFile: keyFile}
+}
+
+// signTestBackup signs the manifest written by writeTestBackup with a new key,
+// and returns the encryption settings of the key.
+func signTestBackup(t *testing.T, dir string) *models.Encryption {
+	t.Helper()
+
+	encryption := newTestKey(t)
+
+	key, err := aeskey.Read(t.Context(), encryption.Policy(), nil)
+	require.NoError(t, err)
+
+	manifestFile := filepath.Join(dir, models.ManifestFileName)
+
+	data, err := os.ReadFile(manifestFile)
+	require.NoError(t, err)
+
+	var manifest models.Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.NoError(t, manifest.Sign(key))
+
+	data, err = json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestFile, data, 0o600))
+
+	return encryption
+}
+
+func TestService_RunSignature(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTestBackup(t)
+	encryption := signTestBackup(t, dir)
+
+	require.NoError(t, newTestServiceWithKey(t, dir, encryption).Run(t.Context()))
+	// Without the key, the signature is not checked.
+	require.ErrorIs(t, newTestService(t, dir).Run(t.Context()), models.ErrVerifySignatureNotChecked)
+
+	// A file is changed along with its checksum in the manifest.
+	data := []byte("changed backup file")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test_0_1.asb"), data, 0o600))
+
+	manifestFile := filepath.Join(dir, models.ManifestFileName)
+
+	content, err := os.ReadFile(manifestFile)
+	require.NoError(t, err)
+
+	var manifest models.Manifest
+	require.NoError(t, json.Unmarshal(content, &manifest))
+
+	sum := sha256.Sum256(data)
+
+	for i := range manifest.Files {
+		if manifest.Files[i].Name == "test_0_1.asb" {
+			manifest.Files[i] = models.ManifestFile{
+				Name: "test_0_1.asb", Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:]),
+			}
+		}
+	}
+
+	content, err = json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestFile, content, 0o600))
+
+	err = newTestServiceWithKey(t, dir, encryption).Run(t.Context())
+	require.ErrorIs(t, err, models.ErrVerifyFailed)
+}
+
+func TestService_CheckSignatureUnsigned(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTestBackup(t)
+
+	s := newTestServiceWithKey(t, dir, newTestKey(t))
+	manifest, err := storage.ReadManifest(t.Context(), s.reader, dir)
+	require.NoError(t, err)
+
+	report := &models.VerifyReport{}
+	s.checkSignature(manifest, report)
+
+	assert.True(t, report.SignatureChecked)
+	assert.Equal(t, models.ErrManifestUnsigned.Error(), report.SignatureError)
+}
+
+func TestService_CheckSignatureSkipped(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTestBackup(t)
+
+	s := newTestService(t, dir)
+	manifest, err := storage.ReadManifest(t.Context(), s.reader, dir)
+	require.NoError(t, err)
+
+	report := &models.VerifyReport{}
+	s.checkSignature(manifest, report)
+
+	assert.False(t, report.SignatureChecked)
+	assert.Equal(t, "the backup is not encrypted, its manifest is not signed", report.SignatureSkipped)
+
+	manifest.Config.Encryption = "AES256"
+	report = &models.VerifyReport{}
+	s.checkSignature(manifest, report)
+
+	assert.Equal(t, "no encryption key given", report.SignatureSkipped)
+}
+
+func TestService_RunMissingManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTestBackup(t)
+	require.NoError(t, os.Remove(filepath.Join(dir, models.ManifestFileName)))
+
+	err := newTestService(t, dir).Run(t.Context())
+	require.Error(t, err)
+	require.NotErrorIs(t, err, models.ErrVerifyFailed)
+}
+
+// testDecodeFiles returns an .asb and an .asbx file of one record each.
+func testDecodeFiles(t *testing.T) map[string][]byte {
+	t.Helper()
+
+	key, aErr := a.NewKey("test", "users", 1)
+	require.NoError(t, aErr)
+
+	asbEncoder := asb.NewEncoder[*bModels.Token](asb.NewEncoderConfig("test", false, false))
+	asbFile := bytes.NewBuffer(asbEncoder.GetHeader(0, true))
+	record := &bModels.Record{Record: &a.Record{Key: key, Bins: a.BinMap{"age": int64(42)}, Generation: 1}}
+	require.NoError(t, asbEncoder.EncodeToken(bModels.NewRecordToken(record, 0, nil), asbFile))
+
+	asbxEncoder := asbx.NewEncoder[*bModels.ASBXToken]("test")
+	asbxFile := bytes.NewBuffer(asbxEncoder.GetHeader(1, true))
+	require.NoError(t, asbxEncoder.EncodeToken(bModels.NewASBXToken(key, []byte("payload")), asbxFile))
+
+	return map[string][]byte{
+		"test_0.asb":    asbFile.Bytes(),
+		"0_test_1.asbx": asbxFile.Bytes(),
+	}
+}
+
+func TestService_CheckDecode(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTestBackupFiles(t, testDecodeFiles(t))
+	s := newTestServiceWithConfig(t, &models.Verify{Directory: dir}, &models.Encryption{})
+
+	report := &models.VerifyReport{}
+	require.NoError(t, s.checkDecode(t.Context(), report))
+
+	assert.True(t, report.DecodeChecked)
+	assert.Empty(t, report.DecodeError)
+	// The records of both files are read, each with the decoder of its format.
+	assert.Equal(t, uint64(2), report.RecordsRead)
+}
+
+func TestService_CheckDecodeTruncatedASBX(t *testing.T) {
+	t.Parallel()
+
+	files := testDecodeFiles(t)
+	files["0_test_1.asbx"] = files["0_test_1.asbx"][:len(files["0_test_1.asbx"])-2]
+
+	dir := writeTestBackupFiles(t, files)
+	s := newTestServiceWithConfig(t, &models.Verify{Directory: dir}, &models.Encryption{})
+
+	report := &models.VerifyReport{}
+	require.NoError(t, s.checkDecode(t.Context(), report))
+
+	assert.False(t, report.DecodeChecked)
+	assert.Contains(t, report.DecodeError, "failed to read payload")
+}
+
+func TestCompareFile(t *testing.T) {
+	t.Parallel()
+
+	expected := map[string]models.ManifestFile{
+		"a.asb": {Name: "a.asb", Size: 10, SHA256: "abc"},
+	}
+
+	report := &models.VerifyReport{}
+
+	compareFile("a.asb", 10, "abc", expected, report)
+	assert.True(t, report.Passed())
+
+	compareFile("a.asb", 5, "abc", expected, report)
+	compareFile("a.asb", 10, "def", expected, report)
+	compareFile("a.asb", 12, "abc", expected, report)
+	compareFile("b.asb", 10, "abc", expected, report)
+
+	assert.Equal(t, []string{"a.asb"}, report.Truncated)
+	assert.Equal(t, []string{"a.asb", "a.asb"}, report.Corrupted)
+	assert.Equal(t, []string{"b.asb"}, report.Extra)
+}
+
+func TestService_CheckConfig(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{
+		compressionMode: "ZSTD",
+		encryptionMode:  modeNone,
+	}
+
+	manifest := &models.Manifest{
+		Config: models.ManifestConfig{
+			Compression: "ZSTD",
+			Encryption:  "AES256",
+		},
+	}
+
+	report := &models.VerifyReport{}
+	s.checkConfig(manifest, report)
+
+	require.Len(t, report.ConfigMismatch, 1)
+	assert.Contains(t, report.ConfigMismatch[0], "encryption mode NONE, backup was made with AES256")
+}